@@ -4,19 +4,40 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/apiversion"
 	"github.com/koraygocmen/golang-boilerplate/internal/aws"
 	"github.com/koraygocmen/golang-boilerplate/internal/config"
 	"github.com/koraygocmen/golang-boilerplate/internal/database"
 	"github.com/koraygocmen/golang-boilerplate/internal/env"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
 	"github.com/koraygocmen/golang-boilerplate/internal/errhandle"
+	"github.com/koraygocmen/golang-boilerplate/internal/event"
+	"github.com/koraygocmen/golang-boilerplate/internal/event/subscriber"
+	"github.com/koraygocmen/golang-boilerplate/internal/i18n"
 	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	"github.com/koraygocmen/golang-boilerplate/internal/outbox"
+	"github.com/koraygocmen/golang-boilerplate/internal/queue"
+	"github.com/koraygocmen/golang-boilerplate/internal/queue/jobs"
+	"github.com/koraygocmen/golang-boilerplate/internal/ratelimit"
+	"github.com/koraygocmen/golang-boilerplate/internal/realtime"
+	"github.com/koraygocmen/golang-boilerplate/internal/reporter"
+	"github.com/koraygocmen/golang-boilerplate/internal/scheduler"
+	"github.com/koraygocmen/golang-boilerplate/internal/scheduler/tasks"
+	"github.com/koraygocmen/golang-boilerplate/internal/slack"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/graphql"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/grpc"
 	"github.com/koraygocmen/golang-boilerplate/internal/transport/handler"
 	"github.com/koraygocmen/golang-boilerplate/internal/transport/middleware"
 	"github.com/koraygocmen/golang-boilerplate/internal/transport/router"
+	"github.com/koraygocmen/golang-boilerplate/internal/webhook"
 	_ "github.com/koraygocmen/golang-boilerplate/migrations"
 	"github.com/koraygocmen/golang-boilerplate/pkg/duration"
 	_ "github.com/koraygocmen/golang-boilerplate/seeds"
@@ -84,6 +105,12 @@ func main() {
 					Level:  config.Log.Level,
 					Mode:   config.Log.Mode,
 					SHASUM: SHASUM,
+					Sinks:  config.Log.Sinks,
+
+					RouteLevels: config.Log.RouteLevels,
+					Sampling:    config.Log.Sampling,
+					Body:        config.Log.Body,
+					Redact:      config.Log.Redact,
 
 					Syslog: config.Log.Syslog,
 					File:   config.Log.File,
@@ -97,6 +124,38 @@ func main() {
 				// Set the logger.
 				logger.Logger = logWriter
 
+				// Set up slack.
+				if err := slack.Init(slack.Config{
+					Token:     config.Slack.Token,
+					APIURL:    config.Slack.APIURL,
+					Channels:  config.Slack.Channels,
+					Mentions:  config.Slack.Mentions,
+					ThreadTTL: duration.Hours(config.Slack.ThreadTTLHours),
+					Webhook:   config.Slack.Webhook,
+				}); err != nil {
+					err = fmt.Errorf("slack init error: %w", err)
+					log.Fatal(err)
+				}
+
+				// Set up error reporter.
+				errReporter, err := reporter.New(reporter.Config{
+					Sinks:      config.Report.Sinks,
+					QueueSize:  config.Report.QueueSize,
+					WindowSec:  config.Report.WindowSec,
+					RatePerMin: config.Report.RatePerMin,
+					Burst:      config.Report.Burst,
+					SHASUM:     SHASUM,
+					Sentry:     config.Report.Sentry,
+					Webhook:    config.Report.Webhook,
+				})
+				if err != nil {
+					err = fmt.Errorf("reporter new error: %w", err)
+					log.Fatal(err)
+				}
+
+				// Set the reporter.
+				reporter.Reporter = errReporter
+
 				// Initialize database.
 				if database.DB, err = database.Connect(logger.Logger, config.Database); err != nil {
 					err = fmt.Errorf("database new error: %w", err)
@@ -256,53 +315,103 @@ func main() {
 		Use:   "serve",
 		Short: "start the server",
 		Run: func(cmd *cobra.Command, args []string) {
-			// Load env variables.
-			env.Init()
+			// Set up and flush the logger and reporter on exit.
+			cleanup := setup(ctx)
+			defer cleanup()
 
-			// Initialize aws client.
-			if err := aws.Init(); err != nil {
-				err = fmt.Errorf("aws init error: %w", err)
-				errhandle.Handle(ctx, nil, err, true)
+			// Run migrations if the automigrate option is set.
+			if config.Database.Migrations.Auto {
+				if err := database.DB.Up(ctx); err != nil {
+					err = fmt.Errorf("migrations auto error: db up error: %w", err)
+					errhandle.Handle(ctx, nil, err, true)
+				}
 			}
 
-			// Load config.
-			config.Load()
-
-			// Set up logger.
-			logWriter, err := logger.New(logger.Config{
-				Level:  config.Log.Level,
-				Mode:   config.Log.Mode,
-				SHASUM: SHASUM,
-
-				Syslog: config.Log.Syslog,
-				File:   config.Log.File,
-				AWS:    config.Log.AWS,
+			// Start the webhook dispatcher and let it finish
+			// the deliveries in flight on exit.
+			webhookDispatcher := webhook.New(webhook.Config{
+				MaxAttempts:    config.Webhook.MaxAttempts,
+				BackoffBaseSec: config.Webhook.BackoffBaseSec,
+				BackoffMaxSec:  config.Webhook.BackoffMaxSec,
+				PollSec:        config.Webhook.PollSec,
+				TimeoutSec:     config.Webhook.TimeoutSec,
+				BatchSize:      config.Webhook.BatchSize,
+			})
+			webhookDispatcher.Start()
+			defer webhookDispatcher.Close()
+
+			// Stream the session events to the clients, the replicas
+			// share the events over the pubsub. The streams end before
+			// the write timeout, or when the broker is closed on exit.
+			broker, err := realtime.New(realtime.Config{
+				PubSub:       config.Realtime.PubSub,
+				DSN:          config.SqlOpts(config.Database),
+				HeartbeatSec: config.Realtime.HeartbeatSec,
+				StreamSec:    config.Server.Timeout.Write,
 			})
 			if err != nil {
-				err = fmt.Errorf("logger new error: %w", err)
+				err = fmt.Errorf("realtime broker error: %w", err)
 				errhandle.Handle(ctx, nil, err, true)
 			}
+			if err := broker.Start(); err != nil {
+				err = fmt.Errorf("realtime broker error: %w", err)
+				errhandle.Handle(ctx, nil, err, true)
+			}
+			realtime.Default = broker
+
+			// Relay the committed domain events to the subscribers,
+			// the relay is closed before the webhook dispatcher.
+			subscriber.Register(event.Default)
+			outboxRelay := outbox.New(outbox.Config{
+				PollSec:        config.Outbox.PollSec,
+				BatchSize:      config.Outbox.BatchSize,
+				BackoffBaseSec: config.Outbox.BackoffBaseSec,
+				BackoffMaxSec:  config.Outbox.BackoffMaxSec,
+			}, event.Default)
+			outboxRelay.Start()
+			defer outboxRelay.Close()
+
+			// Limit the requests with the configured policies,
+			// the replicas share the limits of a shared storage.
+			limiter, err := ratelimit.New(ratelimit.Config{
+				Storage:  config.RateLimit.Storage,
+				Policies: config.RateLimit.Policies,
+				Redis:    config.RateLimit.Redis,
+			})
+			if err != nil {
+				err = fmt.Errorf("rate limiter error: %w", err)
+				errhandle.Handle(ctx, nil, err, true)
+			}
+			ratelimit.Limiter = limiter
+			defer limiter.Close()
 
-			// Set the logger.
-			logger.Logger = logWriter
-
-			// Initialize database.
-			if database.DB, err = database.Connect(logger.Logger, config.Database); err != nil {
-				err = fmt.Errorf("database new error: %w", err)
+			// Deprecate the versions and gate the outdated apps.
+			versioner, err := apiversion.New(apiversion.Config{
+				Deprecations:  config.API.VersionDeprecations,
+				MinAppVersion: config.API.MinAppVersion,
+			})
+			if err != nil {
+				err = fmt.Errorf("versioning error: %w", err)
 				errhandle.Handle(ctx, nil, err, true)
 			}
+			apiversion.Versioner = versioner
 
-			// Run migrations if the automigrate option is set.
-			if config.Database.Migrations.Auto {
-				if err := database.DB.Up(ctx); err != nil {
-					err = fmt.Errorf("migrations auto error: db up error: %w", err)
-					errhandle.Handle(ctx, nil, err, true)
-				}
+			// Limit the depth and the complexity of the graphql queries.
+			executor, err := graphql.New(graphql.Config{
+				MaxDepth:      config.GraphQL.MaxDepth,
+				MaxComplexity: config.GraphQL.MaxComplexity,
+			})
+			if err != nil {
+				err = fmt.Errorf("graphql error: %w", err)
+				errhandle.Handle(ctx, nil, err, true)
 			}
+			graphql.Default = executor
 
 			// Create the handler.
 			handler := handler.New(handler.Config{
-				SHASUM: SHASUM,
+				SHASUM:             SHASUM,
+				ProblemTypeBaseURL: config.Problem.TypeBaseURL,
+				OpenAPIDocs:        config.OpenAPI.Docs,
 			})
 
 			// Create the fiber app.
@@ -318,15 +427,274 @@ func main() {
 			middleware.Setup(app, handler)
 			router.Setup(app, handler)
 
+			// Serve the grpc server alongside the fiber app
+			// if its address is set, and stop it on exit.
+			if config.Server.GRPCAddr != "" {
+				listener, err := net.Listen("tcp", config.Server.GRPCAddr)
+				if err != nil {
+					err = fmt.Errorf("grpc server listen error: %w", err)
+					errhandle.Handle(ctx, nil, err, true)
+				}
+
+				grpcServer := grpc.New()
+				go func() {
+					if err := grpcServer.Serve(listener); err != nil {
+						err = fmt.Errorf("grpc server start error: %w", err)
+						errhandle.Handle(ctx, nil, err, true)
+					}
+				}()
+				defer grpcServer.GracefulStop()
+			}
+
 			// Listen.
-			if err := app.Listen(config.Server.Addr); err != nil {
-				err = fmt.Errorf("server start error: %w", err)
-				errhandle.Handle(ctx, nil, err, true)
+			go func() {
+				if err := app.Listen(config.Server.Addr); err != nil {
+					err = fmt.Errorf("server start error: %w", err)
+					errhandle.Handle(ctx, nil, err, true)
+				}
+			}()
+
+			// Wait for a signal and let the requests in flight finish,
+			// then the deferred closes run and the logs are flushed.
+			signalCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			<-signalCtx.Done()
+
+			// The streams are ended first, the server waits for them.
+			if err := broker.Close(); err != nil {
+				err = fmt.Errorf("realtime broker close error: %w", err)
+				errhandle.Handle(ctx, nil, err, false)
+			}
+
+			if err := app.Shutdown(); err != nil {
+				err = fmt.Errorf("server shutdown error: %w", err)
+				errhandle.Handle(ctx, nil, err, false)
+			}
+		},
+	})
+
+	// Worker commands.
+	// Run the background jobs and the scheduled tasks until interrupted.
+	cmdRoot.AddCommand(&cobra.Command{
+		Use:   "worker",
+		Short: "start the job worker and the task scheduler",
+		Args:  cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, args []string) {
+			// Set up and flush the logger and reporter on exit.
+			cleanup := setup(ctx)
+			defer cleanup()
+
+			// Register the job handlers and start the worker.
+			jobs.Register(queue.Default)
+			worker := queue.New(queue.Config{
+				Concurrency:    config.Queue.Concurrency,
+				PollSec:        config.Queue.PollSec,
+				MaxAttempts:    config.Queue.MaxAttempts,
+				BackoffBaseSec: config.Queue.BackoffBaseSec,
+				BackoffMaxSec:  config.Queue.BackoffMaxSec,
+				TimeoutSec:     config.Queue.TimeoutSec,
+			}, queue.Default)
+			worker.Start()
+
+			// Start the scheduler, the workers elect
+			// the one that runs the tasks.
+			taskScheduler := newScheduler(ctx)
+			taskScheduler.Start()
+
+			// Wait for a signal and let the jobs in flight finish.
+			signalCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			<-signalCtx.Done()
+
+			if err := taskScheduler.Close(); err != nil {
+				err = fmt.Errorf("scheduler close error: %w", err)
+				errhandle.Handle(ctx, nil, err, false)
+			}
+
+			if err := worker.Close(); err != nil {
+				err = fmt.Errorf("worker close error: %w", err)
+				errhandle.Handle(ctx, nil, err, false)
 			}
-			defer app.Shutdown()
 		},
 	})
 
+	// Task commands.
+	// List the scheduled tasks or run one on demand.
+	{
+		cmdTasks := &cobra.Command{
+			Use:   "tasks",
+			Short: "Scheduled tasks",
+			Args:  cobra.MinimumNArgs(1),
+		}
+		cmdRoot.AddCommand(cmdTasks)
+
+		// tasks list command.
+		cmdTasks.AddCommand(&cobra.Command{
+			Use:   "list",
+			Short: "tasks list",
+			Args:  cobra.ExactArgs(0),
+			Run: func(cmd *cobra.Command, args []string) {
+				cleanup := setup(ctx)
+				defer cleanup()
+
+				for _, task := range newScheduler(ctx).Tasks() {
+					fmt.Printf("%-24s %-16s next %s  %s\n",
+						task.Name, task.Schedule, task.Next.Format(time.RFC3339), task.Description)
+				}
+			},
+		})
+
+		// tasks run command.
+		cmdTasks.AddCommand(&cobra.Command{
+			Use:   "run",
+			Short: "tasks run [name]",
+			Args:  cobra.ExactArgs(1),
+			Run: func(cmd *cobra.Command, args []string) {
+				cleanup := setup(ctx)
+				defer cleanup()
+
+				if err := newScheduler(ctx).Run(ctx, args[0]); err != nil {
+					err = fmt.Errorf("tasks run error: %w", err)
+					errhandle.Handle(ctx, nil, err, true)
+				}
+				fmt.Printf("task %s done\n", args[0])
+			},
+		})
+	}
+
+	// Check the message catalogs of the api errors.
+	{
+		cmdI18n := &cobra.Command{
+			Use:   "i18n",
+			Short: "Message catalogs",
+			Args:  cobra.MinimumNArgs(1),
+		}
+		cmdRoot.AddCommand(cmdI18n)
+
+		// i18n check command, the errors of the api
+		// are registered when the packages are imported.
+		cmdI18n.AddCommand(&cobra.Command{
+			Use:   "check",
+			Short: "i18n check",
+			Args:  cobra.ExactArgs(0),
+			Run: func(cmd *cobra.Command, args []string) {
+				missing := i18n.Messages.Missing(errapi.Keys())
+				for _, tag := range i18n.Messages.Languages() {
+					for _, key := range missing[tag] {
+						fmt.Printf("%-8s %s\n", tag, key)
+					}
+				}
+
+				if len(missing) > 0 {
+					os.Exit(1)
+				}
+				fmt.Println("no missing translations")
+			},
+		})
+	}
+
 	// Execute the root command.
 	cmdRoot.Execute()
 }
+
+// setup loads the config and sets up the logger, slack, the error
+// reporter and the database of the long running commands. The
+// returned function flushes the reporter and the logger.
+func setup(ctx context.Context) func() {
+	// Load env variables.
+	env.Init()
+
+	// Initialize aws client.
+	if err := aws.Init(); err != nil {
+		err = fmt.Errorf("aws init error: %w", err)
+		errhandle.Handle(ctx, nil, err, true)
+	}
+
+	// Load config.
+	config.Load()
+
+	// Set up logger.
+	logWriter, err := logger.New(logger.Config{
+		Level:  config.Log.Level,
+		Mode:   config.Log.Mode,
+		SHASUM: SHASUM,
+		Sinks:  config.Log.Sinks,
+
+		RouteLevels: config.Log.RouteLevels,
+		Sampling:    config.Log.Sampling,
+		Body:        config.Log.Body,
+		Redact:      config.Log.Redact,
+
+		Syslog: config.Log.Syslog,
+		File:   config.Log.File,
+		AWS:    config.Log.AWS,
+	})
+	if err != nil {
+		err = fmt.Errorf("logger new error: %w", err)
+		errhandle.Handle(ctx, nil, err, true)
+	}
+
+	// Set the logger.
+	logger.Logger = logWriter
+
+	// Set up slack.
+	if err := slack.Init(slack.Config{
+		Token:     config.Slack.Token,
+		APIURL:    config.Slack.APIURL,
+		Channels:  config.Slack.Channels,
+		Mentions:  config.Slack.Mentions,
+		ThreadTTL: duration.Hours(config.Slack.ThreadTTLHours),
+		Webhook:   config.Slack.Webhook,
+	}); err != nil {
+		err = fmt.Errorf("slack init error: %w", err)
+		errhandle.Handle(ctx, nil, err, true)
+	}
+
+	// Set up error reporter.
+	errReporter, err := reporter.New(reporter.Config{
+		Sinks:      config.Report.Sinks,
+		QueueSize:  config.Report.QueueSize,
+		WindowSec:  config.Report.WindowSec,
+		RatePerMin: config.Report.RatePerMin,
+		Burst:      config.Report.Burst,
+		SHASUM:     SHASUM,
+		Sentry:     config.Report.Sentry,
+		Webhook:    config.Report.Webhook,
+	})
+	if err != nil {
+		err = fmt.Errorf("reporter new error: %w", err)
+		errhandle.Handle(ctx, nil, err, true)
+	}
+
+	// Set the reporter.
+	reporter.Reporter = errReporter
+
+	// Initialize database.
+	if database.DB, err = database.Connect(logger.Logger, config.Database); err != nil {
+		err = fmt.Errorf("database new error: %w", err)
+		errhandle.Handle(ctx, nil, err, true)
+	}
+
+	return func() {
+		reporter.Reporter.Close()
+		logger.Logger.Close()
+	}
+}
+
+// newScheduler creates the scheduler with the tasks registered.
+func newScheduler(ctx context.Context) *scheduler.Scheduler {
+	taskScheduler := scheduler.New(scheduler.Config{
+		ElectSec: config.Scheduler.ElectSec,
+	}, scheduler.NewAdvisoryLocker(database.DB.SQL, "scheduler"))
+
+	if err := tasks.Register(taskScheduler, tasks.Config{
+		SessionPrune:        config.Scheduler.SessionPrune,
+		UserUnverifiedPrune: config.Scheduler.UserUnverifiedPrune,
+		IdempotencyKeyPrune: config.Scheduler.IdempotencyKeyPrune,
+	}); err != nil {
+		err = fmt.Errorf("tasks register error: %w", err)
+		errhandle.Handle(ctx, nil, err, true)
+	}
+
+	return taskScheduler
+}
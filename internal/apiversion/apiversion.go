@@ -0,0 +1,223 @@
+package apiversion
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	V1 = "1"
+
+	// Default is the version of the requests without
+	// a version in the path or the version header.
+	Default = V1
+)
+
+var (
+	// Versions are the versions of the api served by the router,
+	// a version is added here when its routes are added.
+	Versions = map[string]bool{
+		V1: true,
+	}
+
+	// Versioner deprecates the versions and gates the app versions,
+	// the versions are not deprecated and the app versions are not
+	// gated when it is not set.
+	Versioner *Versioning
+)
+
+type Config struct {
+	Deprecations  string
+	MinAppVersion string
+}
+
+// Deprecation of a version, the requests of the version
+// are refused once the sunset passes if it is set.
+type Deprecation struct {
+	Version string
+	At      time.Time
+	Sunset  time.Time
+}
+
+type Versioning struct {
+	deprecations  map[string]Deprecation
+	minAppVersion AppVersion
+	now           func() time.Time
+}
+
+func New(config Config) (*Versioning, error) {
+	deprecations, err := ParseDeprecations(config.Deprecations)
+	if err != nil {
+		err = fmt.Errorf("versioning new error: %w", err)
+		return nil, err
+	}
+
+	v := &Versioning{
+		deprecations: make(map[string]Deprecation, len(deprecations)),
+		now:          time.Now,
+	}
+	for _, deprecation := range deprecations {
+		v.deprecations[deprecation.Version] = deprecation
+	}
+
+	if config.MinAppVersion != "" {
+		if v.minAppVersion, err = ParseAppVersion(config.MinAppVersion); err != nil {
+			err = fmt.Errorf("versioning new error: min app version error: %w", err)
+			return nil, err
+		}
+	}
+
+	return v, nil
+}
+
+// Deprecation returns the deprecation of the version.
+func (v *Versioning) Deprecation(version string) (Deprecation, bool) {
+	deprecation, ok := v.deprecations[version]
+	return deprecation, ok
+}
+
+// Sunset returns true if the sunset of the version passed.
+func (v *Versioning) Sunset(version string) bool {
+	deprecation, ok := v.deprecations[version]
+	return ok && !deprecation.Sunset.IsZero() && !v.now().Before(deprecation.Sunset)
+}
+
+// MinAppVersion returns the minimum version of the app, the
+// app versions are not gated if it is not set.
+func (v *Versioning) MinAppVersion() (AppVersion, bool) {
+	return v.minAppVersion, v.minAppVersion != nil
+}
+
+// AppVersionAllowed returns true if the app version is
+// not older than the minimum version of the app.
+func (v *Versioning) AppVersionAllowed(appVersion AppVersion) bool {
+	return v.minAppVersion == nil || appVersion.Compare(v.minAppVersion) >= 0
+}
+
+// Parse returns the version of the version header,
+// the version is either the number or prefixed with a
+// "v", i.e. "1" and "v1".
+func Parse(version string) string {
+	version = strings.ToLower(strings.TrimSpace(version))
+	return strings.TrimPrefix(version, "v")
+}
+
+// FromPath returns the version of the path and the path
+// without the version, i.e. "1" and "/users" of "/v1/users".
+func FromPath(path string) (string, string, bool) {
+	segment, rest, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if len(segment) < 2 || segment[0] != 'v' {
+		return "", path, false
+	}
+	if _, err := strconv.Atoi(segment[1:]); err != nil {
+		return "", path, false
+	}
+	return segment[1:], "/" + rest, true
+}
+
+// ParseDeprecations parses a comma separated list of deprecations in
+// the form of VERSION:DEPRECATED_AT[:SUNSET_AT] with the dates in the
+// form of YYYY-MM-DD, i.e. "1:2027-01-01:2027-07-01".
+func ParseDeprecations(deprecations string) ([]Deprecation, error) {
+	var parsed []Deprecation
+
+	for _, spec := range strings.Split(deprecations, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		pieces := strings.Split(spec, ":")
+		if len(pieces) != 2 && len(pieces) != 3 {
+			err := fmt.Errorf("parse deprecations error: invalid deprecation: %s", spec)
+			return nil, err
+		}
+
+		version := Parse(pieces[0])
+		if !Versions[version] {
+			err := fmt.Errorf("parse deprecations error: unknown version: %s", pieces[0])
+			return nil, err
+		}
+
+		at, err := time.Parse(time.DateOnly, strings.TrimSpace(pieces[1]))
+		if err != nil {
+			err := fmt.Errorf("parse deprecations error: invalid deprecation date: %s", pieces[1])
+			return nil, err
+		}
+
+		deprecation := Deprecation{Version: version, At: at}
+		if len(pieces) == 3 {
+			if deprecation.Sunset, err = time.Parse(time.DateOnly, strings.TrimSpace(pieces[2])); err != nil {
+				err := fmt.Errorf("parse deprecations error: invalid sunset date: %s", pieces[2])
+				return nil, err
+			}
+			if deprecation.Sunset.Before(at) {
+				err := fmt.Errorf("parse deprecations error: sunset is before the deprecation: %s", spec)
+				return nil, err
+			}
+		}
+
+		parsed = append(parsed, deprecation)
+	}
+
+	return parsed, nil
+}
+
+// AppVersion is the numbers of the version of the app,
+// i.e. [2 4 1] of "2.4.1".
+type AppVersion []int
+
+// ParseAppVersion parses the version of the app, the "v" prefix
+// and the pre-release and the build suffixes are ignored, i.e.
+// "v2.4.1-beta+7" is parsed as "2.4.1".
+func ParseAppVersion(appVersion string) (AppVersion, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(appVersion), "v")
+	if i := strings.IndexAny(s, "-+"); i >= 0 {
+		s = s[:i]
+	}
+
+	pieces := strings.Split(s, ".")
+	parsed := make(AppVersion, 0, len(pieces))
+	for _, piece := range pieces {
+		n, err := strconv.Atoi(piece)
+		if err != nil || n < 0 {
+			err := fmt.Errorf("parse app version error: invalid app version: %s", appVersion)
+			return nil, err
+		}
+		parsed = append(parsed, n)
+	}
+
+	return parsed, nil
+}
+
+// Compare returns -1, 0 or 1 if the version is older than, the same
+// as or newer than the other, the missing numbers are zero.
+func (a AppVersion) Compare(b AppVersion) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+func (a AppVersion) String() string {
+	pieces := make([]string, len(a))
+	for i, n := range a {
+		pieces[i] = strconv.Itoa(n)
+	}
+	return strings.Join(pieces, ".")
+}
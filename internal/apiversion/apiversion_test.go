@@ -0,0 +1,136 @@
+package apiversion
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFromPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		version string
+		rest    string
+		ok      bool
+	}{
+		{"/v1/users", "1", "/users", true},
+		{"/v12/admin/log", "12", "/admin/log", true},
+		{"/v1", "1", "/", true},
+		{"/users", "", "/users", false},
+		{"/health", "", "/health", false},
+		{"/vx/users", "", "/vx/users", false},
+		{"/v/users", "", "/v/users", false},
+	}
+
+	for _, test := range tests {
+		version, rest, ok := FromPath(test.path)
+		if version != test.version || rest != test.rest || ok != test.ok {
+			t.Fatalf("want: %s = %q, %q, %t; got: %q, %q, %t", test.path, test.version, test.rest, test.ok, version, rest, ok)
+		}
+	}
+}
+
+func TestParseDeprecations(t *testing.T) {
+	deprecations, err := ParseDeprecations(" v1:2027-01-01:2027-07-01 ")
+	if err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+	if len(deprecations) != 1 {
+		t.Fatalf("want: 1 deprecation; got: %d", len(deprecations))
+	}
+
+	d := deprecations[0]
+	if d.Version != V1 || !d.At.Equal(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)) || !d.Sunset.Equal(time.Date(2027, 7, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("want: deprecation = 1:2027-01-01:2027-07-01; got: deprecation = %+v", d)
+	}
+
+	for _, invalid := range []string{
+		"1",
+		"9:2027-01-01",
+		"1:01-01-2027",
+		"1:2027-01-01:x",
+		"1:2027-07-01:2027-01-01",
+		"1:2027-01-01:2027-07-01:2028-01-01",
+	} {
+		if _, err := ParseDeprecations(invalid); err == nil {
+			t.Fatalf("want: err for %s; got: err = nil", invalid)
+		}
+	}
+}
+
+func TestSunset(t *testing.T) {
+	v, err := New(Config{Deprecations: "1:2027-01-01:2027-07-01"})
+	if err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+
+	v.now = func() time.Time { return time.Date(2027, 6, 30, 23, 59, 59, 0, time.UTC) }
+	if v.Sunset(V1) {
+		t.Fatalf("want: sunset = false before the sunset; got: true")
+	}
+
+	v.now = func() time.Time { return time.Date(2027, 7, 1, 0, 0, 0, 0, time.UTC) }
+	if !v.Sunset(V1) {
+		t.Fatalf("want: sunset = true at the sunset; got: false")
+	}
+
+	// Test the deprecations without a sunset are never sunset.
+	v, _ = New(Config{Deprecations: "1:2027-01-01"})
+	v.now = func() time.Time { return time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC) }
+	if _, ok := v.Deprecation(V1); !ok || v.Sunset(V1) {
+		t.Fatalf("want: deprecated, not sunset; got: deprecated = %t, sunset = %t", ok, v.Sunset(V1))
+	}
+}
+
+func TestAppVersion(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{"2.4.1", "2.4.1", 0},
+		{"v2.4.1-beta+7", "2.4.1", 0},
+		{"2.4", "2.4.0", 0},
+		{"2.4.1", "2.10.0", -1},
+		{"3", "2.99.99", 1},
+	}
+
+	for _, test := range tests {
+		a, err := ParseAppVersion(test.a)
+		if err != nil {
+			t.Fatalf("want: parse %s err = nil; got: err = %v", test.a, err)
+		}
+		b, err := ParseAppVersion(test.b)
+		if err != nil {
+			t.Fatalf("want: parse %s err = nil; got: err = %v", test.b, err)
+		}
+		if got := a.Compare(b); got != test.want {
+			t.Fatalf("want: compare %s %s = %d; got: %d", test.a, test.b, test.want, got)
+		}
+	}
+
+	for _, invalid := range []string{"", "x", "2..1", "2.-1", "2.4.x"} {
+		if _, err := ParseAppVersion(invalid); err == nil {
+			t.Fatalf("want: err for %q; got: err = nil", invalid)
+		}
+	}
+
+	v, err := New(Config{MinAppVersion: "2.4.0"})
+	if err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+	if min, ok := v.MinAppVersion(); !ok || min.String() != "2.4.0" {
+		t.Fatalf("want: min app version = 2.4.0; got: %s", min)
+	}
+	old, _ := ParseAppVersion("2.3.9")
+	if v.AppVersionAllowed(old) {
+		t.Fatalf("want: app version 2.3.9 not allowed; got: allowed")
+	}
+	current, _ := ParseAppVersion("2.4.0")
+	if !v.AppVersionAllowed(current) {
+		t.Fatalf("want: app version 2.4.0 allowed; got: not allowed")
+	}
+
+	if _, err := New(Config{MinAppVersion: "latest"}); err == nil {
+		t.Fatalf("want: err for min app version latest; got: err = nil")
+	}
+}
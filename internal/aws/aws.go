@@ -17,7 +17,7 @@ type client struct {
 
 	ParamGet  func(ctx context.Ctx, key string) (string, error)
 	SecretGet func(ctx context.Ctx, key string) (string, error)
-	LogStream func(ctx context.Ctx, groupName, streamUnique string) (*Stream, error)
+	LogStream func(ctx context.Ctx, config StreamConfig) (*Stream, error)
 }
 
 var (
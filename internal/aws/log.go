@@ -1,9 +1,13 @@
 package aws
 
 import (
+	goctx "context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	awsgo "github.com/aws/aws-sdk-go-v2/aws"
@@ -13,52 +17,353 @@ import (
 	"github.com/koraygocmen/golang-boilerplate/internal/context"
 )
 
+// PutLogEvents limits, see:
+// https://docs.aws.amazon.com/AmazonCloudWatchLogs/latest/APIReference/API_PutLogEvents.html
+const (
+	logBatchMaxEvents   = 10000
+	logBatchMaxBytes    = 1048576
+	logBatchMaxSpan     = 24 * time.Hour
+	logEventOverhead    = 26
+	logEventMaxBytes    = 262144 - logEventOverhead
+	logEventMaxAge      = 14 * 24 * time.Hour
+	logEventMaxFuture   = 2 * time.Hour
+	logRetryMaxAttempts = 5
+	logRetryBackoffMin  = 200 * time.Millisecond
+	logRetryBackoffMax  = 10 * time.Second
+
+	LogStreamBufferSizeDefault    = 100000
+	LogStreamFlushIntervalDefault = 5 * time.Second
+)
+
+var (
+	ErrLogStreamBufferFull = errors.New("log stream buffer is full")
+	ErrLogStreamClosed     = errors.New("log stream is closed")
+)
+
 type Message struct {
 	Timestamp time.Time
 	Content   []byte
 }
 
+type StreamConfig struct {
+	GroupName        string
+	StreamNameSuffix string
+
+	// BufferSize is the max number of messages waiting to be shipped.
+	// Messages written to a full buffer are dropped and counted.
+	BufferSize int
+
+	// FlushInterval is how often the buffered messages are shipped.
+	FlushInterval time.Duration
+}
+
+// putLogEventsAPI is the part of the cloudwatch logs
+// client the stream needs to ship the messages.
+type putLogEventsAPI interface {
+	PutLogEvents(ctx goctx.Context, params *cloudwatchlogs.PutLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error)
+}
+
+// Stream buffers the messages and ships them to cloudwatch in batches
+// sized to the PutLogEvents limits. Failed batches are retried with
+// exponential backoff and put back to the buffer if they still fail.
+// The buffer is bounded, messages that do not fit are dropped.
 type Stream struct {
 	GroupName  string
 	StreamName string
-	Stop       chan bool
 
-	cloudwatchLogsClient *cloudwatchlogs.Client
-	queue                []Message
-	lock                 *sync.RWMutex
+	client        putLogEventsAPI
+	bufferSize    int
+	flushInterval time.Duration
+
+	buffer  []Message
+	dropped uint64
+	closed  bool
+	lock    *sync.Mutex
+
+	flush chan struct{}
+	stop  chan struct{}
+	done  chan struct{}
 }
 
 func (s *Stream) Write(content []byte) (int, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	s.queue = append(s.queue, Message{
+	if s.closed {
+		atomic.AddUint64(&s.dropped, 1)
+		return 0, ErrLogStreamClosed
+	}
+
+	if len(s.buffer) >= s.bufferSize {
+		atomic.AddUint64(&s.dropped, 1)
+		return 0, ErrLogStreamBufferFull
+	}
+
+	// The caller may reuse the content after write returns.
+	message := Message{
 		Timestamp: time.Now().UTC(),
-		Content:   content,
+		Content:   append([]byte{}, content...),
+	}
+	s.buffer = append(s.buffer, message)
+
+	// Ship right away if there is a full batch waiting.
+	if len(s.buffer) >= logBatchMaxEvents {
+		select {
+		case s.flush <- struct{}{}:
+		default:
+		}
+	}
+
+	return len(content), nil
+}
+
+// Dropped returns the number of messages that
+// were dropped since the stream was created.
+func (s *Stream) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Close stops accepting messages, ships the buffered
+// ones and waits for the shipping to finish.
+func (s *Stream) Close() error {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.lock.Unlock()
+
+	close(s.stop)
+	<-s.done
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.buffer) > 0 {
+		atomic.AddUint64(&s.dropped, uint64(len(s.buffer)))
+		err := fmt.Errorf("log stream close error: %d messages not shipped", len(s.buffer))
+		s.buffer = nil
+		return err
+	}
+
+	return nil
+}
+
+func (s *Stream) run(ctx context.Ctx) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			s.ship(ctx)
+			return
+		case <-ticker.C:
+			s.ship(ctx)
+		case <-s.flush:
+			s.ship(ctx)
+		}
+	}
+}
+
+// ship sends the buffered messages in batches. Messages of the
+// batches that could not be sent are put back to the buffer.
+func (s *Stream) ship(ctx context.Ctx) {
+	s.lock.Lock()
+	messages := s.buffer
+	s.buffer = nil
+	s.lock.Unlock()
+
+	if len(messages) == 0 {
+		return
+	}
+
+	// Events in a batch must be in chronological order.
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].Timestamp.Before(messages[j].Timestamp)
 	})
-	return len(s.queue), nil
+
+	messages = s.filter(messages)
+
+	for len(messages) > 0 {
+		n := batchSize(messages)
+		if err := s.put(ctx, messages[:n]); err != nil {
+			s.requeue(messages)
+			return
+		}
+		messages = messages[n:]
+	}
 }
 
-func logStream(cloudwatchLogsClient *cloudwatchlogs.Client) func(ctx context.Ctx, groupName, streamNameSuffix string) (*Stream, error) {
-	return func(ctx context.Ctx, groupName, streamNameSuffix string) (*Stream, error) {
+// filter drops the messages cloudwatch would reject for
+// their timestamps and truncates the oversized ones.
+func (s *Stream) filter(messages []Message) []Message {
+	var (
+		now      = time.Now().UTC()
+		filtered = messages[:0]
+	)
+
+	for _, message := range messages {
+		if message.Timestamp.Before(now.Add(-logEventMaxAge)) || message.Timestamp.After(now.Add(logEventMaxFuture)) {
+			atomic.AddUint64(&s.dropped, 1)
+			continue
+		}
+
+		if len(message.Content) > logEventMaxBytes {
+			message.Content = message.Content[:logEventMaxBytes]
+		}
+
+		filtered = append(filtered, message)
+	}
+
+	return filtered
+}
+
+// put sends a single batch, retrying with exponential backoff.
+// Batches cloudwatch refuses as invalid are dropped since
+// retrying them would never succeed.
+func (s *Stream) put(ctx context.Ctx, messages []Message) error {
+	events := make([]cwltypes.InputLogEvent, len(messages))
+	for i, message := range messages {
+		events[i] = cwltypes.InputLogEvent{
+			Message:   awsgo.String(string(message.Content)),
+			Timestamp: awsgo.Int64(message.Timestamp.UnixMilli()),
+		}
+	}
+
+	var (
+		backoff = logRetryBackoffMin
+		err     error
+	)
+
+	for attempt := 1; attempt <= logRetryMaxAttempts; attempt++ {
+		var out *cloudwatchlogs.PutLogEventsOutput
+		out, err = s.client.PutLogEvents(ctx, &cloudwatchlogs.PutLogEventsInput{
+			LogEvents:     events,
+			LogGroupName:  &s.GroupName,
+			LogStreamName: &s.StreamName,
+		})
+		if err == nil {
+			s.countRejected(out, len(events))
+			return nil
+		}
+
+		var ipe *cwltypes.InvalidParameterException
+		if errors.As(err, &ipe) {
+			atomic.AddUint64(&s.dropped, uint64(len(events)))
+			return nil
+		}
+
+		// Do not wait for the backoff when stopping, the
+		// remaining attempts are still made right away.
+		select {
+		case <-s.stop:
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > logRetryBackoffMax {
+			backoff = logRetryBackoffMax
+		}
+	}
+
+	err = fmt.Errorf("log stream put error: %w", err)
+	return err
+}
+
+func (s *Stream) countRejected(out *cloudwatchlogs.PutLogEventsOutput, total int) {
+	if out == nil || out.RejectedLogEventsInfo == nil {
+		return
+	}
+
+	var (
+		info     = out.RejectedLogEventsInfo
+		rejected int
+	)
+
+	if info.TooOldLogEventEndIndex != nil {
+		rejected = int(*info.TooOldLogEventEndIndex)
+	}
+
+	if info.ExpiredLogEventEndIndex != nil && int(*info.ExpiredLogEventEndIndex) > rejected {
+		rejected = int(*info.ExpiredLogEventEndIndex)
+	}
+
+	if info.TooNewLogEventStartIndex != nil {
+		rejected += total - int(*info.TooNewLogEventStartIndex)
+	}
+
+	atomic.AddUint64(&s.dropped, uint64(rejected))
+}
+
+// requeue puts the messages back in front of the buffer
+// and drops the oldest ones that do not fit.
+func (s *Stream) requeue(messages []Message) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	buffer := make([]Message, 0, len(messages)+len(s.buffer))
+	buffer = append(buffer, messages...)
+	buffer = append(buffer, s.buffer...)
+	if overflow := len(buffer) - s.bufferSize; overflow > 0 {
+		atomic.AddUint64(&s.dropped, uint64(overflow))
+		buffer = buffer[overflow:]
+	}
+
+	s.buffer = buffer
+}
+
+// batchSize returns the number of messages from the start that
+// fit into a single batch. Messages must be sorted by timestamp.
+func batchSize(messages []Message) int {
+	var size int
+
+	for i, message := range messages {
+		size += len(message.Content) + logEventOverhead
+
+		if i >= logBatchMaxEvents || size > logBatchMaxBytes ||
+			message.Timestamp.Sub(messages[0].Timestamp) > logBatchMaxSpan {
+			return i
+		}
+	}
+
+	return len(messages)
+}
+
+func logStream(cloudwatchLogsClient *cloudwatchlogs.Client) func(ctx context.Ctx, config StreamConfig) (*Stream, error) {
+	return func(ctx context.Ctx, config StreamConfig) (*Stream, error) {
 		if cloudwatchLogsClient == nil {
 			err := fmt.Errorf("log stream error: cloudwatch logs client is nil, maybe aws is not initialized?")
 			return nil, err
 		}
 
-		if groupName == "" {
+		if config.GroupName == "" {
 			err := fmt.Errorf("log stream error: group name is empty")
 			return nil, err
 		}
 
+		if config.BufferSize <= 0 {
+			config.BufferSize = LogStreamBufferSizeDefault
+		}
+
+		if config.FlushInterval <= 0 {
+			config.FlushInterval = LogStreamFlushIntervalDefault
+		}
+
 		stream := &Stream{
-			GroupName:  groupName,
-			StreamName: streamName(streamNameSuffix),
-			Stop:       make(chan bool, 1),
+			GroupName:  config.GroupName,
+			StreamName: streamName(config.StreamNameSuffix),
+
+			client:        cloudwatchLogsClient,
+			bufferSize:    config.BufferSize,
+			flushInterval: config.FlushInterval,
+			lock:          &sync.Mutex{},
 
-			cloudwatchLogsClient: cloudwatchLogsClient,
-			queue:                []Message{},
-			lock:                 &sync.RWMutex{},
+			flush: make(chan struct{}, 1),
+			stop:  make(chan struct{}),
+			done:  make(chan struct{}),
 		}
 
 		logStreamsOut, err := cloudwatchLogsClient.DescribeLogStreams(ctx, &cloudwatchlogs.DescribeLogStreamsInput{
@@ -80,52 +385,7 @@ func logStream(cloudwatchLogsClient *cloudwatchlogs.Client) func(ctx context.Ctx
 			}
 		}
 
-		go func() {
-			queue := []cwltypes.InputLogEvent{}
-
-			for {
-				select {
-				case <-stream.Stop:
-					return
-				default:
-					{
-						// Check if the stream queue has any messages.
-						// if so, add them to the processing queue.
-						stream.lock.RLock()
-						if len(stream.queue) > 0 {
-							for _, message := range stream.queue {
-								messageContent := string(message.Content)
-								queue = append(queue, cwltypes.InputLogEvent{
-									Message:   &messageContent,
-									Timestamp: awsgo.Int64(message.Timestamp.UnixNano() / int64(time.Millisecond)),
-								})
-							}
-
-							// Dump the queue.
-							stream.queue = []Message{}
-						}
-						stream.lock.RUnlock()
-
-						// Process the queue.
-						if len(queue) > 0 {
-							_, err := stream.cloudwatchLogsClient.PutLogEvents(ctx, &cloudwatchlogs.PutLogEventsInput{
-								LogEvents:     queue,
-								LogGroupName:  &stream.GroupName,
-								LogStreamName: &stream.StreamName,
-							})
-							if err != nil {
-								continue
-							}
-
-							// Dump the process queue.
-							queue = []cwltypes.InputLogEvent{}
-						}
-					}
-				}
-
-				time.Sleep(5 * time.Second)
-			}
-		}()
+		go stream.run(context.Background())
 
 		return stream, nil
 	}
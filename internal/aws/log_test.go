@@ -0,0 +1,210 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	awsgo "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+)
+
+// cloudwatchTest is a fake cloudwatch logs endpoint
+// speaking the aws json 1.1 protocol.
+type cloudwatchTest struct {
+	lock     sync.Mutex
+	batches  [][]int64
+	messages []string
+	failures int
+}
+
+func (ct *cloudwatchTest) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ct.lock.Lock()
+	defer ct.lock.Unlock()
+
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+
+	switch target := r.Header.Get("X-Amz-Target"); {
+	case strings.HasSuffix(target, ".DescribeLogStreams"):
+		fmt.Fprint(w, `{"logStreams":[]}`)
+	case strings.HasSuffix(target, ".CreateLogStream"):
+		fmt.Fprint(w, `{}`)
+	case strings.HasSuffix(target, ".PutLogEvents"):
+		if ct.failures > 0 {
+			ct.failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"__type":"ServiceUnavailableException","message":"unavailable"}`)
+			return
+		}
+
+		var input struct {
+			LogEvents []struct {
+				Message   string `json:"message"`
+				Timestamp int64  `json:"timestamp"`
+			} `json:"logEvents"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var timestamps []int64
+		for _, event := range input.LogEvents {
+			timestamps = append(timestamps, event.Timestamp)
+			ct.messages = append(ct.messages, event.Message)
+		}
+		ct.batches = append(ct.batches, timestamps)
+
+		fmt.Fprint(w, `{"nextSequenceToken":"1"}`)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (ct *cloudwatchTest) get() ([][]int64, []string) {
+	ct.lock.Lock()
+	defer ct.lock.Unlock()
+	return ct.batches, ct.messages
+}
+
+func cloudwatchTestStream(t *testing.T, ct *cloudwatchTest, config StreamConfig) *Stream {
+	t.Helper()
+
+	server := httptest.NewServer(ct)
+	t.Cleanup(server.Close)
+
+	client := cloudwatchlogs.New(cloudwatchlogs.Options{
+		Region:           "us-east-1",
+		BaseEndpoint:     awsgo.String(server.URL),
+		Credentials:      awsgo.AnonymousCredentials{},
+		RetryMaxAttempts: 1,
+	})
+
+	config.GroupName = "group"
+	stream, err := logStream(client)(context.Background(), config)
+	if err != nil {
+		t.Fatalf("want: log stream err nil; got: %v", err)
+	}
+
+	return stream
+}
+
+func TestBatchSize(t *testing.T) {
+	now := time.Now().UTC()
+
+	// Max events.
+	messages := make([]Message, logBatchMaxEvents+5)
+	for i := range messages {
+		messages[i] = Message{Timestamp: now, Content: []byte("a")}
+	}
+	if got := batchSize(messages); got != logBatchMaxEvents {
+		t.Fatalf("want: batch size = %d; got: %d", logBatchMaxEvents, got)
+	}
+
+	// Max bytes.
+	content := make([]byte, logEventMaxBytes)
+	messages = []Message{
+		{Timestamp: now, Content: content},
+		{Timestamp: now, Content: content},
+		{Timestamp: now, Content: content},
+		{Timestamp: now, Content: content},
+		{Timestamp: now, Content: content},
+	}
+	if got := batchSize(messages); got != 4 {
+		t.Fatalf("want: batch size = 4; got: %d", got)
+	}
+
+	// Max span.
+	messages = []Message{
+		{Timestamp: now.Add(-25 * time.Hour), Content: []byte("a")},
+		{Timestamp: now, Content: []byte("b")},
+	}
+	if got := batchSize(messages); got != 1 {
+		t.Fatalf("want: batch size = 1; got: %d", got)
+	}
+}
+
+func TestStreamShip(t *testing.T) {
+	ct := &cloudwatchTest{}
+	stream := cloudwatchTestStream(t, ct, StreamConfig{FlushInterval: time.Hour})
+
+	stream.Write([]byte("second"))
+	stream.Write([]byte("third"))
+
+	// Older message written last must be sent first.
+	stream.lock.Lock()
+	stream.buffer = append(stream.buffer, Message{
+		Timestamp: time.Now().UTC().Add(-time.Minute),
+		Content:   []byte("first"),
+	})
+	stream.lock.Unlock()
+
+	if err := stream.Close(); err != nil {
+		t.Fatalf("want: close err nil; got: %v", err)
+	}
+
+	batches, messages := ct.get()
+	if len(batches) != 1 {
+		t.Fatalf("want: 1 batch; got: %d", len(batches))
+	}
+
+	if want := []string{"first", "second", "third"}; strings.Join(messages, ",") != strings.Join(want, ",") {
+		t.Fatalf("want: messages = %v; got: %v", want, messages)
+	}
+
+	for i := 1; i < len(batches[0]); i++ {
+		if batches[0][i] < batches[0][i-1] {
+			t.Fatalf("want: chronological batch; got: %v", batches[0])
+		}
+	}
+
+	// Writes after close are dropped.
+	if _, err := stream.Write([]byte("closed")); err != ErrLogStreamClosed {
+		t.Fatalf("want: write err = %v; got: %v", ErrLogStreamClosed, err)
+	}
+}
+
+func TestStreamRetry(t *testing.T) {
+	ct := &cloudwatchTest{failures: 2}
+	stream := cloudwatchTestStream(t, ct, StreamConfig{FlushInterval: time.Hour})
+
+	stream.Write([]byte("message"))
+
+	if err := stream.Close(); err != nil {
+		t.Fatalf("want: close err nil; got: %v", err)
+	}
+
+	if _, messages := ct.get(); len(messages) != 1 {
+		t.Fatalf("want: 1 message after retries; got: %v", messages)
+	}
+
+	if dropped := stream.Dropped(); dropped != 0 {
+		t.Fatalf("want: dropped = 0; got: %d", dropped)
+	}
+}
+
+func TestStreamBackpressure(t *testing.T) {
+	ct := &cloudwatchTest{failures: logRetryMaxAttempts * 10}
+	stream := cloudwatchTestStream(t, ct, StreamConfig{BufferSize: 2, FlushInterval: time.Hour})
+
+	stream.Write([]byte("1"))
+	stream.Write([]byte("2"))
+	if _, err := stream.Write([]byte("3")); err != ErrLogStreamBufferFull {
+		t.Fatalf("want: write err = %v; got: %v", ErrLogStreamBufferFull, err)
+	}
+
+	// Endpoint keeps failing, the buffered messages are lost on close.
+	if err := stream.Close(); err == nil {
+		t.Fatalf("want: close err not nil; got: nil")
+	}
+
+	if dropped := stream.Dropped(); dropped != 3 {
+		t.Fatalf("want: dropped = 3; got: %d", dropped)
+	}
+}
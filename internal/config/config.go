@@ -6,16 +6,32 @@ import (
 )
 
 var (
-	Server   = ServerConfig{}
-	Database = DatabaseConfig{}
-	AWS      = AwsConfig{}
-	Slack    = SlackConfig{}
-	Log      = LogConfig{}
+	Server      = ServerConfig{}
+	Database    = DatabaseConfig{}
+	AWS         = AwsConfig{}
+	Slack       = SlackConfig{}
+	Report      = ReportConfig{}
+	Log         = LogConfig{}
+	Admin       = AdminConfig{}
+	Webhook     = WebhookConfig{}
+	Outbox      = OutboxConfig{}
+	Queue       = QueueConfig{}
+	Scheduler   = SchedulerConfig{}
+	RateLimit   = RateLimitConfig{}
+	Idempotency = IdempotencyConfig{}
+	Problem     = ProblemConfig{}
+	OpenAPI     = OpenAPIConfig{}
+	API         = APIConfig{}
+	GraphQL     = GraphQLConfig{}
+	Realtime    = RealtimeConfig{}
 )
 
 type ServerConfig struct {
-	Addr    string
-	Timeout struct {
+	Addr string
+	// GRPCAddr is the address of the grpc server,
+	// the grpc server is not started if it is empty.
+	GRPCAddr string
+	Timeout  struct {
 		Read  int
 		Write int
 		Idle  int
@@ -52,15 +68,51 @@ type AwsConfig struct {
 }
 
 type SlackConfig struct {
+	Token          string
+	APIURL         string
+	Channels       string
+	Mentions       string
+	ThreadTTLHours int
+
 	Webhook struct {
 		Errors string
 		Events string
 	}
 }
 
+type ReportConfig struct {
+	Sinks      string
+	QueueSize  int
+	WindowSec  int
+	RatePerMin int
+	Burst      int
+
+	Sentry struct {
+		DSN string
+	}
+
+	Webhook struct {
+		URL    string
+		Secret string
+	}
+}
+
 type LogConfig struct {
-	Level int
-	Mode  string
+	Level       int
+	Mode        string
+	Sinks       string
+	RouteLevels string
+	Sampling    string
+
+	Body struct {
+		Capture  bool
+		MaxBytes int
+	}
+
+	Redact struct {
+		Fields string
+		Paths  string
+	}
 
 	Syslog struct {
 		Addr     string
@@ -69,7 +121,11 @@ type LogConfig struct {
 	}
 
 	File struct {
-		Path string
+		Path        string
+		MaxSizeMB   int
+		RotateHours int
+		MaxBackups  int
+		Compress    bool
 	}
 
 	AWS struct {
@@ -77,13 +133,107 @@ type LogConfig struct {
 			Name   string
 			Region string
 		}
+		BufferSize       int
+		FlushIntervalSec int
 	}
 }
 
+type AdminConfig struct {
+	// Token authorizes the admin endpoints,
+	// they are disabled when it is empty.
+	Token string
+}
+
+type WebhookConfig struct {
+	MaxAttempts    int
+	BackoffBaseSec int
+	BackoffMaxSec  int
+	PollSec        int
+	TimeoutSec     int
+	BatchSize      int
+}
+
+type OutboxConfig struct {
+	PollSec        int
+	BatchSize      int
+	BackoffBaseSec int
+	BackoffMaxSec  int
+}
+
+type QueueConfig struct {
+	Concurrency    int
+	PollSec        int
+	MaxAttempts    int
+	BackoffBaseSec int
+	BackoffMaxSec  int
+	TimeoutSec     int
+}
+
+type SchedulerConfig struct {
+	ElectSec int
+
+	SessionPrune struct {
+		Schedule       string
+		RetentionHours int
+	}
+
+	UserUnverifiedPrune struct {
+		Schedule string
+		AgeHours int
+	}
+
+	IdempotencyKeyPrune struct {
+		Schedule string
+	}
+}
+
+type RateLimitConfig struct {
+	Storage  string
+	Policies string
+
+	Redis struct {
+		Addr      string
+		Password  string
+		DB        int
+		TimeoutMs int
+	}
+}
+
+type IdempotencyConfig struct {
+	TTLHours int
+	Secret   string
+}
+
+type ProblemConfig struct {
+	TypeBaseURL string
+}
+
+type OpenAPIConfig struct {
+	Docs bool
+}
+
+type APIConfig struct {
+	VersionDeprecations string
+	MinAppVersion       string
+}
+
+type GraphQLConfig struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+type RealtimeConfig struct {
+	// PubSub fans the events out to the replicas, "memory"
+	// for a single replica or "postgres" to listen and notify.
+	PubSub       string
+	HeartbeatSec int
+}
+
 func Load() {
 	ctx := context.Background()
 
 	Server.Addr = GetStr(ctx, Param{Key: "SERVER_ADDR", Type: TypeParam, Panic: true})
+	Server.GRPCAddr = GetStr(ctx, Param{Key: "SERVER_GRPC_ADDR", Type: TypeParam, Panic: false})
 	Server.Timeout.Read = GetInt(ctx, Param{Key: "SERVER_TIMEOUT_READ", Type: TypeParam, Panic: true})
 	Server.Timeout.Write = GetInt(ctx, Param{Key: "SERVER_TIMEOUT_WRITE", Type: TypeParam, Panic: true})
 	Server.Timeout.Idle = GetInt(ctx, Param{Key: "SERVER_TIMEOUT_IDLE", Type: TypeParam, Panic: true})
@@ -107,15 +257,94 @@ func Load() {
 	AWS.S3.BucketDocuments = GetStr(ctx, Param{Key: "AWS_S3_BUCKET_DOCUMENTS", Type: TypeParam, Panic: true})
 	AWS.SES.Source = GetStr(ctx, Param{Key: "AWS_SES_SOURCE", Type: TypeParam, Panic: true})
 
-	Slack.Webhook.Errors = GetStr(ctx, Param{Key: "SLACK_WEBHOOK_ERRORS", Type: TypeSecret, Panic: env.IsProd()})
-	Slack.Webhook.Events = GetStr(ctx, Param{Key: "SLACK_WEBHOOK_EVENTS", Type: TypeSecret, Panic: env.IsProd()})
+	Slack.Token = GetStr(ctx, Param{Key: "SLACK_TOKEN", Type: TypeSecret, Panic: false})
+	Slack.APIURL = GetStr(ctx, Param{Key: "SLACK_API_URL", Type: TypeParam, Panic: false})
+	Slack.Channels = GetStr(ctx, Param{Key: "SLACK_CHANNELS", Type: TypeParam, Panic: false})
+	Slack.Mentions = GetStr(ctx, Param{Key: "SLACK_MENTIONS", Type: TypeParam, Panic: false})
+	Slack.ThreadTTLHours = GetInt(ctx, Param{Key: "SLACK_THREAD_TTL_HOURS", Type: TypeParam, Panic: false})
+	// Webhooks are only required without a bot token.
+	Slack.Webhook.Errors = GetStr(ctx, Param{Key: "SLACK_WEBHOOK_ERRORS", Type: TypeSecret, Panic: env.IsProd() && Slack.Token == ""})
+	Slack.Webhook.Events = GetStr(ctx, Param{Key: "SLACK_WEBHOOK_EVENTS", Type: TypeSecret, Panic: env.IsProd() && Slack.Token == ""})
+
+	Report.Sinks = GetStr(ctx, Param{Key: "REPORT_SINKS", Type: TypeParam, Panic: false})
+	Report.QueueSize = GetInt(ctx, Param{Key: "REPORT_QUEUE_SIZE", Type: TypeParam, Panic: false})
+	Report.WindowSec = GetInt(ctx, Param{Key: "REPORT_WINDOW_SEC", Type: TypeParam, Panic: false})
+	Report.RatePerMin = GetInt(ctx, Param{Key: "REPORT_RATE_PER_MIN", Type: TypeParam, Panic: false})
+	Report.Burst = GetInt(ctx, Param{Key: "REPORT_BURST", Type: TypeParam, Panic: false})
+	Report.Sentry.DSN = GetStr(ctx, Param{Key: "REPORT_SENTRY_DSN", Type: TypeSecret, Panic: false})
+	Report.Webhook.URL = GetStr(ctx, Param{Key: "REPORT_WEBHOOK_URL", Type: TypeSecret, Panic: false})
+	Report.Webhook.Secret = GetStr(ctx, Param{Key: "REPORT_WEBHOOK_SECRET", Type: TypeSecret, Panic: false})
 
 	Log.Mode = GetStr(ctx, Param{Key: "LOG_MODE", Type: TypeParam, Panic: true})
 	Log.Level = GetInt(ctx, Param{Key: "LOG_LEVEL", Type: TypeParam, Panic: true})
+	Log.Sinks = GetStr(ctx, Param{Key: "LOG_SINKS", Type: TypeParam, Panic: false})
+	Log.RouteLevels = GetStr(ctx, Param{Key: "LOG_ROUTE_LEVELS", Type: TypeParam, Panic: false})
+	Log.Sampling = GetStr(ctx, Param{Key: "LOG_SAMPLING", Type: TypeParam, Panic: false})
+	Log.Body.Capture = GetBool(ctx, Param{Key: "LOG_BODY_CAPTURE", Type: TypeParam, Panic: false})
+	Log.Body.MaxBytes = GetInt(ctx, Param{Key: "LOG_BODY_MAX_BYTES", Type: TypeParam, Panic: false})
+	Log.Redact.Fields = GetStr(ctx, Param{Key: "LOG_REDACT_FIELDS", Type: TypeParam, Panic: false})
+	Log.Redact.Paths = GetStr(ctx, Param{Key: "LOG_REDACT_PATHS", Type: TypeParam, Panic: false})
 	Log.Syslog.Addr = GetStr(ctx, Param{Key: "LOG_SYSLOG_ADDR", Type: TypeParam, Panic: false})
 	Log.Syslog.Protocol = GetStr(ctx, Param{Key: "LOG_SYSLOG_PROTOCOL", Type: TypeParam, Panic: false})
 	Log.Syslog.Tag = GetStr(ctx, Param{Key: "LOG_SYSLOG_TAG", Type: TypeParam, Panic: false})
 	Log.AWS.LogGroup.Name = GetStr(ctx, Param{Key: "LOG_AWS_LOG_GROUP_NAME", Type: TypeParam, Panic: false})
 	Log.AWS.LogGroup.Region = GetStr(ctx, Param{Key: "LOG_AWS_LOG_GROUP_REGION", Type: TypeParam, Panic: false})
+	Log.AWS.BufferSize = GetInt(ctx, Param{Key: "LOG_AWS_BUFFER_SIZE", Type: TypeParam, Panic: false})
+	Log.AWS.FlushIntervalSec = GetInt(ctx, Param{Key: "LOG_AWS_FLUSH_INTERVAL_SEC", Type: TypeParam, Panic: false})
 	Log.File.Path = GetStr(ctx, Param{Key: "LOG_FILE_PATH", Type: TypeParam, Panic: false})
+	Log.File.MaxSizeMB = GetInt(ctx, Param{Key: "LOG_FILE_MAX_SIZE_MB", Type: TypeParam, Panic: false})
+	Log.File.RotateHours = GetInt(ctx, Param{Key: "LOG_FILE_ROTATE_HOURS", Type: TypeParam, Panic: false})
+	Log.File.MaxBackups = GetInt(ctx, Param{Key: "LOG_FILE_MAX_BACKUPS", Type: TypeParam, Panic: false})
+	Log.File.Compress = GetBool(ctx, Param{Key: "LOG_FILE_COMPRESS", Type: TypeParam, Panic: false})
+
+	Admin.Token = GetStr(ctx, Param{Key: "ADMIN_TOKEN", Type: TypeSecret, Panic: false})
+
+	Webhook.MaxAttempts = GetInt(ctx, Param{Key: "WEBHOOK_MAX_ATTEMPTS", Type: TypeParam, Panic: false})
+	Webhook.BackoffBaseSec = GetInt(ctx, Param{Key: "WEBHOOK_BACKOFF_BASE_SEC", Type: TypeParam, Panic: false})
+	Webhook.BackoffMaxSec = GetInt(ctx, Param{Key: "WEBHOOK_BACKOFF_MAX_SEC", Type: TypeParam, Panic: false})
+	Webhook.PollSec = GetInt(ctx, Param{Key: "WEBHOOK_POLL_SEC", Type: TypeParam, Panic: false})
+	Webhook.TimeoutSec = GetInt(ctx, Param{Key: "WEBHOOK_TIMEOUT_SEC", Type: TypeParam, Panic: false})
+	Webhook.BatchSize = GetInt(ctx, Param{Key: "WEBHOOK_BATCH_SIZE", Type: TypeParam, Panic: false})
+
+	Outbox.PollSec = GetInt(ctx, Param{Key: "OUTBOX_POLL_SEC", Type: TypeParam, Panic: false})
+	Outbox.BatchSize = GetInt(ctx, Param{Key: "OUTBOX_BATCH_SIZE", Type: TypeParam, Panic: false})
+	Outbox.BackoffBaseSec = GetInt(ctx, Param{Key: "OUTBOX_BACKOFF_BASE_SEC", Type: TypeParam, Panic: false})
+	Outbox.BackoffMaxSec = GetInt(ctx, Param{Key: "OUTBOX_BACKOFF_MAX_SEC", Type: TypeParam, Panic: false})
+
+	Queue.Concurrency = GetInt(ctx, Param{Key: "QUEUE_CONCURRENCY", Type: TypeParam, Panic: false})
+	Queue.PollSec = GetInt(ctx, Param{Key: "QUEUE_POLL_SEC", Type: TypeParam, Panic: false})
+	Queue.MaxAttempts = GetInt(ctx, Param{Key: "QUEUE_MAX_ATTEMPTS", Type: TypeParam, Panic: false})
+	Queue.BackoffBaseSec = GetInt(ctx, Param{Key: "QUEUE_BACKOFF_BASE_SEC", Type: TypeParam, Panic: false})
+	Queue.BackoffMaxSec = GetInt(ctx, Param{Key: "QUEUE_BACKOFF_MAX_SEC", Type: TypeParam, Panic: false})
+	Queue.TimeoutSec = GetInt(ctx, Param{Key: "QUEUE_TIMEOUT_SEC", Type: TypeParam, Panic: false})
+
+	Scheduler.ElectSec = GetInt(ctx, Param{Key: "SCHEDULER_ELECT_SEC", Type: TypeParam, Panic: false})
+	Scheduler.SessionPrune.Schedule = GetStr(ctx, Param{Key: "SCHEDULER_SESSION_PRUNE_SCHEDULE", Type: TypeParam, Panic: false})
+	Scheduler.SessionPrune.RetentionHours = GetInt(ctx, Param{Key: "SCHEDULER_SESSION_PRUNE_RETENTION_HOURS", Type: TypeParam, Panic: false})
+	Scheduler.UserUnverifiedPrune.Schedule = GetStr(ctx, Param{Key: "SCHEDULER_USER_UNVERIFIED_PRUNE_SCHEDULE", Type: TypeParam, Panic: false})
+	Scheduler.UserUnverifiedPrune.AgeHours = GetInt(ctx, Param{Key: "SCHEDULER_USER_UNVERIFIED_PRUNE_AGE_HOURS", Type: TypeParam, Panic: false})
+	Scheduler.IdempotencyKeyPrune.Schedule = GetStr(ctx, Param{Key: "SCHEDULER_IDEMPOTENCY_KEY_PRUNE_SCHEDULE", Type: TypeParam, Panic: false})
+
+	RateLimit.Storage = GetStr(ctx, Param{Key: "RATE_LIMIT_STORAGE", Type: TypeParam, Panic: false})
+	RateLimit.Policies = GetStr(ctx, Param{Key: "RATE_LIMIT_POLICIES", Type: TypeParam, Panic: false})
+	RateLimit.Redis.Addr = GetStr(ctx, Param{Key: "RATE_LIMIT_REDIS_ADDR", Type: TypeParam, Panic: RateLimit.Storage == "redis"})
+	RateLimit.Redis.Password = GetStr(ctx, Param{Key: "RATE_LIMIT_REDIS_PASSWORD", Type: TypeSecret, Panic: false})
+	RateLimit.Redis.DB = GetInt(ctx, Param{Key: "RATE_LIMIT_REDIS_DB", Type: TypeParam, Panic: false})
+	RateLimit.Redis.TimeoutMs = GetInt(ctx, Param{Key: "RATE_LIMIT_REDIS_TIMEOUT_MS", Type: TypeParam, Panic: false})
+
+	Idempotency.TTLHours = GetInt(ctx, Param{Key: "IDEMPOTENCY_TTL_HOURS", Type: TypeParam, Panic: false})
+	Idempotency.Secret = GetStr(ctx, Param{Key: "IDEMPOTENCY_SECRET", Type: TypeSecret, Panic: env.IsProd()})
+
+	Problem.TypeBaseURL = GetStr(ctx, Param{Key: "PROBLEM_TYPE_BASE_URL", Type: TypeParam, Panic: false})
+
+	OpenAPI.Docs = GetBool(ctx, Param{Key: "OPENAPI_DOCS", Type: TypeParam, Panic: false})
+
+	API.VersionDeprecations = GetStr(ctx, Param{Key: "API_VERSION_DEPRECATIONS", Type: TypeParam, Panic: false})
+	API.MinAppVersion = GetStr(ctx, Param{Key: "API_MIN_APP_VERSION", Type: TypeParam, Panic: false})
+
+	GraphQL.MaxDepth = GetInt(ctx, Param{Key: "GRAPHQL_MAX_DEPTH", Type: TypeParam, Panic: false})
+	GraphQL.MaxComplexity = GetInt(ctx, Param{Key: "GRAPHQL_MAX_COMPLEXITY", Type: TypeParam, Panic: false})
+
+	Realtime.PubSub = GetStr(ctx, Param{Key: "REALTIME_PUBSUB", Type: TypeParam, Panic: false})
+	Realtime.HeartbeatSec = GetInt(ctx, Param{Key: "REALTIME_HEARTBEAT_SEC", Type: TypeParam, Panic: false})
 }
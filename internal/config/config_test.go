@@ -13,6 +13,7 @@ import (
 func setRequiredFields() {
 	// Set environment variables.
 	os.Setenv("SERVER_ADDR", "server_addr")
+	os.Setenv("SERVER_GRPC_ADDR", "server_grpc_addr")
 	os.Setenv("SERVER_TIMEOUT_READ", "1")
 	os.Setenv("SERVER_TIMEOUT_WRITE", "2")
 	os.Setenv("SERVER_TIMEOUT_IDLE", "3")
@@ -36,18 +37,84 @@ func setRequiredFields() {
 	os.Setenv("AWS_S3_BUCKET_DOCUMENTS", "aws_s3_bucket_documents")
 	os.Setenv("AWS_SES_SOURCE", "aws_ses_source")
 
+	os.Setenv("SLACK_TOKEN", "slack_token")
+	os.Setenv("SLACK_API_URL", "slack_api_url")
+	os.Setenv("SLACK_CHANNELS", "slack_channels")
+	os.Setenv("SLACK_MENTIONS", "slack_mentions")
+	os.Setenv("SLACK_THREAD_TTL_HOURS", "12")
 	os.Setenv("SLACK_WEBHOOK_ERRORS", "slack_webhook_errors")
 	os.Setenv("SLACK_WEBHOOK_EVENTS", "slack_webhook_events")
+	os.Setenv("REPORT_SINKS", "report_sinks")
+	os.Setenv("REPORT_QUEUE_SIZE", "100")
+	os.Setenv("REPORT_WINDOW_SEC", "60")
+	os.Setenv("REPORT_RATE_PER_MIN", "10")
+	os.Setenv("REPORT_BURST", "5")
+	os.Setenv("REPORT_SENTRY_DSN", "report_sentry_dsn")
+	os.Setenv("REPORT_WEBHOOK_URL", "report_webhook_url")
+	os.Setenv("REPORT_WEBHOOK_SECRET", "report_webhook_secret")
 
 	os.Setenv("LOG_MODE", "console")
 	os.Setenv("LOG_LEVEL", "5")
+	os.Setenv("LOG_SINKS", "log_sinks")
+	os.Setenv("LOG_ROUTE_LEVELS", "log_route_levels")
+	os.Setenv("LOG_SAMPLING", "log_sampling")
+	os.Setenv("LOG_BODY_CAPTURE", "true")
+	os.Setenv("LOG_BODY_MAX_BYTES", "2048")
+	os.Setenv("LOG_REDACT_FIELDS", "log_redact_fields")
+	os.Setenv("LOG_REDACT_PATHS", "log_redact_paths")
 	os.Setenv("LOG_FILE_PATH", "log_file_path")
+	os.Setenv("LOG_FILE_MAX_SIZE_MB", "100")
+	os.Setenv("LOG_FILE_ROTATE_HOURS", "24")
+	os.Setenv("LOG_FILE_MAX_BACKUPS", "7")
+	os.Setenv("LOG_FILE_COMPRESS", "true")
 	os.Setenv("LOG_SYSLOG_ADDR", "log_syslog_addr")
 	os.Setenv("LOG_SYSLOG_PROTOCOL", "log_syslog_protocol")
 	os.Setenv("LOG_SYSLOG_TAG", "log_syslog_tag")
 	os.Setenv("LOG_AWS_LOG_GROUP_NAME", "log_aws_log_group_name")
 	os.Setenv("LOG_AWS_LOG_GROUP_REGION", "log_aws_log_group_region")
 	os.Setenv("LOG_AWS_LOG_STREAM_SUFFIX", "log_aws_log_stream_suffix")
+	os.Setenv("ADMIN_TOKEN", "admin_token")
+	os.Setenv("WEBHOOK_MAX_ATTEMPTS", "8")
+	os.Setenv("WEBHOOK_BACKOFF_BASE_SEC", "30")
+	os.Setenv("WEBHOOK_BACKOFF_MAX_SEC", "21600")
+	os.Setenv("WEBHOOK_POLL_SEC", "10")
+	os.Setenv("WEBHOOK_TIMEOUT_SEC", "10")
+	os.Setenv("WEBHOOK_BATCH_SIZE", "20")
+	os.Setenv("OUTBOX_POLL_SEC", "5")
+	os.Setenv("OUTBOX_BATCH_SIZE", "50")
+	os.Setenv("OUTBOX_BACKOFF_BASE_SEC", "5")
+	os.Setenv("OUTBOX_BACKOFF_MAX_SEC", "900")
+	os.Setenv("QUEUE_CONCURRENCY", "4")
+	os.Setenv("QUEUE_POLL_SEC", "5")
+	os.Setenv("QUEUE_MAX_ATTEMPTS", "10")
+	os.Setenv("QUEUE_BACKOFF_BASE_SEC", "10")
+	os.Setenv("QUEUE_BACKOFF_MAX_SEC", "3600")
+	os.Setenv("QUEUE_TIMEOUT_SEC", "300")
+	os.Setenv("SCHEDULER_ELECT_SEC", "15")
+	os.Setenv("SCHEDULER_SESSION_PRUNE_SCHEDULE", "15 * * * *")
+	os.Setenv("SCHEDULER_SESSION_PRUNE_RETENTION_HOURS", "168")
+	os.Setenv("SCHEDULER_USER_UNVERIFIED_PRUNE_SCHEDULE", "30 3 * * *")
+	os.Setenv("SCHEDULER_USER_UNVERIFIED_PRUNE_AGE_HOURS", "720")
+	os.Setenv("SCHEDULER_IDEMPOTENCY_KEY_PRUNE_SCHEDULE", "45 * * * *")
+	os.Setenv("RATE_LIMIT_STORAGE", "redis")
+	os.Setenv("RATE_LIMIT_POLICIES", "*:*:ip:100:60")
+	os.Setenv("RATE_LIMIT_REDIS_ADDR", "localhost:6379")
+	os.Setenv("RATE_LIMIT_REDIS_PASSWORD", "rate_limit_redis_password")
+	os.Setenv("RATE_LIMIT_REDIS_DB", "1")
+	os.Setenv("RATE_LIMIT_REDIS_TIMEOUT_MS", "1000")
+	os.Setenv("IDEMPOTENCY_TTL_HOURS", "24")
+	os.Setenv("IDEMPOTENCY_SECRET", "idempotency_secret")
+	os.Setenv("PROBLEM_TYPE_BASE_URL", "https://docs.example.com/problems")
+	os.Setenv("OPENAPI_DOCS", "true")
+	os.Setenv("API_VERSION_DEPRECATIONS", "1:2027-01-01:2027-07-01")
+	os.Setenv("API_MIN_APP_VERSION", "2.4.0")
+	os.Setenv("GRAPHQL_MAX_DEPTH", "8")
+	os.Setenv("GRAPHQL_MAX_COMPLEXITY", "200")
+	os.Setenv("REALTIME_PUBSUB", "postgres")
+	os.Setenv("REALTIME_HEARTBEAT_SEC", "15")
+
+	os.Setenv("LOG_AWS_BUFFER_SIZE", "1000")
+	os.Setenv("LOG_AWS_FLUSH_INTERVAL_SEC", "10")
 }
 
 func testLoadPanic(testcase string, t *testing.T, f func()) {
@@ -67,6 +134,9 @@ func TestLoad(t *testing.T) {
 	if Server.Addr != "server_addr" {
 		t.Fatalf("Server.Addr = %s; want server_addr", Server.Addr)
 	}
+	if Server.GRPCAddr != "server_grpc_addr" {
+		t.Fatalf("Server.GRPCAddr = %s; want server_grpc_addr", Server.GRPCAddr)
+	}
 	if Server.Timeout.Read != 1 {
 		t.Fatalf("Server.Timeout.Read = %d; want 1", Server.Timeout.Read)
 	}
@@ -115,6 +185,21 @@ func TestLoad(t *testing.T) {
 	}
 
 	// Slack.
+	if Slack.Token != "slack_token" {
+		t.Fatalf("Slack.Token = %s; want slack_token", Slack.Token)
+	}
+	if Slack.APIURL != "slack_api_url" {
+		t.Fatalf("Slack.APIURL = %s; want slack_api_url", Slack.APIURL)
+	}
+	if Slack.Channels != "slack_channels" {
+		t.Fatalf("Slack.Channels = %s; want slack_channels", Slack.Channels)
+	}
+	if Slack.Mentions != "slack_mentions" {
+		t.Fatalf("Slack.Mentions = %s; want slack_mentions", Slack.Mentions)
+	}
+	if Slack.ThreadTTLHours != 12 {
+		t.Fatalf("Slack.ThreadTTLHours = %d; want 12", Slack.ThreadTTLHours)
+	}
 	if Slack.Webhook.Errors != "slack_webhook_errors" {
 		t.Fatalf("Slack.WebhookErrorsURL = %s; want slack_webhook_errors_url", Slack.Webhook.Errors)
 	}
@@ -122,6 +207,32 @@ func TestLoad(t *testing.T) {
 		t.Fatalf("Slack.WebhookEventsURL = %s; want slack_webhook_events_url", Slack.Webhook.Events)
 	}
 
+	// Report.
+	if Report.Sinks != "report_sinks" {
+		t.Fatalf("Report.Sinks = %s; want report_sinks", Report.Sinks)
+	}
+	if Report.QueueSize != 100 {
+		t.Fatalf("Report.QueueSize = %d; want 100", Report.QueueSize)
+	}
+	if Report.WindowSec != 60 {
+		t.Fatalf("Report.WindowSec = %d; want 60", Report.WindowSec)
+	}
+	if Report.RatePerMin != 10 {
+		t.Fatalf("Report.RatePerMin = %d; want 10", Report.RatePerMin)
+	}
+	if Report.Burst != 5 {
+		t.Fatalf("Report.Burst = %d; want 5", Report.Burst)
+	}
+	if Report.Sentry.DSN != "report_sentry_dsn" {
+		t.Fatalf("Report.Sentry.DSN = %s; want report_sentry_dsn", Report.Sentry.DSN)
+	}
+	if Report.Webhook.URL != "report_webhook_url" {
+		t.Fatalf("Report.Webhook.URL = %s; want report_webhook_url", Report.Webhook.URL)
+	}
+	if Report.Webhook.Secret != "report_webhook_secret" {
+		t.Fatalf("Report.Webhook.Secret = %s; want report_webhook_secret", Report.Webhook.Secret)
+	}
+
 	// Log.
 	if Log.Mode != "console" {
 		t.Fatalf("Log.Mode = %s; want log_mode", Log.Mode)
@@ -129,6 +240,27 @@ func TestLoad(t *testing.T) {
 	if Log.Level != 5 {
 		t.Fatalf("Log.Level = %d; want 5", Log.Level)
 	}
+	if Log.Sinks != "log_sinks" {
+		t.Fatalf("Log.Sinks = %s; want log_sinks", Log.Sinks)
+	}
+	if Log.RouteLevels != "log_route_levels" {
+		t.Fatalf("Log.RouteLevels = %s; want log_route_levels", Log.RouteLevels)
+	}
+	if Log.Sampling != "log_sampling" {
+		t.Fatalf("Log.Sampling = %s; want log_sampling", Log.Sampling)
+	}
+	if !Log.Body.Capture {
+		t.Fatalf("Log.Body.Capture = %t; want true", Log.Body.Capture)
+	}
+	if Log.Body.MaxBytes != 2048 {
+		t.Fatalf("Log.Body.MaxBytes = %d; want 2048", Log.Body.MaxBytes)
+	}
+	if Log.Redact.Fields != "log_redact_fields" {
+		t.Fatalf("Log.Redact.Fields = %s; want log_redact_fields", Log.Redact.Fields)
+	}
+	if Log.Redact.Paths != "log_redact_paths" {
+		t.Fatalf("Log.Redact.Paths = %s; want log_redact_paths", Log.Redact.Paths)
+	}
 	if Log.Syslog.Addr != "log_syslog_addr" {
 		t.Fatalf("Log.Syslog.Addr = %s; want log_syslog_addr", Log.Syslog.Addr)
 	}
@@ -141,12 +273,150 @@ func TestLoad(t *testing.T) {
 	if Log.File.Path != "log_file_path" {
 		t.Fatalf("Log.File = %s; want log_file_path", Log.File.Path)
 	}
+	if Log.File.MaxSizeMB != 100 {
+		t.Fatalf("Log.File.MaxSizeMB = %d; want 100", Log.File.MaxSizeMB)
+	}
+	if Log.File.RotateHours != 24 {
+		t.Fatalf("Log.File.RotateHours = %d; want 24", Log.File.RotateHours)
+	}
+	if Log.File.MaxBackups != 7 {
+		t.Fatalf("Log.File.MaxBackups = %d; want 7", Log.File.MaxBackups)
+	}
+	if Log.File.Compress != true {
+		t.Fatalf("Log.File.Compress = %t; want true", Log.File.Compress)
+	}
 	if Log.AWS.LogGroup.Name != "log_aws_log_group_name" {
 		t.Fatalf("Log.AWS.LogGroup.Name = %s; want log_aws_log_group_name", Log.AWS.LogGroup.Name)
 	}
 	if Log.AWS.LogGroup.Region != "log_aws_log_group_region" {
 		t.Fatalf("Log.AWS.LogGroup.Region = %s; want log_aws_log_group_region", Log.AWS.LogGroup.Region)
 	}
+	if Log.AWS.BufferSize != 1000 {
+		t.Fatalf("Log.AWS.BufferSize = %d; want 1000", Log.AWS.BufferSize)
+	}
+	if Log.AWS.FlushIntervalSec != 10 {
+		t.Fatalf("Log.AWS.FlushIntervalSec = %d; want 10", Log.AWS.FlushIntervalSec)
+	}
+
+	// Admin.
+	if Admin.Token != "admin_token" {
+		t.Fatalf("Admin.Token = %s; want admin_token", Admin.Token)
+	}
+	if Webhook.MaxAttempts != 8 {
+		t.Fatalf("Webhook.MaxAttempts = %d; want 8", Webhook.MaxAttempts)
+	}
+	if Webhook.BackoffBaseSec != 30 {
+		t.Fatalf("Webhook.BackoffBaseSec = %d; want 30", Webhook.BackoffBaseSec)
+	}
+	if Webhook.BackoffMaxSec != 21600 {
+		t.Fatalf("Webhook.BackoffMaxSec = %d; want 21600", Webhook.BackoffMaxSec)
+	}
+	if Webhook.PollSec != 10 {
+		t.Fatalf("Webhook.PollSec = %d; want 10", Webhook.PollSec)
+	}
+	if Webhook.TimeoutSec != 10 {
+		t.Fatalf("Webhook.TimeoutSec = %d; want 10", Webhook.TimeoutSec)
+	}
+	if Webhook.BatchSize != 20 {
+		t.Fatalf("Webhook.BatchSize = %d; want 20", Webhook.BatchSize)
+	}
+	if Outbox.PollSec != 5 {
+		t.Fatalf("Outbox.PollSec = %d; want 5", Outbox.PollSec)
+	}
+	if Outbox.BatchSize != 50 {
+		t.Fatalf("Outbox.BatchSize = %d; want 50", Outbox.BatchSize)
+	}
+	if Outbox.BackoffBaseSec != 5 {
+		t.Fatalf("Outbox.BackoffBaseSec = %d; want 5", Outbox.BackoffBaseSec)
+	}
+	if Outbox.BackoffMaxSec != 900 {
+		t.Fatalf("Outbox.BackoffMaxSec = %d; want 900", Outbox.BackoffMaxSec)
+	}
+	if Queue.Concurrency != 4 {
+		t.Fatalf("Queue.Concurrency = %d; want 4", Queue.Concurrency)
+	}
+	if Queue.PollSec != 5 {
+		t.Fatalf("Queue.PollSec = %d; want 5", Queue.PollSec)
+	}
+	if Queue.MaxAttempts != 10 {
+		t.Fatalf("Queue.MaxAttempts = %d; want 10", Queue.MaxAttempts)
+	}
+	if Queue.BackoffBaseSec != 10 {
+		t.Fatalf("Queue.BackoffBaseSec = %d; want 10", Queue.BackoffBaseSec)
+	}
+	if Queue.BackoffMaxSec != 3600 {
+		t.Fatalf("Queue.BackoffMaxSec = %d; want 3600", Queue.BackoffMaxSec)
+	}
+	if Queue.TimeoutSec != 300 {
+		t.Fatalf("Queue.TimeoutSec = %d; want 300", Queue.TimeoutSec)
+	}
+	if Scheduler.ElectSec != 15 {
+		t.Fatalf("Scheduler.ElectSec = %d; want 15", Scheduler.ElectSec)
+	}
+	if Scheduler.SessionPrune.Schedule != "15 * * * *" {
+		t.Fatalf("Scheduler.SessionPrune.Schedule = %s; want 15 * * * *", Scheduler.SessionPrune.Schedule)
+	}
+	if Scheduler.SessionPrune.RetentionHours != 168 {
+		t.Fatalf("Scheduler.SessionPrune.RetentionHours = %d; want 168", Scheduler.SessionPrune.RetentionHours)
+	}
+	if Scheduler.UserUnverifiedPrune.Schedule != "30 3 * * *" {
+		t.Fatalf("Scheduler.UserUnverifiedPrune.Schedule = %s; want 30 3 * * *", Scheduler.UserUnverifiedPrune.Schedule)
+	}
+	if Scheduler.UserUnverifiedPrune.AgeHours != 720 {
+		t.Fatalf("Scheduler.UserUnverifiedPrune.AgeHours = %d; want 720", Scheduler.UserUnverifiedPrune.AgeHours)
+	}
+	if Scheduler.IdempotencyKeyPrune.Schedule != "45 * * * *" {
+		t.Fatalf("Scheduler.IdempotencyKeyPrune.Schedule = %s; want 45 * * * *", Scheduler.IdempotencyKeyPrune.Schedule)
+	}
+	if RateLimit.Storage != "redis" {
+		t.Fatalf("RateLimit.Storage = %s; want redis", RateLimit.Storage)
+	}
+	if RateLimit.Policies != "*:*:ip:100:60" {
+		t.Fatalf("RateLimit.Policies = %s; want *:*:ip:100:60", RateLimit.Policies)
+	}
+	if RateLimit.Redis.Addr != "localhost:6379" {
+		t.Fatalf("RateLimit.Redis.Addr = %s; want localhost:6379", RateLimit.Redis.Addr)
+	}
+	if RateLimit.Redis.Password != "rate_limit_redis_password" {
+		t.Fatalf("RateLimit.Redis.Password = %s; want rate_limit_redis_password", RateLimit.Redis.Password)
+	}
+	if RateLimit.Redis.DB != 1 {
+		t.Fatalf("RateLimit.Redis.DB = %d; want 1", RateLimit.Redis.DB)
+	}
+	if RateLimit.Redis.TimeoutMs != 1000 {
+		t.Fatalf("RateLimit.Redis.TimeoutMs = %d; want 1000", RateLimit.Redis.TimeoutMs)
+	}
+	if Idempotency.TTLHours != 24 {
+		t.Fatalf("Idempotency.TTLHours = %d; want 24", Idempotency.TTLHours)
+	}
+
+	if Idempotency.Secret != "idempotency_secret" {
+		t.Fatalf("Idempotency.Secret = %s; want idempotency_secret", Idempotency.Secret)
+	}
+	if Problem.TypeBaseURL != "https://docs.example.com/problems" {
+		t.Fatalf("Problem.TypeBaseURL = %s; want https://docs.example.com/problems", Problem.TypeBaseURL)
+	}
+	if !OpenAPI.Docs {
+		t.Fatalf("OpenAPI.Docs = %t; want true", OpenAPI.Docs)
+	}
+	if API.VersionDeprecations != "1:2027-01-01:2027-07-01" {
+		t.Fatalf("API.VersionDeprecations = %s; want 1:2027-01-01:2027-07-01", API.VersionDeprecations)
+	}
+	if API.MinAppVersion != "2.4.0" {
+		t.Fatalf("API.MinAppVersion = %s; want 2.4.0", API.MinAppVersion)
+	}
+	if GraphQL.MaxDepth != 8 {
+		t.Fatalf("GraphQL.MaxDepth = %d; want 8", GraphQL.MaxDepth)
+	}
+	if GraphQL.MaxComplexity != 200 {
+		t.Fatalf("GraphQL.MaxComplexity = %d; want 200", GraphQL.MaxComplexity)
+	}
+	if Realtime.PubSub != "postgres" {
+		t.Fatalf("Realtime.PubSub = %s; want postgres", Realtime.PubSub)
+	}
+	if Realtime.HeartbeatSec != 15 {
+		t.Fatalf("Realtime.HeartbeatSec = %d; want 15", Realtime.HeartbeatSec)
+	}
 }
 
 func TestLoadPanic(t *testing.T) {
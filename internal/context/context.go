@@ -1,6 +1,9 @@
 package context
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Ctx context.Context
 
@@ -15,3 +18,7 @@ func WithValue(parent Ctx, key, val interface{}) Ctx {
 func WithCancel(parent Ctx) (Ctx, context.CancelFunc) {
 	return context.WithCancel(parent)
 }
+
+func WithTimeout(parent Ctx, timeout time.Duration) (Ctx, context.CancelFunc) {
+	return context.WithTimeout(parent, timeout)
+}
@@ -1,7 +1,9 @@
 package context
 
 import (
+	"context"
 	"testing"
+	"time"
 )
 
 func TestBackground(t *testing.T) {
@@ -29,3 +31,13 @@ func TestWithCancel(t *testing.T) {
 	}
 	cancel()
 }
+
+func TestWithTimeout(t *testing.T) {
+	ctx, cancel := WithTimeout(Background(), time.Millisecond)
+	defer cancel()
+
+	<-ctx.Done()
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Fatalf("want: ctx.Err() = %v; got: %v", context.DeadlineExceeded, ctx.Err())
+	}
+}
@@ -3,10 +3,10 @@ package context
 import (
 	gocontext "context"
 	"net"
-	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/koraygocmen/golang-boilerplate/internal/env"
+	"github.com/koraygocmen/golang-boilerplate/internal/i18n"
 )
 
 func NewFiberCtx(c *fiber.Ctx) (Ctx, gocontext.CancelFunc) {
@@ -24,9 +24,11 @@ func NewFiberCtx(c *fiber.Ctx) (Ctx, gocontext.CancelFunc) {
 	ctx = WithValue(ctx, KeyPath, string(c.Context().Path()))
 	ctx = WithValue(ctx, KeyQuery, string(c.Context().QueryArgs().QueryString()))
 
-	ctx = WithValue(ctx, KeyLang, LangTR)
-	if lang := c.Get(fiber.HeaderAcceptLanguage); len(lang) >= 2 {
-		ctx = WithValue(ctx, KeyLang, ToLanguage(strings.TrimSpace(lang)[:2]))
+	// Language is negotiated with the q-values of the accept language,
+	// it is not set if nothing matches so the language of the user is
+	// the fallback once the user is authenticated.
+	if lang, ok := i18n.Messages.Match(c.Get(fiber.HeaderAcceptLanguage)); ok {
+		ctx = WithValue(ctx, KeyLang, ToLanguage(lang))
 	}
 
 	return ctx, cancel
@@ -8,11 +8,24 @@ const (
 	KeyLogLevel ContextKey = "log_level"
 	KeyLang     ContextKey = "lang"
 
+	// KeyLogRouteLevel is the log level override of the route.
+	// It is not part of the Keys since it is not logged.
+	KeyLogRouteLevel ContextKey = "log_route_level"
+
+	// KeyProblem is set if the client accepts the errors as
+	// problem details. It is not part of the Keys either.
+	KeyProblem ContextKey = "problem"
+
+	// KeyOutboxID is the id of the event the outbox relays, the
+	// subscribers key their side effects on it. It is not logged.
+	KeyOutboxID ContextKey = "outbox_id"
+
 	// Server context keys.
 	KeyMethod     ContextKey = "method"
 	KeyPath       ContextKey = "path"
 	KeyRequestID  ContextKey = "request_id"
 	KeyQuery      ContextKey = "query"
+	KeyReqHeaders ContextKey = "req_headers"
 	KeyReqBody    ContextKey = "req_body"
 	KeyStatus     ContextKey = "status"
 	KeyResBody    ContextKey = "res_body"
@@ -30,6 +43,7 @@ const (
 	// User context keys.
 	KeyUserID        ContextKey = "user_id"
 	KeyUserSessionID ContextKey = "user_session_id"
+	KeyUserLang      ContextKey = "user_lang"
 )
 
 var (
@@ -44,6 +58,7 @@ var (
 		KeyPath,
 		KeyRequestID,
 		KeyQuery,
+		KeyReqHeaders,
 		KeyReqBody,
 		KeyStatus,
 		KeyResBody,
@@ -61,5 +76,6 @@ var (
 		// User context keys.
 		KeyUserID,
 		KeyUserSessionID,
+		KeyUserLang,
 	}
 )
@@ -2,6 +2,9 @@ package context
 
 import (
 	"strings"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/i18n"
+	"golang.org/x/text/language"
 )
 
 type Language string
@@ -9,15 +12,23 @@ type Language string
 var (
 	LangTR Language = "TR"
 	LangEN Language = "EN"
+	LangDE Language = "DE"
+	LangAR Language = "AR"
 
-	Languages = map[Language]bool{
-		LangTR: true,
-		LangEN: true,
-	}
+	// Languages are the languages of the message catalogs.
+	Languages = languages()
 
-	LangDefault = LangTR
+	LangDefault = Language(strings.ToUpper(i18n.Fallback.String()))
 )
 
+func languages() map[Language]bool {
+	languages := make(map[Language]bool)
+	for _, tag := range i18n.Messages.Languages() {
+		languages[Language(strings.ToUpper(tag.String()))] = true
+	}
+	return languages
+}
+
 func ToLanguage(lang any) Language {
 	if lang == nil {
 		return LangDefault
@@ -29,8 +40,11 @@ func ToLanguage(lang any) Language {
 	)
 
 	if l, ok = lang.(Language); !ok {
-		if lstr, ok := lang.(string); ok {
-			l = Language(strings.ToUpper(strings.TrimSpace(lstr)))
+		switch lang := lang.(type) {
+		case string:
+			l = Language(strings.ToUpper(strings.TrimSpace(lang)))
+		case language.Tag:
+			l = Language(strings.ToUpper(lang.String()))
 		}
 	}
 
@@ -41,7 +55,19 @@ func ToLanguage(lang any) Language {
 	return l
 }
 
+// Lang returns the language negotiated for the request, the
+// stored language of the user is the fallback. Background jobs
+// only have the language of the user.
 func Lang(ctx Ctx) Language {
-	lang := ctx.Value(KeyLang)
+	if lang := ctx.Value(KeyLang); lang != nil {
+		return ToLanguage(lang)
+	}
+
+	lang := ctx.Value(KeyUserLang)
 	return ToLanguage(lang)
 }
+
+// Tag returns the language tag of the language.
+func (l Language) Tag() language.Tag {
+	return language.Make(strings.ToLower(string(l)))
+}
@@ -1,6 +1,10 @@
 package context
 
-import "testing"
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
 
 func TestLang(t *testing.T) {
 	cases := []struct {
@@ -9,6 +13,8 @@ func TestLang(t *testing.T) {
 	}{
 		{"tr", LangTR},
 		{"en", LangEN},
+		{"de", LangDE},
+		{"AR", LangAR},
 		{"", LangTR},
 		{"foo", LangTR},
 	}
@@ -19,4 +25,26 @@ func TestLang(t *testing.T) {
 			t.Errorf("Lang(%q) == %q, want %q", c.lang, got, c.want)
 		}
 	}
+
+	// Languages are converted from the tags as well.
+	if got := ToLanguage(language.German); got != LangDE {
+		t.Errorf("ToLanguage(%s) == %q, want %q", language.German, got, LangDE)
+	}
+	if got := LangAR.Tag(); got != language.Arabic {
+		t.Errorf("LangAR.Tag() == %s, want %s", got, language.Arabic)
+	}
+}
+
+func TestLangUser(t *testing.T) {
+	// The language of the user is the fallback.
+	ctx := WithValue(Background(), KeyUserLang, ToLanguage("de"))
+	if got := Lang(ctx); got != LangDE {
+		t.Errorf("Lang() == %q, want %q", got, LangDE)
+	}
+
+	// The negotiated language comes first.
+	ctx = WithValue(ctx, KeyLang, LangEN)
+	if got := Lang(ctx); got != LangEN {
+		t.Errorf("Lang() == %q, want %q", got, LangEN)
+	}
 }
@@ -27,9 +27,13 @@ func Map(ctx Ctx) map[ContextKey]interface{} {
 			}
 
 			switch key {
-			case KeyReqBody:
-				marshalled, _ := json.Marshal(val)
-				val = string(marshalled)
+			case KeyReqHeaders, KeyReqBody, KeyResBody:
+				// Captured bodies are already marshalled and redacted.
+				marshalled, ok := val.(json.RawMessage)
+				if !ok {
+					marshalled, _ = json.Marshal(val)
+				}
+				val = strings.ReplaceAll(string(marshalled), `"`, `\"`)
 			}
 
 			m[key] = val
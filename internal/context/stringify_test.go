@@ -1,6 +1,7 @@
 package context
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -43,3 +44,14 @@ func TestString(t *testing.T) {
 		t.Fatalf("want: %s; got: %s", want, got)
 	}
 }
+
+func TestStringBody(t *testing.T) {
+	ctx := Background()
+	ctx = WithValue(ctx, KeyReqBody, json.RawMessage(`{"email":"***"}`))
+	ctx = WithValue(ctx, KeyResBody, map[string]interface{}{"success": true})
+
+	want := `req_body="{\"email\":\"***\"}" res_body="{\"success\":true}" format`
+	if got := String(ctx, "format"); got != want {
+		t.Fatalf("want: %s; got: %s", want, got)
+	}
+}
@@ -2,8 +2,11 @@ package errapi
 
 import (
 	"encoding/json"
+	"sort"
+	"sync"
 
 	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/i18n"
 )
 
 // Error type definitions and methods.
@@ -11,25 +14,121 @@ type Error interface {
 	Status() int
 	Code() string
 	Error() string
+	Message() string
+	Fields() []FieldError
 	Localize(ctx context.Ctx) Error
 	MarshalJSON() ([]byte, error)
 }
 
+// FieldError is the error of a field of the request, the
+// field is the path of the field in the request, i.e.
+// "email" or "events.1".
+type FieldError struct {
+	Field string
+	Error Error
+
+	message string
+}
+
+func (f FieldError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"field":   f.Field,
+		"code":    f.Error.Code(),
+		"message": f.message,
+	})
+}
+
 type errapi struct {
-	status   int
-	code     string
-	message  string
-	messages map[context.Language]string
+	status  int
+	code    string
+	key     string
+	params  i18n.Params
+	message string
+	fields  []FieldError
 }
 
-func New(status int, code, tr, en string) Error {
+var (
+	keysMu sync.Mutex
+	keys   = make(map[string]bool)
+)
+
+// New creates the error, the message of the error is the
+// message of the code in the catalog of the language.
+func New(status int, code string) Error {
+	register(code)
 	return &errapi{
 		status: status,
 		code:   code,
-		messages: map[context.Language]string{
-			context.LangTR: tr,
-			context.LangEN: en,
-		},
+		key:    code,
+	}
+}
+
+// WithMessage returns a copy of the error with the message of
+// the key, used if the code has more than a single message.
+func WithMessage(aerr Error, key string) Error {
+	e, ok := aerr.(*errapi)
+	if !ok || e == nil {
+		return aerr
+	}
+
+	register(key)
+	c := e.copy()
+	c.key = key
+	return c
+}
+
+// WithParams returns a copy of the error with
+// the params of the placeholders of the message.
+func WithParams(aerr Error, params i18n.Params) Error {
+	e, ok := aerr.(*errapi)
+	if !ok || e == nil {
+		return aerr
+	}
+
+	c := e.copy()
+	c.params = params
+	return c
+}
+
+// WithFields returns a copy of the error with the field errors.
+func WithFields(aerr Error, fields []FieldError) Error {
+	e, ok := aerr.(*errapi)
+	if !ok || e == nil {
+		return aerr
+	}
+
+	c := e.copy()
+	c.fields = append([]FieldError{}, fields...)
+	return c
+}
+
+// Keys returns the message keys of the errors created, the
+// catalogs are checked for the missing messages with them.
+func Keys() []string {
+	keysMu.Lock()
+	defer keysMu.Unlock()
+
+	list := make([]string, 0, len(keys))
+	for key := range keys {
+		list = append(list, key)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func register(key string) {
+	keysMu.Lock()
+	defer keysMu.Unlock()
+	keys[key] = true
+}
+
+func (e *errapi) copy() *errapi {
+	return &errapi{
+		status: e.status,
+		code:   e.code,
+		key:    e.key,
+		params: e.params,
+		fields: append([]FieldError(nil), e.fields...),
 	}
 }
 
@@ -45,22 +144,42 @@ func (e *errapi) Code() string {
 	return e.code
 }
 
+// Message is the localized message of the error, it is
+// empty until the error is localized.
+func (e *errapi) Message() string {
+	return e.message
+}
+
+func (e *errapi) Fields() []FieldError {
+	return e.fields
+}
+
 func (e *errapi) Localize(ctx context.Ctx) Error {
 	if e == nil {
 		return nil
 	}
 
-	if message, ok := e.messages[context.Lang(ctx)]; ok {
-		e.message = message
+	tag := context.Lang(ctx).Tag()
+	e.message = i18n.Messages.Message(tag, e.key, e.params)
+
+	for i, field := range e.fields {
+		if f, ok := field.Error.(*errapi); ok {
+			e.fields[i].message = i18n.Messages.Message(tag, f.key, f.params)
+		}
 	}
 	return e
 }
 
 func (e *errapi) MarshalJSON() ([]byte, error) {
-	return json.Marshal(map[string]interface{}{
+	m := map[string]interface{}{
 		"code":    e.code,
 		"message": e.message,
-	})
+	}
+
+	if len(e.fields) > 0 {
+		m["errors"] = e.fields
+	}
+	return json.Marshal(m)
 }
 
 func Is(aerr Error, code string) bool {
@@ -80,6 +199,21 @@ const (
 	ErrCodeRequestTimeout        = "requestTimeout"
 	ErrCodeTooManyRequests       = "tooManyRequests"
 	ErrCodeRequestEntityTooLarge = "requestEntityTooLarge"
+	ErrCodeUnsupportedMediaType  = "unsupportedMediaType"
+	ErrCodeRequestBodyInvalid    = "requestBodyInvalid"
+	ErrCodeFieldTypeInvalid      = "fieldTypeInvalid"
+
+	// Version.
+	ErrCodeAPIVersionInvalid     = "apiVersionInvalid"
+	ErrCodeAPIVersionSunset      = "apiVersionSunset"
+	ErrCodeAppVersionInvalid     = "appVersionInvalid"
+	ErrCodeAppVersionUnsupported = "appVersionUnsupported"
+
+	// GraphQL.
+	ErrCodeGraphQLQueryMissing       = "graphqlQueryMissing"
+	ErrCodeGraphQLQueryInvalid       = "graphqlQueryInvalid"
+	ErrCodeGraphQLDepthExceeded      = "graphqlDepthExceeded"
+	ErrCodeGraphQLComplexityExceeded = "graphqlComplexityExceeded"
 
 	// Auth.
 	ErrCodeAuthorizationMissing                 = "authorizationMissing"
@@ -90,6 +224,11 @@ const (
 	ErrCodeAuthorizationInvalidIP               = "authorizationInvalidIP"
 	ErrCodeAuthorizationInsufficientAccessLevel = "authorizationInsufficientAccessLevel"
 
+	// Admin.
+	ErrCodeLogLevelInvalid       = "logLevelInvalid"
+	ErrCodeLogRouteLevelsInvalid = "logRouteLevelsInvalid"
+	ErrCodeLogSampleRulesInvalid = "logSampleRulesInvalid"
+
 	// User Service.
 	ErrCodeUserMissing                 = "userMissing"
 	ErrCodeUserNotFound                = "userNotFound"
@@ -107,6 +246,15 @@ const (
 	ErrCodeUserGivenNamesInvalid       = "userGivenNamesInvalid"
 	ErrCodeUserSurnameMissing          = "userSurnameMissing"
 	ErrCodeUserSurnameInvalid          = "userSurnameInvalid"
+	ErrCodeUserVersionMismatch         = "userVersionMismatch"
+	ErrCodeUserPatchInvalid            = "userPatchInvalid"
+	ErrCodeUserFieldUnknown            = "userFieldUnknown"
+	ErrCodeUserFieldReadOnly           = "userFieldReadOnly"
+
+	// User Preference Service.
+	ErrCodeUserPreferenceParamsMissing   = "userPreferenceParamsMissing"
+	ErrCodeUserPreferenceLocaleInvalid   = "userPreferenceLocaleInvalid"
+	ErrCodeUserPreferenceTimezoneInvalid = "userPreferenceTimezoneInvalid"
 
 	// User Session Service.
 	ErrCodeUserSessionMissing             = "userSessionMissing"
@@ -116,4 +264,21 @@ const (
 	ErrCodeUserSessionPurposeInvalid      = "userSessionPurposeInvalid"
 	ErrCodeUserSessionCredentialsInvalid  = "userSessionCredentialsInvalid"
 	ErrCodeUserSessionPasswordMissing     = "userSessionPasswordMissing"
+
+	// Webhook Service.
+	ErrCodeWebhookSubscriptionIdMissing     = "webhookSubscriptionIdMissing"
+	ErrCodeWebhookSubscriptionNotFound      = "webhookSubscriptionNotFound"
+	ErrCodeWebhookSubscriptionParamsMissing = "webhookSubscriptionParamsMissing"
+	ErrCodeWebhookSubscriptionUrlMissing    = "webhookSubscriptionUrlMissing"
+	ErrCodeWebhookSubscriptionUrlInvalid    = "webhookSubscriptionUrlInvalid"
+	ErrCodeWebhookSubscriptionEventsMissing = "webhookSubscriptionEventsMissing"
+	ErrCodeWebhookSubscriptionEventInvalid  = "webhookSubscriptionEventInvalid"
+	ErrCodeWebhookDeliveryIdMissing         = "webhookDeliveryIdMissing"
+	ErrCodeWebhookDeliveryNotFound          = "webhookDeliveryNotFound"
+	ErrCodeWebhookDeliveryStatusInvalid     = "webhookDeliveryStatusInvalid"
+	ErrCodeWebhookDeliveryPending           = "webhookDeliveryPending"
+
+	// Idempotency Key Service.
+	ErrCodeIdempotencyKeyInvalid = "idempotencyKeyInvalid"
+	ErrCodeIdempotencyKeyReused  = "idempotencyKeyReused"
 )
@@ -4,7 +4,7 @@ import (
 	"github.com/koraygocmen/golang-boilerplate/internal/context"
 	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
 	"github.com/koraygocmen/golang-boilerplate/internal/logger"
-	"github.com/koraygocmen/golang-boilerplate/internal/slack"
+	"github.com/koraygocmen/golang-boilerplate/internal/reporter"
 )
 
 func Handle(ctx context.Ctx, aerr errapi.Error, err error, fatal bool) {
@@ -13,12 +13,14 @@ func Handle(ctx context.Ctx, aerr errapi.Error, err error, fatal bool) {
 	}
 
 	if err != nil {
-		slack.Client.MessageError(ctx, err)
+		reporter.Reporter.Report(ctx, err)
 		ctx = context.WithValue(ctx, context.KeyError, err)
 	}
 
 	if fatal {
 		logger.Logger.Emerf(ctx, `err="emergency exit"`)
+		// Flush the queued reports before exiting.
+		reporter.Reporter.Close()
 		logger.Logger.Fatalf(`err="%v"`, err)
 	} else {
 		logger.Logger.Errorf(ctx, "")
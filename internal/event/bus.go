@@ -0,0 +1,92 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+)
+
+// Handler handles an event. Events are delivered at least once,
+// so a handler may see the same event again and must be idempotent.
+type Handler func(ctx context.Ctx, e Event) error
+
+type subscription struct {
+	subscriber string
+	handler    Handler
+}
+
+// Bus dispatches the events to the in process subscribers.
+type Bus struct {
+	lock          sync.RWMutex
+	subscriptions map[Name][]subscription
+}
+
+var (
+	// Default is the bus the outbox relays to.
+	Default = NewBus()
+)
+
+func NewBus() *Bus {
+	return &Bus{
+		subscriptions: make(map[Name][]subscription),
+	}
+}
+
+// Subscribe registers the handler of the subscriber for the event.
+func (b *Bus) Subscribe(name Name, subscriber string, handler Handler) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.subscriptions[name] = append(b.subscriptions[name], subscription{
+		subscriber: subscriber,
+		handler:    handler,
+	})
+}
+
+// On registers a handler of the typed event T, e.g.
+// On(bus, "slack", func(ctx context.Ctx, e *UserCreated) error {...}).
+func On[T Event](b *Bus, subscriber string, handler func(ctx context.Ctx, e T) error) {
+	var zero T
+	b.Subscribe(zero.EventName(), subscriber, func(ctx context.Ctx, e Event) error {
+		typed, ok := e.(T)
+		if !ok {
+			return fmt.Errorf("unexpected event type %T", e)
+		}
+		return handler(ctx, typed)
+	})
+}
+
+// Subscribers returns the names of the subscribers of the event.
+func (b *Bus) Subscribers(name Name) []string {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	var subscribers []string
+	for _, s := range b.subscriptions[name] {
+		subscribers = append(subscribers, s.subscriber)
+	}
+	return subscribers
+}
+
+// Dispatch calls every handler of the event, a failing handler does
+// not stop the others. The errors of the failed ones are returned.
+func (b *Bus) Dispatch(ctx context.Ctx, e Event) error {
+	b.lock.RLock()
+	subscriptions := b.subscriptions[e.EventName()]
+	b.lock.RUnlock()
+
+	var errs []error
+	for _, s := range subscriptions {
+		if err := s.handler(ctx, e); err != nil {
+			errs = append(errs, fmt.Errorf("subscriber %s error: %w", s.subscriber, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		err := fmt.Errorf("event bus dispatch %s error: %w", e.EventName(), errors.Join(errs...))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
+)
+
+type Name string
+
+const (
+	NameUserCreated Name = "user.created"
+	NameUserUpdated Name = "user.updated"
+	NameUserDeleted Name = "user.deleted"
+
+	NameUserSessionRevoked Name = "user_session.revoked"
+)
+
+const (
+	AggregateUser = "user"
+)
+
+// Event is a domain event. Events of the same aggregate
+// are delivered to the subscribers in the order emitted.
+type Event interface {
+	EventName() Name
+	Aggregate() (aggregateType, aggregateID string)
+}
+
+var (
+	// registry creates the typed event to decode a payload into.
+	registry = map[Name]func() Event{
+		NameUserCreated: func() Event { return &UserCreated{} },
+		NameUserUpdated: func() Event { return &UserUpdated{} },
+		NameUserDeleted: func() Event { return &UserDeleted{} },
+
+		NameUserSessionRevoked: func() Event { return &UserSessionRevoked{} },
+	}
+)
+
+// Encode marshals the event payload.
+func Encode(e Event) (string, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		err = fmt.Errorf("event encode error: marshal %s error: %w", e.EventName(), err)
+		return "", err
+	}
+	return string(payload), nil
+}
+
+// Decode unmarshals the payload into the typed event of the name.
+func Decode(name Name, payload string) (Event, error) {
+	newEvent, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("event decode error: unknown event %q", name)
+	}
+
+	e := newEvent()
+	if err := json.Unmarshal([]byte(payload), e); err != nil {
+		err = fmt.Errorf("event decode error: unmarshal %s error: %w", name, err)
+		return nil, err
+	}
+	return e, nil
+}
+
+// User events.
+
+type UserCreated struct {
+	User *User.User `json:"user"`
+}
+
+func (*UserCreated) EventName() Name {
+	return NameUserCreated
+}
+
+func (e *UserCreated) Aggregate() (string, string) {
+	return AggregateUser, strconv.FormatInt(e.User.ID, 10)
+}
+
+type UserUpdated struct {
+	User *User.User `json:"user"`
+	// PasswordChanged is set when the other sessions are revoked.
+	PasswordChanged bool `json:"passwordChanged"`
+}
+
+func (*UserUpdated) EventName() Name {
+	return NameUserUpdated
+}
+
+func (e *UserUpdated) Aggregate() (string, string) {
+	return AggregateUser, strconv.FormatInt(e.User.ID, 10)
+}
+
+type UserDeleted struct {
+	User *User.User `json:"user"`
+}
+
+func (*UserDeleted) EventName() Name {
+	return NameUserDeleted
+}
+
+func (e *UserDeleted) Aggregate() (string, string) {
+	return AggregateUser, strconv.FormatInt(e.User.ID, 10)
+}
+
+// User session events.
+
+// UserSessionRevoked is emitted when the sessions of the user are
+// deleted, it is of the user aggregate to keep the order with the
+// updates of the user that revoke the sessions.
+type UserSessionRevoked struct {
+	UserID         int64   `json:"userId"`
+	UserSessionIDs []int64 `json:"userSessionIds"`
+}
+
+func (*UserSessionRevoked) EventName() Name {
+	return NameUserSessionRevoked
+}
+
+func (e *UserSessionRevoked) Aggregate() (string, string) {
+	return AggregateUser, strconv.FormatInt(e.UserID, 10)
+}
@@ -0,0 +1,100 @@
+package event
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
+	"github.com/koraygocmen/null"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	e := &UserUpdated{User: &User.User{ID: 7, Surname: null.StringFrom("surname")}, PasswordChanged: true}
+
+	payload, err := Encode(e)
+	if err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+
+	decoded, err := Decode(NameUserUpdated, payload)
+	if err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+
+	got, ok := decoded.(*UserUpdated)
+	if !ok {
+		t.Fatalf("want: *UserUpdated; got: %T", decoded)
+	}
+	if got.User.ID != 7 || got.User.Surname.String != "surname" || !got.PasswordChanged {
+		t.Fatalf("want: %+v; got: %+v", e, got)
+	}
+
+	aggregateType, aggregateID := got.Aggregate()
+	if aggregateType != AggregateUser || aggregateID != "7" {
+		t.Fatalf("want: user/7; got: %s/%s", aggregateType, aggregateID)
+	}
+
+	if _, err := Decode("unknown", payload); err == nil {
+		t.Fatalf("want: err != nil; got: err = nil")
+	}
+
+	// Test the revoked sessions are of the user aggregate.
+	payload, err = Encode(&UserSessionRevoked{UserID: 7, UserSessionIDs: []int64{2, 3}})
+	if err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+
+	decoded, err = Decode(NameUserSessionRevoked, payload)
+	if err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+
+	revoked, ok := decoded.(*UserSessionRevoked)
+	if !ok || len(revoked.UserSessionIDs) != 2 || revoked.UserSessionIDs[1] != 3 {
+		t.Fatalf("want: user session revoked 2, 3; got: %+v", decoded)
+	}
+
+	aggregateType, aggregateID = revoked.Aggregate()
+	if aggregateType != AggregateUser || aggregateID != "7" {
+		t.Fatalf("want: user/7; got: %s/%s", aggregateType, aggregateID)
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	ctx := context.Background()
+	bus := NewBus()
+
+	var calls []string
+	On(bus, "first", func(ctx context.Ctx, e *UserCreated) error {
+		calls = append(calls, "first")
+		return errors.New("first failed")
+	})
+	On(bus, "second", func(ctx context.Ctx, e *UserCreated) error {
+		calls = append(calls, "second")
+		return nil
+	})
+	On(bus, "deleted", func(ctx context.Ctx, e *UserDeleted) error {
+		calls = append(calls, "deleted")
+		return nil
+	})
+
+	if got := bus.Subscribers(NameUserCreated); len(got) != 2 {
+		t.Fatalf("want: 2 subscribers; got: %v", got)
+	}
+
+	err := bus.Dispatch(ctx, &UserCreated{User: &User.User{ID: 1}})
+	if err == nil || !strings.Contains(err.Error(), "subscriber first error: first failed") {
+		t.Fatalf("want: subscriber first error; got: err = %v", err)
+	}
+
+	// A failing handler does not stop the others.
+	if strings.Join(calls, ",") != "first,second" {
+		t.Fatalf("want: calls = first,second; got: calls = %v", calls)
+	}
+
+	if err := bus.Dispatch(ctx, &UserUpdated{User: &User.User{ID: 1}}); err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+}
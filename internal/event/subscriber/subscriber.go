@@ -0,0 +1,103 @@
+package subscriber
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errhandle"
+	"github.com/koraygocmen/golang-boilerplate/internal/event"
+	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
+	WebhookSubscription "github.com/koraygocmen/golang-boilerplate/internal/model/webhook_subscription"
+	"github.com/koraygocmen/golang-boilerplate/internal/realtime"
+	"github.com/koraygocmen/golang-boilerplate/internal/service"
+	"github.com/koraygocmen/golang-boilerplate/internal/slack"
+)
+
+const (
+	SubscriberSlack    = "slack"
+	SubscriberWebhook  = "webhook"
+	SubscriberRealtime = "realtime"
+)
+
+// Register subscribes the in process subscribers to the bus.
+func Register(bus *event.Bus) {
+	// Slack.
+	event.On(bus, SubscriberSlack, slackUserCreated)
+
+	// Webhooks.
+	event.On(bus, SubscriberWebhook, func(ctx context.Ctx, e *event.UserCreated) error {
+		return webhookPublish(ctx, WebhookSubscription.EventUserCreated, e.User)
+	})
+	event.On(bus, SubscriberWebhook, func(ctx context.Ctx, e *event.UserUpdated) error {
+		return webhookPublish(ctx, WebhookSubscription.EventUserUpdated, e.User)
+	})
+	event.On(bus, SubscriberWebhook, func(ctx context.Ctx, e *event.UserDeleted) error {
+		return webhookPublish(ctx, WebhookSubscription.EventUserDeleted, e.User)
+	})
+
+	// Realtime.
+	event.On(bus, SubscriberRealtime, func(ctx context.Ctx, e *event.UserUpdated) error {
+		return realtimePublish(ctx, &realtime.Event{
+			Name:   realtime.NameUserUpdated,
+			UserID: e.User.ID,
+			Data:   map[string]interface{}{"user": e.User},
+		})
+	})
+	event.On(bus, SubscriberRealtime, func(ctx context.Ctx, e *event.UserSessionRevoked) error {
+		return realtimePublish(ctx, &realtime.Event{
+			Name:           realtime.NameSessionRevoked,
+			UserID:         e.UserID,
+			UserSessionIDs: e.UserSessionIDs,
+		})
+	})
+}
+
+// slackUserCreated is best effort, a failed message is
+// reported without retrying the event for the others.
+func slackUserCreated(ctx context.Ctx, e *event.UserCreated) error {
+	if err := slack.Client.MessageEvent(ctx, "New user", e.User.Email.String); err != nil {
+		err = fmt.Errorf("slack user created subscriber error: %w", err)
+		errhandle.Handle(ctx, nil, err, false)
+	}
+	return nil
+}
+
+// webhookPublish queues the webhook deliveries of the event, in
+// the stored language of the user since there is no request.
+func webhookPublish(ctx context.Ctx, webhookEvent WebhookSubscription.Event, user *User.User) error {
+	// Create the service with a new transaction.
+	srv := service.Service.Transaction(ctx, 30*time.Second)
+
+	ctx, err := srv.UserPreference.V1.WithLocale(ctx, user.ID)
+	if err != nil {
+		err = fmt.Errorf("webhook subscriber error: %w", err)
+		srv.Rollback(err)
+		return err
+	}
+
+	if err := srv.Webhook.V1.Publish(ctx, webhookEvent, map[string]interface{}{"user": user}); err != nil {
+		err = fmt.Errorf("webhook subscriber error: %w", err)
+		srv.Rollback(err)
+		return err
+	}
+
+	if err := srv.Commit(); err != nil {
+		err = fmt.Errorf("webhook subscriber error: commit error: %w", err)
+		return err
+	}
+
+	return nil
+}
+
+// realtimePublish publishes the events to the streams of the replicas,
+// the events are published again if the event is retried.
+func realtimePublish(ctx context.Ctx, events ...*realtime.Event) error {
+	for _, e := range events {
+		if err := realtime.Default.Publish(ctx, e); err != nil {
+			err = fmt.Errorf("realtime subscriber error: %w", err)
+			return err
+		}
+	}
+	return nil
+}
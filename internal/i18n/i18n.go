@@ -0,0 +1,206 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var (
+	// Forms are the plural forms of the messages, declared before
+	// the catalog since they are only used through the unmarshaler.
+	forms = map[string]plural.Form{
+		"zero":  plural.Zero,
+		"one":   plural.One,
+		"two":   plural.Two,
+		"few":   plural.Few,
+		"many":  plural.Many,
+		"other": plural.Other,
+	}
+
+	// Fallback is the language of the messages
+	// missing in the catalog of the language.
+	Fallback = language.Turkish
+
+	// Messages is the catalog of the embedded locales.
+	Messages = MustLoad(locales, "locales", Fallback)
+)
+
+// Params are the placeholders of the message, the count
+// param selects the plural form of the message as well.
+type Params map[string]interface{}
+
+// ParamCount is the param the plural form is selected with.
+const ParamCount = "count"
+
+// message is either a single text or the texts
+// of the plural forms, the other form is required.
+type message map[plural.Form]string
+
+func (m *message) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*m = message{plural.Other: text}
+		return nil
+	}
+
+	var texts map[string]string
+	if err := json.Unmarshal(data, &texts); err != nil {
+		return fmt.Errorf("message must be a text or the texts of the plural forms")
+	}
+
+	*m = make(message, len(texts))
+	for name, text := range texts {
+		form, ok := forms[name]
+		if !ok {
+			return fmt.Errorf("plural form %s is unknown", name)
+		}
+		(*m)[form] = text
+	}
+
+	if _, ok := (*m)[plural.Other]; !ok {
+		return fmt.Errorf("plural form other is missing")
+	}
+	return nil
+}
+
+// Catalog is the messages of the languages, a language is
+// added with a json file named with the language tag.
+type Catalog struct {
+	fallback  language.Tag
+	languages []language.Tag
+	matcher   language.Matcher
+	messages  map[language.Tag]map[string]message
+}
+
+// Load loads the catalogs of the json files in the dir, the
+// file of the fallback language must exist.
+func Load(fsys fs.FS, dir string, fallback language.Tag) (*Catalog, error) {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("glob error: %w", err)
+	}
+
+	c := &Catalog{
+		fallback:  fallback,
+		languages: []language.Tag{fallback},
+		messages:  make(map[language.Tag]map[string]message, len(files)),
+	}
+
+	for _, file := range files {
+		tag, err := language.Parse(strings.TrimSuffix(path.Base(file), ".json"))
+		if err != nil {
+			return nil, fmt.Errorf("%s language error: %w", file, err)
+		}
+
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("%s read error: %w", file, err)
+		}
+
+		messages := make(map[string]message)
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("%s unmarshal error: %w", file, err)
+		}
+
+		c.messages[tag] = messages
+		if tag != fallback {
+			c.languages = append(c.languages, tag)
+		}
+	}
+
+	if _, ok := c.messages[fallback]; !ok {
+		return nil, fmt.Errorf("fallback language %s is missing", fallback)
+	}
+
+	// The fallback is the first language, the
+	// matcher returns it if nothing matches.
+	c.matcher = language.NewMatcher(c.languages)
+
+	return c, nil
+}
+
+// MustLoad is Load, it panics if the catalogs can not be loaded.
+func MustLoad(fsys fs.FS, dir string, fallback language.Tag) *Catalog {
+	c, err := Load(fsys, dir, fallback)
+	if err != nil {
+		panic(fmt.Sprintf("i18n load error: %v", err))
+	}
+	return c
+}
+
+// Languages returns the languages of the catalog, the fallback first.
+func (c *Catalog) Languages() []language.Tag {
+	return append([]language.Tag{}, c.languages...)
+}
+
+// Match returns the language of the catalog matching the accept
+// language header best with its q-values. The fallback is returned
+// and ok is false if the header is empty or nothing matches.
+func (c *Catalog) Match(acceptLanguage string) (tag language.Tag, ok bool) {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return c.fallback, false
+	}
+
+	_, index, confidence := c.matcher.Match(tags...)
+	if confidence == language.No {
+		return c.fallback, false
+	}
+	return c.languages[index], true
+}
+
+// Message returns the message of the key in the language with
+// the params. The fallback language is used if the message is
+// missing in the language, and the key if it is missing in both.
+func (c *Catalog) Message(tag language.Tag, key string, params Params) string {
+	m, ok := c.messages[tag][key]
+	if !ok {
+		tag = c.fallback
+		if m, ok = c.messages[tag][key]; !ok {
+			return key
+		}
+	}
+
+	text := m[plural.Other]
+	if count, ok := params[ParamCount]; ok {
+		if n, err := strconv.Atoi(fmt.Sprint(count)); err == nil {
+			if n < 0 {
+				n = -n
+			}
+			if t, ok := m[plural.Cardinal.MatchPlural(tag, n, 0, 0, 0, 0)]; ok {
+				text = t
+			}
+		}
+	}
+
+	for name, value := range params {
+		text = strings.ReplaceAll(text, "{"+name+"}", fmt.Sprint(value))
+	}
+	return text
+}
+
+// Missing returns the keys missing in the catalogs of each language.
+func (c *Catalog) Missing(keys []string) map[language.Tag][]string {
+	missing := make(map[language.Tag][]string)
+	for _, tag := range c.languages {
+		for _, key := range keys {
+			if _, ok := c.messages[tag][key]; !ok {
+				missing[tag] = append(missing[tag], key)
+			}
+		}
+		sort.Strings(missing[tag])
+	}
+	return missing
+}
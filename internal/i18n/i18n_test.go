@@ -0,0 +1,115 @@
+package i18n
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/text/language"
+)
+
+func TestLoad(t *testing.T) {
+	// Test the embedded catalogs have the messages of the fallback.
+	var keys []string
+	for key := range Messages.messages[Fallback] {
+		keys = append(keys, key)
+	}
+
+	for tag, missing := range Messages.Missing(keys) {
+		t.Fatalf("want: no missing messages; got: %s missing %v", tag, missing)
+	}
+
+	// Test the fallback is the first language.
+	if languages := Messages.Languages(); len(languages) != 4 || languages[0] != Fallback {
+		t.Fatalf("want: 4 languages with %s first; got: %v", Fallback, languages)
+	}
+
+	tests := []struct {
+		name string
+		file string
+	}{
+		{"fallback missing", ""},
+		{"invalid json", `{"notFound": `},
+		{"unknown form", `{"notFound": {"several": "text"}}`},
+		{"other form missing", `{"notFound": {"one": "text"}}`},
+	}
+
+	for _, test := range tests {
+		fsys := fstest.MapFS{
+			"locales/en.json": {Data: []byte(`{"notFound": "Not found."}`)},
+		}
+		if test.file != "" {
+			fsys["locales/tr.json"] = &fstest.MapFile{Data: []byte(test.file)}
+		}
+
+		if _, err := Load(fsys, "locales", language.Turkish); err == nil {
+			t.Fatalf("want: load err for %s; got: err nil", test.name)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           language.Tag
+		ok             bool
+	}{
+		{"", language.Turkish, false},
+		{"tr", language.Turkish, true},
+		{"en", language.English, true},
+		{"en-US,en;q=0.9", language.English, true},
+		{"de-CH", language.German, true},
+		{"ar-EG", language.Arabic, true},
+		{"fr", language.Turkish, false},
+		{"fr;q=0.9,de;q=0.8,en;q=0.5", language.German, true},
+		{"en;q=0.2,ar;q=0.8", language.Arabic, true},
+		{"invalid;q=x", language.Turkish, false},
+	}
+
+	for _, test := range tests {
+		if got, ok := Messages.Match(test.acceptLanguage); got != test.want || ok != test.ok {
+			t.Fatalf("want: %s, %t for %q; got: %s, %t", test.want, test.ok, test.acceptLanguage, got, ok)
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	c := MustLoad(fstest.MapFS{
+		"locales/tr.json": {Data: []byte(`{
+			"notFound": "Bulunamadı.",
+			"retry": "{count} saniye sonra tekrar dene."
+		}`)},
+		"locales/en.json": {Data: []byte(`{
+			"notFound": "Not found.",
+			"retry": {"one": "Try again in {count} second.", "other": "Try again in {count} seconds."}
+		}`)},
+		"locales/ar.json": {Data: []byte(`{
+			"retry": {"zero": "0", "one": "1", "two": "2", "few": "few {count}", "many": "many {count}", "other": "other {count}"}
+		}`)},
+	}, "locales", language.Turkish)
+
+	tests := []struct {
+		tag    language.Tag
+		key    string
+		params Params
+		want   string
+	}{
+		{language.English, "notFound", nil, "Not found."},
+		{language.Turkish, "notFound", nil, "Bulunamadı."},
+		{language.Arabic, "notFound", nil, "Bulunamadı."},
+		{language.English, "unknown", nil, "unknown"},
+		{language.English, "retry", Params{ParamCount: 1}, "Try again in 1 second."},
+		{language.English, "retry", Params{ParamCount: 5}, "Try again in 5 seconds."},
+		{language.Turkish, "retry", Params{ParamCount: 5}, "5 saniye sonra tekrar dene."},
+		{language.Arabic, "retry", Params{ParamCount: 0}, "0"},
+		{language.Arabic, "retry", Params{ParamCount: 2}, "2"},
+		{language.Arabic, "retry", Params{ParamCount: 5}, "few 5"},
+		{language.Arabic, "retry", Params{ParamCount: 11}, "many 11"},
+		{language.Arabic, "retry", Params{ParamCount: 100}, "other 100"},
+	}
+
+	for _, test := range tests {
+		if got := c.Message(test.tag, test.key, test.params); got != test.want {
+			t.Fatalf("want: %q for %s %s %v; got: %q", test.want, test.tag, test.key, test.params, got)
+		}
+	}
+}
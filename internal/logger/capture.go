@@ -0,0 +1,147 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/pkg/redact"
+)
+
+const (
+	// captureMaxBytesDefault is the captured body size if not configured.
+	captureMaxBytesDefault = 4096
+	// captureParseLimit is the largest body that is decoded for
+	// redaction, larger bodies are replaced with a placeholder.
+	captureParseLimit = 1 << 20
+)
+
+var (
+	// defaultRedactor is used by the zero Writer.
+	defaultRedactor = redact.New(redact.Config{})
+)
+
+type capture struct {
+	enabled  bool
+	maxBytes int
+	redactor *redact.Redactor
+}
+
+func newCapture(config Config) *capture {
+	c := &capture{
+		enabled:  config.Body.Capture,
+		maxBytes: config.Body.MaxBytes,
+		redactor: redact.New(redact.Config{
+			Fields: splitList(config.Redact.Fields),
+			Paths:  splitList(config.Redact.Paths),
+		}),
+	}
+
+	if c.maxBytes <= 0 {
+		c.maxBytes = captureMaxBytesDefault
+	}
+
+	return c
+}
+
+func (l *Writer) redactor() *redact.Redactor {
+	if l.capture == nil {
+		return defaultRedactor
+	}
+	return l.capture.redactor
+}
+
+// CaptureBody adds the redacted body to the context under the key,
+// context.KeyReqBody or context.KeyResBody. It is a no-op unless
+// body capture is enabled.
+func (l *Writer) CaptureBody(ctx context.Ctx, key context.ContextKey, contentType string, body []byte) context.Ctx {
+	if l.capture == nil || !l.capture.enabled || len(body) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, key, l.capture.body(contentType, body))
+}
+
+// CaptureHeaders adds the request headers to the context with
+// sensitive ones such as Authorization masked. It is a no-op
+// unless body capture is enabled.
+func (l *Writer) CaptureHeaders(ctx context.Ctx, headers map[string][]string) context.Ctx {
+	if l.capture == nil || !l.capture.enabled || len(headers) == 0 {
+		return ctx
+	}
+
+	m := make(map[string]string, len(headers))
+	for key, values := range headers {
+		m[key] = l.capture.redactor.Header(key, strings.Join(values, ", "))
+	}
+
+	marshalled, _ := json.Marshal(m)
+	return context.WithValue(ctx, context.KeyReqHeaders, json.RawMessage(marshalled))
+}
+
+func (c *capture) body(contentType string, body []byte) json.RawMessage {
+	if len(body) > captureParseLimit {
+		return c.placeholder("body too large", len(body))
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	var (
+		redacted json.RawMessage
+		err      error
+	)
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		redacted, err = c.redactor.JSON(body)
+	case mediaType == "application/x-www-form-urlencoded":
+		redacted, err = c.form(body)
+	default:
+		// Other bodies can't be redacted, don't log them.
+		return c.placeholder(fmt.Sprintf("body not captured, content type %q", mediaType), len(body))
+	}
+
+	if err != nil {
+		return c.placeholder("body not captured, malformed", len(body))
+	}
+
+	if len(redacted) > c.maxBytes {
+		// Truncated JSON is no longer valid, log it as a string.
+		marshalled, _ := json.Marshal(fmt.Sprintf("%s...(truncated %d bytes)", redacted[:c.maxBytes], len(redacted)-c.maxBytes))
+		return marshalled
+	}
+
+	return redacted
+}
+
+func (c *capture) form(body []byte) (json.RawMessage, error) {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]interface{}, len(values))
+	for key, vals := range values {
+		m[key] = strings.Join(vals, ",")
+	}
+
+	return json.Marshal(c.redactor.Value(m))
+}
+
+func (c *capture) placeholder(reason string, size int) json.RawMessage {
+	marshalled, _ := json.Marshal(fmt.Sprintf("[%s, %d bytes]", reason, size))
+	return marshalled
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
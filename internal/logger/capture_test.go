@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+)
+
+func TestCaptureBody(t *testing.T) {
+	config := Config{Mode: string(ModeNone)}
+	config.Body.Capture = true
+	config.Body.MaxBytes = 64
+	config.Redact.Paths = "card.number"
+
+	l, err := New(config)
+	if err != nil {
+		t.Fatalf("want: new err nil; got: %v", err)
+	}
+
+	tests := []struct {
+		contentType string
+		body        string
+		want        string
+	}{
+		{
+			contentType: "application/json; charset=utf-8",
+			body:        `{"email":"a@b.com","password":"secret","card":{"number":"4111"}}`,
+			want:        `{"card":{"number":"***"},"email":"***","password":"***"}`,
+		},
+		{
+			contentType: "application/x-www-form-urlencoded",
+			body:        "email=a%40b.com&givenNames=Ada",
+			want:        `{"email":"***","givenNames":"Ada"}`,
+		},
+		{
+			contentType: "application/json",
+			body:        `{"givenNames":"` + strings.Repeat("a", 100) + `"}`,
+			want:        `"{\"givenNames\":\"` + strings.Repeat("a", 49) + `...(truncated 53 bytes)"`,
+		},
+		{
+			contentType: "application/json",
+			body:        `{"email":`,
+			want:        `"[body not captured, malformed, 9 bytes]"`,
+		},
+		{
+			contentType: "text/plain",
+			body:        "password",
+			want:        `"[body not captured, content type \"text/plain\", 8 bytes]"`,
+		},
+	}
+
+	for _, test := range tests {
+		ctx := l.CaptureBody(context.Background(), context.KeyReqBody, test.contentType, []byte(test.body))
+
+		got, _ := ctx.Value(context.KeyReqBody).(json.RawMessage)
+		if string(got) != test.want {
+			t.Fatalf("want: %s; got: %s", test.want, got)
+		}
+	}
+
+	ctx := l.CaptureHeaders(context.Background(), map[string][]string{
+		"Authorization":   {"1-token"},
+		"Accept-Language": {"en"},
+	})
+
+	want := `{"Accept-Language":"en","Authorization":"***"}`
+	if got, _ := ctx.Value(context.KeyReqHeaders).(json.RawMessage); string(got) != want {
+		t.Fatalf("want: %s; got: %s", want, got)
+	}
+}
+
+func TestCaptureDisabled(t *testing.T) {
+	l, err := New(Config{Mode: string(ModeNone)})
+	if err != nil {
+		t.Fatalf("want: new err nil; got: %v", err)
+	}
+
+	ctx := l.CaptureBody(context.Background(), context.KeyReqBody, "application/json", []byte(`{}`))
+	if got := ctx.Value(context.KeyReqBody); got != nil {
+		t.Fatalf("want: nil; got: %v", got)
+	}
+}
+
+func TestParamsFilter(t *testing.T) {
+	sql := `SELECT * FROM "user" WHERE "email" = $1`
+
+	// The zero Writer redacts with the default fields.
+	_, params := (&Writer{}).ParamsFilter(context.Background(), sql, "a@b.com")
+	if len(params) != 1 || params[0] != "***" {
+		t.Fatalf("want: [***]; got: %v", params)
+	}
+}
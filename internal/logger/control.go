@@ -0,0 +1,332 @@
+package logger
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// levelUnset marks that the global level is not overridden
+// and each sink uses its own configured level.
+const levelUnset = -1
+
+// RouteLevel overrides the log level of the requests
+// matching the route. Route is either an exact path or
+// a prefix ending with "*", i.e. "/v1/users*".
+type RouteLevel struct {
+	Route string `json:"route"`
+	Level int    `json:"level"`
+}
+
+// SampleRule keeps the given ratio of the request logs
+// matching the route and the status. Route is matched
+// like RouteLevel.Route, status is either "*", a status
+// class like "5xx" or an exact status like "404".
+type SampleRule struct {
+	Route  string  `json:"route"`
+	Status string  `json:"status"`
+	Rate   float64 `json:"rate"`
+}
+
+// control holds the logging settings that can be changed at
+// runtime. It is shared by the copies of the writer.
+type control struct {
+	level int64
+
+	lock        *sync.RWMutex
+	routeLevels []RouteLevel
+	sampleRules []SampleRule
+}
+
+func newControl() *control {
+	return &control{
+		level: levelUnset,
+		lock:  &sync.RWMutex{},
+	}
+}
+
+// SetLevel overrides the level of all sinks.
+func (l *Writer) SetLevel(level int) error {
+	if l.control == nil {
+		return fmt.Errorf("set level error: logger is not initialized")
+	}
+
+	if err := validateLevel(level); err != nil {
+		err = fmt.Errorf("set level error: %w", err)
+		return err
+	}
+
+	atomic.StoreInt64(&l.control.level, int64(level))
+	return nil
+}
+
+// ResetLevel removes the level override, each
+// sink goes back to its configured level.
+func (l *Writer) ResetLevel() {
+	if l.control == nil {
+		return
+	}
+
+	atomic.StoreInt64(&l.control.level, levelUnset)
+}
+
+// Level returns the global level override if set.
+func (l *Writer) Level() (int, bool) {
+	if l.control == nil {
+		return 0, false
+	}
+
+	level := atomic.LoadInt64(&l.control.level)
+	return int(level), level != levelUnset
+}
+
+func (l *Writer) SetRouteLevels(routeLevels []RouteLevel) error {
+	if l.control == nil {
+		return fmt.Errorf("set route levels error: logger is not initialized")
+	}
+
+	for _, routeLevel := range routeLevels {
+		if err := validateRoute(routeLevel.Route); err != nil {
+			err = fmt.Errorf("set route levels error: %w", err)
+			return err
+		}
+
+		if err := validateLevel(routeLevel.Level); err != nil {
+			err = fmt.Errorf("set route levels error: %w", err)
+			return err
+		}
+	}
+
+	l.control.lock.Lock()
+	defer l.control.lock.Unlock()
+
+	l.control.routeLevels = append([]RouteLevel{}, routeLevels...)
+	return nil
+}
+
+func (l *Writer) RouteLevels() []RouteLevel {
+	if l.control == nil {
+		return []RouteLevel{}
+	}
+
+	l.control.lock.RLock()
+	defer l.control.lock.RUnlock()
+
+	return append([]RouteLevel{}, l.control.routeLevels...)
+}
+
+// RouteLevel returns the level of the first
+// route level matching the path.
+func (l *Writer) RouteLevel(path string) (int, bool) {
+	if l.control == nil {
+		return 0, false
+	}
+
+	l.control.lock.RLock()
+	defer l.control.lock.RUnlock()
+
+	for _, routeLevel := range l.control.routeLevels {
+		if matchRoute(routeLevel.Route, path) {
+			return routeLevel.Level, true
+		}
+	}
+
+	return 0, false
+}
+
+func (l *Writer) SetSampleRules(sampleRules []SampleRule) error {
+	if l.control == nil {
+		return fmt.Errorf("set sample rules error: logger is not initialized")
+	}
+
+	for _, sampleRule := range sampleRules {
+		if err := validateRoute(sampleRule.Route); err != nil {
+			err = fmt.Errorf("set sample rules error: %w", err)
+			return err
+		}
+
+		if err := validateStatus(sampleRule.Status); err != nil {
+			err = fmt.Errorf("set sample rules error: %w", err)
+			return err
+		}
+
+		if sampleRule.Rate < 0 || sampleRule.Rate > 1 {
+			err := fmt.Errorf("set sample rules error: rate must be between 0 and 1: %v", sampleRule.Rate)
+			return err
+		}
+	}
+
+	l.control.lock.Lock()
+	defer l.control.lock.Unlock()
+
+	l.control.sampleRules = append([]SampleRule{}, sampleRules...)
+	return nil
+}
+
+func (l *Writer) SampleRules() []SampleRule {
+	if l.control == nil {
+		return []SampleRule{}
+	}
+
+	l.control.lock.RLock()
+	defer l.control.lock.RUnlock()
+
+	return append([]SampleRule{}, l.control.sampleRules...)
+}
+
+// Sample reports whether the request log should be kept
+// according to the first rule matching the path and status.
+// Request logs not matching any rule are kept.
+func (l *Writer) Sample(path string, status int) bool {
+	if l.control == nil {
+		return true
+	}
+
+	l.control.lock.RLock()
+	defer l.control.lock.RUnlock()
+
+	for _, sampleRule := range l.control.sampleRules {
+		if !matchRoute(sampleRule.Route, path) || !matchStatus(sampleRule.Status, status) {
+			continue
+		}
+
+		switch {
+		case sampleRule.Rate >= 1:
+			return true
+		case sampleRule.Rate <= 0:
+			return false
+		default:
+			return rand.Float64() < sampleRule.Rate
+		}
+	}
+
+	return true
+}
+
+// ParseRouteLevels parses a comma separated list of route
+// levels in the form of ROUTE:LEVEL, i.e. "/v1/users*:debug".
+func ParseRouteLevels(routeLevels string) ([]RouteLevel, error) {
+	var parsed []RouteLevel
+
+	for _, spec := range strings.Split(routeLevels, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		pieces := strings.Split(spec, ":")
+		if len(pieces) != 2 {
+			err := fmt.Errorf("parse route levels error: invalid route level: %s", spec)
+			return nil, err
+		}
+
+		level, err := ToLevel(pieces[1])
+		if err != nil {
+			err = fmt.Errorf("parse route levels error: %w", err)
+			return nil, err
+		}
+
+		parsed = append(parsed, RouteLevel{
+			Route: strings.TrimSpace(pieces[0]),
+			Level: level,
+		})
+	}
+
+	return parsed, nil
+}
+
+// ParseSampleRules parses a comma separated list of sample
+// rules in the form of ROUTE:STATUS:RATE where route and
+// status can be "*", i.e. "/metrics:*:0,*:5xx:1,*:2xx:0.1".
+func ParseSampleRules(sampleRules string) ([]SampleRule, error) {
+	var parsed []SampleRule
+
+	for _, spec := range strings.Split(sampleRules, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		pieces := strings.Split(spec, ":")
+		if len(pieces) != 3 {
+			err := fmt.Errorf("parse sample rules error: invalid sample rule: %s", spec)
+			return nil, err
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(pieces[2]), 64)
+		if err != nil {
+			err = fmt.Errorf("parse sample rules error: invalid rate: %s", pieces[2])
+			return nil, err
+		}
+
+		parsed = append(parsed, SampleRule{
+			Route:  strings.TrimSpace(pieces[0]),
+			Status: strings.ToLower(strings.TrimSpace(pieces[1])),
+			Rate:   rate,
+		})
+	}
+
+	return parsed, nil
+}
+
+func matchRoute(route, path string) bool {
+	if route == "*" {
+		return true
+	}
+
+	if prefix, ok := strings.CutSuffix(route, "*"); ok {
+		return strings.HasPrefix(path, prefix)
+	}
+
+	return route == path
+}
+
+func matchStatus(pattern string, status int) bool {
+	if pattern == "*" {
+		return true
+	}
+
+	if class, ok := strings.CutSuffix(pattern, "xx"); ok {
+		return strconv.Itoa(status/100) == class
+	}
+
+	return pattern == strconv.Itoa(status)
+}
+
+func validateLevel(level int) error {
+	if level < LevelFatal || level > LevelDebug {
+		err := fmt.Errorf("level must be between %d and %d: %d", LevelFatal, LevelDebug, level)
+		return err
+	}
+
+	return nil
+}
+
+func validateRoute(route string) error {
+	if route != "*" && !strings.HasPrefix(route, "/") {
+		err := fmt.Errorf("route must be \"*\" or start with \"/\": %s", route)
+		return err
+	}
+
+	return nil
+}
+
+func validateStatus(status string) error {
+	if status == "*" {
+		return nil
+	}
+
+	if class, ok := strings.CutSuffix(status, "xx"); ok {
+		if len(class) == 1 && class >= "1" && class <= "5" {
+			return nil
+		}
+	} else if code, err := strconv.Atoi(status); err == nil && code >= 100 && code <= 599 {
+		return nil
+	}
+
+	err := fmt.Errorf("status must be \"*\", a status class or a status code: %s", status)
+	return err
+}
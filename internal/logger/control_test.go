@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+)
+
+func TestParseRouteLevels(t *testing.T) {
+	got, err := ParseRouteLevels("/v1/users*:debug, /health:4")
+	if err != nil {
+		t.Fatalf("want: parse route levels err nil; got: %v", err)
+	}
+
+	want := []RouteLevel{
+		{Route: "/v1/users*", Level: LevelDebug},
+		{Route: "/health", Level: LevelError},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("want: len(route levels) = %d; got: %d", len(want), len(got))
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("want: route levels[%d] = %+v; got: %+v", i, want[i], got[i])
+		}
+	}
+
+	if _, err := ParseRouteLevels("/v1/users"); err == nil {
+		t.Fatalf("want: parse route levels err not nil; got: nil")
+	}
+}
+
+func TestSample(t *testing.T) {
+	l, err := New(Config{
+		Mode:     string(ModeNone),
+		Sampling: "/metrics:*:0,*:5xx:1,/v1/users*:404:0,*:2xx:0",
+	})
+	if err != nil {
+		t.Fatalf("want: new err nil; got: %v", err)
+	}
+
+	cases := []struct {
+		path   string
+		status int
+		want   bool
+	}{
+		{"/metrics", 500, false},
+		{"/v1/users", 500, true},
+		{"/v1/users", 200, false},
+		{"/v1/users/sessions", 404, false},
+		{"/v1/other", 404, true},
+		{"/v1/users", 301, true},
+	}
+
+	for _, c := range cases {
+		if got := l.Sample(c.path, c.status); got != c.want {
+			t.Fatalf("want: sample(%s, %d) = %t; got: %t", c.path, c.status, c.want, got)
+		}
+	}
+
+	for _, invalid := range []SampleRule{
+		{Route: "metrics", Status: "*", Rate: 1},
+		{Route: "*", Status: "6xx", Rate: 1},
+		{Route: "*", Status: "*", Rate: 2},
+	} {
+		if err := l.SetSampleRules([]SampleRule{invalid}); err == nil {
+			t.Fatalf("want: set sample rules %+v err not nil; got: nil", invalid)
+		}
+	}
+}
+
+func TestThreshold(t *testing.T) {
+	errorSink := &sinkTest{}
+
+	l := &Writer{
+		level:   LevelError,
+		control: newControl(),
+	}
+	l.sinks = []*sink{newSink(ModeConsole, LevelError, FormatText, errorSink.write, nil)}
+
+	ctx := context.Background()
+	l.Debugf(ctx, "dropped")
+
+	// Global level override.
+	if err := l.SetLevel(LevelDebug); err != nil {
+		t.Fatalf("want: set level err nil; got: %v", err)
+	}
+	l.Debugf(ctx, "global")
+
+	if err := l.SetLevel(LevelDebug + 1); err == nil {
+		t.Fatalf("want: set level err not nil; got: nil")
+	}
+
+	l.ResetLevel()
+	if _, ok := l.Level(); ok {
+		t.Fatalf("want: level not set; got: set")
+	}
+
+	// Route level override.
+	if err := l.SetRouteLevels([]RouteLevel{{Route: "/v1/users*", Level: LevelDebug}}); err != nil {
+		t.Fatalf("want: set route levels err nil; got: %v", err)
+	}
+
+	level, ok := l.RouteLevel("/v1/users/sessions")
+	if !ok || level != LevelDebug {
+		t.Fatalf("want: route level = %d; got: %d, %t", LevelDebug, level, ok)
+	}
+
+	if _, ok := l.RouteLevel("/health"); ok {
+		t.Fatalf("want: no route level for /health; got: route level")
+	}
+
+	l.Debugf(context.WithValue(ctx, context.KeyLogRouteLevel, level), "route")
+
+	l.Close()
+
+	if got := errorSink.get(); len(got) != 2 {
+		t.Fatalf("want: 2 messages; got: %v", got)
+	}
+}
@@ -0,0 +1,361 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	fileBackupTimeFormat = "2006-01-02T15-04-05.000"
+	fileCompressExt      = ".gz"
+)
+
+type FileConfig struct {
+	Path string
+
+	// MaxSizeMB rotates the file when it grows past the size.
+	// Zero disables size based rotation.
+	MaxSizeMB int
+
+	// RotateHours rotates the file after being open for the
+	// given number of hours. Zero disables time based rotation.
+	RotateHours int
+
+	// MaxBackups is the number of rotated files to keep.
+	// Zero keeps all of them.
+	MaxBackups int
+
+	// Compress gzips the rotated files.
+	Compress bool
+}
+
+// fileWriter is an io.Writer that writes to a file and rotates it
+// by size or time. It reopens the file on SIGHUP, so the file can
+// be moved by an external tool such as logrotate.
+type fileWriter struct {
+	config FileConfig
+
+	file     *os.File
+	size     int64
+	rotateAt time.Time
+
+	signals chan os.Signal
+	lock    *sync.Mutex
+	mill    *sync.Mutex
+	wg      *sync.WaitGroup
+}
+
+func newFileWriter(config FileConfig) (*fileWriter, error) {
+	if config.Path == "" {
+		err := fmt.Errorf("file writer error: path is empty")
+		return nil, err
+	}
+
+	f := &fileWriter{
+		config:  config,
+		signals: make(chan os.Signal, 1),
+		lock:    &sync.Mutex{},
+		mill:    &sync.Mutex{},
+		wg:      &sync.WaitGroup{},
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	signal.Notify(f.signals, syscall.SIGHUP)
+	go func() {
+		for range f.signals {
+			// There is nowhere to report the error to,
+			// writes fail until the file can be opened.
+			_ = f.Reopen()
+		}
+	}()
+
+	return f, nil
+}
+
+func (f *fileWriter) Write(p []byte) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if f.shouldRotate(len(p)) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Reopen closes and reopens the file at the configured
+// path without rotating it.
+func (f *fileWriter) Reopen() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if err := f.closeFile(); err != nil {
+		return err
+	}
+
+	return f.open()
+}
+
+func (f *fileWriter) Close() error {
+	signal.Stop(f.signals)
+	close(f.signals)
+
+	f.lock.Lock()
+	err := f.closeFile()
+	f.lock.Unlock()
+
+	// Wait for the compression of rotated files.
+	f.wg.Wait()
+
+	return err
+}
+
+func (f *fileWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.config.Path), 0755); err != nil {
+		err = fmt.Errorf("file writer open error: mkdir error: %w", err)
+		return err
+	}
+
+	file, err := os.OpenFile(f.config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		err = fmt.Errorf("file writer open error: %w", err)
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		err = fmt.Errorf("file writer open error: stat error: %w", err)
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.rotateAt = time.Time{}
+	if f.config.RotateHours > 0 {
+		f.rotateAt = time.Now().Add(time.Duration(f.config.RotateHours) * time.Hour)
+	}
+
+	return nil
+}
+
+func (f *fileWriter) closeFile() error {
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+	if err != nil {
+		err = fmt.Errorf("file writer close error: %w", err)
+		return err
+	}
+
+	return nil
+}
+
+func (f *fileWriter) shouldRotate(n int) bool {
+	// Do not rotate an empty file, even if a single
+	// message is larger than the max size.
+	if f.size == 0 {
+		return false
+	}
+
+	maxSize := int64(f.config.MaxSizeMB) * 1024 * 1024
+	if maxSize > 0 && f.size+int64(n) > maxSize {
+		return true
+	}
+
+	return !f.rotateAt.IsZero() && !time.Now().Before(f.rotateAt)
+}
+
+// rotate moves the current file to a timestamped backup
+// and opens a new file at the configured path.
+func (f *fileWriter) rotate() error {
+	if err := f.closeFile(); err != nil {
+		return err
+	}
+
+	// Backups are named by the rotation time, make sure
+	// a quick succession of rotations does not overwrite one.
+	t := time.Now().UTC()
+	backup := f.backupName(t)
+	for fileExists(backup) || fileExists(backup+fileCompressExt) {
+		t = t.Add(time.Millisecond)
+		backup = f.backupName(t)
+	}
+
+	if err := os.Rename(f.config.Path, backup); err != nil && !os.IsNotExist(err) {
+		err = fmt.Errorf("file writer rotate error: rename error: %w", err)
+		return err
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		f.millRun(backup)
+	}()
+
+	return nil
+}
+
+// millRun compresses the backup if enabled and removes
+// the backups exceeding the max backups.
+func (f *fileWriter) millRun(backup string) {
+	f.mill.Lock()
+	defer f.mill.Unlock()
+
+	if f.config.Compress {
+		// Keep the uncompressed backup if compression fails,
+		// it is still subject to the max backups.
+		_ = compressFile(backup)
+	}
+
+	if f.config.MaxBackups <= 0 {
+		return
+	}
+
+	backups, err := f.backups()
+	if err != nil {
+		return
+	}
+
+	for i := 0; i < len(backups)-f.config.MaxBackups; i++ {
+		_ = os.Remove(backups[i])
+	}
+}
+
+func (f *fileWriter) backupName(t time.Time) string {
+	dir, name, ext := f.split()
+	return filepath.Join(dir, fmt.Sprintf("%s-%s%s", name, t.Format(fileBackupTimeFormat), ext))
+}
+
+// backups returns the rotated files, oldest first.
+func (f *fileWriter) backups() ([]string, error) {
+	dir, name, ext := f.split()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		err = fmt.Errorf("file writer backups error: read dir error: %w", err)
+		return nil, err
+	}
+
+	type backup struct {
+		path string
+		t    time.Time
+	}
+
+	var backups []backup
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		ts := strings.TrimSuffix(entry.Name(), fileCompressExt)
+		if !strings.HasPrefix(ts, name+"-") || !strings.HasSuffix(ts, ext) {
+			continue
+		}
+		ts = strings.TrimSuffix(strings.TrimPrefix(ts, name+"-"), ext)
+
+		t, err := time.Parse(fileBackupTimeFormat, ts)
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, backup{path: filepath.Join(dir, entry.Name()), t: t})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].t.Before(backups[j].t)
+	})
+
+	paths := make([]string, len(backups))
+	for i, b := range backups {
+		paths[i] = b.path
+	}
+
+	return paths, nil
+}
+
+func (f *fileWriter) split() (dir, name, ext string) {
+	dir = filepath.Dir(f.config.Path)
+	base := filepath.Base(f.config.Path)
+	ext = filepath.Ext(base)
+	name = strings.TrimSuffix(base, ext)
+	return dir, name, ext
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		err = fmt.Errorf("compress file error: open error: %w", err)
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+fileCompressExt, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		err = fmt.Errorf("compress file error: open gzip error: %w", err)
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		gz.Close()
+		dst.Close()
+		os.Remove(path + fileCompressExt)
+		err = fmt.Errorf("compress file error: copy error: %w", err)
+		return err
+	}
+
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + fileCompressExt)
+		err = fmt.Errorf("compress file error: gzip close error: %w", err)
+		return err
+	}
+
+	if err := dst.Close(); err != nil {
+		os.Remove(path + fileCompressExt)
+		err = fmt.Errorf("compress file error: close error: %w", err)
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		err = fmt.Errorf("compress file error: remove error: %w", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestFileWriterCreate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "dir", "api.log")
+
+	f, err := newFileWriter(FileConfig{Path: path})
+	if err != nil {
+		t.Fatalf("want: new file writer err nil; got: %v", err)
+	}
+
+	if _, err := f.Write([]byte("message\n")); err != nil {
+		t.Fatalf("want: write err nil; got: %v", err)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatalf("want: close err nil; got: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("want: read file err nil; got: %v", err)
+	}
+
+	if string(content) != "message\n" {
+		t.Fatalf("want: content = %q; got: %q", "message\n", content)
+	}
+}
+
+func TestFileWriterRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.log")
+
+	f, err := newFileWriter(FileConfig{
+		Path:       path,
+		MaxSizeMB:  1,
+		MaxBackups: 2,
+		Compress:   true,
+	})
+	if err != nil {
+		t.Fatalf("want: new file writer err nil; got: %v", err)
+	}
+
+	// Each write is more than half of the max size,
+	// so every write after the first one rotates.
+	chunk := bytes.Repeat([]byte("a"), 600*1024)
+	for i := 0; i < 4; i++ {
+		if _, err := f.Write(chunk); err != nil {
+			t.Fatalf("want: write err nil; got: %v", err)
+		}
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatalf("want: close err nil; got: %v", err)
+	}
+
+	backups, err := f.backups()
+	if err != nil {
+		t.Fatalf("want: backups err nil; got: %v", err)
+	}
+
+	if len(backups) != 2 {
+		t.Fatalf("want: 2 backups; got: %v", backups)
+	}
+
+	for _, backup := range backups {
+		if !strings.HasSuffix(backup, fileCompressExt) {
+			t.Fatalf("want: compressed backup; got: %s", backup)
+		}
+
+		file, err := os.Open(backup)
+		if err != nil {
+			t.Fatalf("want: open backup err nil; got: %v", err)
+		}
+
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatalf("want: gzip reader err nil; got: %v", err)
+		}
+
+		content, err := io.ReadAll(gz)
+		file.Close()
+		if err != nil {
+			t.Fatalf("want: read backup err nil; got: %v", err)
+		}
+
+		if !bytes.Equal(content, chunk) {
+			t.Fatalf("want: backup content len = %d; got: %d", len(chunk), len(content))
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("want: stat err nil; got: %v", err)
+	}
+
+	if info.Size() != int64(len(chunk)) {
+		t.Fatalf("want: file size = %d; got: %d", len(chunk), info.Size())
+	}
+}
+
+func TestFileWriterRotateTime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.log")
+
+	f, err := newFileWriter(FileConfig{Path: path, RotateHours: 1})
+	if err != nil {
+		t.Fatalf("want: new file writer err nil; got: %v", err)
+	}
+	defer f.Close()
+
+	f.Write([]byte("first\n"))
+
+	// Pretend the rotation time has passed.
+	f.lock.Lock()
+	f.rotateAt = time.Now().Add(-time.Second)
+	f.lock.Unlock()
+
+	f.Write([]byte("second\n"))
+
+	backups, err := f.backups()
+	if err != nil {
+		t.Fatalf("want: backups err nil; got: %v", err)
+	}
+
+	if len(backups) != 1 {
+		t.Fatalf("want: 1 backup; got: %v", backups)
+	}
+}
+
+func TestFileWriterReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.log")
+
+	f, err := newFileWriter(FileConfig{Path: path})
+	if err != nil {
+		t.Fatalf("want: new file writer err nil; got: %v", err)
+	}
+	defer f.Close()
+
+	f.Write([]byte("before\n"))
+
+	// Move the file the way logrotate does and signal the process.
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatalf("want: rename err nil; got: %v", err)
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatalf("want: kill err nil; got: %v", err)
+	}
+
+	// Wait for the file to be reopened.
+	deadline := time.Now().Add(5 * time.Second)
+	for !fileExists(path) {
+		if time.Now().After(deadline) {
+			t.Fatalf("want: file reopened; got: file missing")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	f.Write([]byte("after\n"))
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("want: read file err nil; got: %v", err)
+	}
+
+	if string(content) != "after\n" {
+		t.Fatalf("want: content = %q; got: %q", "after\n", content)
+	}
+}
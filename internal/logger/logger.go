@@ -3,7 +3,6 @@ package logger
 import (
 	"errors"
 	"fmt"
-	"io"
 	"log"
 	"log/syslog"
 	"os"
@@ -11,6 +10,7 @@ import (
 
 	"github.com/koraygocmen/golang-boilerplate/internal/aws"
 	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/pkg/duration"
 )
 
 type Config struct {
@@ -18,31 +18,55 @@ type Config struct {
 	Mode   string
 	SHASUM string
 
+	// Sinks are additional log outputs next to the one
+	// configured by Mode and Level, in the form accepted
+	// by ParseSinks.
+	Sinks string
+
+	// RouteLevels and Sampling are the initial runtime settings,
+	// in the form accepted by ParseRouteLevels and ParseSampleRules.
+	RouteLevels string
+	Sampling    string
+
+	// Body enables request and response body capture,
+	// bodies longer than MaxBytes are truncated.
+	Body struct {
+		Capture  bool
+		MaxBytes int
+	}
+
+	// Redact configures the fields and JSON paths masked in captured
+	// bodies, headers and SQL params as comma separated lists.
+	Redact struct {
+		Fields string
+		Paths  string
+	}
+
 	Syslog struct {
 		Addr     string
 		Protocol string
 		Tag      string
 	}
 
-	File struct {
-		Path string
-	}
+	File FileConfig
 
 	AWS struct {
 		LogGroup struct {
 			Name   string
 			Region string
 		}
+		BufferSize       int
+		FlushIntervalSec int
 	}
 }
 
 type Writer struct {
-	mode  Mode
-	level int
-
-	syslogger        *syslog.Writer
-	filelogger       *log.Logger
-	cloudwatchStream *aws.Stream
+	// level is the highest level of all the sinks,
+	// messages above it are discarded right away.
+	level   int
+	sinks   []*sink
+	control *control
+	capture *capture
 }
 
 var (
@@ -53,28 +77,115 @@ var (
 )
 
 func New(config Config) (*Writer, error) {
+	sinkConfigs := []SinkConfig{{
+		Mode:  config.Mode,
+		Level: config.Level,
+	}}
+
+	extraSinkConfigs, err := ParseSinks(config.Sinks)
+	if err != nil {
+		err = fmt.Errorf("failed to parse log sinks: %w", err)
+		return nil, err
+	}
+	sinkConfigs = append(sinkConfigs, extraSinkConfigs...)
+
+	// The file sinks would all write to the file path,
+	// the rotations of one would move the file of the other.
+	var fileSinks int
+	for _, sinkConfig := range sinkConfigs {
+		if ToMode(sinkConfig.Mode) == ModeFile {
+			fileSinks++
+		}
+	}
+	if fileSinks > 1 {
+		return nil, errors.New("only one file log output can be specified")
+	}
+
+	logger := &Writer{
+		control: newControl(),
+		capture: newCapture(config),
+	}
+
+	routeLevels, err := ParseRouteLevels(config.RouteLevels)
+	if err != nil {
+		err = fmt.Errorf("failed to parse log route levels: %w", err)
+		return nil, err
+	}
+
+	if err := logger.SetRouteLevels(routeLevels); err != nil {
+		return nil, err
+	}
+
+	sampleRules, err := ParseSampleRules(config.Sampling)
+	if err != nil {
+		err = fmt.Errorf("failed to parse log sampling: %w", err)
+		return nil, err
+	}
+
+	if err := logger.SetSampleRules(sampleRules); err != nil {
+		return nil, err
+	}
+
+	for _, sinkConfig := range sinkConfigs {
+		s, err := newSinkFromConfig(config, sinkConfig)
+		if err != nil {
+			logger.Close()
+			return nil, err
+		}
+
+		// Nothing to write to.
+		if s == nil {
+			continue
+		}
+
+		if s.level > logger.level {
+			logger.level = s.level
+		}
+		logger.sinks = append(logger.sinks, s)
+	}
+
+	if ToMode(config.Mode) != ModeNone && len(logger.sinks) == 0 {
+		return nil, errors.New("no log output specified")
+	}
+
+	return logger, nil
+}
+
+func newSinkFromConfig(config Config, sinkConfig SinkConfig) (*sink, error) {
 	var (
-		file             *os.File
-		filelogger       *log.Logger
-		syslogger        *syslog.Writer
-		cloudwatchStream *aws.Stream
+		mode   = ToMode(sinkConfig.Mode)
+		level  = sinkConfig.Level
+		format = ToFormat(sinkConfig.Format)
 	)
 
-	mode := ToMode(config.Mode)
 	switch mode {
 	case ModeConsole:
 		{
-			file = os.Stdout
+			consolelogger := log.New(os.Stdout, "", log.LstdFlags|log.Lmicroseconds)
+
+			write := func(p []byte) error {
+				consolelogger.Println(string(p))
+				return nil
+			}
+
+			return newSink(mode, level, format, write, nil), nil
 		}
 	case ModeFile:
 		{
-			logfile, err := os.Open(config.File.Path)
+			logfile, err := newFileWriter(config.File)
 			if err != nil {
 				err = fmt.Errorf("failed to open log file: %w", err)
 				return nil, err
 			}
 
-			file = logfile
+			filelogger := log.New(logfile, "", log.LstdFlags|log.Lmicroseconds)
+
+			write := func(p []byte) error {
+				filelogger.Println(string(p))
+				return nil
+			}
+
+			return newSink(mode, level, format, write, logfile.Close), nil
 		}
 	case ModeSyslog:
 		{
@@ -84,51 +195,60 @@ func New(config Config) (*Writer, error) {
 				syslogTag      = config.Syslog.Tag
 			)
 
-			var err error
-			syslogger, err = syslog.Dial(syslogProtocol, syslogAddr, syslog.LOG_LOCAL0, syslogTag)
+			syslogger, err := syslog.Dial(syslogProtocol, syslogAddr, syslog.LOG_LOCAL0, syslogTag)
 			if err != nil {
 				err = fmt.Errorf("failed to dial syslog: %w", err)
 				return nil, err
 			}
+
+			write := func(p []byte) error {
+				_, err := syslogger.Write(p)
+				return err
+			}
+
+			return newSink(mode, level, format, write, syslogger.Close), nil
 		}
 	case ModeAWS:
 		{
-			var err error
-			cloudwatchStream, err = aws.Client.LogStream(
-				context.Background(),
-				config.AWS.LogGroup.Name,
-				fmt.Sprintf("%s-%d", config.SHASUM, time.Now().Unix()),
-			)
+			cloudwatchStream, err := aws.Client.LogStream(context.Background(), aws.StreamConfig{
+				GroupName:        config.AWS.LogGroup.Name,
+				StreamNameSuffix: fmt.Sprintf("%s-%d", config.SHASUM, time.Now().Unix()),
+				BufferSize:       config.AWS.BufferSize,
+				FlushInterval:    duration.Seconds(config.AWS.FlushIntervalSec),
+			})
 			if err != nil {
 				err = fmt.Errorf("failed to create cloudwatch stream: %w", err)
 				return nil, err
 			}
+
+			write := func(p []byte) error {
+				_, err := cloudwatchStream.Write(p)
+				return err
+			}
+
+			return newSink(mode, level, format, write, cloudwatchStream.Close), nil
 		}
 	case ModeNone:
 		{
-			filelogger = log.New(io.Discard, "", log.LstdFlags)
+			return nil, nil
 		}
-
-	default:
-		err := fmt.Errorf("unknown log mode: %s", config.Mode)
-		return nil, err
 	}
 
-	if mode != ModeNone && file == nil && syslogger == nil && cloudwatchStream == nil {
-		return nil, errors.New("no log output specified")
-	}
+	err := fmt.Errorf("unknown log mode: %s", sinkConfig.Mode)
+	return nil, err
+}
 
-	if file != nil {
-		filelogger = log.New(file, "", log.LstdFlags|log.Lmicroseconds)
-	}
+// Close flushes and closes all the sinks. Messages
+// logged after close are discarded.
+func (l *Writer) Close() error {
+	deadline := time.Now().Add(sinkCloseTimeout)
 
-	logger := &Writer{
-		mode:             mode,
-		level:            config.Level,
-		syslogger:        syslogger,
-		filelogger:       filelogger,
-		cloudwatchStream: cloudwatchStream,
+	var errs []error
+	for _, s := range l.sinks {
+		if err := s.shutdown(deadline); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
-	return logger, nil
+	return errors.Join(errs...)
 }
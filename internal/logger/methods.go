@@ -4,33 +4,23 @@ import (
 	goctx "context"
 	"fmt"
 	"log"
-	"regexp"
 	"strings"
 	"time"
 
 	"github.com/aws/smithy-go/logging"
 	"github.com/koraygocmen/golang-boilerplate/internal/context"
-	"github.com/koraygocmen/golang-boilerplate/internal/env"
 	gormlogger "gorm.io/gorm/logger"
 	"gorm.io/gorm/utils"
 )
 
-var (
-	sqlFilter = regexp.MustCompile(`'([^']{4,})'`)
-)
-
 // Base methods.
-func (l *Writer) Write(p []byte) (n int, err error) {
-	if l.filelogger != nil {
-		l.filelogger.Println(string(p))
-	}
-
-	if l.syslogger != nil {
-		return l.syslogger.Write(p)
-	}
 
-	if l.cloudwatchStream != nil {
-		return l.cloudwatchStream.Write(p)
+// Write writes the raw message to every sink regardless of level. The
+// message is copied since the sinks write it after Write returns.
+func (l *Writer) Write(p []byte) (n int, err error) {
+	message := append([]byte(nil), p...)
+	for _, s := range l.sinks {
+		s.enqueue(message)
 	}
 
 	return len(p), nil
@@ -38,6 +28,7 @@ func (l *Writer) Write(p []byte) (n int, err error) {
 
 func (l *Writer) Fatal(v ...interface{}) {
 	l.Write([]byte(fmt.Sprint(v...)))
+	l.Close()
 	log.Fatal(v...)
 }
 
@@ -87,24 +78,16 @@ func (l *Writer) Debug(ctx goctx.Context, format string, v ...interface{}) {
 	l.Debugf(ctx, format, v...)
 }
 
+// ParamsFilter is called by gorm before the params are
+// interpolated into the traced SQL, sensitive params are masked.
+func (l *Writer) ParamsFilter(ctx goctx.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, l.redactor().SQL(sql, params)
+}
+
 func (l *Writer) Trace(ctx goctx.Context, begin time.Time, fc func() (string, int64), err error) {
 	elapsed := time.Since(begin)
 	sql, rows := fc()
 
-	// Filter out sensitive information from SQL queries.
-	// This is a hacky way to do it, since GORM doesn't provide a way to do this.
-	if env.IsProd() {
-		sqlFiltered := sqlFilter.ReplaceAllFunc([]byte(sql), func(match []byte) []byte {
-			// Technically this can't happen since the regex
-			// only matches strings with at least 4 characters.
-			if len(match) < 4 {
-				return match
-			}
-			return []byte(fmt.Sprintf("'%s***'", match[:4]))
-		})
-		sql = string(sqlFiltered)
-	}
-
 	ctx = context.WithValue(ctx, context.KeyDBQuery, strings.ReplaceAll(sql, `"`, `\"`))
 	ctx = context.WithValue(ctx, context.KeyDBRows, rows)
 	ctx = context.WithValue(ctx, context.KeyDBElapsed, elapsed)
@@ -129,77 +112,72 @@ func (l *Writer) Fatalf(format string, v ...interface{}) {
 
 // Emergf logs a message at emergency level.
 func (l *Writer) Emerf(ctx context.Ctx, format string, v ...interface{}) {
-	if l.level < LevelEmerg {
-		return
-	}
-
-	ctx = context.WithValue(ctx, context.KeyLogLevel, LevelEmerg)
-
-	if l.mode == ModeAWS {
-		l.Printf(context.JSON(ctx, format, v...))
-		return
-	}
-
-	ctx = context.WithValue(ctx, context.KeyLogLevel, LevelEmerg)
-	l.Printf(context.String(ctx, format, v...))
+	l.logf(ctx, LevelEmerg, format, v...)
 }
 
 // Errorf logs a message at error level.
 func (l *Writer) Errorf(ctx context.Ctx, format string, v ...interface{}) {
-	if l.level < LevelError {
-		return
-	}
-
-	if l.mode == ModeAWS {
-		l.Printf(context.JSON(ctx, format, v...))
-		return
-	}
-
-	ctx = context.WithValue(ctx, context.KeyLogLevel, LevelError)
-	l.Printf(context.String(ctx, format, v...))
+	l.logf(ctx, LevelError, format, v...)
 }
 
 // Warngf logs a message at warning level.
 func (l *Writer) Warnf(ctx context.Ctx, format string, v ...interface{}) {
-	if l.level < LevelWarng {
-		return
-	}
-
-	if l.mode == ModeAWS {
-		l.Printf(context.JSON(ctx, format, v...))
-		return
-	}
-
-	ctx = context.WithValue(ctx, context.KeyLogLevel, LevelWarng)
-	l.Printf(context.String(ctx, format, v...))
+	l.logf(ctx, LevelWarng, format, v...)
 }
 
 // Inforf logs a message at info level.
 func (l *Writer) Infof(ctx context.Ctx, format string, v ...interface{}) {
-	if l.level < LevelInfo {
-		return
-	}
+	l.logf(ctx, LevelInfo, format, v...)
+}
 
-	if l.mode == ModeAWS {
-		l.Printf(context.JSON(ctx, format, v...))
+// Debugf logs a message at debug level.
+func (l *Writer) Debugf(ctx context.Ctx, format string, v ...interface{}) {
+	l.logf(ctx, LevelDebug, format, v...)
+}
+
+// logf formats the message once per format and hands
+// it to every sink that accepts the level. The level of
+// the route in the context or the global level override
+// takes precedence over the levels of the sinks.
+func (l *Writer) logf(ctx context.Ctx, level int, format string, v ...interface{}) {
+	threshold, override := l.threshold(ctx)
+	if threshold < level {
 		return
 	}
 
-	ctx = context.WithValue(ctx, context.KeyLogLevel, LevelInfo)
-	l.Printf(context.String(ctx, format, v...))
+	ctx = context.WithValue(ctx, context.KeyLogLevel, level)
+
+	var text, json []byte
+	for _, s := range l.sinks {
+		if !override && s.level < level {
+			continue
+		}
+
+		switch s.format {
+		case FormatJSON:
+			if json == nil {
+				json = []byte(context.JSON(ctx, format, v...))
+			}
+			s.enqueue(json)
+		default:
+			if text == nil {
+				text = []byte(context.String(ctx, format, v...))
+			}
+			s.enqueue(text)
+		}
+	}
 }
 
-// Debugf logs a message at debug level.
-func (l *Writer) Debugf(ctx context.Ctx, format string, v ...interface{}) {
-	if l.level < LevelDebug {
-		return
+func (l *Writer) threshold(ctx context.Ctx) (int, bool) {
+	if ctx != nil {
+		if level, ok := ctx.Value(context.KeyLogRouteLevel).(int); ok {
+			return level, true
+		}
 	}
 
-	if l.mode == ModeAWS {
-		l.Printf(context.JSON(ctx, format, v...))
-		return
+	if level, ok := l.Level(); ok {
+		return level, true
 	}
 
-	ctx = context.WithValue(ctx, context.KeyLogLevel, LevelDebug)
-	l.Printf(context.String(ctx, format, v...))
+	return l.level, false
 }
@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// sinkQueueSize is the number of messages a sink buffers
+	// before it starts dropping new messages.
+	sinkQueueSize = 1024
+
+	// sinkCloseTimeout is how long close waits for a sink
+	// to drain its queue.
+	sinkCloseTimeout = 5 * time.Second
+)
+
+// SinkConfig is the configuration of a single log output.
+type SinkConfig struct {
+	Mode   string
+	Level  int
+	Format string
+}
+
+// sink is a single log output with its own minimum level and format.
+// Messages are handed over through a bounded queue and written by a
+// dedicated goroutine, so a slow output never blocks the caller or
+// the other sinks. Messages are dropped when the queue is full.
+type sink struct {
+	mode   Mode
+	level  int
+	format Format
+
+	write func(p []byte) error
+	close func() error
+
+	queue   chan []byte
+	done    chan struct{}
+	dropped uint64
+	closed  bool
+	lock    *sync.RWMutex
+}
+
+func newSink(mode Mode, level int, format Format, write func(p []byte) error, close func() error) *sink {
+	if format == "" {
+		format = FormatText
+		if mode == ModeAWS {
+			format = FormatJSON
+		}
+	}
+
+	s := &sink{
+		mode:   mode,
+		level:  level,
+		format: format,
+
+		write: write,
+		close: close,
+
+		queue: make(chan []byte, sinkQueueSize),
+		done:  make(chan struct{}),
+		lock:  &sync.RWMutex{},
+	}
+
+	go s.run()
+
+	return s
+}
+
+func (s *sink) run() {
+	defer close(s.done)
+
+	for p := range s.queue {
+		// There is nowhere to report a failed write to,
+		// the message is lost.
+		_ = s.write(p)
+
+		if dropped := atomic.SwapUint64(&s.dropped, 0); dropped > 0 {
+			_ = s.write([]byte(fmt.Sprintf(`logger dropped %d messages, sink="%s"`, dropped, s.mode)))
+		}
+	}
+}
+
+// enqueue never blocks. If the sink is closed or its queue
+// is full the message is dropped.
+func (s *sink) enqueue(p []byte) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.queue <- p:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+}
+
+// shutdown stops accepting messages and waits for the
+// queued ones to be written until the deadline.
+func (s *sink) shutdown(deadline time.Time) error {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	s.lock.Unlock()
+
+	select {
+	case <-s.done:
+	case <-time.After(time.Until(deadline)):
+		err := fmt.Errorf("sink %s close error: timed out draining queue", s.mode)
+		return err
+	}
+
+	if s.close != nil {
+		return s.close()
+	}
+
+	return nil
+}
+
+// ParseSinks parses a comma separated list of sinks in the
+// form of MODE:LEVEL[:FORMAT], i.e. "console:debug,aws:info:json".
+// Level is either the level name or the numeric level.
+func ParseSinks(sinks string) ([]SinkConfig, error) {
+	var configs []SinkConfig
+
+	for _, spec := range strings.Split(sinks, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		pieces := strings.Split(spec, ":")
+		if len(pieces) < 2 || len(pieces) > 3 {
+			err := fmt.Errorf("parse sinks error: invalid sink: %s", spec)
+			return nil, err
+		}
+
+		level, err := ToLevel(pieces[1])
+		if err != nil {
+			err = fmt.Errorf("parse sinks error: %w", err)
+			return nil, err
+		}
+
+		config := SinkConfig{
+			Mode:  string(ToMode(pieces[0])),
+			Level: level,
+		}
+
+		if len(pieces) == 3 {
+			format := ToFormat(pieces[2])
+			if format != FormatText && format != FormatJSON {
+				err := fmt.Errorf("parse sinks error: unknown log format: %s", pieces[2])
+				return nil, err
+			}
+			config.Format = string(format)
+		}
+
+		configs = append(configs, config)
+	}
+
+	return configs, nil
+}
@@ -0,0 +1,173 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+)
+
+type sinkTest struct {
+	lock     sync.Mutex
+	messages []string
+}
+
+func (st *sinkTest) write(p []byte) error {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+	st.messages = append(st.messages, string(p))
+	return nil
+}
+
+func (st *sinkTest) get() []string {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+	return append([]string{}, st.messages...)
+}
+
+func TestParseSinks(t *testing.T) {
+	got, err := ParseSinks("console:debug, aws:7:json,file:error:text")
+	if err != nil {
+		t.Fatalf("want: parse sinks err nil; got: %v", err)
+	}
+
+	want := []SinkConfig{
+		{Mode: string(ModeConsole), Level: LevelDebug},
+		{Mode: string(ModeAWS), Level: LevelInfo, Format: string(FormatJSON)},
+		{Mode: string(ModeFile), Level: LevelError, Format: string(FormatText)},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("want: len(sinks) = %d; got: %d", len(want), len(got))
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("want: sinks[%d] = %+v; got: %+v", i, want[i], got[i])
+		}
+	}
+
+	if got, err := ParseSinks(""); err != nil || len(got) != 0 {
+		t.Fatalf("want: empty sinks; got: %v, err = %v", got, err)
+	}
+
+	for _, invalid := range []string{"console", "console:loud", "console:info:xml", "a:b:c:d"} {
+		if _, err := ParseSinks(invalid); err == nil {
+			t.Fatalf("want: parse sinks %q err not nil; got: nil", invalid)
+		}
+	}
+}
+
+func TestSinkLevels(t *testing.T) {
+	debugSink, errorSink := &sinkTest{}, &sinkTest{}
+
+	l := &Writer{
+		level: LevelDebug,
+		sinks: []*sink{
+			newSink(ModeConsole, LevelDebug, FormatText, debugSink.write, nil),
+			newSink(ModeAWS, LevelError, "", errorSink.write, nil),
+		},
+	}
+
+	ctx := context.WithValue(context.Background(), context.KeyPath, "/path")
+	l.Debugf(ctx, "debug")
+	l.Errorf(ctx, "error")
+
+	if err := l.Close(); err != nil {
+		t.Fatalf("want: close err nil; got: %v", err)
+	}
+
+	if got := debugSink.get(); len(got) != 2 {
+		t.Fatalf("want: debug sink 2 messages; got: %v", got)
+	}
+
+	got := errorSink.get()
+	if len(got) != 1 {
+		t.Fatalf("want: error sink 1 message; got: %v", got)
+	}
+
+	// AWS sinks default to json.
+	if !strings.HasPrefix(got[0], "{") || !strings.Contains(got[0], `"path": "/path"`) {
+		t.Fatalf("want: error sink json message; got: %s", got[0])
+	}
+
+	// Messages after close are discarded.
+	l.Errorf(ctx, "closed")
+	if got := errorSink.get(); len(got) != 1 {
+		t.Fatalf("want: error sink 1 message after close; got: %v", got)
+	}
+}
+
+func TestSinkSlow(t *testing.T) {
+	block := make(chan struct{})
+	fastSink := &sinkTest{}
+
+	l := &Writer{
+		level: LevelInfo,
+		sinks: []*sink{
+			newSink(ModeSyslog, LevelInfo, FormatText, func(p []byte) error {
+				<-block
+				return nil
+			}, nil),
+			newSink(ModeConsole, LevelInfo, FormatText, fastSink.write, nil),
+		},
+	}
+
+	// Logging more messages than the queue can hold
+	// must not block on the slow sink.
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < sinkQueueSize*2; i++ {
+			l.Infof(context.Background(), "message")
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("want: logging not blocked by slow sink; got: blocked")
+	}
+
+	close(block)
+	l.Close()
+
+	if got := fastSink.get(); len(got) == 0 {
+		t.Fatalf("want: fast sink messages; got: none")
+	}
+}
+
+func TestSinkWrite(t *testing.T) {
+	st := &sinkTest{}
+
+	l := &Writer{
+		level: LevelInfo,
+		sinks: []*sink{
+			newSink(ModeConsole, LevelInfo, FormatText, st.write, nil),
+		},
+	}
+
+	// The message is written after Write returns,
+	// the caller may reuse the buffer right away.
+	p := []byte("message")
+	l.Write(p)
+	copy(p, "changed")
+
+	if err := l.Close(); err != nil {
+		t.Fatalf("want: close err nil; got: %v", err)
+	}
+
+	if got := st.get(); len(got) != 1 || got[0] != "message" {
+		t.Fatalf("want: [message]; got: %v", got)
+	}
+}
+
+func TestNewFileSinks(t *testing.T) {
+	// The file sinks would share the file path.
+	_, err := New(Config{Mode: string(ModeFile), Level: LevelInfo, Sinks: "file:error"})
+	if err == nil {
+		t.Fatalf("want: new err not nil; got: nil")
+	}
+}
@@ -1,8 +1,13 @@
 package logger
 
-import "strings"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 type Mode string
+type Format string
 
 const (
 	LevelFatal = iota
@@ -20,8 +25,45 @@ const (
 	ModeSyslog  Mode = "SYSLOG"
 	ModeConsole Mode = "CONSOLE"
 	ModeNone    Mode = "NONE"
+
+	FormatText Format = "TEXT"
+	FormatJSON Format = "JSON"
+)
+
+var (
+	Levels = map[string]int{
+		"FATAL":  LevelFatal,
+		"EMERG":  LevelEmerg,
+		"ALERT":  LevelAlert,
+		"CRIT":   LevelCrit,
+		"ERROR":  LevelError,
+		"WARN":   LevelWarng,
+		"NOTICE": LevelNotice,
+		"INFO":   LevelInfo,
+		"DEBUG":  LevelDebug,
+	}
 )
 
 func ToMode(mode string) Mode {
 	return Mode(strings.TrimSpace(strings.ToUpper(mode)))
 }
+
+func ToFormat(format string) Format {
+	return Format(strings.TrimSpace(strings.ToUpper(format)))
+}
+
+// ToLevel accepts either the numeric level or the level name.
+func ToLevel(level string) (int, error) {
+	level = strings.TrimSpace(strings.ToUpper(level))
+
+	if l, err := strconv.Atoi(level); err == nil {
+		return l, nil
+	}
+
+	if l, ok := Levels[level]; ok {
+		return l, nil
+	}
+
+	err := fmt.Errorf("unknown log level: %s", level)
+	return 0, err
+}
@@ -0,0 +1,26 @@
+package idempotency_key
+
+import (
+	"time"
+)
+
+// IdempotencyKey is the first response of a request sent with an
+// idempotency key. The scope is the user or the request of the client and
+// the fingerprint identifies the request the key was first used with.
+// The body is encrypted with the secret and the key.
+type IdempotencyKey struct {
+	ID          int64     `gorm:"type:bigint; primaryKey;" json:"id"`
+	Scope       string    `gorm:"type:text; not null; uniqueIndex:idx_idempotency_key_scope_key;" json:"scope"`
+	Key         string    `gorm:"type:text; not null; uniqueIndex:idx_idempotency_key_scope_key;" json:"key"`
+	Fingerprint string    `gorm:"type:text; not null;" json:"fingerprint"`
+	StatusCode  int       `gorm:"type:integer; not null; default:0;" json:"statusCode"`
+	ContentType string    `gorm:"type:text; not null; default:'';" json:"contentType"`
+	Body        []byte    `gorm:"type:bytea;" json:"-"`
+	CreatedAt   time.Time `gorm:"type:timestamp; default:(now() at time zone 'utc');" json:"createdAt"`
+	ExpireAt    time.Time `gorm:"type:timestamp; not null; index;" json:"expireAt"`
+}
+
+// IsReplay reports whether the response of the first request is stored.
+func (i *IdempotencyKey) IsReplay() bool {
+	return i.StatusCode != 0
+}
@@ -0,0 +1,45 @@
+package job
+
+import (
+	"strings"
+	"time"
+
+	"github.com/koraygocmen/null"
+)
+
+type Status string
+
+var (
+	// StatusPending jobs are run at run at.
+	StatusPending   Status = "PENDING"
+	StatusSucceeded Status = "SUCCEEDED"
+	// StatusDead jobs ran out of attempts.
+	StatusDead Status = "DEAD"
+
+	Statuses = map[Status]bool{
+		StatusPending:   true,
+		StatusSucceeded: true,
+		StatusDead:      true,
+	}
+)
+
+func ToStatus(status string) Status {
+	return Status(strings.ToUpper(strings.TrimSpace(status)))
+}
+
+// Job is a unit of deferred work, the payload is
+// decoded into the arguments of the kind's handler.
+type Job struct {
+	ID        int64       `gorm:"type:integer; primaryKey;" json:"id"`
+	CreatedAt time.Time   `gorm:"type:timestamp; autoCreateTime;" json:"createdAt"`
+	Kind      string      `gorm:"type:text; not null; index;" json:"kind"`
+	Payload   string      `gorm:"type:text; not null;" json:"payload"`
+	Status    Status      `gorm:"type:text; not null;" json:"status"`
+	UniqueKey null.String `gorm:"type:text;" json:"uniqueKey"`
+	Attempts  int         `gorm:"type:integer; not null; default:0;" json:"attempts"`
+	// MaxAttempts overrides the attempts of the worker when set.
+	MaxAttempts int         `gorm:"type:integer; not null; default:0;" json:"maxAttempts"`
+	RunAt       time.Time   `gorm:"type:timestamp; not null;" json:"runAt"`
+	LastError   null.String `gorm:"type:text;" json:"lastError"`
+	FinishedAt  null.Time   `gorm:"type:timestamp;" json:"finishedAt"`
+}
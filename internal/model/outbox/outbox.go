@@ -0,0 +1,24 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/koraygocmen/null"
+)
+
+// Outbox is a domain event written in the transaction of the
+// change it describes, it is relayed to the subscribers once
+// the transaction commits.
+type Outbox struct {
+	ID            int64       `gorm:"type:integer; primaryKey;" json:"id"`
+	CreatedAt     time.Time   `gorm:"type:timestamp; autoCreateTime;" json:"createdAt"`
+	AggregateType string      `gorm:"type:text; not null;" json:"aggregateType"`
+	AggregateID   string      `gorm:"type:text; not null;" json:"aggregateId"`
+	Event         string      `gorm:"type:text; not null; index;" json:"event"`
+	Payload       string      `gorm:"type:text; not null;" json:"payload"`
+	RequestID     null.String `gorm:"type:text;" json:"requestId"`
+	Attempts      int         `gorm:"type:integer; not null; default:0;" json:"attempts"`
+	NextAttemptAt time.Time   `gorm:"type:timestamp; not null;" json:"nextAttemptAt"`
+	LastError     null.String `gorm:"type:text;" json:"lastError"`
+	PublishedAt   null.Time   `gorm:"type:timestamp; index;" json:"publishedAt"`
+}
@@ -0,0 +1,13 @@
+package rate_limit
+
+import (
+	"time"
+)
+
+// RateLimit is the request counter of a rate limit window,
+// the key identifies the policy, the subject and the window.
+type RateLimit struct {
+	Key      string    `gorm:"type:text; primaryKey;" json:"key"`
+	Count    int64     `gorm:"type:integer; not null; default:0;" json:"count"`
+	ExpireAt time.Time `gorm:"type:timestamp; not null; index;" json:"expireAt"`
+}
@@ -66,6 +66,7 @@ type User struct {
 	PasswordSet   bool           `gorm:"-" json:"passwordSet"`
 	GivenNames    null.String    `gorm:"type:text; index;" json:"givenNames"`
 	Surname       null.String    `gorm:"type:text; index;" json:"surname"`
+	Version       int64          `gorm:"type:integer; not null; default:1;" json:"version"`
 }
 
 // Gorm hooks.
@@ -117,6 +118,29 @@ func (u *User) PasswordHashCompare(password string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(u.PasswordHash.String), []byte(password)) == nil
 }
 
+// ETag is the entity tag of the user version, the
+// version is incremented each time the user is saved.
+func (u *User) ETag() string {
+	return fmt.Sprintf(`"%d"`, u.Version)
+}
+
+// ETagMatch reports whether the If-Match header matches the user version.
+// The header is "*" or a list of entity tags, the weak tags never match.
+func (u *User) ETagMatch(ifMatch string) bool {
+	ifMatch = strings.TrimSpace(ifMatch)
+	if ifMatch == "*" {
+		return true
+	}
+
+	etag := u.ETag()
+	for _, tag := range strings.Split(ifMatch, ",") {
+		if strings.TrimSpace(tag) == etag {
+			return true
+		}
+	}
+	return false
+}
+
 func (User) ReferralCodeCreate() string {
 	// User referral code is alphanumeric and 5 characters.
 	return generate.AlphaCode(5, true)
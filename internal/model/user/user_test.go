@@ -18,6 +18,7 @@ func TestJSON(t *testing.T) {
 		PasswordHash:  null.StringFrom("hash"),
 		GivenNames:    null.StringFrom("Koray"),
 		Surname:       null.StringFrom("Gocmen"),
+		Version:       2,
 	}
 
 	marshalled, err := json.Marshal(src)
@@ -30,8 +31,8 @@ func TestJSON(t *testing.T) {
 		t.Fatalf("want: no error when unmarshalling; got: %v", err)
 	}
 
-	if len(m) != 7 {
-		t.Fatalf("want: 7 fields; got: %v", len(m))
+	if len(m) != 8 {
+		t.Fatalf("want: 8 fields; got: %v", len(m))
 	}
 
 	var dst User
@@ -74,6 +75,33 @@ func TestJSON(t *testing.T) {
 	if src.Surname.String != dst.Surname.String {
 		t.Fatalf("want: surname to match; got: %v", dst.Surname)
 	}
+
+	if src.Version != dst.Version {
+		t.Fatalf("want: version to match; got: %v", dst.Version)
+	}
+}
+
+func TestETag(t *testing.T) {
+	user := &User{Version: 2}
+
+	if etag := user.ETag(); etag != `"2"` {
+		t.Fatalf(`want: etag = "2"; got: %v`, etag)
+	}
+
+	tests := map[string]bool{
+		`"2"`:      true,
+		`*`:        true,
+		`"1", "2"`: true,
+		`"1"`:      false,
+		`W/"2"`:    false,
+		`2`:        false,
+		`"1","3"`:  false,
+	}
+	for ifMatch, want := range tests {
+		if got := user.ETagMatch(ifMatch); got != want {
+			t.Fatalf("want: etag match %s = %v; got: %v", ifMatch, want, got)
+		}
+	}
 }
 
 func TestPasswordHashCreate(t *testing.T) {
@@ -0,0 +1,57 @@
+package user_preference
+
+import (
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/i18n"
+)
+
+const (
+	TimezoneDefault = "UTC"
+)
+
+// Notifications are the notification opt-ins of the user, the
+// security notifications are on and the marketing ones are off
+// unless the user opts otherwise. The defaults are not set on
+// the fields since gorm would insert the defaults for false.
+type Notifications struct {
+	Security  bool `gorm:"type:boolean; not null;" json:"security"`
+	Product   bool `gorm:"type:boolean; not null;" json:"product"`
+	Marketing bool `gorm:"type:boolean; not null;" json:"marketing"`
+}
+
+// UserPreference is the preferences of the user. The locale is the
+// language of the user when no request is involved, i.e. in emails.
+type UserPreference struct {
+	UserID        int64         `gorm:"type:integer; primaryKey;" json:"-"`
+	CreatedAt     time.Time     `gorm:"type:timestamp; autoCreateTime;" json:"-"`
+	UpdatedAt     time.Time     `gorm:"type:timestamp; autoUpdateTime;" json:"updatedAt"`
+	Locale        string        `gorm:"type:text; not null;" json:"locale"`
+	Timezone      string        `gorm:"type:text; not null;" json:"timezone"`
+	Notifications Notifications `gorm:"embedded; embeddedPrefix:notify_;" json:"notifications"`
+}
+
+// Default returns the preferences of the user
+// that did not set the preferences yet.
+func Default(userID int64) *UserPreference {
+	return &UserPreference{
+		UserID:   userID,
+		Locale:   i18n.Fallback.String(),
+		Timezone: TimezoneDefault,
+		Notifications: Notifications{
+			Security:  true,
+			Product:   true,
+			Marketing: false,
+		},
+	}
+}
+
+// Location returns the location of the timezone, UTC
+// if the timezone can not be loaded.
+func (u *UserPreference) Location() *time.Location {
+	location, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
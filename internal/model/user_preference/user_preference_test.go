@@ -0,0 +1,31 @@
+package user_preference
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDefault(t *testing.T) {
+	userPreference := Default(1)
+
+	if userPreference.UserID != 1 || userPreference.Locale != "tr" || userPreference.Timezone != TimezoneDefault {
+		t.Fatalf("want: user 1, tr, %s; got: %+v", TimezoneDefault, userPreference)
+	}
+	if !userPreference.Notifications.Security || !userPreference.Notifications.Product || userPreference.Notifications.Marketing {
+		t.Fatalf("want: security and product notifications only; got: %+v", userPreference.Notifications)
+	}
+}
+
+func TestLocation(t *testing.T) {
+	userPreference := Default(1)
+
+	userPreference.Timezone = "Europe/Istanbul"
+	if location := userPreference.Location(); location.String() != "Europe/Istanbul" {
+		t.Fatalf("want: Europe/Istanbul; got: %s", location)
+	}
+
+	userPreference.Timezone = "Mars/Olympus"
+	if location := userPreference.Location(); location != time.UTC {
+		t.Fatalf("want: UTC; got: %s", location)
+	}
+}
@@ -0,0 +1,48 @@
+package webhook_delivery
+
+import (
+	"strings"
+	"time"
+
+	"github.com/koraygocmen/null"
+)
+
+type Status string
+
+var (
+	// StatusPending deliveries are attempted at next attempt at.
+	StatusPending   Status = "PENDING"
+	StatusSucceeded Status = "SUCCEEDED"
+	// StatusDead deliveries ran out of attempts, they are only
+	// attempted again when they are redelivered.
+	StatusDead Status = "DEAD"
+
+	Statuses = map[Status]bool{
+		StatusPending:   true,
+		StatusSucceeded: true,
+		StatusDead:      true,
+	}
+)
+
+func ToStatus(status string) Status {
+	return Status(strings.ToUpper(strings.TrimSpace(status)))
+}
+
+// WebhookDelivery is the delivery of an event to a subscription. The
+// outbox id is of the relayed event the delivery is published for, a
+// retried event does not publish the delivery again.
+type WebhookDelivery struct {
+	ID             int64       `gorm:"type:integer; primaryKey;" json:"id"`
+	CreatedAt      time.Time   `gorm:"type:timestamp; autoCreateTime;" json:"createdAt"`
+	SubscriptionID int64       `gorm:"type:integer; not null; index;" json:"subscriptionId"`
+	OutboxID       null.Int    `gorm:"type:integer;" json:"-"`
+	Event          string      `gorm:"type:text; not null; index;" json:"event"`
+	Payload        string      `gorm:"type:text; not null;" json:"payload"`
+	Status         Status      `gorm:"type:text; not null;" json:"status"`
+	Attempts       int         `gorm:"type:integer; not null; default:0;" json:"attempts"`
+	NextAttemptAt  null.Time   `gorm:"type:timestamp;" json:"nextAttemptAt"`
+	LastAttemptAt  null.Time   `gorm:"type:timestamp;" json:"lastAttemptAt"`
+	LastStatusCode null.Int    `gorm:"type:integer;" json:"lastStatusCode"`
+	LastError      null.String `gorm:"type:text;" json:"lastError"`
+	DeliveredAt    null.Time   `gorm:"type:timestamp;" json:"deliveredAt"`
+}
@@ -0,0 +1,78 @@
+package webhook_subscription
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Event string
+
+var (
+	EventUserCreated Event = "user.created"
+	EventUserUpdated Event = "user.updated"
+	EventUserDeleted Event = "user.deleted"
+
+	Events = map[Event]bool{
+		EventUserCreated: true,
+		EventUserUpdated: true,
+		EventUserDeleted: true,
+	}
+)
+
+func ToEvent(event string) Event {
+	return Event(strings.ToLower(strings.TrimSpace(event)))
+}
+
+type WebhookSubscription struct {
+	ID        int64          `gorm:"type:integer; primaryKey;" json:"id"`
+	CreatedAt time.Time      `gorm:"type:timestamp; autoCreateTime;" json:"createdAt"`
+	DeletedAt gorm.DeletedAt `gorm:"type:timestamp; index;" json:"-"`
+	URL       string         `gorm:"type:text; not null;" json:"url"`
+	Secret    string         `gorm:"type:text; not null;" json:"-"`
+	Events    string         `gorm:"type:text; not null;" json:"events"`
+	Active    bool           `gorm:"type:boolean; not null; default:true; index;" json:"active"`
+}
+
+// SecretCreate creates the secret the payloads are signed with.
+func (w *WebhookSubscription) SecretCreate() error {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		err = fmt.Errorf("secret create error: rand read error: %w", err)
+		return err
+	}
+
+	w.Secret = "whsec_" + hex.EncodeToString(secret)
+	return nil
+}
+
+// EventsSet sets the events as a sorted comma separated list.
+func (w *WebhookSubscription) EventsSet(events []Event) {
+	list := make([]string, 0, len(events))
+	seen := make(map[Event]bool, len(events))
+	for _, event := range events {
+		if seen[event] {
+			continue
+		}
+		seen[event] = true
+		list = append(list, string(event))
+	}
+	sort.Strings(list)
+
+	w.Events = strings.Join(list, ",")
+}
+
+// Subscribed returns true if the subscription receives the event.
+func (w *WebhookSubscription) Subscribed(event Event) bool {
+	for _, e := range strings.Split(w.Events, ",") {
+		if Event(e) == event {
+			return true
+		}
+	}
+	return false
+}
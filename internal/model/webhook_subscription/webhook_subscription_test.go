@@ -0,0 +1,45 @@
+package webhook_subscription
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestEvents(t *testing.T) {
+	w := &WebhookSubscription{}
+	w.EventsSet([]Event{EventUserUpdated, EventUserCreated, EventUserUpdated})
+
+	if w.Events != "user.created,user.updated" {
+		t.Fatalf("want: user.created,user.updated; got: %s", w.Events)
+	}
+
+	if !w.Subscribed(EventUserCreated) {
+		t.Fatalf("want: subscribed to %s; got: not subscribed", EventUserCreated)
+	}
+
+	if w.Subscribed(EventUserDeleted) {
+		t.Fatalf("want: not subscribed to %s; got: subscribed", EventUserDeleted)
+	}
+}
+
+func TestSecret(t *testing.T) {
+	w := &WebhookSubscription{}
+	if err := w.SecretCreate(); err != nil {
+		t.Fatalf("want: secret create error nil; got: %v", err)
+	}
+
+	if !strings.HasPrefix(w.Secret, "whsec_") || len(w.Secret) != 70 {
+		t.Fatalf("want: whsec_ and 64 hex characters; got: %s", w.Secret)
+	}
+
+	// The secret is only returned when the subscription is created.
+	marshalled, err := json.Marshal(w)
+	if err != nil {
+		t.Fatalf("want: no error when marshalling; got: %v", err)
+	}
+
+	if strings.Contains(string(marshalled), w.Secret) {
+		t.Fatalf("want: secret not marshalled; got: %s", marshalled)
+	}
+}
@@ -0,0 +1,256 @@
+package outbox
+
+import (
+	goctx "context"
+	"fmt"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/event"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	Outbox "github.com/koraygocmen/golang-boilerplate/internal/model/outbox"
+	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+	"github.com/koraygocmen/golang-boilerplate/pkg/duration"
+	"github.com/koraygocmen/null"
+)
+
+const (
+	PollDefault        = 5 * time.Second
+	BatchSizeDefault   = 50
+	BackoffBaseDefault = 5 * time.Second
+	BackoffMaxDefault  = 15 * time.Minute
+
+	// closeTimeout bounds the wait for the batch in flight on Close.
+	closeTimeout = 30 * time.Second
+)
+
+var (
+	// notify wakes up the relay before the next poll.
+	notify = make(chan struct{}, 1)
+)
+
+type Config struct {
+	PollSec        int
+	BatchSize      int
+	BackoffBaseSec int
+	BackoffMaxSec  int
+}
+
+// Emit writes the event to the outbox in the transaction. It is
+// relayed to the subscribers once the transaction commits and
+// dropped with the rest of the changes if it rolls back.
+func Emit(ctx context.Ctx, tx *repo.Transaction, e event.Event) error {
+	payload, err := event.Encode(e)
+	if err != nil {
+		err = fmt.Errorf("outbox emit error: %w", err)
+		return err
+	}
+
+	aggregateType, aggregateID := e.Aggregate()
+
+	outbox := &Outbox.Outbox{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Event:         string(e.EventName()),
+		Payload:       payload,
+		NextAttemptAt: time.Now().UTC(),
+	}
+
+	if requestID, ok := ctx.Value(context.KeyRequestID).(string); ok && requestID != "" {
+		outbox.RequestID = null.StringFrom(requestID)
+	}
+
+	if err := tx.Outbox.Create(ctx, outbox); err != nil {
+		err = fmt.Errorf("outbox emit error: %w", err)
+		return err
+	}
+
+	tx.AfterCommit(Notify)
+
+	return nil
+}
+
+// Notify wakes up the relay, it never blocks.
+func Notify() {
+	select {
+	case notify <- struct{}{}:
+	default:
+	}
+}
+
+// Relay delivers the committed outbox events to the bus. An event
+// is marked published once every subscriber handled it, failed ones
+// are retried with a backoff and hold back the later events of
+// their aggregate until they succeed.
+type Relay struct {
+	bus         *event.Bus
+	poll        time.Duration
+	batchSize   int
+	backoffBase time.Duration
+	backoffMax  time.Duration
+	now         func() time.Time
+
+	cancel goctx.CancelFunc
+	done   chan struct{}
+}
+
+func New(config Config, bus *event.Bus) *Relay {
+	r := &Relay{
+		bus:         bus,
+		poll:        duration.Seconds(config.PollSec),
+		batchSize:   config.BatchSize,
+		backoffBase: duration.Seconds(config.BackoffBaseSec),
+		backoffMax:  duration.Seconds(config.BackoffMaxSec),
+		now:         time.Now,
+	}
+
+	if r.poll <= 0 {
+		r.poll = PollDefault
+	}
+
+	if r.batchSize <= 0 {
+		r.batchSize = BatchSizeDefault
+	}
+
+	if r.backoffBase <= 0 {
+		r.backoffBase = BackoffBaseDefault
+	}
+
+	if r.backoffMax <= 0 {
+		r.backoffMax = BackoffMaxDefault
+	}
+
+	return r
+}
+
+// Start relays in a goroutine until Close.
+func (r *Relay) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go r.run(ctx)
+}
+
+// Close stops the relay and waits for the batch in flight.
+func (r *Relay) Close() error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-time.After(closeTimeout):
+		return fmt.Errorf("outbox relay close error: timed out after %v", closeTimeout)
+	}
+}
+
+func (r *Relay) run(ctx context.Ctx) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.poll)
+	defer ticker.Stop()
+
+	for {
+		// Keep going while events are published, each batch
+		// releases the next events of the aggregates.
+		for {
+			n, err := r.Relay(ctx)
+			if err != nil {
+				logger.Logger.Errorf(ctx, `msg="outbox relay failed", err="%v"`, err)
+				break
+			}
+
+			if n == 0 || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-notify:
+		}
+	}
+}
+
+// Relay dispatches a batch of the pending events
+// and returns the number of events published.
+func (r *Relay) Relay(ctx context.Ctx) (int, error) {
+	tx := repo.New(ctx)
+
+	outboxes, err := tx.Outbox.ListPending(ctx, r.now().UTC(), r.batchSize)
+	if err != nil {
+		tx.Rollback()
+		err = fmt.Errorf("outbox relay error: %w", err)
+		return 0, err
+	}
+
+	if len(outboxes) == 0 {
+		tx.Rollback()
+		return 0, nil
+	}
+
+	var published int
+	for _, outbox := range outboxes {
+		err := r.dispatch(ctx, outbox)
+		r.record(outbox, err)
+
+		if err != nil {
+			logger.Logger.Errorf(ctx, `msg="outbox event failed", outbox_id="%d", event="%s", attempts="%d", err="%v"`,
+				outbox.ID, outbox.Event, outbox.Attempts, err)
+		} else {
+			published++
+		}
+
+		if err := tx.Outbox.Save(ctx, outbox); err != nil {
+			tx.Rollback()
+			err = fmt.Errorf("outbox relay error: %w", err)
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		err = fmt.Errorf("outbox relay error: commit error: %w", err)
+		return 0, err
+	}
+
+	return published, nil
+}
+
+func (r *Relay) dispatch(ctx context.Ctx, outbox *Outbox.Outbox) error {
+	e, err := event.Decode(event.Name(outbox.Event), outbox.Payload)
+	if err != nil {
+		return err
+	}
+
+	// Carry the request id over to correlate the logs.
+	if outbox.RequestID.Valid {
+		ctx = context.WithValue(ctx, context.KeyRequestID, outbox.RequestID.String)
+	}
+
+	// The event is dispatched to every subscriber again if one of them
+	// fails, the subscribers use the id to not repeat their side effects.
+	ctx = context.WithValue(ctx, context.KeyOutboxID, outbox.ID)
+
+	return r.bus.Dispatch(ctx, e)
+}
+
+// record updates the event with the result of the dispatch.
+func (r *Relay) record(outbox *Outbox.Outbox, err error) {
+	now := r.now().UTC()
+
+	outbox.Attempts++
+
+	if err == nil {
+		outbox.LastError = null.String{}
+		outbox.PublishedAt = null.TimeFrom(now)
+		return
+	}
+
+	outbox.LastError = null.StringFrom(err.Error())
+	outbox.NextAttemptAt = now.Add(duration.Backoff(outbox.Attempts, r.backoffBase, r.backoffMax))
+}
@@ -0,0 +1,133 @@
+package outbox
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/event"
+	Outbox "github.com/koraygocmen/golang-boilerplate/internal/model/outbox"
+	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
+	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+	OutboxRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/outbox"
+)
+
+func TestEmit(t *testing.T) {
+	ctx := context.WithValue(context.Background(), context.KeyRequestID, "request-id")
+
+	var (
+		created   *Outbox.Outbox
+		callbacks []func()
+	)
+	tx := &repo.Transaction{
+		Outbox: &OutboxRepo.Repo{
+			Create: func(ctx context.Ctx, outbox *Outbox.Outbox) error {
+				created = outbox
+				return nil
+			},
+		},
+		AfterCommit: func(fn func()) {
+			callbacks = append(callbacks, fn)
+		},
+	}
+
+	if err := Emit(ctx, tx, &event.UserCreated{User: &User.User{ID: 3}}); err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+
+	if created == nil {
+		t.Fatalf("want: outbox created; got: nil")
+	}
+	if created.Event != string(event.NameUserCreated) {
+		t.Fatalf("want: event = %s; got: event = %s", event.NameUserCreated, created.Event)
+	}
+	if created.AggregateType != event.AggregateUser || created.AggregateID != "3" {
+		t.Fatalf("want: aggregate = user/3; got: aggregate = %s/%s", created.AggregateType, created.AggregateID)
+	}
+	if created.RequestID.String != "request-id" {
+		t.Fatalf("want: request id = request-id; got: request id = %s", created.RequestID.String)
+	}
+	if len(callbacks) != 1 {
+		t.Fatalf("want: 1 after commit callback; got: %d", len(callbacks))
+	}
+}
+
+func TestRecord(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	r := New(Config{BackoffBaseSec: 5, BackoffMaxSec: 60}, event.NewBus())
+	r.now = func() time.Time { return now }
+
+	outbox := &Outbox.Outbox{}
+
+	r.record(outbox, errors.New("failed"))
+	if outbox.Attempts != 1 || outbox.LastError.String != "failed" || outbox.PublishedAt.Valid {
+		t.Fatalf("want: failed attempt; got: %+v", outbox)
+	}
+	if !outbox.NextAttemptAt.After(now) {
+		t.Fatalf("want: next attempt after %v; got: %v", now, outbox.NextAttemptAt)
+	}
+
+	r.record(outbox, nil)
+	if outbox.Attempts != 2 || outbox.LastError.Valid || !outbox.PublishedAt.Valid {
+		t.Fatalf("want: published; got: %+v", outbox)
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	ctx := context.Background()
+	bus := event.NewBus()
+
+	var (
+		gotRequestID string
+		gotOutboxID  int64
+	)
+	event.On(bus, "test", func(ctx context.Ctx, e *event.UserDeleted) error {
+		gotRequestID, _ = ctx.Value(context.KeyRequestID).(string)
+		gotOutboxID, _ = ctx.Value(context.KeyOutboxID).(int64)
+		return nil
+	})
+
+	r := New(Config{}, bus)
+
+	payload, err := event.Encode(&event.UserDeleted{User: &User.User{ID: 1}})
+	if err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+
+	outbox := &Outbox.Outbox{ID: 7, Event: string(event.NameUserDeleted), Payload: payload}
+	outbox.RequestID.String, outbox.RequestID.Valid = "request-id", true
+
+	if err := r.dispatch(ctx, outbox); err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+	if gotRequestID != "request-id" {
+		t.Fatalf("want: request id = request-id; got: request id = %s", gotRequestID)
+	}
+	if gotOutboxID != 7 {
+		t.Fatalf("want: outbox id = 7; got: outbox id = %d", gotOutboxID)
+	}
+
+	outbox.Event = "unknown"
+	if err := r.dispatch(ctx, outbox); err == nil {
+		t.Fatalf("want: err != nil; got: err = nil")
+	}
+}
+
+func TestNotify(t *testing.T) {
+	// Drain a pending notification of another test.
+	select {
+	case <-notify:
+	default:
+	}
+
+	Notify()
+	Notify()
+
+	select {
+	case <-notify:
+	default:
+		t.Fatalf("want: notification; got: none")
+	}
+}
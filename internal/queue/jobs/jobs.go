@@ -0,0 +1,145 @@
+package jobs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	"github.com/koraygocmen/golang-boilerplate/internal/queue"
+	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+	UserServiceV1 "github.com/koraygocmen/golang-boilerplate/internal/service/user/v1"
+)
+
+const (
+	KindSessionPurge        queue.Kind = "user_session.purge"
+	KindUserUnverifiedPurge queue.Kind = "user.unverified_purge"
+	KindIdempotencyKeyPurge queue.Kind = "idempotency_key.purge"
+
+	// userUnverifiedBatchSize bounds the users deleted in a transaction.
+	userUnverifiedBatchSize = 100
+)
+
+// Register sets the handlers of the jobs on the registry.
+func Register(registry *queue.Registry) {
+	queue.Handle(registry, sessionPurge)
+	queue.Handle(registry, userUnverifiedPurge)
+	queue.Handle(registry, idempotencyKeyPurge)
+}
+
+// SessionPurge permanently deletes the sessions
+// that expired or were deleted before the retention.
+type SessionPurge struct {
+	RetentionHours int `json:"retentionHours"`
+}
+
+func (SessionPurge) JobKind() queue.Kind {
+	return KindSessionPurge
+}
+
+func sessionPurge(ctx context.Ctx, args SessionPurge) error {
+	before := time.Now().UTC().Add(-time.Duration(args.RetentionHours) * time.Hour)
+
+	tx := repo.New(ctx)
+
+	n, err := tx.UserSession.Purge(ctx, before)
+	if err != nil {
+		tx.Rollback()
+		err = fmt.Errorf("session purge job error: %w", err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		err = fmt.Errorf("session purge job error: commit error: %w", err)
+		return err
+	}
+
+	logger.Logger.Infof(ctx, `msg="user sessions purged", count="%d", before="%s"`, n, before.Format(time.RFC3339))
+	return nil
+}
+
+// UserUnverifiedPurge deletes the users that did not verify their
+// email within the age. It is not scheduled until there is email
+// verification, every user is unverified until then.
+type UserUnverifiedPurge struct {
+	AgeHours int `json:"ageHours"`
+}
+
+func (UserUnverifiedPurge) JobKind() queue.Kind {
+	return KindUserUnverifiedPurge
+}
+
+func userUnverifiedPurge(ctx context.Ctx, args UserUnverifiedPurge) error {
+	before := time.Now().UTC().Add(-time.Duration(args.AgeHours) * time.Hour)
+
+	var total int
+	for {
+		n, err := userUnverifiedPurgeBatch(ctx, before)
+		if err != nil {
+			err = fmt.Errorf("user unverified purge job error: %w", err)
+			return err
+		}
+
+		total += n
+		if n < userUnverifiedBatchSize {
+			break
+		}
+	}
+
+	logger.Logger.Infof(ctx, `msg="unverified users purged", count="%d", before="%s"`, total, before.Format(time.RFC3339))
+	return nil
+}
+
+// userUnverifiedPurgeBatch deletes a batch of the users in a transaction
+// with the user service, the sessions of the users are revoked and the
+// subscribers are notified of each deleted user.
+func userUnverifiedPurgeBatch(ctx context.Ctx, before time.Time) (int, error) {
+	tx := repo.New(ctx)
+
+	users, err := tx.User.ListUnverified(ctx, before, userUnverifiedBatchSize)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	userService := UserServiceV1.New(tx)
+	for _, user := range users {
+		if _, _, err := userService.Delete(ctx, user); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		err = fmt.Errorf("commit error: %w", err)
+		return 0, err
+	}
+
+	return len(users), nil
+}
+
+// IdempotencyKeyPurge deletes the expired idempotency keys.
+type IdempotencyKeyPurge struct{}
+
+func (IdempotencyKeyPurge) JobKind() queue.Kind {
+	return KindIdempotencyKeyPurge
+}
+
+func idempotencyKeyPurge(ctx context.Ctx, args IdempotencyKeyPurge) error {
+	tx := repo.New(ctx)
+
+	n, err := tx.IdempotencyKey.DeleteExpired(ctx, time.Now().UTC())
+	if err != nil {
+		tx.Rollback()
+		err = fmt.Errorf("idempotency key purge job error: %w", err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		err = fmt.Errorf("idempotency key purge job error: commit error: %w", err)
+		return err
+	}
+
+	logger.Logger.Infof(ctx, `msg="idempotency keys purged", count="%d"`, n)
+	return nil
+}
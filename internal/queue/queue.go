@@ -0,0 +1,140 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	Job "github.com/koraygocmen/golang-boilerplate/internal/model/job"
+	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+	"github.com/koraygocmen/null"
+)
+
+type Kind string
+
+// Args are the arguments of a job, they are stored
+// as the json payload of the job and the kind selects
+// the handler that runs it.
+type Args interface {
+	JobKind() Kind
+}
+
+// Handler runs a job. Jobs are run at least once, a job
+// interrupted by a crash is run again, so a handler must
+// be idempotent.
+type Handler func(ctx context.Ctx, payload string) error
+
+// Registry holds the handlers of the job kinds.
+type Registry struct {
+	lock     sync.RWMutex
+	handlers map[Kind]Handler
+}
+
+var (
+	// Default is the registry the worker runs.
+	Default = NewRegistry()
+)
+
+func NewRegistry() *Registry {
+	return &Registry{
+		handlers: make(map[Kind]Handler),
+	}
+}
+
+// Register sets the handler of the job kind.
+func (r *Registry) Register(kind Kind, handler Handler) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.handlers[kind] = handler
+}
+
+// Handle registers a handler of the typed arguments T, e.g.
+// Handle(registry, func(ctx context.Ctx, args SessionPurge) error {...}).
+func Handle[T Args](r *Registry, handler func(ctx context.Ctx, args T) error) {
+	var zero T
+	r.Register(zero.JobKind(), func(ctx context.Ctx, payload string) error {
+		var args T
+		if err := json.Unmarshal([]byte(payload), &args); err != nil {
+			return fmt.Errorf("unmarshal %s args error: %w", zero.JobKind(), err)
+		}
+		return handler(ctx, args)
+	})
+}
+
+// Handler returns the handler of the job kind.
+func (r *Registry) Handler(kind Kind) (Handler, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	handler, ok := r.handlers[kind]
+	return handler, ok
+}
+
+// Kinds returns the registered job kinds.
+func (r *Registry) Kinds() []Kind {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var kinds []Kind
+	for kind := range r.handlers {
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+type Options struct {
+	// RunAt delays the job, it is run right away when zero.
+	RunAt time.Time
+	// UniqueKey drops the job while a pending job has the key.
+	UniqueKey string
+	// MaxAttempts overrides the attempts of the worker config.
+	MaxAttempts int
+}
+
+// Enqueue writes the job in the transaction, so it is only run once
+// the transaction commits. It returns nil when a pending job has the
+// unique key of the options.
+func Enqueue(ctx context.Ctx, tx *repo.Transaction, args Args, options *Options) (*Job.Job, error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	payload, err := json.Marshal(args)
+	if err != nil {
+		err = fmt.Errorf("queue enqueue error: marshal %s args error: %w", args.JobKind(), err)
+		return nil, err
+	}
+
+	job := &Job.Job{
+		Kind:        string(args.JobKind()),
+		Payload:     string(payload),
+		Status:      Job.StatusPending,
+		MaxAttempts: options.MaxAttempts,
+		RunAt:       options.RunAt.UTC(),
+	}
+
+	if options.RunAt.IsZero() {
+		job.RunAt = time.Now().UTC()
+	}
+
+	if options.UniqueKey != "" {
+		job.UniqueKey = null.StringFrom(options.UniqueKey)
+	}
+
+	created, err := tx.Job.Create(ctx, job)
+	if err != nil {
+		err = fmt.Errorf("queue enqueue error: %w", err)
+		return nil, err
+	}
+
+	if !created {
+		return nil, nil
+	}
+
+	tx.AfterCommit(Notify)
+
+	return job, nil
+}
@@ -0,0 +1,193 @@
+package queue
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	Job "github.com/koraygocmen/golang-boilerplate/internal/model/job"
+	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+	JobRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/job"
+)
+
+type testArgs struct {
+	Name string `json:"name"`
+}
+
+func (testArgs) JobKind() Kind {
+	return "test"
+}
+
+// transactionTest records the created jobs, a job
+// with a unique key is only created once.
+func transactionTest(jobs *[]*Job.Job, callbacks *int) *repo.Transaction {
+	keys := map[string]bool{}
+	return &repo.Transaction{
+		Job: &JobRepo.Repo{
+			Create: func(ctx context.Ctx, job *Job.Job) (bool, error) {
+				if job.UniqueKey.Valid {
+					if keys[job.UniqueKey.String] {
+						return false, nil
+					}
+					keys[job.UniqueKey.String] = true
+				}
+				*jobs = append(*jobs, job)
+				return true, nil
+			},
+		},
+		AfterCommit: func(fn func()) {
+			*callbacks++
+		},
+	}
+}
+
+func TestEnqueue(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		jobs      []*Job.Job
+		callbacks int
+	)
+	tx := transactionTest(&jobs, &callbacks)
+
+	runAt := time.Now().Add(time.Hour)
+	job, err := Enqueue(ctx, tx, testArgs{Name: "name"}, &Options{RunAt: runAt, UniqueKey: "key", MaxAttempts: 3})
+	if err != nil || job == nil {
+		t.Fatalf("want: job; got: job = %v, err = %v", job, err)
+	}
+
+	if job.Kind != "test" || job.Payload != `{"name":"name"}` || job.Status != Job.StatusPending {
+		t.Fatalf("want: pending test job; got: %+v", job)
+	}
+	if !job.RunAt.Equal(runAt) || job.MaxAttempts != 3 || job.UniqueKey.String != "key" {
+		t.Fatalf("want: options set; got: %+v", job)
+	}
+
+	// The duplicate is dropped.
+	job, err = Enqueue(ctx, tx, testArgs{Name: "name"}, &Options{UniqueKey: "key"})
+	if err != nil || job != nil {
+		t.Fatalf("want: nil, nil; got: job = %v, err = %v", job, err)
+	}
+
+	job, err = Enqueue(ctx, tx, testArgs{}, nil)
+	if err != nil || job == nil {
+		t.Fatalf("want: job; got: job = %v, err = %v", job, err)
+	}
+	if job.RunAt.IsZero() || job.UniqueKey.Valid {
+		t.Fatalf("want: run now without a unique key; got: %+v", job)
+	}
+
+	if len(jobs) != 2 || callbacks != 2 {
+		t.Fatalf("want: 2 jobs and callbacks; got: %d jobs, %d callbacks", len(jobs), callbacks)
+	}
+}
+
+func TestHandle(t *testing.T) {
+	registry := NewRegistry()
+
+	var got testArgs
+	Handle(registry, func(ctx context.Ctx, args testArgs) error {
+		got = args
+		return nil
+	})
+
+	handler, ok := registry.Handler("test")
+	if !ok {
+		t.Fatalf("want: handler of test; got: none")
+	}
+
+	if err := handler(context.Background(), `{"name":"name"}`); err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+	if got.Name != "name" {
+		t.Fatalf("want: name; got: %s", got.Name)
+	}
+
+	if err := handler(context.Background(), `{`); err == nil {
+		t.Fatalf("want: err != nil; got: err = nil")
+	}
+
+	if kinds := registry.Kinds(); len(kinds) != 1 || kinds[0] != "test" {
+		t.Fatalf("want: kinds = [test]; got: %v", kinds)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("panic", func(ctx context.Ctx, payload string) error {
+		panic("boom")
+	})
+	registry.Register("timeout", func(ctx context.Ctx, payload string) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	w := New(Config{}, registry)
+	w.timeout = time.Millisecond
+
+	cases := []struct {
+		kind string
+		want string
+	}{
+		{kind: "panic", want: "job panic: boom"},
+		{kind: "timeout", want: "context deadline exceeded"},
+		{kind: "unknown", want: "no handler for job kind unknown"},
+	}
+
+	for _, c := range cases {
+		err := w.execute(context.Background(), &Job.Job{Kind: c.kind})
+		if err == nil || err.Error() != c.want {
+			t.Fatalf("%s: want: err = %s; got: err = %v", c.kind, c.want, err)
+		}
+	}
+}
+
+func TestRecord(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	w := New(Config{MaxAttempts: 2, BackoffBaseSec: 10, BackoffMaxSec: 60}, NewRegistry())
+	w.now = func() time.Time { return now }
+
+	job := &Job.Job{Status: Job.StatusPending}
+
+	w.record(job, errors.New("failed"))
+	if job.Status != Job.StatusPending || job.Attempts != 1 || !job.RunAt.After(now) {
+		t.Fatalf("want: retried later; got: %+v", job)
+	}
+
+	w.record(job, errors.New("failed"))
+	if job.Status != Job.StatusDead || !job.FinishedAt.Valid || job.LastError.String != "failed" {
+		t.Fatalf("want: dead; got: %+v", job)
+	}
+
+	// The job attempts override the worker.
+	job = &Job.Job{Status: Job.StatusPending, MaxAttempts: 1}
+	w.record(job, errors.New("failed"))
+	if job.Status != Job.StatusDead {
+		t.Fatalf("want: dead; got: %+v", job)
+	}
+
+	job = &Job.Job{Status: Job.StatusPending}
+	w.record(job, nil)
+	if job.Status != Job.StatusSucceeded || !job.FinishedAt.Valid || job.LastError.Valid {
+		t.Fatalf("want: succeeded; got: %+v", job)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	// Drain a pending notification of another test.
+	select {
+	case <-notify:
+	default:
+	}
+
+	Notify()
+	Notify()
+
+	select {
+	case <-notify:
+	default:
+		t.Fatalf("want: notification; got: none")
+	}
+}
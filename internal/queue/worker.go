@@ -0,0 +1,261 @@
+package queue
+
+import (
+	goctx "context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	Job "github.com/koraygocmen/golang-boilerplate/internal/model/job"
+	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+	"github.com/koraygocmen/golang-boilerplate/pkg/duration"
+	"github.com/koraygocmen/null"
+)
+
+const (
+	ConcurrencyDefault = 4
+	PollDefault        = 5 * time.Second
+	MaxAttemptsDefault = 10
+	BackoffBaseDefault = 10 * time.Second
+	BackoffMaxDefault  = time.Hour
+	TimeoutDefault     = 5 * time.Minute
+
+	// errorMaxLen bounds the error stored with the job.
+	errorMaxLen = 1024
+	// closeTimeout bounds the wait for the jobs in flight on Close.
+	closeTimeout = 30 * time.Second
+)
+
+var (
+	// notify wakes up an idle worker before the next poll.
+	notify = make(chan struct{}, 1)
+)
+
+type Config struct {
+	Concurrency    int
+	PollSec        int
+	MaxAttempts    int
+	BackoffBaseSec int
+	BackoffMaxSec  int
+	TimeoutSec     int
+}
+
+// Notify wakes up an idle worker, it never blocks.
+func Notify() {
+	select {
+	case notify <- struct{}{}:
+	default:
+	}
+}
+
+// Worker runs the due jobs. Each job runs in the transaction
+// that locks its row, so the other workers skip it and a job
+// interrupted by a crash is unlocked and run again.
+type Worker struct {
+	registry    *Registry
+	concurrency int
+	poll        time.Duration
+	maxAttempts int
+	backoffBase time.Duration
+	backoffMax  time.Duration
+	timeout     time.Duration
+	now         func() time.Time
+
+	cancel goctx.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New(config Config, registry *Registry) *Worker {
+	w := &Worker{
+		registry:    registry,
+		concurrency: config.Concurrency,
+		poll:        duration.Seconds(config.PollSec),
+		maxAttempts: config.MaxAttempts,
+		backoffBase: duration.Seconds(config.BackoffBaseSec),
+		backoffMax:  duration.Seconds(config.BackoffMaxSec),
+		timeout:     duration.Seconds(config.TimeoutSec),
+		now:         time.Now,
+	}
+
+	if w.concurrency <= 0 {
+		w.concurrency = ConcurrencyDefault
+	}
+
+	if w.poll <= 0 {
+		w.poll = PollDefault
+	}
+
+	if w.maxAttempts <= 0 {
+		w.maxAttempts = MaxAttemptsDefault
+	}
+
+	if w.backoffBase <= 0 {
+		w.backoffBase = BackoffBaseDefault
+	}
+
+	if w.backoffMax <= 0 {
+		w.backoffMax = BackoffMaxDefault
+	}
+
+	if w.timeout <= 0 {
+		w.timeout = TimeoutDefault
+	}
+
+	return w
+}
+
+// Start runs the jobs in concurrency goroutines until Close.
+func (w *Worker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	for i := 0; i < w.concurrency; i++ {
+		w.wg.Add(1)
+		go w.run(ctx)
+	}
+}
+
+// Close stops the worker and waits for the jobs in flight.
+func (w *Worker) Close() error {
+	if w.cancel == nil {
+		return nil
+	}
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(closeTimeout):
+		return fmt.Errorf("queue worker close error: timed out after %v", closeTimeout)
+	}
+}
+
+func (w *Worker) run(ctx context.Ctx) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.poll)
+	defer ticker.Stop()
+
+	for {
+		// Keep going while jobs are due. The jobs are not run
+		// with the worker context, so Close lets them finish.
+		for ctx.Err() == nil {
+			ran, err := w.Work(context.Background())
+			if err != nil {
+				logger.Logger.Errorf(ctx, `msg="queue work failed", err="%v"`, err)
+				break
+			}
+
+			if !ran {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-notify:
+		}
+	}
+}
+
+// Work runs the next due job and reports whether a job was run.
+func (w *Worker) Work(ctx context.Ctx) (bool, error) {
+	tx := repo.New(ctx)
+
+	jobs, err := tx.Job.ListDue(ctx, w.now().UTC(), 1)
+	if err != nil {
+		tx.Rollback()
+		err = fmt.Errorf("queue work error: %w", err)
+		return false, err
+	}
+
+	if len(jobs) == 0 {
+		tx.Rollback()
+		return false, nil
+	}
+	job := jobs[0]
+
+	err = w.execute(ctx, job)
+	w.record(job, err)
+
+	if err != nil {
+		logger.Logger.Errorf(ctx, `msg="queue job failed", job_id="%d", kind="%s", attempts="%d", status="%s", err="%v"`,
+			job.ID, job.Kind, job.Attempts, job.Status, err)
+	}
+
+	if err := tx.Job.Save(ctx, job); err != nil {
+		tx.Rollback()
+		err = fmt.Errorf("queue work error: %w", err)
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		err = fmt.Errorf("queue work error: commit error: %w", err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// execute runs the handler of the job with the timeout,
+// a panic of the handler fails the attempt.
+func (w *Worker) execute(ctx context.Ctx, job *Job.Job) (err error) {
+	handler, ok := w.registry.Handler(Kind(job.Kind))
+	if !ok {
+		return fmt.Errorf("no handler for job kind %s", job.Kind)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panic: %v", r)
+		}
+	}()
+
+	return handler(ctx, job.Payload)
+}
+
+// record updates the job with the result of the attempt.
+func (w *Worker) record(job *Job.Job, err error) {
+	now := w.now().UTC()
+
+	job.Attempts++
+
+	if err == nil {
+		job.Status = Job.StatusSucceeded
+		job.LastError = null.String{}
+		job.FinishedAt = null.TimeFrom(now)
+		return
+	}
+
+	lastError := err.Error()
+	if len(lastError) > errorMaxLen {
+		lastError = lastError[:errorMaxLen]
+	}
+	job.LastError = null.StringFrom(lastError)
+
+	maxAttempts := job.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = w.maxAttempts
+	}
+
+	if job.Attempts >= maxAttempts {
+		job.Status = Job.StatusDead
+		job.FinishedAt = null.TimeFrom(now)
+		return
+	}
+
+	job.RunAt = now.Add(duration.Backoff(job.Attempts, w.backoffBase, w.backoffMax))
+}
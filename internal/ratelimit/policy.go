@@ -0,0 +1,138 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Subject string
+
+const (
+	// SubjectRoute shares the limit between all the clients of the route.
+	SubjectRoute Subject = "route"
+	// SubjectIP limits each client IP.
+	SubjectIP Subject = "ip"
+	// SubjectUser limits each authenticated user.
+	SubjectUser Subject = "user"
+	// SubjectAPIKey limits each API key.
+	SubjectAPIKey Subject = "apikey"
+)
+
+var (
+	Subjects = map[Subject]bool{
+		SubjectRoute:  true,
+		SubjectIP:     true,
+		SubjectUser:   true,
+		SubjectAPIKey: true,
+	}
+)
+
+// Policy allows limit requests per window for each subject
+// of the requests matching the route and the method. Route
+// is "*", an exact path or a prefix ending with "*", i.e.
+// "/v1/users*". Method is "*" or methods separated by "|".
+type Policy struct {
+	Route   string        `json:"route"`
+	Methods []string      `json:"methods"`
+	Subject Subject       `json:"subject"`
+	Limit   int64         `json:"limit"`
+	Window  time.Duration `json:"window"`
+}
+
+// ID identifies the counters of the policy.
+func (p Policy) ID() string {
+	methods := "*"
+	if len(p.Methods) > 0 {
+		methods = strings.Join(p.Methods, "|")
+	}
+	return fmt.Sprintf("%s:%s:%s:%d:%d", p.Route, methods, p.Subject, p.Limit, int64(p.Window.Seconds()))
+}
+
+// Match reports whether the policy applies to the request.
+func (p Policy) Match(method, path string) bool {
+	if len(p.Methods) > 0 {
+		var ok bool
+		for _, m := range p.Methods {
+			if m == method {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	if p.Route == "*" {
+		return true
+	}
+
+	if prefix, ok := strings.CutSuffix(p.Route, "*"); ok {
+		return strings.HasPrefix(path, prefix)
+	}
+
+	return p.Route == path
+}
+
+// ParsePolicies parses a comma separated list of policies in the
+// form of ROUTE:METHODS:SUBJECT:LIMIT:WINDOW_SEC where route and
+// methods can be "*", i.e. "/v1/users:POST:ip:5:60,*:*:user:600:60".
+func ParsePolicies(policies string) ([]Policy, error) {
+	var parsed []Policy
+
+	for _, spec := range strings.Split(policies, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		pieces := strings.Split(spec, ":")
+		if len(pieces) != 5 {
+			err := fmt.Errorf("parse policies error: invalid policy: %s", spec)
+			return nil, err
+		}
+
+		route := strings.TrimSpace(pieces[0])
+		if route != "*" && !strings.HasPrefix(route, "/") {
+			err := fmt.Errorf("parse policies error: route must be \"*\" or start with \"/\": %s", route)
+			return nil, err
+		}
+
+		var methods []string
+		if m := strings.TrimSpace(pieces[1]); m != "*" {
+			for _, method := range strings.Split(m, "|") {
+				methods = append(methods, strings.ToUpper(strings.TrimSpace(method)))
+			}
+		}
+
+		subject := Subject(strings.ToLower(strings.TrimSpace(pieces[2])))
+		if !Subjects[subject] {
+			err := fmt.Errorf("parse policies error: invalid subject: %s", pieces[2])
+			return nil, err
+		}
+
+		limit, err := strconv.ParseInt(strings.TrimSpace(pieces[3]), 10, 64)
+		if err != nil || limit <= 0 {
+			err := fmt.Errorf("parse policies error: invalid limit: %s", pieces[3])
+			return nil, err
+		}
+
+		windowSec, err := strconv.Atoi(strings.TrimSpace(pieces[4]))
+		if err != nil || windowSec <= 0 {
+			err := fmt.Errorf("parse policies error: invalid window: %s", pieces[4])
+			return nil, err
+		}
+
+		parsed = append(parsed, Policy{
+			Route:   route,
+			Methods: methods,
+			Subject: subject,
+			Limit:   limit,
+			Window:  time.Duration(windowSec) * time.Second,
+		})
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,204 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+)
+
+const (
+	StorageMemory   = "memory"
+	StoragePostgres = "postgres"
+	StorageRedis    = "redis"
+
+	// PoliciesDefault limits the writes of each
+	// IP to 20 requests per minute.
+	PoliciesDefault = "*:POST|PUT|PATCH|DELETE:ip:20:60"
+)
+
+var (
+	// Limiter is the rate limiter of the requests, the
+	// requests are not limited when it is not set.
+	Limiter *RateLimiter
+)
+
+type Config struct {
+	Storage  string
+	Policies string
+
+	Redis struct {
+		Addr      string
+		Password  string
+		DB        int
+		TimeoutMs int
+	}
+}
+
+// Result is the state of the most restrictive
+// policy that applied to a request.
+type Result struct {
+	Policy    Policy
+	Limit     int64
+	Remaining int64
+	// Reset is the time left until the window resets.
+	Reset   time.Duration
+	Allowed bool
+}
+
+// RetryAfter is the wait before the request is allowed again.
+func (r *Result) RetryAfter() time.Duration {
+	if r.Allowed {
+		return 0
+	}
+	return r.Reset
+}
+
+// RateLimiter counts the requests of the policies in fixed windows.
+// The counters live in the storage, so the replicas sharing a
+// storage share the limits.
+type RateLimiter struct {
+	storage  Storage
+	policies []Policy
+	now      func() time.Time
+}
+
+func New(config Config) (*RateLimiter, error) {
+	policies := config.Policies
+	if policies == "" {
+		policies = PoliciesDefault
+	}
+
+	parsed, err := ParsePolicies(policies)
+	if err != nil {
+		err = fmt.Errorf("rate limiter new error: %w", err)
+		return nil, err
+	}
+
+	var storage Storage
+	switch strings.ToLower(config.Storage) {
+	case "", StorageMemory:
+		storage = NewMemoryStorage()
+	case StoragePostgres:
+		storage = NewPostgresStorage()
+	case StorageRedis:
+		storage, err = NewRedisStorage(RedisConfig{
+			Addr:     config.Redis.Addr,
+			Password: config.Redis.Password,
+			DB:       config.Redis.DB,
+			Timeout:  time.Duration(config.Redis.TimeoutMs) * time.Millisecond,
+		})
+		if err != nil {
+			err = fmt.Errorf("rate limiter new error: %w", err)
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("rate limiter new error: unknown storage: %s", config.Storage)
+	}
+
+	return NewWithStorage(storage, parsed), nil
+}
+
+func NewWithStorage(storage Storage, policies []Policy) *RateLimiter {
+	return &RateLimiter{
+		storage:  storage,
+		policies: policies,
+		now:      time.Now,
+	}
+}
+
+// Policies returns the policies of the limiter.
+func (l *RateLimiter) Policies() []Policy {
+	return append([]Policy{}, l.policies...)
+}
+
+// Allow counts the request against each policy of the subjects that
+// matches the method and the path. The subjects without a value are
+// skipped. It returns nil when no policy applies.
+func (l *RateLimiter) Allow(ctx context.Ctx, method, path string, subjects map[Subject]string) (*Result, error) {
+	now := l.now()
+
+	var result *Result
+	for _, policy := range l.policies {
+		value, ok := subjects[policy.Subject]
+		if !ok || (value == "" && policy.Subject != SubjectRoute) || !policy.Match(method, path) {
+			continue
+		}
+
+		// Fixed windows aligned to the epoch, the
+		// window index is part of the counter key.
+		window := now.UnixNano() / int64(policy.Window)
+		windowEnd := time.Unix(0, (window+1)*int64(policy.Window))
+
+		key := "rl:" + policy.ID() + ":" + subjectKey(policy.Subject, value) + ":" + strconv.FormatInt(window, 10)
+
+		count, err := l.storage.Incr(ctx, key, windowEnd.Sub(now))
+		if err != nil {
+			err = fmt.Errorf("rate limiter allow error: %w", err)
+			return nil, err
+		}
+
+		r := &Result{
+			Policy:    policy,
+			Limit:     policy.Limit,
+			Remaining: policy.Limit - count,
+			Reset:     windowEnd.Sub(now),
+			Allowed:   count <= policy.Limit,
+		}
+		if r.Remaining < 0 {
+			r.Remaining = 0
+		}
+
+		if result == nil || restrictive(r, result) {
+			result = r
+		}
+	}
+
+	return result, nil
+}
+
+// restrictive reports whether a is more restrictive than b,
+// a denied result wins over an allowed one.
+func restrictive(a, b *Result) bool {
+	if a.Allowed != b.Allowed {
+		return !a.Allowed
+	}
+
+	if !a.Allowed {
+		return a.Reset > b.Reset
+	}
+
+	return a.Remaining < b.Remaining
+}
+
+// subjectKey keeps the API keys out of the storage.
+func subjectKey(subject Subject, value string) string {
+	if subject == SubjectAPIKey {
+		sum := sha256.Sum256([]byte(value))
+		return hex.EncodeToString(sum[:16])
+	}
+	return value
+}
+
+// Close closes the storage.
+func (l *RateLimiter) Close() error {
+	return l.storage.Close()
+}
+
+// Seconds rounds the duration up to whole seconds for the headers.
+func Seconds(d time.Duration) int {
+	seconds := int(d / time.Second)
+	if d%time.Second > 0 {
+		seconds++
+	}
+	return seconds
+}
+
+// PolicyHeader formats the policy for the RateLimit-Policy header.
+func PolicyHeader(policy Policy) string {
+	return fmt.Sprintf("%d;w=%d", policy.Limit, Seconds(policy.Window))
+}
@@ -0,0 +1,201 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+)
+
+func TestParsePolicies(t *testing.T) {
+	policies, err := ParsePolicies("*:POST|put:ip:20:60, /v1/users*:*:User:600:3600")
+	if err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+	if len(policies) != 2 {
+		t.Fatalf("want: 2 policies; got: %d", len(policies))
+	}
+
+	if p := policies[0]; p.Route != "*" || len(p.Methods) != 2 || p.Methods[1] != "PUT" || p.Subject != SubjectIP || p.Limit != 20 || p.Window != time.Minute {
+		t.Fatalf("want: policy = *:POST|PUT:ip:20:60; got: policy = %s", p.ID())
+	}
+	if p := policies[1]; p.Route != "/v1/users*" || p.Methods != nil || p.Subject != SubjectUser || p.Limit != 600 || p.Window != time.Hour {
+		t.Fatalf("want: policy = /v1/users*:*:user:600:3600; got: policy = %s", p.ID())
+	}
+
+	for _, invalid := range []string{
+		"*:*:ip:20",
+		"v1/users:*:ip:20:60",
+		"*:*:device:20:60",
+		"*:*:ip:0:60",
+		"*:*:ip:20:x",
+	} {
+		if _, err := ParsePolicies(invalid); err == nil {
+			t.Fatalf("want: err for %s; got: err = nil", invalid)
+		}
+	}
+}
+
+func TestPolicyMatch(t *testing.T) {
+	tests := []struct {
+		policy Policy
+		method string
+		path   string
+		want   bool
+	}{
+		{Policy{Route: "*"}, "GET", "/v1/users", true},
+		{Policy{Route: "*", Methods: []string{"POST"}}, "GET", "/v1/users", false},
+		{Policy{Route: "/v1/users"}, "GET", "/v1/users", true},
+		{Policy{Route: "/v1/users"}, "GET", "/v1/users/sessions", false},
+		{Policy{Route: "/v1/users*"}, "GET", "/v1/users/sessions", true},
+		{Policy{Route: "/v1/admin*"}, "GET", "/v1/users", false},
+	}
+
+	for _, test := range tests {
+		if got := test.policy.Match(test.method, test.path); got != test.want {
+			t.Fatalf("want: match %s %s %s = %t; got: %t", test.policy.ID(), test.method, test.path, test.want, got)
+		}
+	}
+}
+
+func TestAllow(t *testing.T) {
+	ctx := context.Background()
+
+	policies, err := ParsePolicies("*:POST:ip:2:60,/v1/users/sessions:POST:ip:1:60,*:*:user:3:60")
+	if err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+
+	now := time.Date(2026, 1, 1, 0, 0, 15, 0, time.UTC)
+	storage := NewMemoryStorage().(*memoryStorage)
+	storage.now = func() time.Time { return now }
+
+	l := NewWithStorage(storage, policies)
+	l.now = func() time.Time { return now }
+
+	// No policy of the subjects applies.
+	result, err := l.Allow(ctx, "GET", "/v1/users", map[Subject]string{SubjectIP: "1.1.1.1"})
+	if err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+	if result != nil {
+		t.Fatalf("want: result = nil; got: result = %+v", result)
+	}
+
+	ip := map[Subject]string{SubjectIP: "1.1.1.1"}
+
+	result, _ = l.Allow(ctx, "POST", "/v1/users", ip)
+	if !result.Allowed || result.Limit != 2 || result.Remaining != 1 {
+		t.Fatalf("want: allowed, limit = 2, remaining = 1; got: %+v", result)
+	}
+	if result.Reset != 45*time.Second {
+		t.Fatalf("want: reset = 45s; got: reset = %s", result.Reset)
+	}
+
+	result, _ = l.Allow(ctx, "POST", "/v1/users/sessions", ip)
+	if !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("want: allowed, remaining = 0; got: %+v", result)
+	}
+
+	// The requests over the limit are denied.
+	result, _ = l.Allow(ctx, "POST", "/v1/users/sessions", ip)
+	if result.Allowed || result.RetryAfter() != 45*time.Second {
+		t.Fatalf("want: denied, retry after = 45s; got: %+v", result)
+	}
+
+	// Other subjects have their own counters.
+	result, _ = l.Allow(ctx, "POST", "/v1/users", map[Subject]string{SubjectIP: "2.2.2.2"})
+	if !result.Allowed {
+		t.Fatalf("want: allowed; got: %+v", result)
+	}
+
+	// The subjects without a value are skipped.
+	result, _ = l.Allow(ctx, "GET", "/v1/users", map[Subject]string{SubjectUser: ""})
+	if result != nil {
+		t.Fatalf("want: result = nil; got: result = %+v", result)
+	}
+
+	// The next window starts over.
+	now = now.Add(45 * time.Second)
+	result, _ = l.Allow(ctx, "POST", "/v1/users", ip)
+	if !result.Allowed || result.Remaining != 1 || result.Reset != time.Minute {
+		t.Fatalf("want: allowed, remaining = 1, reset = 1m; got: %+v", result)
+	}
+}
+
+func TestMemoryStorage(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	storage := NewMemoryStorage().(*memoryStorage)
+	storage.now = func() time.Time { return now }
+
+	for i := int64(1); i <= 3; i++ {
+		count, err := storage.Incr(ctx, "key", 2*time.Minute)
+		if err != nil {
+			t.Fatalf("want: err = nil; got: err = %v", err)
+		}
+		if count != i {
+			t.Fatalf("want: count = %d; got: count = %d", i, count)
+		}
+	}
+
+	// The expired counters start over and are swept.
+	now = now.Add(2 * time.Minute)
+	if count, _ := storage.Incr(ctx, "other", time.Minute); count != 1 {
+		t.Fatalf("want: count = 1; got: count = %d", count)
+	}
+	if _, ok := storage.counters["key"]; ok {
+		t.Fatalf("want: expired counter swept; got: counter kept")
+	}
+	if count, _ := storage.Incr(ctx, "key", time.Minute); count != 1 {
+		t.Fatalf("want: count = 1; got: count = %d", count)
+	}
+}
+
+func TestRedisStorage(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := NewRedisStorage(RedisConfig{}); err == nil {
+		t.Fatalf("want: err for missing address; got: err = nil")
+	}
+
+	server := miniredis.RunT(t)
+	server.RequireAuth("password")
+
+	storage, err := NewRedisStorage(RedisConfig{
+		Addr:     server.Addr(),
+		Password: "password",
+		DB:       2,
+	})
+	if err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+	defer storage.Close()
+
+	for i := int64(1); i <= 2; i++ {
+		count, err := storage.Incr(ctx, "key", 1500*time.Millisecond)
+		if err != nil {
+			t.Fatalf("want: err = nil; got: err = %v", err)
+		}
+		if count != i {
+			t.Fatalf("want: count = %d; got: count = %d", i, count)
+		}
+	}
+
+	// The counter is kept in the db with the ttl of the first incr.
+	server.Select(2)
+	if got, _ := server.Get("key"); got != "2" {
+		t.Fatalf("want: counter = 2; got: counter = %s", got)
+	}
+	if ttl := server.TTL("key"); ttl != 1500*time.Millisecond {
+		t.Fatalf("want: ttl = 1.5s; got: ttl = %s", ttl)
+	}
+
+	// The counter is reset once it expires.
+	server.FastForward(2 * time.Second)
+	if count, err := storage.Incr(ctx, "key", time.Minute); err != nil || count != 1 {
+		t.Fatalf("want: count = 1; got: count = %d, err = %v", count, err)
+	}
+}
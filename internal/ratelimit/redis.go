@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	RedisTimeoutDefault = time.Second
+)
+
+var (
+	// redisIncr increments the counter and sets the expiry
+	// of a new counter in a single round trip.
+	redisIncr = redis.NewScript(`local c = redis.call('INCR', KEYS[1])
+if c == 1 then redis.call('PEXPIRE', KEYS[1], ARGV[1]) end
+return c`)
+)
+
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+	Timeout  time.Duration
+}
+
+// redisStorage keeps the counters in any server that speaks the
+// redis protocol and runs lua scripts, i.e. redis, valkey or
+// dragonfly. The client pools the connections.
+type redisStorage struct {
+	client *redis.Client
+}
+
+func NewRedisStorage(config RedisConfig) (Storage, error) {
+	if config.Addr == "" {
+		return nil, fmt.Errorf("redis storage new error: address missing")
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = RedisTimeoutDefault
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:         config.Addr,
+		Password:     config.Password,
+		DB:           config.DB,
+		DialTimeout:  config.Timeout,
+		ReadTimeout:  config.Timeout,
+		WriteTimeout: config.Timeout,
+	})
+
+	return &redisStorage{
+		client: client,
+	}, nil
+}
+
+func (r *redisStorage) Incr(ctx context.Ctx, key string, ttl time.Duration) (int64, error) {
+	count, err := redisIncr.Run(ctx, r.client, []string{key}, ttl.Milliseconds()).Int64()
+	if err != nil {
+		err = fmt.Errorf("redis storage incr error: %w", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *redisStorage) Close() error {
+	if err := r.client.Close(); err != nil {
+		err = fmt.Errorf("redis storage close error: %w", err)
+		return err
+	}
+	return nil
+}
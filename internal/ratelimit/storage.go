@@ -0,0 +1,134 @@
+package ratelimit
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/database"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	RateLimitRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/rate_limit"
+)
+
+const (
+	// sweepInterval is how often the expired counters are removed.
+	sweepInterval = time.Minute
+)
+
+// Storage keeps the request counters.
+type Storage interface {
+	// Incr increments the counter of the key and returns the new
+	// count. A new counter expires after the ttl.
+	Incr(ctx context.Ctx, key string, ttl time.Duration) (int64, error)
+	Close() error
+}
+
+// memoryStorage keeps the counters of this replica only.
+type memoryStorage struct {
+	lock     sync.Mutex
+	counters map[string]*memoryCounter
+	now      func() time.Time
+	swept    time.Time
+}
+
+type memoryCounter struct {
+	count    int64
+	expireAt time.Time
+}
+
+func NewMemoryStorage() Storage {
+	return &memoryStorage{
+		counters: make(map[string]*memoryCounter),
+		now:      time.Now,
+	}
+}
+
+func (m *memoryStorage) Incr(ctx context.Ctx, key string, ttl time.Duration) (int64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := m.now()
+
+	// Remove the expired counters now and then.
+	if now.Sub(m.swept) >= sweepInterval {
+		for k, c := range m.counters {
+			if !now.Before(c.expireAt) {
+				delete(m.counters, k)
+			}
+		}
+		m.swept = now
+	}
+
+	counter, ok := m.counters[key]
+	if !ok || !now.Before(counter.expireAt) {
+		counter = &memoryCounter{expireAt: now.Add(ttl)}
+		m.counters[key] = counter
+	}
+
+	counter.count++
+	return counter.count, nil
+}
+
+func (m *memoryStorage) Close() error {
+	return nil
+}
+
+// postgresStorage keeps the counters in the rate limit table, the
+// expired counters are removed in the background until Close. The
+// counters expire with the clock of the database.
+type postgresStorage struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewPostgresStorage() Storage {
+	p := &postgresStorage{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go p.sweep()
+
+	return p
+}
+
+func (p *postgresStorage) Incr(ctx context.Ctx, key string, ttl time.Duration) (int64, error) {
+	// The counters are not part of the request transaction.
+	rateLimitRepo := RateLimitRepo.New(database.DB.GORM)
+
+	count, err := rateLimitRepo.Incr(ctx, key, ttl)
+	if err != nil {
+		err = fmt.Errorf("postgres storage incr error: %w", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+func (p *postgresStorage) sweep() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx := context.Background()
+		rateLimitRepo := RateLimitRepo.New(database.DB.GORM)
+
+		if _, err := rateLimitRepo.DeleteExpired(ctx); err != nil {
+			logger.Logger.Errorf(ctx, `msg="rate limit sweep failed", err="%v"`, err)
+		}
+	}
+}
+
+func (p *postgresStorage) Close() error {
+	close(p.stop)
+	<-p.done
+	return nil
+}
@@ -0,0 +1,166 @@
+package realtime
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/database"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	"github.com/lib/pq"
+)
+
+const (
+	// Channel is the postgres channel of the events.
+	Channel = "realtime"
+
+	// PayloadMax is the max size of the payloads of the
+	// postgres notifications, the larger ones are refused.
+	PayloadMax = 8000
+
+	listenerReconnectMin = time.Second
+	listenerReconnectMax = time.Minute
+	// listenerPing checks the connection of the listener
+	// while no notifications arrive.
+	listenerPing = 90 * time.Second
+)
+
+// PubSub fans the events out to the brokers of the replicas.
+type PubSub interface {
+	// Publish sends the payload to the listeners of all the replicas.
+	Publish(ctx context.Ctx, payload string) error
+	// Listen calls deliver with the published payloads until Close.
+	Listen(deliver func(payload string)) error
+	Close() error
+}
+
+// memoryPubSub delivers the events to the listener of this replica only.
+type memoryPubSub struct {
+	lock    sync.RWMutex
+	deliver func(payload string)
+}
+
+func NewMemoryPubSub() PubSub {
+	return &memoryPubSub{}
+}
+
+func (m *memoryPubSub) Publish(ctx context.Ctx, payload string) error {
+	m.lock.RLock()
+	deliver := m.deliver
+	m.lock.RUnlock()
+
+	if deliver != nil {
+		deliver(payload)
+	}
+	return nil
+}
+
+func (m *memoryPubSub) Listen(deliver func(payload string)) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.deliver = deliver
+	return nil
+}
+
+func (m *memoryPubSub) Close() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.deliver = nil
+	return nil
+}
+
+// postgresPubSub notifies the events on the channel, the listeners of
+// the replicas receive them on their own connection. The notifications
+// are sent once the transaction of the notify commits, and they are not
+// kept while a listener is reconnecting.
+type postgresPubSub struct {
+	dsn      string
+	listener *pq.Listener
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewPostgresPubSub(dsn string) PubSub {
+	return &postgresPubSub{
+		dsn: dsn,
+	}
+}
+
+func (p *postgresPubSub) Publish(ctx context.Ctx, payload string) error {
+	if len(payload) > PayloadMax {
+		return fmt.Errorf("postgres pubsub publish error: payload of %d bytes exceeds %d", len(payload), PayloadMax)
+	}
+
+	if err := database.DB.GORM.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", Channel, payload).Error; err != nil {
+		err = fmt.Errorf("postgres pubsub publish error: %w", err)
+		return err
+	}
+	return nil
+}
+
+func (p *postgresPubSub) Listen(deliver func(payload string)) error {
+	ctx := context.Background()
+
+	p.listener = pq.NewListener(p.dsn, listenerReconnectMin, listenerReconnectMax, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			logger.Logger.Errorf(ctx, `msg="realtime listener failed", err="%v"`, err)
+		}
+	})
+
+	if err := p.listener.Listen(Channel); err != nil {
+		p.listener.Close()
+		err = fmt.Errorf("postgres pubsub listen error: %w", err)
+		return err
+	}
+
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	go p.receive(deliver)
+
+	return nil
+}
+
+func (p *postgresPubSub) receive(deliver func(payload string)) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(listenerPing)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+
+		case notification := <-p.listener.Notify:
+			// The listener reconnected, the events
+			// of the meantime are not received.
+			if notification == nil {
+				logger.Logger.Warnf(context.Background(), `msg="realtime listener reconnected"`)
+				continue
+			}
+			deliver(notification.Extra)
+
+		case <-ticker.C:
+			go p.listener.Ping()
+		}
+	}
+}
+
+func (p *postgresPubSub) Close() error {
+	if p.listener == nil {
+		return nil
+	}
+
+	close(p.stop)
+	<-p.done
+
+	if err := p.listener.Close(); err != nil {
+		err = fmt.Errorf("postgres pubsub close error: %w", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package realtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	"github.com/koraygocmen/golang-boilerplate/pkg/duration"
+)
+
+type Name string
+
+const (
+	NameSessionRevoked Name = "session.revoked"
+	NameUserUpdated    Name = "user.updated"
+)
+
+const (
+	PubSubMemory   = "memory"
+	PubSubPostgres = "postgres"
+
+	HeartbeatDefault = 15 * time.Second
+
+	// BufferSize is the number of the events a stream may fall behind,
+	// the streams that fall further behind are closed.
+	BufferSize = 16
+
+	// streamMargin ends the streams before the write timeout.
+	streamMargin = time.Second
+)
+
+var (
+	// Default is the broker of the streams of this replica, it is set
+	// up on start. The events are dropped until it is started.
+	Default = NewWithPubSub(Config{}, NewMemoryPubSub())
+)
+
+type Config struct {
+	PubSub string
+	// DSN is the connection of the postgres listener.
+	DSN          string
+	HeartbeatSec int
+	// StreamSec is the write timeout of the server, the streams end
+	// before it and the clients reconnect. The streams do not end if
+	// it is not set.
+	StreamSec int
+}
+
+// Event is the event pushed to the streams of the sessions of the user.
+type Event struct {
+	Name   Name  `json:"name"`
+	UserID int64 `json:"userId"`
+	// UserSessionIDs are the sessions the event is pushed
+	// to, it is pushed to all the sessions if it is empty.
+	UserSessionIDs []int64                `json:"userSessionIds,omitempty"`
+	Data           map[string]interface{} `json:"data,omitempty"`
+}
+
+// targets reports whether the event is pushed to the session.
+func (e *Event) targets(userSessionID int64) bool {
+	if len(e.UserSessionIDs) == 0 {
+		return true
+	}
+
+	for _, id := range e.UserSessionIDs {
+		if id == userSessionID {
+			return true
+		}
+	}
+	return false
+}
+
+// Broker pushes the events to the streams of the sessions. The events
+// are published to the pubsub, which fans them out to the brokers of
+// all the replicas, and every broker pushes them to its own streams.
+type Broker struct {
+	pubsub    PubSub
+	heartbeat time.Duration
+	lifetime  time.Duration
+
+	lock          sync.Mutex
+	subscriptions map[int64]map[*Subscription]bool
+	closed        bool
+}
+
+func New(config Config) (*Broker, error) {
+	var pubsub PubSub
+	switch strings.ToLower(config.PubSub) {
+	case "", PubSubMemory:
+		pubsub = NewMemoryPubSub()
+	case PubSubPostgres:
+		pubsub = NewPostgresPubSub(config.DSN)
+	default:
+		return nil, fmt.Errorf("realtime new error: unknown pubsub: %s", config.PubSub)
+	}
+
+	return NewWithPubSub(config, pubsub), nil
+}
+
+func NewWithPubSub(config Config, pubsub PubSub) *Broker {
+	b := &Broker{
+		pubsub:        pubsub,
+		heartbeat:     duration.Seconds(config.HeartbeatSec),
+		lifetime:      duration.Seconds(config.StreamSec) - streamMargin,
+		subscriptions: make(map[int64]map[*Subscription]bool),
+	}
+
+	if b.heartbeat <= 0 {
+		b.heartbeat = HeartbeatDefault
+	}
+
+	if b.lifetime <= 0 {
+		b.lifetime = 0
+	}
+
+	return b
+}
+
+// Start listens to the events of the replicas until Close.
+func (b *Broker) Start() error {
+	if err := b.pubsub.Listen(b.deliver); err != nil {
+		err = fmt.Errorf("realtime start error: %w", err)
+		return err
+	}
+	return nil
+}
+
+// Close stops listening and closes the streams.
+func (b *Broker) Close() error {
+	err := b.pubsub.Close()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.closed = true
+	for _, subscriptions := range b.subscriptions {
+		for s := range subscriptions {
+			b.remove(s)
+		}
+	}
+
+	if err != nil {
+		err = fmt.Errorf("realtime close error: %w", err)
+		return err
+	}
+	return nil
+}
+
+// Publish publishes the event to the brokers of the replicas.
+func (b *Broker) Publish(ctx context.Ctx, e *Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		err = fmt.Errorf("realtime publish error: marshal %s error: %w", e.Name, err)
+		return err
+	}
+
+	if err := b.pubsub.Publish(ctx, string(payload)); err != nil {
+		err = fmt.Errorf("realtime publish error: %w", err)
+		return err
+	}
+	return nil
+}
+
+// Subscribe returns the subscription of the stream of the session,
+// the subscription is closed if the broker is closed.
+func (b *Broker) Subscribe(userID, userSessionID int64) *Subscription {
+	s := &Subscription{
+		UserID:        userID,
+		UserSessionID: userSessionID,
+		broker:        b,
+		events:        make(chan *Event, BufferSize),
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.closed {
+		s.closed = true
+		close(s.events)
+		return s
+	}
+
+	if b.subscriptions[userID] == nil {
+		b.subscriptions[userID] = make(map[*Subscription]bool)
+	}
+	b.subscriptions[userID][s] = true
+
+	return s
+}
+
+// deliver pushes the published event to the streams of this replica.
+func (b *Broker) deliver(payload string) {
+	var e Event
+	if err := json.Unmarshal([]byte(payload), &e); err != nil {
+		logger.Logger.Errorf(context.Background(), `msg="realtime deliver failed", err="%v"`, err)
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for s := range b.subscriptions[e.UserID] {
+		if !e.targets(s.UserSessionID) {
+			continue
+		}
+
+		select {
+		case s.events <- &e:
+		default:
+			// The stream fell behind, it is closed
+			// and the client catches up as it reconnects.
+			b.remove(s)
+		}
+	}
+}
+
+// remove closes the subscription, the lock is held by the caller.
+func (b *Broker) remove(s *Subscription) {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.events)
+
+	delete(b.subscriptions[s.UserID], s)
+	if len(b.subscriptions[s.UserID]) == 0 {
+		delete(b.subscriptions, s.UserID)
+	}
+}
+
+// Subscription is the subscription of the stream of a session.
+type Subscription struct {
+	UserID        int64
+	UserSessionID int64
+
+	broker *Broker
+	events chan *Event
+	// closed is guarded by the lock of the broker.
+	closed bool
+}
+
+// Events returns the events of the session, it is
+// closed once the subscription or the broker is.
+func (s *Subscription) Events() <-chan *Event {
+	return s.events
+}
+
+// Close closes the subscription.
+func (s *Subscription) Close() {
+	s.broker.lock.Lock()
+	defer s.broker.lock.Unlock()
+
+	s.broker.remove(s)
+}
@@ -0,0 +1,110 @@
+package realtime
+
+import (
+	"testing"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+)
+
+// brokerTest returns the started broker of the memory pubsub.
+func brokerTest(t *testing.T, config Config) *Broker {
+	t.Helper()
+
+	b := NewWithPubSub(config, NewMemoryPubSub())
+	if err := b.Start(); err != nil {
+		t.Fatalf("want: start error nil; got: %v", err)
+	}
+	t.Cleanup(func() { b.Close() })
+
+	return b
+}
+
+// received returns the events received by the subscription so far.
+func received(s *Subscription) []Name {
+	var names []Name
+	for {
+		select {
+		case e, ok := <-s.Events():
+			if !ok {
+				return names
+			}
+			names = append(names, e.Name)
+		default:
+			return names
+		}
+	}
+}
+
+func TestBroker(t *testing.T) {
+	ctx := context.Background()
+	b := brokerTest(t, Config{})
+
+	s1 := b.Subscribe(1, 1)
+	s2 := b.Subscribe(1, 2)
+	s3 := b.Subscribe(2, 3)
+
+	// Test the events are pushed to the sessions of the user.
+	if err := b.Publish(ctx, &Event{Name: NameUserUpdated, UserID: 1, Data: map[string]interface{}{"user": map[string]interface{}{"id": 1}}}); err != nil {
+		t.Fatalf("want: publish error nil; got: %v", err)
+	}
+	if err := b.Publish(ctx, &Event{Name: NameSessionRevoked, UserID: 1, UserSessionIDs: []int64{2}}); err != nil {
+		t.Fatalf("want: publish error nil; got: %v", err)
+	}
+
+	if got := received(s1); len(got) != 1 || got[0] != NameUserUpdated {
+		t.Fatalf("want: session 1 events [%s]; got: %v", NameUserUpdated, got)
+	}
+	if got := received(s2); len(got) != 2 || got[1] != NameSessionRevoked {
+		t.Fatalf("want: session 2 events [%s %s]; got: %v", NameUserUpdated, NameSessionRevoked, got)
+	}
+	if got := received(s3); len(got) != 0 {
+		t.Fatalf("want: session 3 no events; got: %v", got)
+	}
+
+	// Test the closed subscriptions are not pushed to.
+	s1.Close()
+	s1.Close()
+	if _, ok := <-s1.Events(); ok {
+		t.Fatalf("want: session 1 events closed; got: open")
+	}
+	if err := b.Publish(ctx, &Event{Name: NameUserUpdated, UserID: 1}); err != nil {
+		t.Fatalf("want: publish error nil; got: %v", err)
+	}
+	if got := received(s2); len(got) != 1 {
+		t.Fatalf("want: session 2 events [%s]; got: %v", NameUserUpdated, got)
+	}
+
+	// Test the subscriptions behind are closed.
+	for i := 0; i <= BufferSize; i++ {
+		b.Publish(ctx, &Event{Name: NameUserUpdated, UserID: 2})
+	}
+	if got := received(s3); len(got) != BufferSize {
+		t.Fatalf("want: session 3 %d events then closed; got: %d", BufferSize, len(got))
+	}
+	if _, ok := <-s3.Events(); ok {
+		t.Fatalf("want: session 3 events closed; got: open")
+	}
+
+	// Test the subscriptions are closed with the broker.
+	b.Close()
+	if _, ok := <-s2.Events(); ok {
+		t.Fatalf("want: session 2 events closed; got: open")
+	}
+	if _, ok := <-b.Subscribe(1, 1).Events(); ok {
+		t.Fatalf("want: subscription of the closed broker closed; got: open")
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Config{PubSub: "unknown"}); err == nil {
+		t.Fatalf("want: new error; got: nil")
+	}
+
+	b, err := New(Config{HeartbeatSec: 0, StreamSec: 30})
+	if err != nil {
+		t.Fatalf("want: new error nil; got: %v", err)
+	}
+	if b.heartbeat != HeartbeatDefault || b.lifetime != 29e9 {
+		t.Fatalf("want: heartbeat %v lifetime 29s; got: heartbeat %v lifetime %v", HeartbeatDefault, b.heartbeat, b.lifetime)
+	}
+}
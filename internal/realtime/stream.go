@@ -0,0 +1,79 @@
+package realtime
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	MIMETextEventStream = "text/event-stream"
+
+	// RetryMs is the delay of the clients before they reconnect.
+	RetryMs = 1000
+)
+
+// Accepted reports whether the accept header accepts the event streams,
+// the streams are only served to the requests that accept them.
+func Accepted(accept string) bool {
+	return strings.Contains(accept, MIMETextEventStream)
+}
+
+// Stream writes the events of the subscription as server sent events
+// until the subscription is closed, the session is revoked, the client
+// goes away or the lifetime of the streams passes. A comment is written
+// every heartbeat to find out the clients that went away.
+func (b *Broker) Stream(w *bufio.Writer, s *Subscription) {
+	defer s.Close()
+
+	fmt.Fprintf(w, "retry: %d\n\n", RetryMs)
+	if err := w.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(b.heartbeat)
+	defer heartbeat.Stop()
+
+	var end <-chan time.Time
+	if b.lifetime > 0 {
+		timer := time.NewTimer(b.lifetime)
+		defer timer.Stop()
+		end = timer.C
+	}
+
+	for {
+		select {
+		case e, ok := <-s.Events():
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(e)
+			if err != nil {
+				return
+			}
+
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Name, data)
+			if err := w.Flush(); err != nil {
+				return
+			}
+
+			// The stream of a revoked session ends with the event,
+			// the session cannot reconnect once it is revoked.
+			if e.Name == NameSessionRevoked {
+				return
+			}
+
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			if err := w.Flush(); err != nil {
+				return
+			}
+
+		case <-end:
+			return
+		}
+	}
+}
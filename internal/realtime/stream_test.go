@@ -0,0 +1,61 @@
+package realtime
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+)
+
+func TestStream(t *testing.T) {
+	ctx := context.Background()
+	b := brokerTest(t, Config{StreamSec: 2})
+
+	// Test the stream of a revoked session ends with the event.
+	var buf bytes.Buffer
+	s := b.Subscribe(1, 1)
+
+	done := make(chan struct{})
+	go func() {
+		b.Stream(bufio.NewWriter(&buf), s)
+		close(done)
+	}()
+
+	b.Publish(ctx, &Event{Name: NameUserUpdated, UserID: 1})
+	b.Publish(ctx, &Event{Name: NameSessionRevoked, UserID: 1, UserSessionIDs: []int64{1}})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second / 2):
+		t.Fatalf("want: stream ended by the revoke; got: still streaming")
+	}
+
+	want := "retry: 1000\n\n" +
+		"event: user.updated\ndata: {\"name\":\"user.updated\",\"userId\":1}\n\n" +
+		"event: session.revoked\ndata: {\"name\":\"session.revoked\",\"userId\":1,\"userSessionIds\":[1]}\n\n"
+	if buf.String() != want {
+		t.Fatalf("want: %q; got: %q", want, buf.String())
+	}
+	if _, ok := <-s.Events(); ok {
+		t.Fatalf("want: subscription closed; got: open")
+	}
+
+	// Test the stream ends before the write timeout.
+	buf.Reset()
+	start := time.Now()
+	b.Stream(bufio.NewWriter(&buf), b.Subscribe(1, 2))
+	if elapsed := time.Since(start); elapsed < time.Second || elapsed > 2*time.Second {
+		t.Fatalf("want: stream ended after 1s; got: %v", elapsed)
+	}
+}
+
+func TestAccepted(t *testing.T) {
+	if !Accepted("text/event-stream") || !Accepted("application/json, text/event-stream") {
+		t.Fatalf("want: event stream accepted; got: not accepted")
+	}
+	if Accepted("") || Accepted("*/*") || Accepted("application/json") {
+		t.Fatalf("want: event stream not accepted; got: accepted")
+	}
+}
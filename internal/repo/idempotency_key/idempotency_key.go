@@ -0,0 +1,105 @@
+package idempotency_key
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	IdempotencyKey "github.com/koraygocmen/golang-boilerplate/internal/model/idempotency_key"
+	"gorm.io/gorm"
+)
+
+// Function types.
+type ClaimFn func(ctx context.Ctx, idempotencyKey *IdempotencyKey.IdempotencyKey) (bool, error)
+type GetFn func(ctx context.Ctx, scope, key string) (*IdempotencyKey.IdempotencyKey, error)
+type SaveFn func(ctx context.Ctx, idempotencyKey *IdempotencyKey.IdempotencyKey) error
+type DeleteExpiredFn func(ctx context.Ctx, now time.Time) (int64, error)
+
+// Repo.
+// Repo definition and repo related fields.
+type Repo struct {
+	Claim         ClaimFn
+	Get           GetFn
+	Save          SaveFn
+	DeleteExpired DeleteExpiredFn
+}
+
+func New(tx *gorm.DB) *Repo {
+	return &Repo{
+		Claim:         claim(tx),
+		Get:           get(tx),
+		Save:          save(tx),
+		DeleteExpired: deleteExpired(tx),
+	}
+}
+
+// Functions.
+
+// claim inserts the key unless a key of the scope that has not expired
+// exists, an expired key is taken over. It reports whether the key was
+// claimed. The insert waits for a concurrent transaction that claimed
+// the same key, so the duplicates are serialized until it ends.
+func claim(tx *gorm.DB) ClaimFn {
+	return func(ctx context.Ctx, idempotencyKey *IdempotencyKey.IdempotencyKey) (bool, error) {
+		result := tx.WithContext(ctx).
+			Raw(`INSERT INTO "idempotency_key" ("scope", "key", "fingerprint", "expire_at") VALUES (?, ?, ?, ?)
+				ON CONFLICT ("scope", "key") DO UPDATE SET
+					"fingerprint" = EXCLUDED."fingerprint",
+					"status_code" = 0,
+					"content_type" = '',
+					"body" = NULL,
+					"created_at" = now() at time zone 'utc',
+					"expire_at" = EXCLUDED."expire_at"
+				WHERE "idempotency_key"."expire_at" <= now() at time zone 'utc'
+				RETURNING *`, idempotencyKey.Scope, idempotencyKey.Key, idempotencyKey.Fingerprint, idempotencyKey.ExpireAt).
+			Scan(idempotencyKey)
+		if err := result.Error; err != nil {
+			err = fmt.Errorf("idempotency key repo claim error: %w", err)
+			return false, err
+		}
+		return result.RowsAffected > 0, nil
+	}
+}
+
+func get(tx *gorm.DB) GetFn {
+	return func(ctx context.Ctx, scope, key string) (*IdempotencyKey.IdempotencyKey, error) {
+		var idempotencyKey IdempotencyKey.IdempotencyKey
+		err := tx.WithContext(ctx).
+			Where(`"scope" = ? AND "key" = ?`, scope, key).
+			First(&idempotencyKey).
+			Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			err = fmt.Errorf("idempotency key repo get error: %w", err)
+			return nil, err
+		}
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return &idempotencyKey, nil
+	}
+}
+
+func save(tx *gorm.DB) SaveFn {
+	return func(ctx context.Ctx, idempotencyKey *IdempotencyKey.IdempotencyKey) error {
+		if err := tx.WithContext(ctx).Save(idempotencyKey).Error; err != nil {
+			err = fmt.Errorf("idempotency key repo save error: %w", err)
+			return err
+		}
+		return nil
+	}
+}
+
+func deleteExpired(tx *gorm.DB) DeleteExpiredFn {
+	return func(ctx context.Ctx, now time.Time) (int64, error) {
+		result := tx.WithContext(ctx).
+			Where(`"expire_at" <= ?`, now).
+			Delete(&IdempotencyKey.IdempotencyKey{})
+		if err := result.Error; err != nil {
+			err = fmt.Errorf("idempotency key repo delete expired error: %w", err)
+			return 0, err
+		}
+		return result.RowsAffected, nil
+	}
+}
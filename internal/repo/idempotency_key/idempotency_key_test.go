@@ -0,0 +1,123 @@
+package idempotency_key
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/database/databasetest"
+	IdempotencyKey "github.com/koraygocmen/golang-boilerplate/internal/model/idempotency_key"
+)
+
+var (
+	dbTest = databasetest.Get()
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+
+	// Purge and exit.
+	dbTest.Purge()
+	os.Exit(code)
+}
+
+func dbClean() {
+	ctx := context.Background()
+
+	dbTest.DB.Reset(ctx)
+	dbTest.DB.Up(ctx)
+	dbTest.DB.Seed(ctx)
+}
+
+func TestClaim(t *testing.T) {
+	dbClean()
+
+	ctx := context.Background()
+	idempotencyKeyRepo := New(dbTest.DB.GORM)
+
+	now := time.Now().UTC()
+	first := &IdempotencyKey.IdempotencyKey{Scope: "user:1", Key: "key", Fingerprint: "a", ExpireAt: now.Add(time.Hour)}
+
+	claimed, err := idempotencyKeyRepo.Claim(ctx, first)
+	if err != nil {
+		t.Fatalf("want: claim error nil; got: %v", err)
+	}
+	if !claimed || first.ID == 0 {
+		t.Fatalf("want: claimed with id; got: claimed = %t, id = %d", claimed, first.ID)
+	}
+
+	first.StatusCode = 201
+	first.Body = []byte(`{"ok":true}`)
+	if err := idempotencyKeyRepo.Save(ctx, first); err != nil {
+		t.Fatalf("want: save error nil; got: %v", err)
+	}
+
+	// A key that has not expired is not claimed again.
+	second := &IdempotencyKey.IdempotencyKey{Scope: "user:1", Key: "key", Fingerprint: "b", ExpireAt: now.Add(time.Hour)}
+	claimed, err = idempotencyKeyRepo.Claim(ctx, second)
+	if err != nil || claimed {
+		t.Fatalf("want: not claimed; got: claimed = %t, err = %v", claimed, err)
+	}
+
+	got, err := idempotencyKeyRepo.Get(ctx, "user:1", "key")
+	if err != nil || got == nil {
+		t.Fatalf("want: idempotency key; got: %v, err = %v", got, err)
+	}
+	if got.Fingerprint != "a" || got.StatusCode != 201 || string(got.Body) != `{"ok":true}` {
+		t.Fatalf("want: first response; got: %+v", got)
+	}
+
+	// The same key of another scope is claimed.
+	other := &IdempotencyKey.IdempotencyKey{Scope: "user:1", Key: "key", Fingerprint: "a", ExpireAt: now.Add(time.Hour)}
+	if claimed, err := idempotencyKeyRepo.Claim(ctx, other); err != nil || !claimed {
+		t.Fatalf("want: claimed; got: claimed = %t, err = %v", claimed, err)
+	}
+
+	// An expired key is taken over.
+	expired := &IdempotencyKey.IdempotencyKey{Scope: "user:2", Key: "key", Fingerprint: "a", ExpireAt: now.Add(-time.Hour)}
+	idempotencyKeyRepo.Claim(ctx, expired)
+
+	renewed := &IdempotencyKey.IdempotencyKey{Scope: "user:2", Key: "key", Fingerprint: "b", ExpireAt: now.Add(time.Hour)}
+	claimed, err = idempotencyKeyRepo.Claim(ctx, renewed)
+	if err != nil || !claimed {
+		t.Fatalf("want: expired key claimed; got: claimed = %t, err = %v", claimed, err)
+	}
+	if renewed.Fingerprint != "b" || renewed.StatusCode != 0 {
+		t.Fatalf("want: renewed key; got: %+v", renewed)
+	}
+}
+
+func TestGetNotFound(t *testing.T) {
+	dbClean()
+
+	ctx := context.Background()
+	idempotencyKeyRepo := New(dbTest.DB.GORM)
+
+	got, err := idempotencyKeyRepo.Get(ctx, "user:1", "missing")
+	if err != nil {
+		t.Fatalf("want: get error nil; got: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("want: nil; got: %+v", got)
+	}
+}
+
+func TestDeleteExpired(t *testing.T) {
+	dbClean()
+
+	ctx := context.Background()
+	idempotencyKeyRepo := New(dbTest.DB.GORM)
+
+	now := time.Now().UTC()
+	idempotencyKeyRepo.Claim(ctx, &IdempotencyKey.IdempotencyKey{Scope: "user:1", Key: "expired", Fingerprint: "a", ExpireAt: now.Add(-time.Minute)})
+	idempotencyKeyRepo.Claim(ctx, &IdempotencyKey.IdempotencyKey{Scope: "user:1", Key: "active", Fingerprint: "a", ExpireAt: now.Add(time.Minute)})
+
+	n, err := idempotencyKeyRepo.DeleteExpired(ctx, now)
+	if err != nil {
+		t.Fatalf("want: delete expired error nil; got: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("want: 1 deleted; got: %d", n)
+	}
+}
@@ -0,0 +1,118 @@
+package job
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	Job "github.com/koraygocmen/golang-boilerplate/internal/model/job"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Function types.
+type CreateFn func(ctx context.Ctx, job *Job.Job) (bool, error)
+type SaveFn func(ctx context.Ctx, job *Job.Job) error
+type ListDueFn func(ctx context.Ctx, now time.Time, limit int) ([]*Job.Job, error)
+type GetByIDFn func(ctx context.Ctx, id int64) (*Job.Job, error)
+
+// Repo.
+// Repo definition and repo related fields.
+type Repo struct {
+	Create  CreateFn
+	Save    SaveFn
+	ListDue ListDueFn
+	GetByID GetByIDFn
+}
+
+func New(tx *gorm.DB) *Repo {
+	return &Repo{
+		Create:  create(tx),
+		Save:    save(tx),
+		ListDue: listDue(tx),
+		GetByID: getByID(tx),
+	}
+}
+
+// Functions.
+
+// create inserts the job and reports whether it was created. A job
+// with a unique key is not created while a pending job has the key.
+// The predicate of the partial index is a literal, a bound parameter
+// may not match the index once the statement is planned generically.
+func create(tx *gorm.DB) CreateFn {
+	return func(ctx context.Ctx, job *Job.Job) (bool, error) {
+		result := tx.WithContext(ctx).
+			Omit(clause.Associations).
+			Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "unique_key"}},
+				TargetWhere: clause.Where{Exprs: []clause.Expression{
+					clause.Expr{SQL: `"status" = 'PENDING'`},
+				}},
+				DoNothing: true,
+			}).
+			Create(job)
+		if err := result.Error; err != nil {
+			err = fmt.Errorf("job repo create error: %w", err)
+			return false, err
+		}
+		return result.RowsAffected > 0, nil
+	}
+}
+
+func save(tx *gorm.DB) SaveFn {
+	return func(ctx context.Ctx, job *Job.Job) error {
+		err := tx.WithContext(ctx).
+			Omit(clause.Associations).
+			Save(job).
+			Error
+		if err != nil {
+			err = fmt.Errorf("job repo save error: %w", err)
+			return err
+		}
+		return nil
+	}
+}
+
+// listDue locks the pending jobs that are due. Rows locked by other
+// workers are skipped and stay locked until the transaction ends.
+func listDue(tx *gorm.DB) ListDueFn {
+	return func(ctx context.Ctx, now time.Time, limit int) ([]*Job.Job, error) {
+		var jobs []*Job.Job
+		err := tx.WithContext(ctx).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where(`"status" = ?`, Job.StatusPending).
+			Where(`"run_at" <= ?`, now).
+			Order(`"run_at" ASC, "id" ASC`).
+			Limit(limit).
+			Find(&jobs).
+			Error
+		if err != nil {
+			err = fmt.Errorf("job repo list due error: %w", err)
+			return nil, err
+		}
+
+		return jobs, nil
+	}
+}
+
+func getByID(tx *gorm.DB) GetByIDFn {
+	return func(ctx context.Ctx, id int64) (*Job.Job, error) {
+		var job Job.Job
+		err := tx.WithContext(ctx).
+			Where(`"id" = ?`, id).
+			First(&job).
+			Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			err = fmt.Errorf("job repo get by id error: %w", err)
+			return nil, err
+		}
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return &job, nil
+	}
+}
@@ -0,0 +1,150 @@
+package job
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/database/databasetest"
+	Job "github.com/koraygocmen/golang-boilerplate/internal/model/job"
+	"github.com/koraygocmen/null"
+)
+
+var (
+	dbTest = databasetest.Get()
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+
+	// Purge and exit.
+	dbTest.Purge()
+	os.Exit(code)
+}
+
+func dbClean() {
+	ctx := context.Background()
+
+	dbTest.DB.Reset(ctx)
+	dbTest.DB.Up(ctx)
+	dbTest.DB.Seed(ctx)
+}
+
+func populate(now time.Time) ([]*Job.Job, error) {
+	jobRepo := New(dbTest.DB.GORM)
+
+	jobs := []*Job.Job{
+		{Status: Job.StatusPending, RunAt: now.Add(-time.Minute)},
+		{Status: Job.StatusPending, RunAt: now.Add(-time.Hour)},
+		{Status: Job.StatusPending, RunAt: now.Add(time.Minute)},
+		{Status: Job.StatusDead, RunAt: now.Add(-time.Minute)},
+		{Status: Job.StatusSucceeded, RunAt: now.Add(-time.Minute)},
+	}
+	for _, j := range jobs {
+		j.Kind = "test"
+		j.Payload = "{}"
+		j.MaxAttempts = 3
+		if _, err := jobRepo.Create(context.Background(), j); err != nil {
+			return nil, err
+		}
+	}
+
+	return jobs, nil
+}
+
+func TestCreateUnique(t *testing.T) {
+	dbClean()
+
+	ctx := context.Background()
+	jobRepo := New(dbTest.DB.GORM)
+
+	newJob := func() *Job.Job {
+		return &Job.Job{
+			Kind:        "test",
+			Payload:     "{}",
+			Status:      Job.StatusPending,
+			UniqueKey:   null.StringFrom("unique"),
+			MaxAttempts: 3,
+			RunAt:       time.Now().UTC(),
+		}
+	}
+
+	first := newJob()
+	created, err := jobRepo.Create(ctx, first)
+	if err != nil || !created {
+		t.Fatalf("want: created; got: created = %v, err = %v", created, err)
+	}
+
+	// A pending job holds the key.
+	created, err = jobRepo.Create(ctx, newJob())
+	if err != nil || created {
+		t.Fatalf("want: not created; got: created = %v, err = %v", created, err)
+	}
+
+	// The key is released once the job is finished.
+	first.Status = Job.StatusSucceeded
+	if err := jobRepo.Save(ctx, first); err != nil {
+		t.Fatalf("want: save error nil; got: %v", err)
+	}
+
+	created, err = jobRepo.Create(ctx, newJob())
+	if err != nil || !created {
+		t.Fatalf("want: created; got: created = %v, err = %v", created, err)
+	}
+}
+
+func TestListDue(t *testing.T) {
+	dbClean()
+
+	now := time.Now().UTC()
+	jobs, err := populate(now)
+	if err != nil {
+		t.Fatalf("populate error: %v", err)
+	}
+
+	tx1 := dbTest.DB.GORM.Begin()
+	defer tx1.Rollback()
+
+	got, err := New(tx1).ListDue(context.Background(), now, 1)
+	if err != nil {
+		t.Fatalf("want: list due error nil; got: %v", err)
+	}
+
+	// The oldest due job first.
+	if len(got) != 1 || got[0].ID != jobs[1].ID {
+		t.Fatalf("want: job %d; got: %+v", jobs[1].ID, got)
+	}
+
+	// The other workers skip the locked job.
+	tx2 := dbTest.DB.GORM.Begin()
+	defer tx2.Rollback()
+
+	got, err = New(tx2).ListDue(context.Background(), now, 10)
+	if err != nil {
+		t.Fatalf("want: list due error nil; got: %v", err)
+	}
+
+	if len(got) != 1 || got[0].ID != jobs[0].ID {
+		t.Fatalf("want: job %d; got: %+v", jobs[0].ID, got)
+	}
+}
+
+func TestGetByID(t *testing.T) {
+	dbClean()
+
+	jobs, err := populate(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("populate error: %v", err)
+	}
+
+	got, err := New(dbTest.DB.GORM).GetByID(context.Background(), jobs[0].ID)
+	if err != nil || got == nil || got.ID != jobs[0].ID {
+		t.Fatalf("want: job %d; got: %+v, err = %v", jobs[0].ID, got, err)
+	}
+
+	got, err = New(dbTest.DB.GORM).GetByID(context.Background(), -1)
+	if err != nil || got != nil {
+		t.Fatalf("want: nil, nil; got: %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,93 @@
+package outbox
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	Outbox "github.com/koraygocmen/golang-boilerplate/internal/model/outbox"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Function types.
+type CreateFn func(ctx context.Ctx, outbox *Outbox.Outbox) error
+type SaveFn func(ctx context.Ctx, outbox *Outbox.Outbox) error
+type ListPendingFn func(ctx context.Ctx, now time.Time, limit int) ([]*Outbox.Outbox, error)
+
+// Repo.
+// Repo definition and repo related fields.
+type Repo struct {
+	Create      CreateFn
+	Save        SaveFn
+	ListPending ListPendingFn
+}
+
+func New(tx *gorm.DB) *Repo {
+	return &Repo{
+		Create:      create(tx),
+		Save:        save(tx),
+		ListPending: listPending(tx),
+	}
+}
+
+// Functions.
+func create(tx *gorm.DB) CreateFn {
+	return func(ctx context.Ctx, outbox *Outbox.Outbox) error {
+		err := tx.WithContext(ctx).
+			Omit(clause.Associations).
+			Create(outbox).
+			Error
+		if err != nil {
+			err = fmt.Errorf("outbox repo create error: %w", err)
+			return err
+		}
+		return nil
+	}
+}
+
+func save(tx *gorm.DB) SaveFn {
+	return func(ctx context.Ctx, outbox *Outbox.Outbox) error {
+		err := tx.WithContext(ctx).
+			Omit(clause.Associations).
+			Save(outbox).
+			Error
+		if err != nil {
+			err = fmt.Errorf("outbox repo save error: %w", err)
+			return err
+		}
+		return nil
+	}
+}
+
+// listPending locks the unpublished events that are due. Only the
+// oldest unpublished event of each aggregate is listed, so the events
+// of an aggregate are relayed in order, one after the other. Rows
+// locked by other relays are skipped and stay locked until the
+// transaction ends.
+func listPending(tx *gorm.DB) ListPendingFn {
+	return func(ctx context.Ctx, now time.Time, limit int) ([]*Outbox.Outbox, error) {
+		var outboxes []*Outbox.Outbox
+		err := tx.WithContext(ctx).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where(`"outbox"."published_at" IS NULL`).
+			Where(`"outbox"."next_attempt_at" <= ?`, now).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM "outbox" AS "prev"
+				WHERE "prev"."aggregate_type" = "outbox"."aggregate_type"
+				AND "prev"."aggregate_id" = "outbox"."aggregate_id"
+				AND "prev"."published_at" IS NULL
+				AND "prev"."id" < "outbox"."id"
+			)`).
+			Order(`"outbox"."id" ASC`).
+			Limit(limit).
+			Find(&outboxes).
+			Error
+		if err != nil {
+			err = fmt.Errorf("outbox repo list pending error: %w", err)
+			return nil, err
+		}
+
+		return outboxes, nil
+	}
+}
@@ -0,0 +1,105 @@
+package outbox
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/database/databasetest"
+	Outbox "github.com/koraygocmen/golang-boilerplate/internal/model/outbox"
+	"github.com/koraygocmen/null"
+)
+
+var (
+	dbTest = databasetest.Get()
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+
+	// Purge and exit.
+	dbTest.Purge()
+	os.Exit(code)
+}
+
+func dbClean() {
+	ctx := context.Background()
+
+	dbTest.DB.Reset(ctx)
+	dbTest.DB.Up(ctx)
+	dbTest.DB.Seed(ctx)
+}
+
+func populate(now time.Time) ([]*Outbox.Outbox, error) {
+	outboxRepo := New(dbTest.DB.GORM)
+
+	outboxes := []*Outbox.Outbox{
+		{AggregateType: "user", AggregateID: "1", Event: "user.created", NextAttemptAt: now},
+		{AggregateType: "user", AggregateID: "1", Event: "user.updated", NextAttemptAt: now},
+		{AggregateType: "user", AggregateID: "2", Event: "user.created", NextAttemptAt: now.Add(time.Minute)},
+		{AggregateType: "user", AggregateID: "3", Event: "user.created", NextAttemptAt: now, PublishedAt: null.TimeFrom(now)},
+		{AggregateType: "user", AggregateID: "3", Event: "user.updated", NextAttemptAt: now},
+	}
+	for _, o := range outboxes {
+		o.Payload = "{}"
+		if err := outboxRepo.Create(context.Background(), o); err != nil {
+			return nil, err
+		}
+	}
+
+	return outboxes, nil
+}
+
+func TestListPending(t *testing.T) {
+	dbClean()
+
+	now := time.Now().UTC()
+	outboxes, err := populate(now)
+	if err != nil {
+		t.Fatalf("populate error: %v", err)
+	}
+
+	tx1 := dbTest.DB.GORM.Begin()
+	defer tx1.Rollback()
+
+	// The head of each aggregate that is due.
+	got, err := New(tx1).ListPending(context.Background(), now, 10)
+	if err != nil {
+		t.Fatalf("want: list pending error nil; got: %v", err)
+	}
+
+	if len(got) != 2 || got[0].ID != outboxes[0].ID || got[1].ID != outboxes[4].ID {
+		t.Fatalf("want: events %d and %d; got: %+v", outboxes[0].ID, outboxes[4].ID, got)
+	}
+
+	// The other relays skip the locked heads and wait
+	// for them before the next event of the aggregate.
+	tx2 := dbTest.DB.GORM.Begin()
+	defer tx2.Rollback()
+
+	got, err = New(tx2).ListPending(context.Background(), now, 10)
+	if err != nil {
+		t.Fatalf("want: list pending error nil; got: %v", err)
+	}
+
+	if len(got) != 0 {
+		t.Fatalf("want: no events; got: %+v", got)
+	}
+
+	// Publishing the head releases the next event.
+	outboxes[0].PublishedAt = null.TimeFrom(now)
+	if err := New(tx1).Save(context.Background(), outboxes[0]); err != nil {
+		t.Fatalf("want: save error nil; got: %v", err)
+	}
+	tx1.Commit()
+
+	got, err = New(tx2).ListPending(context.Background(), now, 10)
+	if err != nil {
+		t.Fatalf("want: list pending error nil; got: %v", err)
+	}
+
+	if len(got) != 2 || got[0].ID != outboxes[1].ID {
+		t.Fatalf("want: event %d first; got: %+v", outboxes[1].ID, got)
+	}
+}
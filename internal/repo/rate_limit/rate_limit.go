@@ -0,0 +1,69 @@
+package rate_limit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	RateLimit "github.com/koraygocmen/golang-boilerplate/internal/model/rate_limit"
+	"gorm.io/gorm"
+)
+
+// Function types.
+type IncrFn func(ctx context.Ctx, key string, ttl time.Duration) (int64, error)
+type DeleteExpiredFn func(ctx context.Ctx) (int64, error)
+
+// Repo.
+// Repo definition and repo related fields.
+type Repo struct {
+	Incr          IncrFn
+	DeleteExpired DeleteExpiredFn
+}
+
+func New(tx *gorm.DB) *Repo {
+	return &Repo{
+		Incr:          incr(tx),
+		DeleteExpired: deleteExpired(tx),
+	}
+}
+
+// Functions.
+
+// incr increments the counter of the key in a single statement and
+// returns the new count. An expired counter starts over from one. The
+// expiry is set and compared with the clock of the database, so the
+// instances with skewed clocks share the same windows.
+func incr(tx *gorm.DB) IncrFn {
+	return func(ctx context.Ctx, key string, ttl time.Duration) (int64, error) {
+		var rateLimit RateLimit.RateLimit
+		err := tx.WithContext(ctx).
+			Raw(`INSERT INTO "rate_limit" ("key", "count", "expire_at")
+				VALUES (?, 1, now() at time zone 'utc' + ?::bigint * interval '1 millisecond')
+				ON CONFLICT ("key") DO UPDATE SET
+					"count" = CASE WHEN "rate_limit"."expire_at" <= now() at time zone 'utc' THEN 1 ELSE "rate_limit"."count" + 1 END,
+					"expire_at" = CASE WHEN "rate_limit"."expire_at" <= now() at time zone 'utc' THEN EXCLUDED."expire_at" ELSE "rate_limit"."expire_at" END
+				RETURNING "key", "count", "expire_at"`, key, ttl.Milliseconds()).
+			Scan(&rateLimit).
+			Error
+		if err != nil {
+			err = fmt.Errorf("rate limit repo incr error: %w", err)
+			return 0, err
+		}
+		return rateLimit.Count, nil
+	}
+}
+
+// deleteExpired deletes the expired counters with the
+// clock of the database like incr expires them.
+func deleteExpired(tx *gorm.DB) DeleteExpiredFn {
+	return func(ctx context.Ctx) (int64, error) {
+		result := tx.WithContext(ctx).
+			Where(`"expire_at" <= now() at time zone 'utc'`).
+			Delete(&RateLimit.RateLimit{})
+		if err := result.Error; err != nil {
+			err = fmt.Errorf("rate limit repo delete expired error: %w", err)
+			return 0, err
+		}
+		return result.RowsAffected, nil
+	}
+}
@@ -0,0 +1,76 @@
+package rate_limit
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/database/databasetest"
+)
+
+var (
+	dbTest = databasetest.Get()
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+
+	// Purge and exit.
+	dbTest.Purge()
+	os.Exit(code)
+}
+
+func dbClean() {
+	ctx := context.Background()
+
+	dbTest.DB.Reset(ctx)
+	dbTest.DB.Up(ctx)
+	dbTest.DB.Seed(ctx)
+}
+
+func TestIncr(t *testing.T) {
+	dbClean()
+
+	ctx := context.Background()
+	rateLimitRepo := New(dbTest.DB.GORM)
+
+	for want := int64(1); want <= 3; want++ {
+		got, err := rateLimitRepo.Incr(ctx, "key", time.Minute)
+		if err != nil {
+			t.Fatalf("want: incr error nil; got: %v", err)
+		}
+		if got != want {
+			t.Fatalf("want: count %d; got: %d", want, got)
+		}
+	}
+
+	// An expired counter starts over.
+	got, err := rateLimitRepo.Incr(ctx, "expired", -time.Minute)
+	if err != nil || got != 1 {
+		t.Fatalf("want: count 1; got: %d, err = %v", got, err)
+	}
+
+	got, err = rateLimitRepo.Incr(ctx, "expired", time.Minute)
+	if err != nil || got != 1 {
+		t.Fatalf("want: count 1 after expiry; got: %d, err = %v", got, err)
+	}
+}
+
+func TestDeleteExpired(t *testing.T) {
+	dbClean()
+
+	ctx := context.Background()
+	rateLimitRepo := New(dbTest.DB.GORM)
+
+	rateLimitRepo.Incr(ctx, "expired", -time.Minute)
+	rateLimitRepo.Incr(ctx, "active", time.Minute)
+
+	n, err := rateLimitRepo.DeleteExpired(ctx)
+	if err != nil {
+		t.Fatalf("want: delete expired error nil; got: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("want: 1 deleted; got: %d", n)
+	}
+}
@@ -3,8 +3,15 @@ package repo
 import (
 	"github.com/koraygocmen/golang-boilerplate/internal/context"
 	"github.com/koraygocmen/golang-boilerplate/internal/database"
+	IdempotencyKeyRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/idempotency_key"
+	JobRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/job"
+	OutboxRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/outbox"
+	RateLimitRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/rate_limit"
 	UserRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/user"
+	UserPreferenceRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/user_preference"
 	UserSessionRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/user_session"
+	WebhookDeliveryRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/webhook_delivery"
+	WebhookSubscriptionRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/webhook_subscription"
 	"gorm.io/gorm"
 	gormlogger "gorm.io/gorm/logger"
 )
@@ -14,14 +21,25 @@ import (
 // ensure that all operations are done in the
 // same transaction.
 type Transaction struct {
-	tx *gorm.DB
+	tx          *gorm.DB
+	afterCommit []func()
 
 	Commit   func() error
 	Rollback func() error
 	Ping     func() error
+	// AfterCommit registers a function to run once the
+	// transaction commits. It is not run on rollback.
+	AfterCommit func(fn func())
 
-	User        *UserRepo.Repo
-	UserSession *UserSessionRepo.Repo
+	IdempotencyKey      *IdempotencyKeyRepo.Repo
+	Job                 *JobRepo.Repo
+	Outbox              *OutboxRepo.Repo
+	RateLimit           *RateLimitRepo.Repo
+	User                *UserRepo.Repo
+	UserPreference      *UserPreferenceRepo.Repo
+	UserSession         *UserSessionRepo.Repo
+	WebhookDelivery     *WebhookDeliveryRepo.Repo
+	WebhookSubscription *WebhookSubscriptionRepo.Repo
 }
 
 func New(ctx context.Ctx) *Transaction {
@@ -30,30 +48,56 @@ func New(ctx context.Ctx) *Transaction {
 	transaction := &Transaction{
 		tx: tx,
 
-		User:        UserRepo.New(tx),
-		UserSession: UserSessionRepo.New(tx),
+		IdempotencyKey:      IdempotencyKeyRepo.New(tx),
+		Job:                 JobRepo.New(tx),
+		Outbox:              OutboxRepo.New(tx),
+		RateLimit:           RateLimitRepo.New(tx),
+		User:                UserRepo.New(tx),
+		UserPreference:      UserPreferenceRepo.New(tx),
+		UserSession:         UserSessionRepo.New(tx),
+		WebhookDelivery:     WebhookDeliveryRepo.New(tx),
+		WebhookSubscription: WebhookSubscriptionRepo.New(tx),
 	}
 
 	// Set the commit, rollback and ping functions.
 	transaction.Commit = commit(transaction)
 	transaction.Rollback = rollback(transaction)
 	transaction.Ping = ping(transaction)
+	transaction.AfterCommit = afterCommit(transaction)
 
 	return transaction
 }
 
 func commit(transaction *Transaction) func() error {
 	return func() error {
-		return transaction.tx.Commit().Error
+		if err := transaction.tx.Commit().Error; err != nil {
+			transaction.afterCommit = nil
+			return err
+		}
+
+		// The functions registered in the transaction run once the
+		// commit succeeds, i.e. the relay of the emitted events.
+		for _, fn := range transaction.afterCommit {
+			fn()
+		}
+		transaction.afterCommit = nil
+		return nil
 	}
 }
 
 func rollback(transaction *Transaction) func() error {
 	return func() error {
+		transaction.afterCommit = nil
 		return transaction.tx.Rollback().Error
 	}
 }
 
+func afterCommit(transaction *Transaction) func(fn func()) {
+	return func(fn func()) {
+		transaction.afterCommit = append(transaction.afterCommit, fn)
+	}
+}
+
 func ping(transaction *Transaction) func() error {
 	return func() error {
 		return transaction.tx.
@@ -3,6 +3,7 @@ package user_repo
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/koraygocmen/golang-boilerplate/internal/context"
 	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
@@ -10,33 +11,48 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+var (
+	// ErrVersionConflict is returned when the user
+	// was saved by another transaction meanwhile.
+	ErrVersionConflict = errors.New("version conflict")
+)
+
 // Function types.
 type CreateFn func(ctx context.Ctx, user *User.User) error
 type SaveFn func(ctx context.Ctx, user *User.User) error
 type ListFn func(ctx context.Ctx, pageSize, pageNum int) ([]*User.User, error)
 type TotalFn func(ctx context.Ctx) (int64, error)
 type GetByIDFn func(ctx context.Ctx, id int64) (*User.User, error)
+type ListByIDsFn func(ctx context.Ctx, ids []int64) ([]*User.User, error)
 type GetByEmailFn func(ctx context.Ctx, email string) (*User.User, error)
+type ListUnverifiedFn func(ctx context.Ctx, before time.Time, limit int) ([]*User.User, error)
+type DeleteFn func(ctx context.Ctx, user *User.User) error
 
 // Repo.
 // Repo definition and repo related fields.
 type Repo struct {
-	Create     CreateFn
-	Save       SaveFn
-	List       ListFn
-	Total      TotalFn
-	GetByID    GetByIDFn
-	GetByEmail GetByEmailFn
+	Create         CreateFn
+	Save           SaveFn
+	List           ListFn
+	Total          TotalFn
+	GetByID        GetByIDFn
+	ListByIDs      ListByIDsFn
+	GetByEmail     GetByEmailFn
+	ListUnverified ListUnverifiedFn
+	Delete         DeleteFn
 }
 
 func New(tx *gorm.DB) *Repo {
 	return &Repo{
-		Create:     create(tx),
-		Save:       save(tx),
-		List:       list(tx),
-		Total:      total(tx),
-		GetByID:    getByID(tx),
-		GetByEmail: getByEmail(tx),
+		Create:         create(tx),
+		Save:           save(tx),
+		List:           list(tx),
+		Total:          total(tx),
+		GetByID:        getByID(tx),
+		ListByIDs:      listByIDs(tx),
+		GetByEmail:     getByEmail(tx),
+		ListUnverified: listUnverified(tx),
+		Delete:         delete(tx),
 	}
 }
 
@@ -55,16 +71,31 @@ func create(tx *gorm.DB) CreateFn {
 	}
 }
 
+// save updates all the fields of the user guarded by the version, the
+// version is incremented. The user saved by another transaction since
+// it was read is not updated and ErrVersionConflict is returned.
 func save(tx *gorm.DB) SaveFn {
 	return func(ctx context.Ctx, user *User.User) error {
-		err := tx.WithContext(ctx).
-			Omit(clause.Associations).
-			Save(user).
-			Error
-		if err != nil {
+		version := user.Version
+		user.Version++
+
+		result := tx.WithContext(ctx).
+			Model(user).
+			Select("*").
+			Omit(clause.Associations, "id", "created_at").
+			Where(`"version" = ?`, version).
+			Updates(user)
+		if err := result.Error; err != nil {
+			user.Version = version
 			err = fmt.Errorf("user repo save error: %w", err)
 			return err
 		}
+
+		if result.RowsAffected == 0 {
+			user.Version = version
+			err := fmt.Errorf("user repo save error: %w", ErrVersionConflict)
+			return err
+		}
 		return nil
 	}
 }
@@ -126,6 +157,29 @@ func getByID(tx *gorm.DB) GetByIDFn {
 	}
 }
 
+// listByIDs returns the users of the ids in the order of
+// their ids, the ids without a user are skipped.
+func listByIDs(tx *gorm.DB) ListByIDsFn {
+	return func(ctx context.Ctx, ids []int64) ([]*User.User, error) {
+		users := []*User.User{}
+		if len(ids) == 0 {
+			return users, nil
+		}
+
+		err := tx.WithContext(ctx).
+			Where(`"id" IN ?`, ids).
+			Order(`"id" ASC`).
+			Find(&users).
+			Error
+		if err != nil {
+			err = fmt.Errorf("user repo list by ids error: %w", err)
+			return nil, err
+		}
+
+		return users, nil
+	}
+}
+
 func getByEmail(tx *gorm.DB) GetByEmailFn {
 	return func(ctx context.Ctx, email string) (*User.User, error) {
 		var user User.User
@@ -145,3 +199,38 @@ func getByEmail(tx *gorm.DB) GetByEmailFn {
 		return &user, nil
 	}
 }
+
+// listUnverified lists the users that did not verify
+// their email and were created before the time.
+func listUnverified(tx *gorm.DB) ListUnverifiedFn {
+	return func(ctx context.Ctx, before time.Time, limit int) ([]*User.User, error) {
+		var users []*User.User
+		err := tx.WithContext(ctx).
+			Where(`"email_verified" IS NOT TRUE`).
+			Where(`"created_at" < ?`, before).
+			Order(`"id" ASC`).
+			Limit(limit).
+			Find(&users).
+			Error
+		if err != nil {
+			err = fmt.Errorf("user repo list unverified error: %w", err)
+			return nil, err
+		}
+
+		return users, nil
+	}
+}
+
+func delete(tx *gorm.DB) DeleteFn {
+	return func(ctx context.Ctx, user *User.User) error {
+		err := tx.WithContext(ctx).
+			Omit(clause.Associations).
+			Delete(user).
+			Error
+		if err != nil {
+			err = fmt.Errorf("user repo delete error: %w", err)
+			return err
+		}
+		return nil
+	}
+}
@@ -1,8 +1,10 @@
 package user_repo
 
 import (
+	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/koraygocmen/golang-boilerplate/internal/context"
 	databasetest "github.com/koraygocmen/golang-boilerplate/internal/database/databasetest"
@@ -164,6 +166,22 @@ func TestSave(t *testing.T) {
 	if u.Surname != uGot.Surname {
 		t.Fatal("want: surname match; got: surname does not match", u.Surname, uGot.Surname)
 	}
+
+	if uGot.Version != 2 || u.Version != 2 {
+		t.Fatalf("want: version 2; got: %d, %d", u.Version, uGot.Version)
+	}
+
+	// Save a stale version of the user.
+	stale := uGot
+	stale.Version = 1
+	stale.Surname = null.StringFrom("Stale")
+	err = userRepo.Save(context.Background(), &stale)
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("want: save error %v; got: %v", ErrVersionConflict, err)
+	}
+	if stale.Version != 1 {
+		t.Fatalf("want: stale version 1; got: %d", stale.Version)
+	}
 }
 
 func TestList(t *testing.T) {
@@ -246,6 +264,46 @@ func TestGetByID(t *testing.T) {
 	}
 }
 
+func TestListByIDs(t *testing.T) {
+	dbClean()
+
+	userRepo := New(dbTest.DB.GORM)
+
+	users := []*User.User{
+		{
+			Email:        null.StringFrom("koray1@test.com"),
+			PasswordHash: null.StringFrom("hash1"),
+		},
+		{
+			Email:        null.StringFrom("koray2@test.com"),
+			PasswordHash: null.StringFrom("hash2"),
+		},
+		{
+			Email:        null.StringFrom("koray3@test.com"),
+			PasswordHash: null.StringFrom("hash3"),
+		},
+	}
+	for _, u := range users {
+		if err := userRepo.Create(context.Background(), u); err != nil {
+			t.Fatalf("want: create error nil; got: %v", err)
+		}
+	}
+
+	// The ids without a user are skipped.
+	usersGot, err := userRepo.ListByIDs(context.Background(), []int64{users[2].ID, users[0].ID, 999})
+	if err != nil {
+		t.Fatalf("want: list by ids error nil; got: %v", err)
+	}
+	if len(usersGot) != 2 || usersGot[0].ID != users[0].ID || usersGot[1].ID != users[2].ID {
+		t.Fatalf("want: users %d and %d; got: %v", users[0].ID, users[2].ID, usersGot)
+	}
+
+	usersGot, err = userRepo.ListByIDs(context.Background(), nil)
+	if err != nil || len(usersGot) != 0 {
+		t.Fatalf("want: no users; got: %v, err = %v", usersGot, err)
+	}
+}
+
 func TestGetByEmail(t *testing.T) {
 	dbClean()
 
@@ -302,3 +360,55 @@ func TestGetByEmail(t *testing.T) {
 		}
 	}
 }
+
+func TestListUnverified(t *testing.T) {
+	dbClean()
+
+	userRepo := New(dbTest.DB.GORM)
+
+	users := []*User.User{
+		{
+			Email:         null.StringFrom("test@test.com"),
+			EmailVerified: null.BoolFrom(true),
+			PasswordHash:  null.StringFrom("hash1"),
+		},
+		{
+			Email:         null.StringFrom("test2@test.com"),
+			EmailVerified: null.BoolFrom(false),
+			PasswordHash:  null.StringFrom("hash2"),
+		},
+		{
+			Email:         null.StringFrom("test3@test.com"),
+			EmailVerified: null.BoolFrom(false),
+			PasswordHash:  null.StringFrom("hash3"),
+		},
+	}
+	for _, u := range users {
+		if err := userRepo.Create(context.Background(), u); err != nil {
+			t.Fatalf("want: create error nil; got: %v", err)
+		}
+	}
+
+	// A deleted user is not listed again.
+	if err := userRepo.Delete(context.Background(), users[2]); err != nil {
+		t.Fatalf("want: delete error nil; got: %v", err)
+	}
+
+	usersGot, err := userRepo.ListUnverified(context.Background(), time.Now().UTC().Add(time.Minute), 10)
+	if err != nil {
+		t.Fatalf("want: list unverified error nil; got: %v", err)
+	}
+
+	if len(usersGot) != 1 || usersGot[0].ID != users[1].ID {
+		t.Fatalf("want: user %d; got: %+v", users[1].ID, usersGot)
+	}
+
+	usersGot, err = userRepo.ListUnverified(context.Background(), time.Now().UTC().Add(-time.Hour), 10)
+	if err != nil {
+		t.Fatalf("want: list unverified error nil; got: %v", err)
+	}
+
+	if len(usersGot) != 0 {
+		t.Fatalf("want: no users; got: %+v", usersGot)
+	}
+}
@@ -0,0 +1,103 @@
+package user_preference
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	UserPreference "github.com/koraygocmen/golang-boilerplate/internal/model/user_preference"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Function types.
+type GetFn func(ctx context.Ctx, userID int64) (*UserPreference.UserPreference, error)
+type ListByUserIDsFn func(ctx context.Ctx, userIDs []int64) ([]*UserPreference.UserPreference, error)
+type SaveFn func(ctx context.Ctx, userPreference *UserPreference.UserPreference) error
+
+// Repo.
+// Repo definition and repo related fields.
+type Repo struct {
+	Get           GetFn
+	ListByUserIDs ListByUserIDsFn
+	Save          SaveFn
+}
+
+func New(tx *gorm.DB) *Repo {
+	return &Repo{
+		Get:           get(tx),
+		ListByUserIDs: listByUserIDs(tx),
+		Save:          save(tx),
+	}
+}
+
+// Functions.
+func get(tx *gorm.DB) GetFn {
+	return func(ctx context.Ctx, userID int64) (*UserPreference.UserPreference, error) {
+		var userPreference UserPreference.UserPreference
+		err := tx.WithContext(ctx).
+			Where(`"user_id" = ?`, userID).
+			First(&userPreference).
+			Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			err = fmt.Errorf("user preference repo get error: %w", err)
+			return nil, err
+		}
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return &userPreference, nil
+	}
+}
+
+// listByUserIDs returns the preferences of the users, the
+// users without the preferences are skipped.
+func listByUserIDs(tx *gorm.DB) ListByUserIDsFn {
+	return func(ctx context.Ctx, userIDs []int64) ([]*UserPreference.UserPreference, error) {
+		userPreferences := []*UserPreference.UserPreference{}
+		if len(userIDs) == 0 {
+			return userPreferences, nil
+		}
+
+		err := tx.WithContext(ctx).
+			Where(`"user_id" IN ?`, userIDs).
+			Order(`"user_id" ASC`).
+			Find(&userPreferences).
+			Error
+		if err != nil {
+			err = fmt.Errorf("user preference repo list by user ids error: %w", err)
+			return nil, err
+		}
+
+		return userPreferences, nil
+	}
+}
+
+// save inserts the preferences of the user or
+// updates them if the user already has them.
+func save(tx *gorm.DB) SaveFn {
+	return func(ctx context.Ctx, userPreference *UserPreference.UserPreference) error {
+		err := tx.WithContext(ctx).
+			Omit(clause.Associations).
+			Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "user_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{
+					"updated_at",
+					"locale",
+					"timezone",
+					"notify_security",
+					"notify_product",
+					"notify_marketing",
+				}),
+			}).
+			Create(userPreference).
+			Error
+		if err != nil {
+			err = fmt.Errorf("user preference repo save error: %w", err)
+			return err
+		}
+		return nil
+	}
+}
@@ -0,0 +1,140 @@
+package user_preference
+
+import (
+	"os"
+	"testing"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/database/databasetest"
+	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
+	UserPreference "github.com/koraygocmen/golang-boilerplate/internal/model/user_preference"
+	UserRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/user"
+	"github.com/koraygocmen/null"
+)
+
+var (
+	dbTest = databasetest.Get()
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+
+	// Purge and exit.
+	dbTest.Purge()
+	os.Exit(code)
+}
+
+func dbClean() {
+	ctx := context.Background()
+
+	dbTest.DB.Reset(ctx)
+	dbTest.DB.Up(ctx)
+	dbTest.DB.Seed(ctx)
+}
+
+func populate() (*User.User, error) {
+	userRepo := UserRepo.New(dbTest.DB.GORM)
+
+	user := &User.User{
+		Email:         null.StringFrom("koray@test.com"),
+		EmailVerified: null.BoolFrom(true),
+		Password:      null.StringFrom("123456"),
+		PasswordHash:  null.StringFrom("hash1"),
+		GivenNames:    null.StringFrom("KORAY"),
+		Surname:       null.StringFrom("GOCMEN"),
+	}
+	if err := userRepo.Create(context.Background(), user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func TestGetNotFound(t *testing.T) {
+	dbClean()
+
+	ctx := context.Background()
+	userPreferenceRepo := New(dbTest.DB.GORM)
+
+	got, err := userPreferenceRepo.Get(ctx, 1)
+	if err != nil {
+		t.Fatalf("want: get error nil; got: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("want: nil; got: %+v", got)
+	}
+}
+
+func TestSave(t *testing.T) {
+	dbClean()
+
+	user, err := populate()
+	if err != nil {
+		t.Fatalf("want: populate error nil; got: %v", err)
+	}
+
+	ctx := context.Background()
+	userPreferenceRepo := New(dbTest.DB.GORM)
+
+	// Test the preferences are inserted, the false
+	// opt-ins are not replaced with the defaults.
+	userPreference := UserPreference.Default(user.ID)
+	userPreference.Notifications.Security = false
+	if err := userPreferenceRepo.Save(ctx, userPreference); err != nil {
+		t.Fatalf("want: save error nil; got: %v", err)
+	}
+
+	got, err := userPreferenceRepo.Get(ctx, user.ID)
+	if err != nil || got == nil {
+		t.Fatalf("want: user preference; got: %v, err = %v", got, err)
+	}
+	if got.Locale != "tr" || got.Notifications.Security || !got.Notifications.Product {
+		t.Fatalf("want: saved preferences; got: %+v", got)
+	}
+
+	// Test the preferences are updated.
+	userPreference.Locale = "de"
+	userPreference.Timezone = "Europe/Berlin"
+	userPreference.Notifications.Marketing = true
+	if err := userPreferenceRepo.Save(ctx, userPreference); err != nil {
+		t.Fatalf("want: save error nil; got: %v", err)
+	}
+
+	got, err = userPreferenceRepo.Get(ctx, user.ID)
+	if err != nil || got == nil {
+		t.Fatalf("want: user preference; got: %v, err = %v", got, err)
+	}
+	if got.Locale != "de" || got.Timezone != "Europe/Berlin" || !got.Notifications.Marketing {
+		t.Fatalf("want: updated preferences; got: %+v", got)
+	}
+
+	// Test the preferences of a user that does not exist.
+	if err := userPreferenceRepo.Save(ctx, UserPreference.Default(user.ID+1)); err == nil {
+		t.Fatalf("want: save error; got: nil")
+	}
+}
+
+func TestListByUserIDs(t *testing.T) {
+	dbClean()
+
+	user, err := populate()
+	if err != nil {
+		t.Fatalf("want: populate error nil; got: %v", err)
+	}
+
+	ctx := context.Background()
+	userPreferenceRepo := New(dbTest.DB.GORM)
+
+	if err := userPreferenceRepo.Save(ctx, UserPreference.Default(user.ID)); err != nil {
+		t.Fatalf("want: save error nil; got: %v", err)
+	}
+
+	// The users without the preferences are skipped.
+	got, err := userPreferenceRepo.ListByUserIDs(ctx, []int64{user.ID, user.ID + 1})
+	if err != nil {
+		t.Fatalf("want: list by user ids error nil; got: %v", err)
+	}
+	if len(got) != 1 || got[0].UserID != user.ID {
+		t.Fatalf("want: preferences of user %d; got: %v", user.ID, got)
+	}
+}
@@ -15,23 +15,29 @@ import (
 type CreateFn func(ctx context.Ctx, userSession *UserSession.UserSession) error
 type GetByIDFn func(ctx context.Ctx, id int64) (*UserSession.UserSession, error)
 type ListActiveFn func(ctx context.Ctx, userID int64) ([]*UserSession.UserSession, error)
+type ListActiveByUserIDsFn func(ctx context.Ctx, userIDs []int64) ([]*UserSession.UserSession, error)
 type DeleteFn func(ctx context.Ctx, userSession *UserSession.UserSession) error
+type PurgeFn func(ctx context.Ctx, before time.Time) (int64, error)
 
 // Repo.
 // Repo definition and repo related fields.
 type Repo struct {
-	Create     CreateFn
-	GetByID    GetByIDFn
-	ListActive ListActiveFn
-	Delete     DeleteFn
+	Create              CreateFn
+	GetByID             GetByIDFn
+	ListActive          ListActiveFn
+	ListActiveByUserIDs ListActiveByUserIDsFn
+	Delete              DeleteFn
+	Purge               PurgeFn
 }
 
 func New(tx *gorm.DB) *Repo {
 	return &Repo{
-		Create:     create(tx),
-		GetByID:    getByID(tx),
-		ListActive: listActive(tx),
-		Delete:     delete(tx),
+		Create:              create(tx),
+		GetByID:             getByID(tx),
+		ListActive:          listActive(tx),
+		ListActiveByUserIDs: listActiveByUserIDs(tx),
+		Delete:              delete(tx),
+		Purge:               purge(tx),
 	}
 }
 
@@ -87,6 +93,30 @@ func listActive(tx *gorm.DB) ListActiveFn {
 	}
 }
 
+// listActiveByUserIDs returns the active sessions of the
+// users in the order of their ids.
+func listActiveByUserIDs(tx *gorm.DB) ListActiveByUserIDsFn {
+	return func(ctx context.Ctx, userIDs []int64) ([]*UserSession.UserSession, error) {
+		userSessions := []*UserSession.UserSession{}
+		if len(userIDs) == 0 {
+			return userSessions, nil
+		}
+
+		err := tx.WithContext(ctx).
+			Where(`"user_id" IN ?`, userIDs).
+			Where(`"expire_at" > ?`, time.Now().UTC()).
+			Order(`"id" ASC`).
+			Find(&userSessions).
+			Error
+		if err != nil {
+			err = fmt.Errorf("user session repo list active by user ids error: %w", err)
+			return nil, err
+		}
+
+		return userSessions, nil
+	}
+}
+
 func delete(tx *gorm.DB) DeleteFn {
 	return func(ctx context.Ctx, userSession *UserSession.UserSession) error {
 		err := tx.WithContext(ctx).
@@ -100,3 +130,19 @@ func delete(tx *gorm.DB) DeleteFn {
 		return nil
 	}
 }
+
+// purge permanently deletes the sessions that expired or
+// were deleted before the time and returns the count.
+func purge(tx *gorm.DB) PurgeFn {
+	return func(ctx context.Ctx, before time.Time) (int64, error) {
+		result := tx.WithContext(ctx).
+			Unscoped().
+			Where(`"expire_at" < ? OR "deleted_at" < ?`, before, before).
+			Delete(&UserSession.UserSession{})
+		if err := result.Error; err != nil {
+			err = fmt.Errorf("user session repo purge error: %w", err)
+			return 0, err
+		}
+		return result.RowsAffected, nil
+	}
+}
@@ -251,3 +251,65 @@ func TestListActive(t *testing.T) {
 		}
 	}
 }
+
+func TestListActiveByUserIDs(t *testing.T) {
+	dbClean()
+
+	users, userSessions, err := populate()
+	if err != nil {
+		t.Fatalf("populate error: %v", err)
+	}
+
+	userSessionRepo := New(dbTest.DB.GORM)
+
+	uGot, err := userSessionRepo.ListActiveByUserIDs(context.Background(), []int64{users[1].ID, users[0].ID, 999})
+	if err != nil {
+		t.Fatalf("want: list error nil; got: %v", err)
+	}
+
+	want := []*UserSession.UserSession{userSessions[1], userSessions[3]}
+	if len(uGot) != len(want) {
+		t.Fatalf("want: user sessions list length %d; got: %d", len(want), len(uGot))
+	}
+	for i, u := range uGot {
+		if u.ID != want[i].ID {
+			t.Fatalf("want: id match; got: id mismatch: %d != %d", u.ID, want[i].ID)
+		}
+	}
+
+	uGot, err = userSessionRepo.ListActiveByUserIDs(context.Background(), nil)
+	if err != nil || len(uGot) != 0 {
+		t.Fatalf("want: no user sessions; got: %v, err = %v", uGot, err)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	dbClean()
+
+	_, userSessions, err := populate()
+	if err != nil {
+		t.Fatalf("populate error: %v", err)
+	}
+
+	userSessionRepo := New(dbTest.DB.GORM)
+
+	// A deleted session is purged even if it has not expired.
+	if err := userSessionRepo.Delete(context.Background(), userSessions[1]); err != nil {
+		t.Fatalf("want: delete error nil; got: %v", err)
+	}
+
+	n, err := userSessionRepo.Purge(context.Background(), time.Now().UTC().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("want: purge error nil; got: %v", err)
+	}
+
+	if n != 3 {
+		t.Fatalf("want: 3 purged; got: %d", n)
+	}
+
+	var count int64
+	dbTest.DB.GORM.Unscoped().Model(&UserSession.UserSession{}).Count(&count)
+	if count != 1 {
+		t.Fatalf("want: 1 session left; got: %d", count)
+	}
+}
@@ -0,0 +1,144 @@
+package webhook_delivery
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	WebhookDelivery "github.com/koraygocmen/golang-boilerplate/internal/model/webhook_delivery"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Function types.
+type CreateFn func(ctx context.Ctx, webhookDelivery *WebhookDelivery.WebhookDelivery) error
+type SaveFn func(ctx context.Ctx, webhookDelivery *WebhookDelivery.WebhookDelivery) error
+type ListFn func(ctx context.Ctx, subscriptionID int64, status WebhookDelivery.Status, pageSize, pageNum int) ([]*WebhookDelivery.WebhookDelivery, error)
+type ListDueFn func(ctx context.Ctx, now time.Time, limit int) ([]*WebhookDelivery.WebhookDelivery, error)
+type GetByIDFn func(ctx context.Ctx, id int64) (*WebhookDelivery.WebhookDelivery, error)
+
+// Repo.
+// Repo definition and repo related fields.
+type Repo struct {
+	Create  CreateFn
+	Save    SaveFn
+	List    ListFn
+	ListDue ListDueFn
+	GetByID GetByIDFn
+}
+
+func New(tx *gorm.DB) *Repo {
+	return &Repo{
+		Create:  create(tx),
+		Save:    save(tx),
+		List:    list(tx),
+		ListDue: listDue(tx),
+		GetByID: getByID(tx),
+	}
+}
+
+// Functions.
+
+// create does nothing if the delivery of the event to the
+// subscription already exists, i.e. the event is retried.
+func create(tx *gorm.DB) CreateFn {
+	return func(ctx context.Ctx, webhookDelivery *WebhookDelivery.WebhookDelivery) error {
+		err := tx.WithContext(ctx).
+			Omit(clause.Associations).
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "outbox_id"}, {Name: "subscription_id"}},
+				DoNothing: true,
+			}).
+			Create(webhookDelivery).
+			Error
+		if err != nil {
+			err = fmt.Errorf("webhook delivery repo create error: %w", err)
+			return err
+		}
+		return nil
+	}
+}
+
+func save(tx *gorm.DB) SaveFn {
+	return func(ctx context.Ctx, webhookDelivery *WebhookDelivery.WebhookDelivery) error {
+		err := tx.WithContext(ctx).
+			Omit(clause.Associations).
+			Save(webhookDelivery).
+			Error
+		if err != nil {
+			err = fmt.Errorf("webhook delivery repo save error: %w", err)
+			return err
+		}
+		return nil
+	}
+}
+
+// list lists the deliveries of the subscription, newest first.
+// All statuses are listed if the status is empty.
+func list(tx *gorm.DB) ListFn {
+	return func(ctx context.Ctx, subscriptionID int64, status WebhookDelivery.Status, pageSize, pageNum int) ([]*WebhookDelivery.WebhookDelivery, error) {
+		query := tx.WithContext(ctx).
+			Where(`"subscription_id" = ?`, subscriptionID)
+		if status != "" {
+			query = query.Where(`"status" = ?`, status)
+		}
+
+		var webhookDeliveries []*WebhookDelivery.WebhookDelivery
+		err := query.
+			Order(`"id" DESC`).
+			Limit(pageSize).
+			Offset(pageSize * (pageNum - 1)).
+			Find(&webhookDeliveries).
+			Error
+		if err != nil {
+			err = fmt.Errorf("webhook delivery repo list error: %w", err)
+			return nil, err
+		}
+
+		return webhookDeliveries, nil
+	}
+}
+
+// listDue locks the pending deliveries that are due. Rows locked by
+// other dispatchers are skipped, so each delivery is attempted by
+// one dispatcher at a time. The locks are held until the transaction ends.
+func listDue(tx *gorm.DB) ListDueFn {
+	return func(ctx context.Ctx, now time.Time, limit int) ([]*WebhookDelivery.WebhookDelivery, error) {
+		var webhookDeliveries []*WebhookDelivery.WebhookDelivery
+		err := tx.WithContext(ctx).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where(`"status" = ?`, WebhookDelivery.StatusPending).
+			Where(`"next_attempt_at" <= ?`, now).
+			Order(`"next_attempt_at" ASC, "id" ASC`).
+			Limit(limit).
+			Find(&webhookDeliveries).
+			Error
+		if err != nil {
+			err = fmt.Errorf("webhook delivery repo list due error: %w", err)
+			return nil, err
+		}
+
+		return webhookDeliveries, nil
+	}
+}
+
+func getByID(tx *gorm.DB) GetByIDFn {
+	return func(ctx context.Ctx, id int64) (*WebhookDelivery.WebhookDelivery, error) {
+		var webhookDelivery WebhookDelivery.WebhookDelivery
+		err := tx.WithContext(ctx).
+			Where(`"id" = ?`, id).
+			First(&webhookDelivery).
+			Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			err = fmt.Errorf("webhook delivery repo get by id error: %w", err)
+			return nil, err
+		}
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return &webhookDelivery, nil
+	}
+}
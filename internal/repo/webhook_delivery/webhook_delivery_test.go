@@ -0,0 +1,168 @@
+package webhook_delivery
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/database/databasetest"
+	WebhookDelivery "github.com/koraygocmen/golang-boilerplate/internal/model/webhook_delivery"
+	WebhookSubscription "github.com/koraygocmen/golang-boilerplate/internal/model/webhook_subscription"
+	WebhookSubscriptionRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/webhook_subscription"
+	"github.com/koraygocmen/null"
+)
+
+var (
+	dbTest = databasetest.Get()
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+
+	// Purge and exit.
+	dbTest.Purge()
+	os.Exit(code)
+}
+
+func dbClean() {
+	ctx := context.Background()
+
+	dbTest.DB.Reset(ctx)
+	dbTest.DB.Up(ctx)
+	dbTest.DB.Seed(ctx)
+}
+
+func populate(now time.Time) ([]*WebhookDelivery.WebhookDelivery, error) {
+	webhookSubscriptionRepo := WebhookSubscriptionRepo.New(dbTest.DB.GORM)
+	webhookDeliveryRepo := New(dbTest.DB.GORM)
+
+	webhookSubscription := &WebhookSubscription.WebhookSubscription{
+		URL:    "https://example.com",
+		Secret: "secret",
+		Events: "user.created",
+		Active: true,
+	}
+	if err := webhookSubscriptionRepo.Create(context.Background(), webhookSubscription); err != nil {
+		return nil, err
+	}
+
+	webhookDeliveries := []*WebhookDelivery.WebhookDelivery{
+		{Status: WebhookDelivery.StatusPending, NextAttemptAt: null.TimeFrom(now.Add(-time.Minute))},
+		{Status: WebhookDelivery.StatusPending, NextAttemptAt: null.TimeFrom(now.Add(-time.Hour))},
+		{Status: WebhookDelivery.StatusPending, NextAttemptAt: null.TimeFrom(now.Add(time.Minute))},
+		{Status: WebhookDelivery.StatusDead, NextAttemptAt: null.TimeFrom(now.Add(-time.Minute))},
+		{Status: WebhookDelivery.StatusSucceeded},
+	}
+	for _, d := range webhookDeliveries {
+		d.SubscriptionID = webhookSubscription.ID
+		d.Event = "user.created"
+		d.Payload = `{"event":"user.created"}`
+		if err := webhookDeliveryRepo.Create(context.Background(), d); err != nil {
+			return nil, err
+		}
+	}
+
+	return webhookDeliveries, nil
+}
+
+func TestCreate(t *testing.T) {
+	dbClean()
+
+	webhookDeliveries, err := populate(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("populate error: %v", err)
+	}
+
+	webhookDeliveryRepo := New(dbTest.DB.GORM)
+	subscriptionID := webhookDeliveries[0].SubscriptionID
+
+	// The delivery of a retried event is not created again.
+	for i := 0; i < 2; i++ {
+		err := webhookDeliveryRepo.Create(context.Background(), &WebhookDelivery.WebhookDelivery{
+			SubscriptionID: subscriptionID,
+			OutboxID:       null.IntFrom(1),
+			Event:          "user.created",
+			Payload:        `{"event":"user.created"}`,
+			Status:         WebhookDelivery.StatusPending,
+		})
+		if err != nil {
+			t.Fatalf("want: create error nil; got: %v", err)
+		}
+	}
+
+	got, err := webhookDeliveryRepo.List(context.Background(), subscriptionID, "", 10, 1)
+	if err != nil {
+		t.Fatalf("want: list error nil; got: %v", err)
+	}
+
+	if len(got) != len(webhookDeliveries)+1 {
+		t.Fatalf("want: %d deliveries; got: %d", len(webhookDeliveries)+1, len(got))
+	}
+}
+
+func TestList(t *testing.T) {
+	dbClean()
+
+	webhookDeliveries, err := populate(time.Now().UTC())
+	if err != nil {
+		t.Fatalf("populate error: %v", err)
+	}
+
+	webhookDeliveryRepo := New(dbTest.DB.GORM)
+	subscriptionID := webhookDeliveries[0].SubscriptionID
+
+	got, err := webhookDeliveryRepo.List(context.Background(), subscriptionID, "", 10, 1)
+	if err != nil {
+		t.Fatalf("want: list error nil; got: %v", err)
+	}
+
+	if len(got) != 5 || got[0].ID != webhookDeliveries[4].ID {
+		t.Fatalf("want: 5 deliveries newest first; got: %+v", got)
+	}
+
+	got, err = webhookDeliveryRepo.List(context.Background(), subscriptionID, WebhookDelivery.StatusDead, 10, 1)
+	if err != nil {
+		t.Fatalf("want: list error nil; got: %v", err)
+	}
+
+	if len(got) != 1 || got[0].ID != webhookDeliveries[3].ID {
+		t.Fatalf("want: dead delivery; got: %+v", got)
+	}
+}
+
+func TestListDue(t *testing.T) {
+	dbClean()
+
+	now := time.Now().UTC()
+	webhookDeliveries, err := populate(now)
+	if err != nil {
+		t.Fatalf("populate error: %v", err)
+	}
+
+	tx1 := dbTest.DB.GORM.Begin()
+	defer tx1.Rollback()
+
+	// The oldest due delivery first.
+	got, err := New(tx1).ListDue(context.Background(), now, 1)
+	if err != nil {
+		t.Fatalf("want: list due error nil; got: %v", err)
+	}
+
+	if len(got) != 1 || got[0].ID != webhookDeliveries[1].ID {
+		t.Fatalf("want: delivery %d; got: %+v", webhookDeliveries[1].ID, got)
+	}
+
+	// The locked delivery is skipped by the other transactions.
+	tx2 := dbTest.DB.GORM.Begin()
+	defer tx2.Rollback()
+
+	got, err = New(tx2).ListDue(context.Background(), now, 10)
+	if err != nil {
+		t.Fatalf("want: list due error nil; got: %v", err)
+	}
+
+	if len(got) != 1 || got[0].ID != webhookDeliveries[0].ID {
+		t.Fatalf("want: delivery %d; got: %+v", webhookDeliveries[0].ID, got)
+	}
+}
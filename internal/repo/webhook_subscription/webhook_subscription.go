@@ -0,0 +1,139 @@
+package webhook_subscription
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	WebhookSubscription "github.com/koraygocmen/golang-boilerplate/internal/model/webhook_subscription"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Function types.
+type CreateFn func(ctx context.Ctx, webhookSubscription *WebhookSubscription.WebhookSubscription) error
+type SaveFn func(ctx context.Ctx, webhookSubscription *WebhookSubscription.WebhookSubscription) error
+type ListFn func(ctx context.Ctx) ([]*WebhookSubscription.WebhookSubscription, error)
+type ListByEventFn func(ctx context.Ctx, event WebhookSubscription.Event) ([]*WebhookSubscription.WebhookSubscription, error)
+type GetByIDFn func(ctx context.Ctx, id int64) (*WebhookSubscription.WebhookSubscription, error)
+type DeleteFn func(ctx context.Ctx, webhookSubscription *WebhookSubscription.WebhookSubscription) error
+
+// Repo.
+// Repo definition and repo related fields.
+type Repo struct {
+	Create      CreateFn
+	Save        SaveFn
+	List        ListFn
+	ListByEvent ListByEventFn
+	GetByID     GetByIDFn
+	Delete      DeleteFn
+}
+
+func New(tx *gorm.DB) *Repo {
+	return &Repo{
+		Create:      create(tx),
+		Save:        save(tx),
+		List:        list(tx),
+		ListByEvent: listByEvent(tx),
+		GetByID:     getByID(tx),
+		Delete:      delete(tx),
+	}
+}
+
+// Functions.
+func create(tx *gorm.DB) CreateFn {
+	return func(ctx context.Ctx, webhookSubscription *WebhookSubscription.WebhookSubscription) error {
+		err := tx.WithContext(ctx).
+			Omit(clause.Associations).
+			Create(webhookSubscription).
+			Error
+		if err != nil {
+			err = fmt.Errorf("webhook subscription repo create error: %w", err)
+			return err
+		}
+		return nil
+	}
+}
+
+func save(tx *gorm.DB) SaveFn {
+	return func(ctx context.Ctx, webhookSubscription *WebhookSubscription.WebhookSubscription) error {
+		err := tx.WithContext(ctx).
+			Omit(clause.Associations).
+			Save(webhookSubscription).
+			Error
+		if err != nil {
+			err = fmt.Errorf("webhook subscription repo save error: %w", err)
+			return err
+		}
+		return nil
+	}
+}
+
+func list(tx *gorm.DB) ListFn {
+	return func(ctx context.Ctx) ([]*WebhookSubscription.WebhookSubscription, error) {
+		var webhookSubscriptions []*WebhookSubscription.WebhookSubscription
+		err := tx.WithContext(ctx).
+			Order(`"id" ASC`).
+			Find(&webhookSubscriptions).
+			Error
+		if err != nil {
+			err = fmt.Errorf("webhook subscription repo list error: %w", err)
+			return nil, err
+		}
+
+		return webhookSubscriptions, nil
+	}
+}
+
+// listByEvent lists the active subscriptions that receive the event.
+func listByEvent(tx *gorm.DB) ListByEventFn {
+	return func(ctx context.Ctx, event WebhookSubscription.Event) ([]*WebhookSubscription.WebhookSubscription, error) {
+		var webhookSubscriptions []*WebhookSubscription.WebhookSubscription
+		err := tx.WithContext(ctx).
+			Where(`"active" = ?`, true).
+			Where(`',' || "events" || ',' LIKE ?`, "%,"+string(event)+",%").
+			Order(`"id" ASC`).
+			Find(&webhookSubscriptions).
+			Error
+		if err != nil {
+			err = fmt.Errorf("webhook subscription repo list by event error: %w", err)
+			return nil, err
+		}
+
+		return webhookSubscriptions, nil
+	}
+}
+
+func getByID(tx *gorm.DB) GetByIDFn {
+	return func(ctx context.Ctx, id int64) (*WebhookSubscription.WebhookSubscription, error) {
+		var webhookSubscription WebhookSubscription.WebhookSubscription
+		err := tx.WithContext(ctx).
+			Where(`"id" = ?`, id).
+			First(&webhookSubscription).
+			Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			err = fmt.Errorf("webhook subscription repo get by id error: %w", err)
+			return nil, err
+		}
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return &webhookSubscription, nil
+	}
+}
+
+func delete(tx *gorm.DB) DeleteFn {
+	return func(ctx context.Ctx, webhookSubscription *WebhookSubscription.WebhookSubscription) error {
+		err := tx.WithContext(ctx).
+			Omit(clause.Associations).
+			Delete(webhookSubscription).
+			Error
+		if err != nil {
+			err = fmt.Errorf("webhook subscription repo delete error: %w", err)
+			return err
+		}
+		return nil
+	}
+}
@@ -0,0 +1,128 @@
+package webhook_subscription
+
+import (
+	"os"
+	"testing"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/database/databasetest"
+	WebhookSubscription "github.com/koraygocmen/golang-boilerplate/internal/model/webhook_subscription"
+)
+
+var (
+	dbTest = databasetest.Get()
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+
+	// Purge and exit.
+	dbTest.Purge()
+	os.Exit(code)
+}
+
+func dbClean() {
+	ctx := context.Background()
+
+	dbTest.DB.Reset(ctx)
+	dbTest.DB.Up(ctx)
+	dbTest.DB.Seed(ctx)
+}
+
+func populate() ([]*WebhookSubscription.WebhookSubscription, error) {
+	webhookSubscriptionRepo := New(dbTest.DB.GORM)
+
+	webhookSubscriptions := []*WebhookSubscription.WebhookSubscription{
+		{URL: "https://example.com/1", Secret: "secret1", Events: "user.created,user.updated", Active: true},
+		{URL: "https://example.com/2", Secret: "secret2", Events: "user.created", Active: false},
+		{URL: "https://example.com/3", Secret: "secret3", Events: "user.deleted,user.updated", Active: true},
+	}
+	for _, w := range webhookSubscriptions {
+		if err := webhookSubscriptionRepo.Create(context.Background(), w); err != nil {
+			return nil, err
+		}
+	}
+
+	// Active defaults to true in the db, set it explicitly.
+	if err := webhookSubscriptionRepo.Save(context.Background(), webhookSubscriptions[1]); err != nil {
+		return nil, err
+	}
+
+	return webhookSubscriptions, nil
+}
+
+func TestCreate(t *testing.T) {
+	dbClean()
+
+	webhookSubscriptions, err := populate()
+	if err != nil {
+		t.Fatalf("populate error: %v", err)
+	}
+
+	for _, w := range webhookSubscriptions {
+		var wGot WebhookSubscription.WebhookSubscription
+		err = dbTest.DB.GORM.
+			Raw(`SELECT * FROM public.webhook_subscription WHERE id = ?`, w.ID).
+			Scan(&wGot).
+			Error
+		if err != nil {
+			t.Fatalf("select error: %v", err)
+		}
+
+		if w.ID != wGot.ID {
+			t.Fatalf("want: id match; got: id mismatch: %d != %d", w.ID, wGot.ID)
+		}
+
+		if wGot.CreatedAt.IsZero() {
+			t.Fatalf("want: created at to be set; got: created at zero")
+		}
+
+		if w.URL != wGot.URL || w.Secret != wGot.Secret || w.Events != wGot.Events || w.Active != wGot.Active {
+			t.Fatalf("want: %+v; got: %+v", w, wGot)
+		}
+	}
+}
+
+func TestListByEvent(t *testing.T) {
+	dbClean()
+
+	webhookSubscriptions, err := populate()
+	if err != nil {
+		t.Fatalf("populate error: %v", err)
+	}
+
+	webhookSubscriptionRepo := New(dbTest.DB.GORM)
+
+	got, err := webhookSubscriptionRepo.ListByEvent(context.Background(), WebhookSubscription.EventUserUpdated)
+	if err != nil {
+		t.Fatalf("want: list by event error nil; got: %v", err)
+	}
+
+	if len(got) != 2 || got[0].ID != webhookSubscriptions[0].ID || got[1].ID != webhookSubscriptions[2].ID {
+		t.Fatalf("want: subscriptions 1 and 3; got: %+v", got)
+	}
+
+	// Inactive subscriptions are not listed.
+	got, err = webhookSubscriptionRepo.ListByEvent(context.Background(), WebhookSubscription.EventUserCreated)
+	if err != nil {
+		t.Fatalf("want: list by event error nil; got: %v", err)
+	}
+
+	if len(got) != 1 || got[0].ID != webhookSubscriptions[0].ID {
+		t.Fatalf("want: subscription 1; got: %+v", got)
+	}
+
+	// Deleted subscriptions are not listed.
+	if err := webhookSubscriptionRepo.Delete(context.Background(), webhookSubscriptions[2]); err != nil {
+		t.Fatalf("want: delete error nil; got: %v", err)
+	}
+
+	got, err = webhookSubscriptionRepo.ListByEvent(context.Background(), WebhookSubscription.EventUserDeleted)
+	if err != nil {
+		t.Fatalf("want: list by event error nil; got: %v", err)
+	}
+
+	if len(got) != 0 {
+		t.Fatalf("want: no subscriptions; got: %+v", got)
+	}
+}
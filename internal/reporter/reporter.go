@@ -0,0 +1,364 @@
+package reporter
+
+import (
+	goctx "context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	"github.com/koraygocmen/golang-boilerplate/pkg/duration"
+)
+
+const (
+	QueueSizeDefault  = 1000
+	WindowDefault     = 5 * time.Minute
+	RatePerMinDefault = 30
+	BurstDefault      = 10
+
+	// sendTimeout bounds a single sink delivery.
+	sendTimeout = 10 * time.Second
+	// closeTimeout bounds the flush on Close.
+	closeTimeout = 5 * time.Second
+	// fingerprintsMax is the number of fingerprints
+	// after which expired ones are pruned.
+	fingerprintsMax = 1024
+)
+
+var (
+	// Fingerprints ignore the variable parts of the messages
+	// such as ids, so that the same failure groups together.
+	fingerprintUUID   = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	fingerprintHex    = regexp.MustCompile(`0x[0-9a-fA-F]+`)
+	fingerprintNumber = regexp.MustCompile(`\d+`)
+
+	ErrReporterClosed = errors.New("reporter closed")
+)
+
+// ErrorReporter reports internal errors to the configured sinks.
+// Report must not block the caller.
+type ErrorReporter interface {
+	Report(ctx context.Ctx, err error)
+	Close() error
+}
+
+// Sink delivers a reported error, e.g. to slack.
+type Sink interface {
+	Name() string
+	Send(ctx goctx.Context, event Event) error
+}
+
+// Event is a reported error.
+type Event struct {
+	// Ctx carries the context values of the request that failed.
+	Ctx         context.Ctx
+	Err         error
+	Fingerprint string
+	Time        time.Time
+	// Count is the number of occurrences this event stands for,
+	// including the ones suppressed since the last report.
+	Count int
+}
+
+type Config struct {
+	// Sinks is a comma separated list of slack, sentry and webhook.
+	Sinks      string
+	QueueSize  int
+	WindowSec  int
+	RatePerMin int
+	Burst      int
+	SHASUM     string
+
+	Sentry struct {
+		DSN string
+	}
+
+	Webhook struct {
+		URL    string
+		Secret string
+	}
+}
+
+type Stats struct {
+	Reported    int64
+	Suppressed  int64
+	RateLimited int64
+	QueueFull   int64
+	Failed      int64
+}
+
+type seen struct {
+	reported   time.Time
+	suppressed int
+}
+
+// Async is the ErrorReporter that deduplicates and rate limits
+// the errors, and delivers them to the sinks from a goroutine.
+type Async struct {
+	sinks  []Sink
+	window time.Duration
+	now    func() time.Time
+
+	lock    sync.Mutex
+	closed  bool
+	seen    map[string]*seen
+	limiter *limiter
+
+	queue chan Event
+	done  chan struct{}
+
+	reported    atomic.Int64
+	suppressed  atomic.Int64
+	rateLimited atomic.Int64
+	queueFull   atomic.Int64
+	failed      atomic.Int64
+}
+
+var (
+	// Reporter is the global error reporter.
+	// It is initialized in cmd/api/main.go.
+	// Do not let it be nil.
+	Reporter ErrorReporter = Nop{}
+)
+
+// Nop drops every error.
+type Nop struct{}
+
+func (Nop) Report(ctx context.Ctx, err error) {}
+func (Nop) Close() error                      { return nil }
+
+// New creates the reporter with the sinks in config and the given
+// extra sinks, and starts delivering.
+func New(config Config, sinks ...Sink) (*Async, error) {
+	configSinks, err := newSinks(config)
+	if err != nil {
+		return nil, err
+	}
+	sinks = append(configSinks, sinks...)
+
+	if config.QueueSize <= 0 {
+		config.QueueSize = QueueSizeDefault
+	}
+
+	window := duration.Seconds(config.WindowSec)
+	if window <= 0 {
+		window = WindowDefault
+	}
+
+	if config.RatePerMin <= 0 {
+		config.RatePerMin = RatePerMinDefault
+	}
+
+	if config.Burst <= 0 {
+		config.Burst = BurstDefault
+	}
+
+	r := &Async{
+		sinks:   sinks,
+		window:  window,
+		now:     time.Now,
+		seen:    make(map[string]*seen),
+		limiter: newLimiter(float64(config.RatePerMin)/float64(time.Minute), config.Burst),
+		queue:   make(chan Event, config.QueueSize),
+		done:    make(chan struct{}),
+	}
+
+	go r.run()
+
+	return r, nil
+}
+
+func newSinks(config Config) ([]Sink, error) {
+	var sinks []Sink
+	for _, name := range strings.Split(config.Sinks, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "":
+			continue
+		case SinkSlack:
+			sinks = append(sinks, NewSlack())
+		case SinkSentry:
+			sentry, err := NewSentry(config.Sentry.DSN, config.SHASUM)
+			if err != nil {
+				err = fmt.Errorf("reporter new error: %w", err)
+				return nil, err
+			}
+			sinks = append(sinks, sentry)
+		case SinkWebhook:
+			webhook, err := NewWebhook(config.Webhook.URL, config.Webhook.Secret)
+			if err != nil {
+				err = fmt.Errorf("reporter new error: %w", err)
+				return nil, err
+			}
+			sinks = append(sinks, webhook)
+		default:
+			err := fmt.Errorf("reporter new error: unknown sink %q", name)
+			return nil, err
+		}
+	}
+	return sinks, nil
+}
+
+// Report queues the error unless an identical one was reported
+// within the window or the rate limit is exceeded.
+func (r *Async) Report(ctx context.Ctx, err error) {
+	if err == nil {
+		return
+	}
+
+	now := r.now()
+	fingerprint := Fingerprint(err)
+
+	r.lock.Lock()
+	if r.closed {
+		r.lock.Unlock()
+		return
+	}
+
+	s, ok := r.seen[fingerprint]
+	if ok && now.Sub(s.reported) < r.window {
+		s.suppressed++
+		r.lock.Unlock()
+		r.suppressed.Add(1)
+		return
+	}
+
+	if !r.limiter.allow(now) {
+		r.lock.Unlock()
+		r.rateLimited.Add(1)
+		return
+	}
+
+	count := 1
+	if ok {
+		count += s.suppressed
+	}
+
+	if len(r.seen) >= fingerprintsMax {
+		r.prune(now)
+	}
+	r.seen[fingerprint] = &seen{reported: now}
+
+	event := Event{
+		Ctx:         ctx,
+		Err:         err,
+		Fingerprint: fingerprint,
+		Time:        now,
+		Count:       count,
+	}
+
+	// Sending while holding the lock keeps Close from closing
+	// the queue underneath, the send itself never blocks.
+	select {
+	case r.queue <- event:
+		r.reported.Add(1)
+	default:
+		r.queueFull.Add(1)
+	}
+	r.lock.Unlock()
+}
+
+func (r *Async) prune(now time.Time) {
+	for fingerprint, s := range r.seen {
+		if now.Sub(s.reported) >= r.window {
+			delete(r.seen, fingerprint)
+		}
+	}
+}
+
+func (r *Async) run() {
+	defer close(r.done)
+
+	for event := range r.queue {
+		for _, sink := range r.sinks {
+			ctx, cancel := goctx.WithTimeout(goctx.Background(), sendTimeout)
+			err := sink.Send(ctx, event)
+			cancel()
+
+			if err != nil {
+				r.failed.Add(1)
+				// Logged directly, reporting it would loop back here.
+				logger.Logger.Errorf(event.Ctx, `msg="error report failed", sink="%s", err="%v"`, sink.Name(), err)
+			}
+		}
+	}
+}
+
+// Close stops accepting errors and waits for the queued ones to be sent.
+func (r *Async) Close() error {
+	r.lock.Lock()
+	if r.closed {
+		r.lock.Unlock()
+		return ErrReporterClosed
+	}
+	r.closed = true
+	close(r.queue)
+	r.lock.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-time.After(closeTimeout):
+		return fmt.Errorf("reporter close error: %d errors not sent", len(r.queue))
+	}
+}
+
+func (r *Async) Stats() Stats {
+	return Stats{
+		Reported:    r.reported.Load(),
+		Suppressed:  r.suppressed.Load(),
+		RateLimited: r.rateLimited.Load(),
+		QueueFull:   r.queueFull.Load(),
+		Failed:      r.failed.Load(),
+	}
+}
+
+// Fingerprint groups errors by the type of the innermost error
+// and the message with the variable parts replaced.
+func Fingerprint(err error) string {
+	msg := err.Error()
+	msg = fingerprintUUID.ReplaceAllString(msg, "<uuid>")
+	msg = fingerprintHex.ReplaceAllString(msg, "<hex>")
+	msg = fingerprintNumber.ReplaceAllString(msg, "<n>")
+
+	sum := sha1.Sum([]byte(fmt.Sprintf("%T:%s", rootError(err), msg)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// limiter is a token bucket.
+type limiter struct {
+	rate   float64 // tokens per nanosecond
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(rate float64, burst int) *limiter {
+	return &limiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+func (l *limiter) allow(now time.Time) bool {
+	if !l.last.IsZero() {
+		l.tokens += float64(now.Sub(l.last)) * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
@@ -0,0 +1,252 @@
+package reporter
+
+import (
+	goctx "context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+)
+
+type sinkTest struct {
+	lock   sync.Mutex
+	events []Event
+	block  chan struct{}
+}
+
+func (st *sinkTest) Name() string {
+	return "test"
+}
+
+func (st *sinkTest) Send(ctx goctx.Context, event Event) error {
+	if st.block != nil {
+		<-st.block
+	}
+
+	st.lock.Lock()
+	defer st.lock.Unlock()
+	st.events = append(st.events, event)
+	return nil
+}
+
+func (st *sinkTest) get() []Event {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+	return append([]Event{}, st.events...)
+}
+
+func TestFingerprint(t *testing.T) {
+	a := fmt.Errorf("user 12 get error: %w", errors.New("connection refused"))
+	b := fmt.Errorf("user 345 get error: %w", errors.New("connection refused"))
+	c := fmt.Errorf("user 12 save error: %w", errors.New("connection refused"))
+
+	if Fingerprint(a) != Fingerprint(b) {
+		t.Fatalf("want: fingerprint(a) = fingerprint(b); got: %s != %s", Fingerprint(a), Fingerprint(b))
+	}
+
+	if Fingerprint(a) == Fingerprint(c) {
+		t.Fatalf("want: fingerprint(a) != fingerprint(c); got: %s", Fingerprint(a))
+	}
+}
+
+func TestReportDedup(t *testing.T) {
+	sink := &sinkTest{}
+
+	r, err := New(Config{WindowSec: 60}, sink)
+	if err != nil {
+		t.Fatalf("want: new err nil; got: %v", err)
+	}
+
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		r.Report(ctx, fmt.Errorf("user %d get error", i))
+	}
+
+	// The window passed, the next one carries the suppressed ones.
+	now = now.Add(time.Minute)
+	r.Report(ctx, errors.New("user 9 get error"))
+
+	r.Close()
+
+	events := sink.get()
+	if len(events) != 2 {
+		t.Fatalf("want: 2 events; got: %d", len(events))
+	}
+
+	if events[0].Count != 1 || events[1].Count != 5 {
+		t.Fatalf("want: counts 1, 5; got: %d, %d", events[0].Count, events[1].Count)
+	}
+
+	if stats := r.Stats(); stats.Suppressed != 4 || stats.Reported != 2 {
+		t.Fatalf("want: suppressed 4, reported 2; got: %+v", stats)
+	}
+}
+
+func TestReportRateLimit(t *testing.T) {
+	sink := &sinkTest{}
+
+	r, err := New(Config{RatePerMin: 60, Burst: 3}, sink)
+	if err != nil {
+		t.Fatalf("want: new err nil; got: %v", err)
+	}
+
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for _, msg := range []string{"a", "b", "c", "d", "e"} {
+		r.Report(ctx, errors.New(msg))
+	}
+
+	// One token per second.
+	now = now.Add(time.Second)
+	r.Report(ctx, errors.New("f"))
+
+	r.Close()
+
+	if got := len(sink.get()); got != 4 {
+		t.Fatalf("want: 4 events; got: %d", got)
+	}
+
+	if stats := r.Stats(); stats.RateLimited != 2 {
+		t.Fatalf("want: rate limited 2; got: %+v", stats)
+	}
+}
+
+func TestReportQueueFull(t *testing.T) {
+	sink := &sinkTest{block: make(chan struct{})}
+
+	r, err := New(Config{QueueSize: 1, Burst: 10}, sink)
+	if err != nil {
+		t.Fatalf("want: new err nil; got: %v", err)
+	}
+
+	// Report never blocks, even with a stuck sink.
+	done := make(chan struct{})
+	go func() {
+		ctx := context.Background()
+		for _, msg := range []string{"a", "b", "c", "d", "e"} {
+			r.Report(ctx, errors.New(msg))
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("want: report not blocking; got: blocked")
+	}
+
+	close(sink.block)
+	r.Close()
+
+	stats := r.Stats()
+	if stats.QueueFull == 0 || stats.Reported+stats.QueueFull != 5 {
+		t.Fatalf("want: queue full > 0; got: %+v", stats)
+	}
+}
+
+func TestSentry(t *testing.T) {
+	var (
+		gotPath string
+		gotAuth string
+		gotBody map[string]interface{}
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("X-Sentry-Auth")
+		json.NewDecoder(r.Body).Decode(&gotBody)
+	}))
+	defer server.Close()
+
+	sink, err := NewSentry("http://key@"+server.Listener.Addr().String()+"/42", "shasum")
+	if err != nil {
+		t.Fatalf("want: new sentry err nil; got: %v", err)
+	}
+
+	ctx := context.WithValue(context.Background(), context.KeyPath, "/v1/users")
+	err = sink.Send(goctx.Background(), Event{
+		Ctx:         ctx,
+		Err:         errors.New("boom"),
+		Fingerprint: "fingerprint",
+		Time:        time.Now(),
+		Count:       3,
+	})
+	if err != nil {
+		t.Fatalf("want: send err nil; got: %v", err)
+	}
+
+	if gotPath != "/api/42/store/" {
+		t.Fatalf("want: /api/42/store/; got: %s", gotPath)
+	}
+
+	if want := "Sentry sentry_version=7, sentry_client=golang-boilerplate/1.0, sentry_key=key"; gotAuth != want {
+		t.Fatalf("want: %s; got: %s", want, gotAuth)
+	}
+
+	tags, _ := gotBody["tags"].(map[string]interface{})
+	if gotBody["release"] != "shasum" || tags["path"] != "/v1/users" {
+		t.Fatalf("want: release shasum, path tag /v1/users; got: %v", gotBody)
+	}
+
+	if _, err := NewSentry("http://example.com/42", ""); err == nil {
+		t.Fatalf("want: new sentry without key err not nil; got: nil")
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var (
+		gotSignature string
+		gotBody      []byte
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get(HeaderSignature)
+		gotBody, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhook(server.URL, "secret")
+	if err != nil {
+		t.Fatalf("want: new webhook err nil; got: %v", err)
+	}
+
+	err = sink.Send(goctx.Background(), Event{
+		Ctx:   context.Background(),
+		Err:   errors.New("boom"),
+		Time:  time.Now(),
+		Count: 1,
+	})
+	if err != nil {
+		t.Fatalf("want: send err nil; got: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(gotBody)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); gotSignature != want {
+		t.Fatalf("want: %s; got: %s", want, gotSignature)
+	}
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	err = sink.Send(goctx.Background(), Event{Ctx: context.Background(), Err: errors.New("boom")})
+	if err == nil {
+		t.Fatalf("want: send err not nil; got: nil")
+	}
+}
@@ -0,0 +1,255 @@
+package reporter
+
+import (
+	"bytes"
+	goctx "context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/env"
+	"github.com/koraygocmen/golang-boilerplate/internal/slack"
+)
+
+const (
+	SinkSlack   = "slack"
+	SinkSentry  = "sentry"
+	SinkWebhook = "webhook"
+
+	// HeaderSignature is the hex HMAC-SHA256 of the webhook body.
+	HeaderSignature = "X-Signature-256"
+)
+
+var (
+	httpClient = &http.Client{}
+)
+
+// Slack.
+
+type slackSink struct{}
+
+func NewSlack() Sink {
+	return slackSink{}
+}
+
+func (slackSink) Name() string {
+	return SinkSlack
+}
+
+func (slackSink) Send(ctx goctx.Context, event Event) error {
+	// Recovered panics carry a stack trace.
+	severity := slack.SeverityError
+	if event.Ctx.Value(context.KeyErrorStack) != nil {
+		severity = slack.SeverityCritical
+	}
+
+	return slack.Client.MessageError(event.Ctx, slack.Alert{
+		Severity:  severity,
+		ThreadKey: event.Fingerprint,
+		Err:       event.Err,
+		Count:     event.Count,
+	})
+}
+
+// Sentry compatible store endpoint.
+
+type sentrySink struct {
+	endpoint string
+	auth     string
+	release  string
+}
+
+// NewSentry creates the sink from a DSN in the
+// form https://<key>@<host>/<project>.
+func NewSentry(dsn, release string) (Sink, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		err = fmt.Errorf("sentry dsn parse error: %w", err)
+		return nil, err
+	}
+
+	key := u.User.Username()
+	project := path.Base(u.Path)
+	if u.Scheme == "" || u.Host == "" || key == "" || project == "" || project == "/" || project == "." {
+		return nil, errors.New("sentry dsn invalid")
+	}
+
+	endpoint := url.URL{
+		Scheme: u.Scheme,
+		Host:   u.Host,
+		Path:   path.Join(path.Dir(u.Path), "api", project, "store") + "/",
+	}
+
+	return &sentrySink{
+		endpoint: endpoint.String(),
+		auth:     fmt.Sprintf("Sentry sentry_version=7, sentry_client=golang-boilerplate/1.0, sentry_key=%s", key),
+		release:  release,
+	}, nil
+}
+
+func (s *sentrySink) Name() string {
+	return SinkSentry
+}
+
+func (s *sentrySink) Send(ctx goctx.Context, event Event) error {
+	eventID := make([]byte, 16)
+	if _, err := rand.Read(eventID); err != nil {
+		err = fmt.Errorf("sentry send error: rand read error: %w", err)
+		return err
+	}
+
+	tags := map[string]string{}
+	extra := map[string]interface{}{
+		"count": event.Count,
+	}
+	for key, val := range context.Map(event.Ctx) {
+		switch key {
+		case context.KeyMethod, context.KeyPath, context.KeyRequestID, context.KeyUserID:
+			tags[string(key)] = fmt.Sprint(val)
+		case context.KeyError:
+			// Sent as the exception.
+		default:
+			extra[string(key)] = val
+		}
+	}
+
+	reqbody, err := json.Marshal(map[string]interface{}{
+		"event_id":    hex.EncodeToString(eventID),
+		"timestamp":   event.Time.UTC().Format(time.RFC3339),
+		"platform":    "go",
+		"level":       "error",
+		"logger":      "errhandle",
+		"release":     s.release,
+		"environment": strings.ToLower(env.ENV),
+		"message": map[string]string{
+			"formatted": event.Err.Error(),
+		},
+		"exception": map[string]interface{}{
+			"values": []map[string]string{{
+				"type":  fmt.Sprintf("%T", rootError(event.Err)),
+				"value": event.Err.Error(),
+			}},
+		},
+		"fingerprint": []string{event.Fingerprint},
+		"tags":        tags,
+		"extra":       extra,
+	})
+	if err != nil {
+		err = fmt.Errorf("sentry send error: marshal event error: %w", err)
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(reqbody))
+	if err != nil {
+		err = fmt.Errorf("sentry send error: http new request error: %w", err)
+		return err
+	}
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set("X-Sentry-Auth", s.auth)
+
+	if err := do(req); err != nil {
+		err = fmt.Errorf("sentry send error: %w", err)
+		return err
+	}
+
+	return nil
+}
+
+// Generic webhook.
+
+type webhookSink struct {
+	url    string
+	secret string
+}
+
+// NewWebhook creates the sink posting the events as JSON to the url.
+// The body is signed with the secret if it is set.
+func NewWebhook(rawURL, secret string) (Sink, error) {
+	if u, err := url.Parse(rawURL); err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, errors.New("webhook url invalid")
+	}
+
+	return &webhookSink{
+		url:    rawURL,
+		secret: secret,
+	}, nil
+}
+
+func (w *webhookSink) Name() string {
+	return SinkWebhook
+}
+
+func (w *webhookSink) Send(ctx goctx.Context, event Event) error {
+	fields := map[string]interface{}{}
+	for key, val := range context.Map(event.Ctx) {
+		fields[string(key)] = val
+	}
+
+	reqbody, err := json.Marshal(map[string]interface{}{
+		"fingerprint": event.Fingerprint,
+		"error":       event.Err.Error(),
+		"count":       event.Count,
+		"time":        event.Time.UTC(),
+		"context":     fields,
+	})
+	if err != nil {
+		err = fmt.Errorf("webhook send error: marshal event error: %w", err)
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(reqbody))
+	if err != nil {
+		err = fmt.Errorf("webhook send error: http new request error: %w", err)
+		return err
+	}
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+	if w.secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.secret))
+		mac.Write(reqbody)
+		req.Header.Set(HeaderSignature, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	if err := do(req); err != nil {
+		err = fmt.Errorf("webhook send error: %w", err)
+		return err
+	}
+
+	return nil
+}
+
+func do(req *http.Request) error {
+	res, err := httpClient.Do(req)
+	if err != nil {
+		err = fmt.Errorf("http client do error: %w", err)
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		resbody, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		err = fmt.Errorf("status code: %v, body: %v", res.StatusCode, string(resbody))
+		return err
+	}
+
+	return nil
+}
+
+func rootError(err error) error {
+	for unwrapped := errors.Unwrap(err); unwrapped != nil; unwrapped = errors.Unwrap(err) {
+		err = unwrapped
+	}
+	return err
+}
@@ -0,0 +1,96 @@
+package scheduler
+
+import (
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+)
+
+// Locker elects the leader of the replicas.
+type Locker interface {
+	// Acquire takes or confirms the leadership
+	// and reports whether this replica leads.
+	Acquire(ctx context.Ctx) (bool, error)
+	// Release gives up the leadership.
+	Release(ctx context.Ctx) error
+}
+
+// AdvisoryLocker elects the leader with a Postgres session advisory
+// lock. The lock is held on a dedicated connection, so it is released
+// by Postgres when the leader goes away with its connection.
+type AdvisoryLocker struct {
+	db  *sql.DB
+	key int64
+
+	lock sync.Mutex
+	conn *sql.Conn
+}
+
+func NewAdvisoryLocker(db *sql.DB, name string) *AdvisoryLocker {
+	return &AdvisoryLocker{
+		db:  db,
+		key: LockKey(name),
+	}
+}
+
+// LockKey hashes the name into an advisory lock key.
+func LockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
+
+func (l *AdvisoryLocker) Acquire(ctx context.Ctx) (bool, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	// The lock is held as long as the connection is alive.
+	if l.conn != nil {
+		if err := l.conn.PingContext(ctx); err != nil {
+			l.conn.Close()
+			l.conn = nil
+			return false, fmt.Errorf("advisory lock acquire error: connection lost: %w", err)
+		}
+		return true, nil
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("advisory lock acquire error: %w", err)
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, l.key).Scan(&locked); err != nil {
+		conn.Close()
+		return false, fmt.Errorf("advisory lock acquire error: %w", err)
+	}
+
+	if !locked {
+		conn.Close()
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+func (l *AdvisoryLocker) Release(ctx context.Ctx) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+	defer func() {
+		l.conn.Close()
+		l.conn = nil
+	}()
+
+	if _, err := l.conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, l.key); err != nil {
+		return fmt.Errorf("advisory lock release error: %w", err)
+	}
+	return nil
+}
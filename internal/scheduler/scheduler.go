@@ -0,0 +1,269 @@
+package scheduler
+
+import (
+	goctx "context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	"github.com/koraygocmen/golang-boilerplate/pkg/cron"
+	"github.com/koraygocmen/golang-boilerplate/pkg/duration"
+)
+
+const (
+	ElectDefault = 15 * time.Second
+
+	// tick is the resolution of the schedules.
+	tick = time.Second
+	// closeTimeout bounds the wait for the tasks in flight on Close.
+	closeTimeout = 30 * time.Second
+)
+
+type Config struct {
+	ElectSec int
+}
+
+// Task is a named periodic task.
+type Task struct {
+	Name        string
+	Description string
+	// Schedule is a cron expression in UTC, see cron.Parse.
+	Schedule string
+	Run      func(ctx context.Ctx) error
+}
+
+type entry struct {
+	task     Task
+	schedule *cron.Schedule
+	next     time.Time
+	running  bool
+}
+
+// Scheduler runs the tasks on their schedules. The replicas elect a
+// leader with the locker and only the leader runs the tasks, the
+// others take over when it goes away.
+type Scheduler struct {
+	locker Locker
+	elect  time.Duration
+	now    func() time.Time
+
+	lock    sync.Mutex
+	entries map[string]*entry
+	leader  bool
+
+	cancel goctx.CancelFunc
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+func New(config Config, locker Locker) *Scheduler {
+	s := &Scheduler{
+		locker:  locker,
+		elect:   duration.Seconds(config.ElectSec),
+		now:     time.Now,
+		entries: make(map[string]*entry),
+	}
+
+	if s.elect <= 0 {
+		s.elect = ElectDefault
+	}
+
+	return s
+}
+
+// Add registers the task, the names are unique.
+func (s *Scheduler) Add(task Task) error {
+	schedule, err := cron.Parse(task.Schedule)
+	if err != nil {
+		err = fmt.Errorf("scheduler add %s error: %w", task.Name, err)
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.entries[task.Name]; ok {
+		return fmt.Errorf("scheduler add %s error: duplicate task", task.Name)
+	}
+
+	s.entries[task.Name] = &entry{
+		task:     task,
+		schedule: schedule,
+		next:     schedule.Next(s.now().UTC()),
+	}
+	return nil
+}
+
+// TaskInfo describes a registered task.
+type TaskInfo struct {
+	Name        string
+	Description string
+	Schedule    string
+	Next        time.Time
+}
+
+// Tasks returns the registered tasks sorted by name.
+func (s *Scheduler) Tasks() []TaskInfo {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	tasks := make([]TaskInfo, 0, len(s.entries))
+	for _, e := range s.entries {
+		tasks = append(tasks, TaskInfo{
+			Name:        e.task.Name,
+			Description: e.task.Description,
+			Schedule:    e.task.Schedule,
+			Next:        e.next,
+		})
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].Name < tasks[j].Name
+	})
+	return tasks
+}
+
+// Run runs the task now regardless of the schedule and the leader.
+func (s *Scheduler) Run(ctx context.Ctx, name string) error {
+	s.lock.Lock()
+	e, ok := s.entries[name]
+	s.lock.Unlock()
+
+	if !ok {
+		return fmt.Errorf("scheduler run error: unknown task %s", name)
+	}
+
+	if err := e.task.Run(ctx); err != nil {
+		err = fmt.Errorf("scheduler run %s error: %w", name, err)
+		return err
+	}
+	return nil
+}
+
+// Start runs the scheduler in a goroutine until Close.
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go s.run(ctx)
+}
+
+// Close stops the scheduler, waits for the tasks in
+// flight and gives up the leadership.
+func (s *Scheduler) Close() error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+	<-s.done
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-time.After(closeTimeout):
+		err = fmt.Errorf("scheduler close error: timed out after %v", closeTimeout)
+	}
+
+	if releaseErr := s.locker.Release(context.Background()); releaseErr != nil && err == nil {
+		err = fmt.Errorf("scheduler close error: %w", releaseErr)
+	}
+	return err
+}
+
+func (s *Scheduler) run(ctx context.Ctx) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	var elected time.Time
+	for {
+		now := s.now().UTC()
+
+		// Take or confirm the leadership every elect interval.
+		if now.Sub(elected) >= s.elect {
+			s.electLeader(ctx)
+			elected = now
+		}
+
+		s.runDue(ctx, now)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) electLeader(ctx context.Ctx) {
+	leader, err := s.locker.Acquire(ctx)
+	if err != nil {
+		logger.Logger.Errorf(ctx, `msg="scheduler leader election failed", err="%v"`, err)
+	}
+
+	s.lock.Lock()
+	changed := leader != s.leader
+	s.leader = leader
+	s.lock.Unlock()
+
+	if changed {
+		logger.Logger.Infof(ctx, `msg="scheduler leadership changed", leader="%t"`, leader)
+	}
+}
+
+// runDue starts the tasks that are due when leading. The followers
+// only advance the schedules, so a new leader does not catch up on
+// the runs of the old one. A task still running skips its next run.
+func (s *Scheduler) runDue(ctx context.Ctx, now time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, e := range s.entries {
+		if e.next.IsZero() || e.next.After(now) {
+			continue
+		}
+		e.next = e.schedule.Next(now)
+
+		if !s.leader || e.running {
+			continue
+		}
+		e.running = true
+
+		s.wg.Add(1)
+		go s.runTask(ctx, e)
+	}
+}
+
+func (s *Scheduler) runTask(ctx context.Ctx, e *entry) {
+	defer s.wg.Done()
+	defer func() {
+		s.lock.Lock()
+		e.running = false
+		s.lock.Unlock()
+	}()
+
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Logger.Errorf(ctx, `msg="scheduler task panic", task="%s", panic="%v"`, e.task.Name, r)
+		}
+	}()
+
+	started := s.now()
+	if err := e.task.Run(ctx); err != nil {
+		logger.Logger.Errorf(ctx, `msg="scheduler task failed", task="%s", err="%v"`, e.task.Name, err)
+		return
+	}
+
+	logger.Logger.Infof(ctx, `msg="scheduler task done", task="%s", took="%v"`, e.task.Name, s.now().Sub(started))
+}
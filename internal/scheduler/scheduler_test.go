@@ -0,0 +1,181 @@
+package scheduler
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+)
+
+type lockerTest struct {
+	leader   bool
+	released bool
+}
+
+func (l *lockerTest) Acquire(ctx context.Ctx) (bool, error) {
+	return l.leader, nil
+}
+
+func (l *lockerTest) Release(ctx context.Ctx) error {
+	l.released = true
+	return nil
+}
+
+func TestAdd(t *testing.T) {
+	s := New(Config{}, &lockerTest{})
+
+	run := func(ctx context.Ctx) error { return nil }
+
+	if err := s.Add(Task{Name: "b", Schedule: "@hourly", Run: run}); err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+	if err := s.Add(Task{Name: "a", Schedule: "0 3 * * *", Run: run}); err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+
+	if err := s.Add(Task{Name: "a", Schedule: "@hourly", Run: run}); err == nil {
+		t.Fatalf("want: duplicate error; got: err = nil")
+	}
+	if err := s.Add(Task{Name: "c", Schedule: "every hour", Run: run}); err == nil {
+		t.Fatalf("want: schedule error; got: err = nil")
+	}
+
+	tasks := s.Tasks()
+	if len(tasks) != 2 || tasks[0].Name != "a" || tasks[1].Name != "b" {
+		t.Fatalf("want: tasks a and b; got: %+v", tasks)
+	}
+	if tasks[0].Next.IsZero() {
+		t.Fatalf("want: next run set; got: zero")
+	}
+}
+
+func TestRun(t *testing.T) {
+	s := New(Config{}, &lockerTest{})
+
+	var ran bool
+	s.Add(Task{Name: "a", Schedule: "@hourly", Run: func(ctx context.Ctx) error {
+		ran = true
+		return nil
+	}})
+	s.Add(Task{Name: "b", Schedule: "@hourly", Run: func(ctx context.Ctx) error {
+		return errors.New("failed")
+	}})
+
+	// On demand runs do not need the leadership.
+	if err := s.Run(context.Background(), "a"); err != nil || !ran {
+		t.Fatalf("want: ran; got: ran = %v, err = %v", ran, err)
+	}
+
+	if err := s.Run(context.Background(), "b"); err == nil {
+		t.Fatalf("want: task error; got: err = nil")
+	}
+
+	if err := s.Run(context.Background(), "unknown"); err == nil {
+		t.Fatalf("want: unknown task error; got: err = nil")
+	}
+}
+
+func TestRunDue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 10, 0, 30, 0, time.UTC)
+
+	locker := &lockerTest{}
+	s := New(Config{}, locker)
+	s.now = func() time.Time { return now }
+
+	var (
+		lock sync.Mutex
+		runs int
+	)
+	s.Add(Task{Name: "a", Schedule: "* * * * *", Run: func(ctx context.Ctx) error {
+		lock.Lock()
+		runs++
+		lock.Unlock()
+		return nil
+	}})
+
+	// The followers only advance the schedule.
+	s.electLeader(ctx)
+	s.runDue(ctx, now.Add(time.Minute))
+	s.wg.Wait()
+
+	if runs != 0 {
+		t.Fatalf("want: no runs as a follower; got: %d", runs)
+	}
+
+	locker.leader = true
+	s.electLeader(ctx)
+
+	// Not due yet.
+	s.runDue(ctx, now.Add(time.Minute))
+	s.wg.Wait()
+
+	if runs != 0 {
+		t.Fatalf("want: no runs before due; got: %d", runs)
+	}
+
+	s.runDue(ctx, now.Add(2*time.Minute))
+	s.wg.Wait()
+
+	if runs != 1 {
+		t.Fatalf("want: 1 run as the leader; got: %d", runs)
+	}
+
+	next := s.Tasks()[0].Next
+	if want := time.Date(2026, 1, 1, 10, 3, 0, 0, time.UTC); !next.Equal(want) {
+		t.Fatalf("want: next = %v; got: %v", want, next)
+	}
+}
+
+func TestRunDueSkipsRunning(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 10, 0, 30, 0, time.UTC)
+
+	s := New(Config{}, &lockerTest{leader: true})
+	s.now = func() time.Time { return now }
+	s.electLeader(ctx)
+
+	release := make(chan struct{})
+	var runs int
+	s.Add(Task{Name: "a", Schedule: "* * * * *", Run: func(ctx context.Ctx) error {
+		runs++
+		<-release
+		return nil
+	}})
+
+	s.runDue(ctx, now.Add(time.Minute))
+	// Still running, the run is skipped.
+	s.runDue(ctx, now.Add(2*time.Minute))
+
+	close(release)
+	s.wg.Wait()
+
+	if runs != 1 {
+		t.Fatalf("want: 1 run; got: %d", runs)
+	}
+}
+
+func TestClose(t *testing.T) {
+	locker := &lockerTest{leader: true}
+	s := New(Config{}, locker)
+
+	s.Start()
+	if err := s.Close(); err != nil {
+		t.Fatalf("want: err = nil; got: err = %v", err)
+	}
+
+	if !locker.released {
+		t.Fatalf("want: leadership released; got: held")
+	}
+}
+
+func TestLockKey(t *testing.T) {
+	if LockKey("scheduler") != LockKey("scheduler") {
+		t.Fatalf("want: stable key; got: different keys")
+	}
+	if LockKey("scheduler") == LockKey("other") {
+		t.Fatalf("want: different keys; got: same key")
+	}
+}
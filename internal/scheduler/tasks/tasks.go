@@ -0,0 +1,105 @@
+package tasks
+
+import (
+	"fmt"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/queue"
+	"github.com/koraygocmen/golang-boilerplate/internal/queue/jobs"
+	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+	"github.com/koraygocmen/golang-boilerplate/internal/scheduler"
+)
+
+const (
+	NameSessionPrune        = "session-prune"
+	NameUserUnverifiedPrune = "user-unverified-prune"
+	NameIdempotencyKeyPrune = "idempotency-key-prune"
+
+	SessionPruneScheduleDefault       = "15 * * * *"
+	SessionPruneRetentionHoursDefault = 7 * 24
+
+	IdempotencyKeyPruneScheduleDefault = "45 * * * *"
+)
+
+type Config struct {
+	SessionPrune struct {
+		Schedule       string
+		RetentionHours int
+	}
+
+	// UserUnverifiedPrune is refused, there is no email verification
+	// yet so every user is unverified and would be deleted.
+	UserUnverifiedPrune struct {
+		Schedule string
+		AgeHours int
+	}
+
+	IdempotencyKeyPrune struct {
+		Schedule string
+	}
+}
+
+// Register adds the maintenance tasks to the scheduler. The tasks
+// enqueue their jobs, so the worker runs them with its retries.
+func Register(s *scheduler.Scheduler, config Config) error {
+	if config.UserUnverifiedPrune.Schedule != "" {
+		err := fmt.Errorf("tasks register error: %s needs email verification", NameUserUnverifiedPrune)
+		return err
+	}
+
+	if config.SessionPrune.Schedule == "" {
+		config.SessionPrune.Schedule = SessionPruneScheduleDefault
+	}
+
+	if config.SessionPrune.RetentionHours <= 0 {
+		config.SessionPrune.RetentionHours = SessionPruneRetentionHoursDefault
+	}
+
+	if config.IdempotencyKeyPrune.Schedule == "" {
+		config.IdempotencyKeyPrune.Schedule = IdempotencyKeyPruneScheduleDefault
+	}
+
+	tasks := []scheduler.Task{
+		{
+			Name:        NameSessionPrune,
+			Description: "purge the expired and deleted user sessions",
+			Schedule:    config.SessionPrune.Schedule,
+			Run: enqueue(jobs.SessionPurge{
+				RetentionHours: config.SessionPrune.RetentionHours,
+			}),
+		},
+		{
+			Name:        NameIdempotencyKeyPrune,
+			Description: "delete the expired idempotency keys",
+			Schedule:    config.IdempotencyKeyPrune.Schedule,
+			Run:         enqueue(jobs.IdempotencyKeyPurge{}),
+		},
+	}
+
+	for _, task := range tasks {
+		if err := s.Add(task); err != nil {
+			err = fmt.Errorf("tasks register error: %w", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// enqueue returns a task run that enqueues the job, a job
+// of the kind that is still pending is not enqueued again.
+func enqueue(args queue.Args) func(ctx context.Ctx) error {
+	return func(ctx context.Ctx) error {
+		tx := repo.New(ctx)
+
+		if _, err := queue.Enqueue(ctx, tx, args, &queue.Options{UniqueKey: string(args.JobKind())}); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			err = fmt.Errorf("commit error: %w", err)
+			return err
+		}
+		return nil
+	}
+}
@@ -6,48 +6,44 @@ import (
 )
 
 var (
-	ErrInternalServer = errapi.New(
-		fiber.StatusInternalServerError,
-		errapi.ErrCodeInternalServerError,
-		"Beklenmedik bir hata oluştu. Lütfen daha sonra tekrar dene.",
-		"An unexpected error occurred. Please try again later.",
-	)
-	ErrUrlParamInvalid = errapi.New(
-		fiber.StatusBadRequest,
-		errapi.ErrCodeUrlParamInvalid,
-		"Beklenmedik bir hata oluştu. Lütfen daha sonra tekrar dene.",
-		"An unexpected error occurred. Please try again later.",
-	)
-	ErrNotFound = errapi.New(
-		fiber.StatusNotFound,
-		errapi.ErrCodeNotFound,
-		"Yanlış bir url adresi girdin.",
-		"You entered an incorrect url address.",
-	)
-	ErrMethodNotAllowed = errapi.New(
-		fiber.StatusMethodNotAllowed,
-		errapi.ErrCodeMethodNotAllowed,
-		"Yapmaya çalıştığın işlemi gerçekleştirmek için gerekli izinlere sahip değilsin.",
-		"You do not have the necessary permissions to perform the operation you are trying to perform.",
-	)
-	ErrRequestTimeout = errapi.New(
-		fiber.StatusRequestTimeout,
-		errapi.ErrCodeRequestTimeout,
-		"İşlem yapmak için gerekli olan süre aşıldı. Lütfen daha sonra tekrar dene.",
-		"The time required to perform the operation has expired. Please try again later.",
-	)
-	ErrTooManyRequests = errapi.New(
-		fiber.StatusTooManyRequests,
-		errapi.ErrCodeTooManyRequests,
-		"Çok fazla istek gönderdin. Lütfen daha sonra tekrar dene.",
-		"You have sent too many requests. Please try again later.",
-	)
-	ErrRequestEntityTooLarge = errapi.New(
-		fiber.StatusRequestEntityTooLarge,
-		errapi.ErrCodeRequestEntityTooLarge,
-		"Gönderilen istek boyutu fazla büyük.",
-		"The request entity sent is too large.",
-	)
+	ErrInternalServer        = errapi.New(fiber.StatusInternalServerError, errapi.ErrCodeInternalServerError)
+	ErrUrlParamInvalid       = errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUrlParamInvalid)
+	ErrNotFound              = errapi.New(fiber.StatusNotFound, errapi.ErrCodeNotFound)
+	ErrMethodNotAllowed      = errapi.New(fiber.StatusMethodNotAllowed, errapi.ErrCodeMethodNotAllowed)
+	ErrRequestTimeout        = errapi.New(fiber.StatusRequestTimeout, errapi.ErrCodeRequestTimeout)
+	ErrTooManyRequests       = errapi.New(fiber.StatusTooManyRequests, errapi.ErrCodeTooManyRequests)
+	ErrRequestEntityTooLarge = errapi.New(fiber.StatusRequestEntityTooLarge, errapi.ErrCodeRequestEntityTooLarge)
+	ErrUnsupportedMediaType  = errapi.New(fiber.StatusUnsupportedMediaType, errapi.ErrCodeUnsupportedMediaType)
+
+	// ErrTooManyRequestsRetryAfter has the seconds to retry after as the count param.
+	ErrTooManyRequestsRetryAfter = errapi.WithMessage(ErrTooManyRequests, "tooManyRequests.retryAfter")
+
+	// ErrVersion.AppVersionUnsupported has the minimum version of the app as the version param.
+	ErrVersion = struct {
+		APIVersionInvalid     errapi.Error
+		APIVersionSunset      errapi.Error
+		AppVersionInvalid     errapi.Error
+		AppVersionUnsupported errapi.Error
+	}{
+		APIVersionInvalid:     errapi.New(fiber.StatusBadRequest, errapi.ErrCodeAPIVersionInvalid),
+		APIVersionSunset:      errapi.New(fiber.StatusGone, errapi.ErrCodeAPIVersionSunset),
+		AppVersionInvalid:     errapi.New(fiber.StatusBadRequest, errapi.ErrCodeAppVersionInvalid),
+		AppVersionUnsupported: errapi.New(fiber.StatusUpgradeRequired, errapi.ErrCodeAppVersionUnsupported),
+	}
+
+	// ErrGraphQL.DepthExceeded and ErrGraphQL.ComplexityExceeded
+	// have the limit of the query as the max param.
+	ErrGraphQL = struct {
+		QueryMissing       errapi.Error
+		QueryInvalid       errapi.Error
+		DepthExceeded      errapi.Error
+		ComplexityExceeded errapi.Error
+	}{
+		QueryMissing:       errapi.New(fiber.StatusBadRequest, errapi.ErrCodeGraphQLQueryMissing),
+		QueryInvalid:       errapi.New(fiber.StatusBadRequest, errapi.ErrCodeGraphQLQueryInvalid),
+		DepthExceeded:      errapi.New(fiber.StatusBadRequest, errapi.ErrCodeGraphQLDepthExceeded),
+		ComplexityExceeded: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeGraphQLComplexityExceeded),
+	}
 
 	ErrUserAuth = struct {
 		AuthorizationMissing errapi.Error
@@ -55,58 +51,31 @@ var (
 		AuthorizationWrong   errapi.Error
 		AuthorizationExpired errapi.Error
 	}{
-		AuthorizationMissing: errapi.New(
-			fiber.StatusUnauthorized,
-			errapi.ErrCodeAuthorizationMissing,
-			"Kullanıcı oturum kodu eksik.",
-			"Authorization is missing.",
-		),
-		AuthorizationInvalid: errapi.New(
-			fiber.StatusUnauthorized,
-			errapi.ErrCodeAuthorizationInvalid,
-			"Kullanıcı oturum kodu geçersiz.",
-			"Authorization is invalid.",
-		),
-		AuthorizationWrong: errapi.New(
-			fiber.StatusUnauthorized,
-			errapi.ErrCodeAuthorizationWrong,
-			"Kullanıcı oturum kodu hatalı.",
-			"Authorization is wrong.",
-		),
-		AuthorizationExpired: errapi.New(
-			fiber.StatusUnauthorized,
-			errapi.ErrCodeAuthorizationExpired,
-			"Kullanıcı oturum kodu süresi geçmiş.",
-			"Authorization is expired.",
-		),
+		AuthorizationMissing: errapi.New(fiber.StatusUnauthorized, errapi.ErrCodeAuthorizationMissing),
+		AuthorizationInvalid: errapi.New(fiber.StatusUnauthorized, errapi.ErrCodeAuthorizationInvalid),
+		AuthorizationWrong:   errapi.New(fiber.StatusUnauthorized, errapi.ErrCodeAuthorizationWrong),
+		AuthorizationExpired: errapi.New(fiber.StatusUnauthorized, errapi.ErrCodeAuthorizationExpired),
 	}
 
 	ErrUserVerify = struct {
 		AuthorizationNotVerified errapi.Error
 	}{
-		AuthorizationNotVerified: errapi.New(
-			fiber.StatusUnauthorized,
-			errapi.ErrCodeAuthorizationNotVerified,
-			"Kullanıcı oturumu doğrulanmamış.",
-			"Authorization is not verified.",
-		),
+		AuthorizationNotVerified: errapi.New(fiber.StatusUnauthorized, errapi.ErrCodeAuthorizationNotVerified),
 	}
 
 	ErrAgentAuth = struct {
 		AuthorizationInvalidIP          errapi.Error
 		AuthorizationInvalidAccessLevel errapi.Error
 	}{
-		AuthorizationInvalidIP: errapi.New(
-			fiber.StatusUnauthorized,
-			errapi.ErrCodeAuthorizationInvalidIP,
-			"Temsilci oturum kodunun IP adresi geçersiz.",
-			"Agent has invalid IP address.",
-		),
-		AuthorizationInvalidAccessLevel: errapi.New(
-			fiber.StatusUnauthorized,
-			errapi.ErrCodeAuthorizationInsufficientAccessLevel,
-			"Temsilci erişim seviyesi yetersiz.",
-			"Agent has invalid access level.",
-		),
+		AuthorizationInvalidIP:          errapi.New(fiber.StatusUnauthorized, errapi.ErrCodeAuthorizationInvalidIP),
+		AuthorizationInvalidAccessLevel: errapi.New(fiber.StatusUnauthorized, errapi.ErrCodeAuthorizationInsufficientAccessLevel),
+	}
+
+	ErrAdminAuth = struct {
+		AuthorizationMissing errapi.Error
+		AuthorizationWrong   errapi.Error
+	}{
+		AuthorizationMissing: errapi.WithMessage(errapi.New(fiber.StatusUnauthorized, errapi.ErrCodeAuthorizationMissing), "authorizationMissing.admin"),
+		AuthorizationWrong:   errapi.WithMessage(errapi.New(fiber.StatusUnauthorized, errapi.ErrCodeAuthorizationWrong), "authorizationWrong.admin"),
 	}
 )
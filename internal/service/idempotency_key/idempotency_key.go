@@ -0,0 +1,17 @@
+package idempotency_key
+
+import (
+	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+	IdempotencyKeyServiceV1 "github.com/koraygocmen/golang-boilerplate/internal/service/idempotency_key/v1"
+)
+
+// Service definition.
+type Service struct {
+	V1 *IdempotencyKeyServiceV1.Service
+}
+
+func New(tx *repo.Transaction) *Service {
+	return &Service{
+		V1: IdempotencyKeyServiceV1.New(tx),
+	}
+}
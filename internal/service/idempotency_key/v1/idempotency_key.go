@@ -0,0 +1,215 @@
+package idempotency_key_v1
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	IdempotencyKey "github.com/koraygocmen/golang-boilerplate/internal/model/idempotency_key"
+	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+)
+
+// Function definitions to make it easier to reference the functions.
+type ClaimParams struct {
+	Scope string
+	Key   string
+	// Method, Path and Body identify the request the key is used with.
+	Method string
+	Path   string
+	Body   []byte
+	TTL    time.Duration
+	// Secret encrypts the stored responses with the key.
+	Secret string
+}
+type ClaimFn func(ctx context.Ctx, params *ClaimParams) (*IdempotencyKey.IdempotencyKey, errapi.Error, error)
+type SaveParams struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	Secret      string
+}
+type SaveFn func(ctx context.Ctx, idempotencyKey *IdempotencyKey.IdempotencyKey, params *SaveParams) error
+
+const (
+	keyLenMax  = 255
+	ttlDefault = 24 * time.Hour
+)
+
+// Service definition.
+type Service struct {
+	Claim ClaimFn
+	Save  SaveFn
+}
+
+func New(tx *repo.Transaction) *Service {
+	return &Service{
+		Claim: claim(tx),
+		Save:  save(tx),
+	}
+}
+
+// Fingerprint identifies the request an idempotency key is used with.
+func Fingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + "\n" + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// claim claims the key for the request or returns the key stored by
+// the first request. The stored key has the response of the first
+// request to replay. A key used with another request is rejected.
+func claim(tx *repo.Transaction) ClaimFn {
+	return func(ctx context.Ctx, params *ClaimParams) (*IdempotencyKey.IdempotencyKey, errapi.Error, error) {
+		if params == nil || params.Key == "" || len(params.Key) > keyLenMax {
+			aerr := ErrClaim.IdempotencyKeyInvalid
+			return nil, aerr, nil
+		}
+
+		for _, r := range params.Key {
+			if r < 0x21 || r > 0x7e {
+				aerr := ErrClaim.IdempotencyKeyInvalid
+				return nil, aerr, nil
+			}
+		}
+
+		ttl := params.TTL
+		if ttl <= 0 {
+			ttl = ttlDefault
+		}
+
+		idempotencyKey := &IdempotencyKey.IdempotencyKey{
+			Scope:       params.Scope,
+			Key:         params.Key,
+			Fingerprint: Fingerprint(params.Method, params.Path, params.Body),
+			ExpireAt:    time.Now().UTC().Add(ttl),
+		}
+
+		claimed, err := tx.IdempotencyKey.Claim(ctx, idempotencyKey)
+		if err != nil {
+			err = fmt.Errorf("idempotency key service claim error: %w", err)
+			return nil, nil, err
+		}
+
+		if claimed {
+			return idempotencyKey, nil, nil
+		}
+
+		stored, err := tx.IdempotencyKey.Get(ctx, params.Scope, params.Key)
+		if err != nil {
+			err = fmt.Errorf("idempotency key service claim error: %w", err)
+			return nil, nil, err
+		}
+
+		// The key was taken by a committed request, so it
+		// cannot disappear unless it was deleted meanwhile.
+		if stored == nil {
+			err = fmt.Errorf("idempotency key service claim error: key not found: %s", params.Key)
+			return nil, nil, err
+		}
+
+		if stored.Fingerprint != idempotencyKey.Fingerprint {
+			aerr := ErrClaim.IdempotencyKeyReused
+			return nil, aerr, nil
+		}
+
+		if stored.IsReplay() {
+			body, err := open(params.Secret, stored.Scope, stored.Key, stored.Body)
+			if err != nil {
+				err = fmt.Errorf("idempotency key service claim error: %w", err)
+				return nil, nil, err
+			}
+			stored.Body = body
+		}
+
+		return stored, nil, nil
+	}
+}
+
+// save stores the response encrypted, the responses can have the
+// credentials like the tokens of the user sessions.
+func save(tx *repo.Transaction) SaveFn {
+	return func(ctx context.Ctx, idempotencyKey *IdempotencyKey.IdempotencyKey, params *SaveParams) error {
+		body, err := seal(params.Secret, idempotencyKey.Scope, idempotencyKey.Key, params.Body)
+		if err != nil {
+			err = fmt.Errorf("idempotency key service save error: %w", err)
+			return err
+		}
+
+		idempotencyKey.StatusCode = params.StatusCode
+		idempotencyKey.ContentType = params.ContentType
+		idempotencyKey.Body = body
+
+		if err := tx.IdempotencyKey.Save(ctx, idempotencyKey); err != nil {
+			err = fmt.Errorf("idempotency key service save error: %w", err)
+			return err
+		}
+		return nil
+	}
+}
+
+// aead creates the cipher of the key. The cipher key is derived from
+// the secret and the idempotency key, so a stored response is read by
+// neither the database nor the other keys without the secret.
+func aead(secret, scope, key string) (cipher.AEAD, error) {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(scope + "\n" + key))
+
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		err = fmt.Errorf("aes new cipher error: %w", err)
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		err = fmt.Errorf("cipher new gcm error: %w", err)
+		return nil, err
+	}
+	return gcm, nil
+}
+
+// seal encrypts the body, the nonce is prepended to the ciphertext.
+func seal(secret, scope, key string, body []byte) ([]byte, error) {
+	gcm, err := aead(secret, scope, key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		err = fmt.Errorf("nonce read error: %w", err)
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, body, nil), nil
+}
+
+// open decrypts the body sealed by seal.
+func open(secret, scope, key string, body []byte) ([]byte, error) {
+	gcm, err := aead(secret, scope, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) < gcm.NonceSize() {
+		err = fmt.Errorf("body open error: body too short")
+		return nil, err
+	}
+
+	nonce, ciphertext := body[:gcm.NonceSize()], body[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		err = fmt.Errorf("gcm open error: %w", err)
+		return nil, err
+	}
+	return plaintext, nil
+}
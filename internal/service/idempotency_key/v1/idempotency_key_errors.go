@@ -0,0 +1,16 @@
+package idempotency_key_v1
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+)
+
+var (
+	ErrClaim = struct {
+		IdempotencyKeyInvalid errapi.Error
+		IdempotencyKeyReused  errapi.Error
+	}{
+		IdempotencyKeyInvalid: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeIdempotencyKeyInvalid),
+		IdempotencyKeyReused:  errapi.New(fiber.StatusConflict, errapi.ErrCodeIdempotencyKeyReused),
+	}
+)
@@ -0,0 +1,148 @@
+package idempotency_key_v1
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	IdempotencyKey "github.com/koraygocmen/golang-boilerplate/internal/model/idempotency_key"
+	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+	IdempotencyKeyRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/idempotency_key"
+)
+
+// idempotencyKeyRepoTest keeps the keys in a map, a key
+// that exists is not claimed again.
+func idempotencyKeyRepoTest(keys map[string]*IdempotencyKey.IdempotencyKey) *IdempotencyKeyRepo.Repo {
+	return &IdempotencyKeyRepo.Repo{
+		Claim: func(ctx context.Ctx, idempotencyKey *IdempotencyKey.IdempotencyKey) (bool, error) {
+			if _, ok := keys[idempotencyKey.Scope+idempotencyKey.Key]; ok {
+				return false, nil
+			}
+			keys[idempotencyKey.Scope+idempotencyKey.Key] = idempotencyKey
+			return true, nil
+		},
+		Get: func(ctx context.Ctx, scope, key string) (*IdempotencyKey.IdempotencyKey, error) {
+			return keys[scope+key], nil
+		},
+		Save: func(ctx context.Ctx, idempotencyKey *IdempotencyKey.IdempotencyKey) error {
+			keys[idempotencyKey.Scope+idempotencyKey.Key] = idempotencyKey
+			return nil
+		},
+	}
+}
+
+func TestClaim(t *testing.T) {
+	ctx := context.Background()
+
+	keys := map[string]*IdempotencyKey.IdempotencyKey{}
+	idempotencyKeyService := New(&repo.Transaction{IdempotencyKey: idempotencyKeyRepoTest(keys)})
+
+	// Test invalid keys.
+	for _, key := range []string{"", "with space", strings.Repeat("k", keyLenMax+1)} {
+		_, aerr, err := idempotencyKeyService.Claim(ctx, &ClaimParams{Scope: "user:1", Key: key})
+		if err != nil {
+			t.Fatalf(`want: claim err nil; got: err = %v`, err)
+		}
+		if !errapi.Is(aerr, errapi.ErrCodeIdempotencyKeyInvalid) {
+			t.Fatalf(`want: aerr = %v; got: aerr = %v`, errapi.ErrCodeIdempotencyKeyInvalid, aerr)
+		}
+	}
+
+	params := &ClaimParams{
+		Scope:  "user:1",
+		Key:    "key",
+		Method: "POST",
+		Path:   "/v1/users",
+		Body:   []byte(`{"email":"a@example.com"}`),
+		Secret: "secret",
+	}
+
+	// Test claim.
+	idempotencyKey, aerr, err := idempotencyKeyService.Claim(ctx, params)
+	if err != nil || aerr != nil {
+		t.Fatalf(`want: claim err nil; got: aerr = %v, err = %v`, aerr, err)
+	}
+	if idempotencyKey.IsReplay() {
+		t.Fatalf(`want: claimed key; got: replay`)
+	}
+	if d := time.Until(idempotencyKey.ExpireAt); d < ttlDefault-time.Minute || d > ttlDefault {
+		t.Fatalf(`want: expire in %s; got: %s`, ttlDefault, d)
+	}
+
+	err = idempotencyKeyService.Save(ctx, idempotencyKey, &SaveParams{
+		StatusCode:  201,
+		ContentType: "application/json",
+		Body:        []byte(`{"ok":true}`),
+		Secret:      "secret",
+	})
+	if err != nil {
+		t.Fatalf(`want: save err nil; got: err = %v`, err)
+	}
+	if stored := keys[params.Scope+params.Key]; bytes.Contains(stored.Body, []byte(`{"ok":true}`)) {
+		t.Fatalf(`want: encrypted body; got: %s`, stored.Body)
+	}
+
+	// Test replay.
+	idempotencyKey, aerr, err = idempotencyKeyService.Claim(ctx, params)
+	if err != nil || aerr != nil {
+		t.Fatalf(`want: claim err nil; got: aerr = %v, err = %v`, aerr, err)
+	}
+	if !idempotencyKey.IsReplay() || idempotencyKey.StatusCode != 201 || string(idempotencyKey.Body) != `{"ok":true}` {
+		t.Fatalf(`want: replay of the first response; got: %+v`, idempotencyKey)
+	}
+
+	// Test reused key with another payload.
+	params.Body = []byte(`{"email":"b@example.com"}`)
+	_, aerr, err = idempotencyKeyService.Claim(ctx, params)
+	if err != nil {
+		t.Fatalf(`want: claim err nil; got: err = %v`, err)
+	}
+	if !errapi.Is(aerr, errapi.ErrCodeIdempotencyKeyReused) {
+		t.Fatalf(`want: aerr = %v; got: aerr = %v`, errapi.ErrCodeIdempotencyKeyReused, aerr)
+	}
+
+	// Test the same key of another scope.
+	params.Scope = "user:2"
+	idempotencyKey, aerr, err = idempotencyKeyService.Claim(ctx, params)
+	if err != nil || aerr != nil || idempotencyKey.IsReplay() {
+		t.Fatalf(`want: claimed key; got: %+v, aerr = %v, err = %v`, idempotencyKey, aerr, err)
+	}
+}
+
+func TestSeal(t *testing.T) {
+	body := []byte(`{"userSession":{"token":"token"}}`)
+
+	sealed, err := seal("secret", "user:1", "key", body)
+	if err != nil {
+		t.Fatalf(`want: seal err nil; got: err = %v`, err)
+	}
+	if bytes.Contains(sealed, []byte("token")) {
+		t.Fatalf(`want: encrypted body; got: %s`, sealed)
+	}
+
+	opened, err := open("secret", "user:1", "key", sealed)
+	if err != nil || !bytes.Equal(opened, body) {
+		t.Fatalf(`want: body = %s; got: body = %s, err = %v`, body, opened, err)
+	}
+
+	// Test open with another secret or key.
+	if _, err := open("other", "user:1", "key", sealed); err == nil {
+		t.Fatalf(`want: open err with another secret; got: nil`)
+	}
+	if _, err := open("secret", "user:1", "other", sealed); err == nil {
+		t.Fatalf(`want: open err with another key; got: nil`)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := Fingerprint("POST", "/v1/users", []byte("{}"))
+	if a != Fingerprint("POST", "/v1/users", []byte("{}")) {
+		t.Fatalf(`want: same fingerprint; got: different`)
+	}
+	if a == Fingerprint("POST", "/v1/users/sessions", []byte("{}")) {
+		t.Fatalf(`want: different fingerprint for another path; got: same`)
+	}
+}
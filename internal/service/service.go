@@ -8,8 +8,11 @@ import (
 	"github.com/koraygocmen/golang-boilerplate/internal/logger"
 	"github.com/koraygocmen/golang-boilerplate/internal/repo"
 
+	IdempotencyKeyService "github.com/koraygocmen/golang-boilerplate/internal/service/idempotency_key"
 	UserService "github.com/koraygocmen/golang-boilerplate/internal/service/user"
+	UserPreferenceService "github.com/koraygocmen/golang-boilerplate/internal/service/user_preference"
 	UserSessionService "github.com/koraygocmen/golang-boilerplate/internal/service/user_session"
+	WebhookService "github.com/koraygocmen/golang-boilerplate/internal/service/webhook"
 )
 
 // Service.
@@ -35,22 +38,31 @@ type Transaction struct {
 	Rollback func(err error) error
 	Ping     func() error
 
-	User        *UserService.Service
-	UserSession *UserSessionService.Service
+	IdempotencyKey *IdempotencyKeyService.Service
+	User           *UserService.Service
+	UserPreference *UserPreferenceService.Service
+	UserSession    *UserSessionService.Service
+	Webhook        *WebhookService.Service
 }
 
 func transaction() func(ctx context.Ctx, timeout time.Duration) *Transaction {
 	return func(ctx context.Ctx, timeout time.Duration) *Transaction {
 		tx := repo.New(ctx)
 
+		idempotencyKeyService := IdempotencyKeyService.New(tx)
 		userService := UserService.New(tx)
+		userPreferenceService := UserPreferenceService.New(tx)
 		userSessionService := UserSessionService.New(tx, userService)
+		webhookService := WebhookService.New(tx)
 
 		transaction := &Transaction{
 			tx: tx,
 
-			User:        userService,
-			UserSession: userSessionService,
+			IdempotencyKey: idempotencyKeyService,
+			User:           userService,
+			UserPreference: userPreferenceService,
+			UserSession:    userSessionService,
+			Webhook:        webhookService,
 		}
 
 		// Set the commit, rollback and ping functions.
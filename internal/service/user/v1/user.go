@@ -1,17 +1,21 @@
 package user_v1
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
-	"github.com/asaskevich/govalidator"
 	"github.com/koraygocmen/golang-boilerplate/internal/context"
 	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
-	"github.com/koraygocmen/golang-boilerplate/internal/errhandle"
+	"github.com/koraygocmen/golang-boilerplate/internal/event"
 	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
 	UserSession "github.com/koraygocmen/golang-boilerplate/internal/model/user_session"
+	"github.com/koraygocmen/golang-boilerplate/internal/outbox"
 	"github.com/koraygocmen/golang-boilerplate/internal/repo"
-	"github.com/koraygocmen/golang-boilerplate/internal/slack"
+	UserRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/user"
+	"github.com/koraygocmen/golang-boilerplate/internal/validate"
 	"github.com/koraygocmen/null"
 )
 
@@ -28,9 +32,21 @@ type UpdateParams struct {
 	Password   null.String `json:"password"`
 	GivenNames null.String `json:"givenNames"`
 	Surname    null.String `json:"surname"`
+	// IfMatch is the If-Match header, the user is only
+	// updated if it matches the version of the user.
+	IfMatch string `json:"-"`
 }
 type UpdateFn func(ctx context.Ctx, user *User.User, userSession *UserSession.UserSession, params *UpdateParams) (*User.User, errapi.Error, error)
+type PatchParams struct {
+	// Document is the merge patch document of RFC 7386.
+	Document []byte
+	// IfMatch is the If-Match header, the user is only
+	// patched if it matches the version of the user.
+	IfMatch string
+}
+type PatchFn func(ctx context.Ctx, user *User.User, userSession *UserSession.UserSession, params *PatchParams) (*User.User, errapi.Error, error)
 type GetFn func(ctx context.Ctx, id int64) (*User.User, errapi.Error, error)
+type GetByIDsFn func(ctx context.Ctx, ids []int64) (map[int64]*User.User, errapi.Error, error)
 type DeleteFn func(ctx context.Ctx, user *User.User) (*User.User, errapi.Error, error)
 type ValidateParams struct {
 	Email      null.String `json:"email"`
@@ -39,20 +55,51 @@ type ValidateParams struct {
 	Surname    null.String `json:"surname"`
 }
 
+// passwordMinLen is the minimum length of the password.
+const passwordMinLen = 6
+
+// patchField is a field of the user that can be patched, the nullable
+// fields are cleared with null and the others are missing.
+type patchField struct {
+	nullable bool
+	missing  errapi.Error
+}
+
+var (
+	patchFields = map[string]patchField{
+		"email":      {missing: ErrValidateParams.UserEmailMissing},
+		"password":   {missing: ErrValidateParams.UserPasswordMissing},
+		"givenNames": {nullable: true},
+		"surname":    {nullable: true},
+	}
+
+	patchFieldsReadOnly = map[string]bool{
+		"id":            true,
+		"createdAt":     true,
+		"emailVerified": true,
+		"passwordSet":   true,
+		"version":       true,
+	}
+)
+
 // Service definition.
 type Service struct {
-	Create CreateFn
-	Update UpdateFn
-	Get    GetFn
-	Delete DeleteFn
+	Create   CreateFn
+	Update   UpdateFn
+	Patch    PatchFn
+	Get      GetFn
+	GetByIDs GetByIDsFn
+	Delete   DeleteFn
 }
 
 func New(tx *repo.Transaction) *Service {
 	return &Service{
-		Create: create(tx),
-		Update: update(tx),
-		Get:    get(tx),
-		Delete: delete(tx),
+		Create:   create(tx),
+		Update:   update(tx),
+		Patch:    patch(tx),
+		Get:      get(tx),
+		GetByIDs: getByIDs(tx),
+		Delete:   delete(tx),
 	}
 }
 
@@ -110,16 +157,19 @@ func create(tx *repo.Transaction) CreateFn {
 			return nil, nil, err
 		}
 
-		// Send slack notification.
-		if err := slack.Client.MessageEvent(ctx, "New user", user.Email.String); err != nil {
+		// The subscribers are notified once the transaction commits.
+		if err := outbox.Emit(ctx, tx, &event.UserCreated{User: user}); err != nil {
 			err = fmt.Errorf("user service create error: %w", err)
-			errhandle.Handle(ctx, nil, err, false)
+			return nil, nil, err
 		}
 
 		return user, nil, nil
 	}
 }
 
+// update replaces the user with the params, the nullable fields left
+// out are cleared. The email and the password cannot be cleared, they
+// are kept when left out since the password is never responded.
 func update(tx *repo.Transaction) UpdateFn {
 	return func(ctx context.Ctx, user *User.User, userSession *UserSession.UserSession, params *UpdateParams) (*User.User, errapi.Error, error) {
 		if user == nil {
@@ -130,6 +180,10 @@ func update(tx *repo.Transaction) UpdateFn {
 			return nil, ErrUpdate.UserUpdateParamsMissing, nil
 		}
 
+		if params.IfMatch != "" && !user.ETagMatch(params.IfMatch) {
+			return nil, ErrUpdate.UserVersionMismatch, nil
+		}
+
 		paramsValidated, aerr := validateParams(&ValidateParams{
 			Email:      params.Email,
 			Password:   params.Password,
@@ -140,71 +194,114 @@ func update(tx *repo.Transaction) UpdateFn {
 			return nil, aerr, nil
 		}
 
-		var (
-			userPasswordChanged bool
-		)
-
-		if paramsValidated.Email.Valid {
-			if paramsValidated.Email.String != user.Email.String {
-				userFound, err := tx.User.GetByEmail(ctx, paramsValidated.Email.String)
-				if err != nil {
-					err = fmt.Errorf("user service update error: %w", err)
-					return nil, nil, err
-				}
+		clear := map[string]bool{
+			"givenNames": !paramsValidated.GivenNames.Valid,
+			"surname":    !paramsValidated.Surname.Valid,
+		}
 
-				if userFound != nil {
-					return nil, ErrUpdate.UserEmailExists, nil
-				}
+		user, aerr, err := apply(ctx, tx, user, userSession, paramsValidated, clear)
+		if err != nil {
+			err = fmt.Errorf("user service update error: %w", err)
+			return nil, nil, err
+		}
 
-				user.Email = paramsValidated.Email
-				user.EmailVerified = null.BoolFrom(false)
-			}
+		if aerr != nil {
+			return nil, aerr, nil
 		}
 
-		if paramsValidated.Password.Valid {
-			user.Password = paramsValidated.Password
-			if err := user.PasswordHashCreate(); err != nil {
-				err = fmt.Errorf("user service update error: %w", err)
-				return nil, nil, err
-			}
+		return user, nil, nil
+	}
+}
+
+// patch applies a merge patch document of RFC 7386 to the user. Only
+// the fields in the document are changed and the nullable fields are
+// cleared with null. The read only and the unknown fields are rejected.
+func patch(tx *repo.Transaction) PatchFn {
+	return func(ctx context.Ctx, user *User.User, userSession *UserSession.UserSession, params *PatchParams) (*User.User, errapi.Error, error) {
+		if user == nil {
+			return nil, ErrUpdate.UserMissing, nil
+		}
 
-			userPasswordChanged = true
+		if params == nil || len(params.Document) == 0 {
+			return nil, ErrPatch.UserPatchInvalid, nil
 		}
 
-		if paramsValidated.GivenNames.Valid {
-			user.GivenNames = paramsValidated.GivenNames
+		if params.IfMatch != "" && !user.ETagMatch(params.IfMatch) {
+			return nil, ErrUpdate.UserVersionMismatch, nil
 		}
 
-		if paramsValidated.Surname.Valid {
-			user.Surname = paramsValidated.Surname
+		// The document must be an object, the
+		// other values would replace the user.
+		var document map[string]json.RawMessage
+		if err := json.Unmarshal(params.Document, &document); err != nil || document == nil {
+			return nil, ErrPatch.UserPatchInvalid, nil
 		}
 
-		// Update the user.
-		if err := tx.User.Save(ctx, user); err != nil {
-			err = fmt.Errorf("user service update error: %w", err)
-			return nil, nil, err
+		fields := make([]string, 0, len(document))
+		for field := range document {
+			fields = append(fields, field)
 		}
+		sort.Strings(fields)
 
-		if userPasswordChanged {
-			// Invalidate all user sessions except the current one.
-			userSessions, err := tx.UserSession.ListActive(ctx, user.ID)
-			if err != nil {
-				err = fmt.Errorf("user service update error: %w", err)
-				return nil, nil, err
+		var (
+			v           = validate.New()
+			patchParams ValidateParams
+			clear       = map[string]bool{}
+		)
+
+		for _, field := range fields {
+			if patchFieldsReadOnly[field] {
+				v.Check(field, false, ErrPatch.UserFieldReadOnly)
+				continue
 			}
 
-			for _, us := range userSessions {
-				if userSession != nil && us.ID == userSession.ID {
-					continue
-				}
+			value, ok := patchFields[field]
+			if !ok {
+				v.Check(field, false, ErrPatch.UserFieldUnknown)
+				continue
+			}
 
-				if err := tx.UserSession.Delete(ctx, us); err != nil {
-					err = fmt.Errorf("user service update error: %w", err)
-					return nil, nil, err
+			raw := document[field]
+			if string(raw) == "null" {
+				if v.Check(field, value.nullable, value.missing) {
+					clear[field] = true
 				}
+				continue
+			}
+
+			var str string
+			if err := json.Unmarshal(raw, &str); err != nil {
+				v.Check(field, false, ErrPatch.UserPatchInvalid)
+				continue
+			}
+
+			switch field {
+			case "email":
+				patchParams.Email = null.StringFrom(str)
+			case "password":
+				patchParams.Password = null.StringFrom(str)
+			case "givenNames":
+				patchParams.GivenNames = null.StringFrom(str)
+			case "surname":
+				patchParams.Surname = null.StringFrom(str)
 			}
 		}
 
+		paramsValidated := checkParams(v, &patchParams)
+		if aerr := v.Error(); aerr != nil {
+			return nil, aerr, nil
+		}
+
+		user, aerr, err := apply(ctx, tx, user, userSession, paramsValidated, clear)
+		if err != nil {
+			err = fmt.Errorf("user service patch error: %w", err)
+			return nil, nil, err
+		}
+
+		if aerr != nil {
+			return nil, aerr, nil
+		}
+
 		return user, nil, nil
 	}
 }
@@ -229,76 +326,211 @@ func get(tx *repo.Transaction) GetFn {
 	}
 }
 
+// getByIDs returns the users of the ids by their ids, the ids
+// without a user are missing from the map. The users are loaded
+// with one query, i.e. for the fields of the graphql queries.
+func getByIDs(tx *repo.Transaction) GetByIDsFn {
+	return func(ctx context.Ctx, ids []int64) (map[int64]*User.User, errapi.Error, error) {
+		users, err := tx.User.ListByIDs(ctx, ids)
+		if err != nil {
+			err = fmt.Errorf("user service get by ids error: %w", err)
+			return nil, nil, err
+		}
+
+		usersByID := make(map[int64]*User.User, len(users))
+		for _, user := range users {
+			usersByID[user.ID] = user
+		}
+
+		return usersByID, nil, nil
+	}
+}
+
 func delete(tx *repo.Transaction) DeleteFn {
 	return func(ctx context.Ctx, user *User.User) (*User.User, errapi.Error, error) {
 		if user == nil {
 			return nil, ErrDelete.UserMissing, nil
 		}
 
-		// Should delete the user.
+		if err := tx.User.Delete(ctx, user); err != nil {
+			err = fmt.Errorf("user service delete error: %w", err)
+			return nil, nil, err
+		}
+
+		// All the sessions of the deleted user are revoked.
+		if err := revokeSessions(ctx, tx, user.ID, nil); err != nil {
+			err = fmt.Errorf("user service delete error: %w", err)
+			return nil, nil, err
+		}
+
+		// The subscribers are notified once the transaction commits.
+		if err := outbox.Emit(ctx, tx, &event.UserDeleted{User: user}); err != nil {
+			err = fmt.Errorf("user service delete error: %w", err)
+			return nil, nil, err
+		}
+
 		return user, nil, nil
 	}
 }
 
-// validateParams validates the user params.
-func validateParams(params *ValidateParams) (*ValidateParams, errapi.Error) {
-	// Validate email input.
-	if params.Email.Valid {
-		email := strings.ToLower(strings.TrimSpace(params.Email.String))
+// apply sets the validated params on the user and saves it, the fields
+// in clear are set to null. The other sessions of the user are deleted
+// when the password changes.
+func apply(ctx context.Ctx, tx *repo.Transaction, user *User.User, userSession *UserSession.UserSession, paramsValidated *ValidateParams, clear map[string]bool) (*User.User, errapi.Error, error) {
+	var (
+		userPasswordChanged bool
+	)
+
+	if paramsValidated.Email.Valid {
+		if paramsValidated.Email.String != user.Email.String {
+			userFound, err := tx.User.GetByEmail(ctx, paramsValidated.Email.String)
+			if err != nil {
+				err = fmt.Errorf("apply error: %w", err)
+				return nil, nil, err
+			}
+
+			if userFound != nil {
+				return nil, ErrUpdate.UserEmailExists, nil
+			}
 
-		if email == "" {
-			return nil, ErrValidateParams.UserEmailMissing
+			user.Email = paramsValidated.Email
+			user.EmailVerified = null.BoolFrom(false)
 		}
+	}
 
-		if !govalidator.IsEmail(email) {
-			return nil, ErrValidateParams.UserEmailInvalid
+	if paramsValidated.Password.Valid {
+		user.Password = paramsValidated.Password
+		if err := user.PasswordHashCreate(); err != nil {
+			err = fmt.Errorf("apply error: %w", err)
+			return nil, nil, err
 		}
 
-		email = strings.ToLower(email)
-		params.Email = null.StringFrom(email)
+		userPasswordChanged = true
 	}
 
-	// Validate password input.
-	if params.Password.Valid {
-		password := params.Password.String
-		if password == "" {
-			return nil, ErrValidateParams.UserPasswordMissing
-		}
+	if paramsValidated.GivenNames.Valid {
+		user.GivenNames = paramsValidated.GivenNames
+	}
 
-		if len(password) < 6 {
-			return nil, ErrValidateParams.UserPasswordInvalid
-		}
+	if paramsValidated.Surname.Valid {
+		user.Surname = paramsValidated.Surname
+	}
 
-		params.Password = null.StringFrom(password)
+	if clear["givenNames"] {
+		user.GivenNames = null.String{}
 	}
 
-	// Validate given names input.
-	if params.GivenNames.Valid {
-		givenNames := User.ToGivenNames(params.GivenNames.String)
-		if givenNames == "" {
-			return nil, ErrValidateParams.UserGivenNamesMissing
+	if clear["surname"] {
+		user.Surname = null.String{}
+	}
+
+	// Update the user, the save fails if the user
+	// was updated by another request meanwhile.
+	if err := tx.User.Save(ctx, user); err != nil {
+		if errors.Is(err, UserRepo.ErrVersionConflict) {
+			return nil, ErrUpdate.UserVersionMismatch, nil
 		}
 
-		if len(givenNames) < 2 {
-			return nil, ErrValidateParams.UserGivenNamesInvalid
+		err = fmt.Errorf("apply error: %w", err)
+		return nil, nil, err
+	}
+
+	if userPasswordChanged {
+		// Invalidate all user sessions except the current one.
+		if err := revokeSessions(ctx, tx, user.ID, userSession); err != nil {
+			err = fmt.Errorf("apply error: %w", err)
+			return nil, nil, err
 		}
+	}
 
-		params.GivenNames = null.StringFrom(string(givenNames))
+	// The subscribers are notified once the transaction commits.
+	if err := outbox.Emit(ctx, tx, &event.UserUpdated{User: user, PasswordChanged: userPasswordChanged}); err != nil {
+		err = fmt.Errorf("apply error: %w", err)
+		return nil, nil, err
 	}
 
-	// Validate surname input.
-	if params.Surname.Valid {
-		surname := User.ToSurname(params.Surname.String)
-		if surname == "" {
-			return nil, ErrValidateParams.UserSurnameMissing
+	return user, nil, nil
+}
+
+// revokeSessions deletes the active sessions of the user except the
+// kept one, the devices of the revoked sessions are notified.
+func revokeSessions(ctx context.Ctx, tx *repo.Transaction, userID int64, userSessionKept *UserSession.UserSession) error {
+	userSessions, err := tx.UserSession.ListActive(ctx, userID)
+	if err != nil {
+		err = fmt.Errorf("revoke sessions error: %w", err)
+		return err
+	}
+
+	var userSessionIDs []int64
+	for _, userSession := range userSessions {
+		if userSessionKept != nil && userSession.ID == userSessionKept.ID {
+			continue
 		}
 
-		if len(surname) < 2 {
-			return nil, ErrValidateParams.UserSurnameInvalid
+		if err := tx.UserSession.Delete(ctx, userSession); err != nil {
+			err = fmt.Errorf("revoke sessions error: %w", err)
+			return err
 		}
+		userSessionIDs = append(userSessionIDs, userSession.ID)
+	}
 
-		params.Surname = null.StringFrom(string(surname))
+	if len(userSessionIDs) == 0 {
+		return nil
+	}
+
+	if err := outbox.Emit(ctx, tx, &event.UserSessionRevoked{UserID: userID, UserSessionIDs: userSessionIDs}); err != nil {
+		err = fmt.Errorf("revoke sessions error: %w", err)
+		return err
+	}
+	return nil
+}
+
+// validateParams validates the user params, the
+// errors of all the fields are returned together.
+func validateParams(params *ValidateParams) (*ValidateParams, errapi.Error) {
+	v := validate.New()
+
+	params = checkParams(v, params)
+	if aerr := v.Error(); aerr != nil {
+		return nil, aerr
 	}
 
 	return params, nil
 }
+
+// checkParams normalizes the user params and checks them with the validator.
+func checkParams(v *validate.Validator, params *ValidateParams) *ValidateParams {
+	// Validate email input.
+	if params.Email.Valid {
+		params.Email = null.StringFrom(strings.ToLower(strings.TrimSpace(params.Email.String)))
+	}
+
+	v.String("email", params.Email).
+		NotEmpty(ErrValidateParams.UserEmailMissing).
+		Email(ErrValidateParams.UserEmailInvalid)
+
+	// Validate password input.
+	v.String("password", params.Password).
+		NotEmpty(ErrValidateParams.UserPasswordMissing).
+		MinLen(passwordMinLen, ErrValidateParams.UserPasswordInvalid)
+
+	// Validate given names input.
+	if params.GivenNames.Valid {
+		params.GivenNames = null.StringFrom(string(User.ToGivenNames(params.GivenNames.String)))
+	}
+
+	v.String("givenNames", params.GivenNames).
+		NotEmpty(ErrValidateParams.UserGivenNamesMissing).
+		MinLen(2, ErrValidateParams.UserGivenNamesInvalid)
+
+	// Validate surname input.
+	if params.Surname.Valid {
+		params.Surname = null.StringFrom(string(User.ToSurname(params.Surname.String)))
+	}
+
+	v.String("surname", params.Surname).
+		NotEmpty(ErrValidateParams.UserSurnameMissing).
+		MinLen(2, ErrValidateParams.UserSurnameInvalid)
+
+	return params
+}
@@ -3,6 +3,7 @@ package user_v1
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/golang-boilerplate/internal/i18n"
 )
 
 var (
@@ -10,90 +11,52 @@ var (
 		UserCreateParamsMissing errapi.Error
 		UserEmailExists         errapi.Error
 	}{
-		UserCreateParamsMissing: errapi.New(
-			fiber.StatusBadRequest,
-			errapi.ErrCodeUserCreateParamsMissing,
-			"Lütfen tüm eksik alanları doldur.",
-			"Please fill in all missing fields.",
-		),
-		UserEmailExists: errapi.New(
-			fiber.StatusConflict,
-			errapi.ErrCodeUserEmailExists,
-			"Bu e-posta adresi zaten kayıtlı.",
-			"This email address is already registered.",
-		),
+		UserCreateParamsMissing: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserCreateParamsMissing),
+		UserEmailExists:         errapi.New(fiber.StatusConflict, errapi.ErrCodeUserEmailExists),
 	}
 
 	ErrUpdate = struct {
 		UserMissing             errapi.Error
 		UserUpdateParamsMissing errapi.Error
 		UserEmailExists         errapi.Error
+		UserVersionMismatch     errapi.Error
 	}{
-		UserMissing: errapi.New(
-			fiber.StatusBadRequest,
-			errapi.ErrCodeUserMissing,
-			"Kullanıcı bulunamadı.",
-			"User not found.",
-		),
-		UserUpdateParamsMissing: errapi.New(
-			fiber.StatusBadRequest,
-			errapi.ErrCodeUserUpdateParamsMissing,
-			"Lütfen tüm eksik alanları doldur.",
-			"Please fill in all missing fields.",
-		),
-		UserEmailExists: errapi.New(
-			fiber.StatusConflict,
-			errapi.ErrCodeUserEmailExists,
-			"Bu e-posta adresi zaten kayıtlı.",
-			"This email address is already registered.",
-		),
+		UserMissing:             errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserMissing),
+		UserUpdateParamsMissing: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserUpdateParamsMissing),
+		UserEmailExists:         errapi.New(fiber.StatusConflict, errapi.ErrCodeUserEmailExists),
+		UserVersionMismatch:     errapi.New(fiber.StatusPreconditionFailed, errapi.ErrCodeUserVersionMismatch),
+	}
+
+	ErrPatch = struct {
+		UserPatchInvalid  errapi.Error
+		UserFieldUnknown  errapi.Error
+		UserFieldReadOnly errapi.Error
+	}{
+		UserPatchInvalid:  errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserPatchInvalid),
+		UserFieldUnknown:  errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserFieldUnknown),
+		UserFieldReadOnly: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserFieldReadOnly),
 	}
 
 	ErrGet = struct {
 		UserIdMissing errapi.Error
 		UserNotFound  errapi.Error
 	}{
-		UserIdMissing: errapi.New(
-			fiber.StatusBadRequest,
-			errapi.ErrCodeUserIdMissing,
-			"Kullanıcı kimligi eksik.",
-			"User id missing.",
-		),
-		UserNotFound: errapi.New(
-			fiber.StatusBadRequest,
-			errapi.ErrCodeUserNotFound,
-			"Kullanıcı bulunamadı.",
-			"User not found.",
-		),
+		UserIdMissing: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserIdMissing),
+		UserNotFound:  errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserNotFound),
 	}
 
 	ErrDelete = struct {
 		UserMissing errapi.Error
 	}{
-		UserMissing: errapi.New(
-			fiber.StatusBadRequest,
-			errapi.ErrCodeUserMissing,
-			"Kullanıcı bulunamadı.",
-			"User not found.",
-		),
+		UserMissing: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserMissing),
 	}
 
 	ErrGetByEmailOrPhoneNumber = struct {
 		UserEmailPhoneNumberMissing errapi.Error
 		UserNotFound                errapi.Error
 	}{
-		UserEmailPhoneNumberMissing: errapi.New(
-			fiber.StatusBadRequest,
-			errapi.ErrCodeUserEmailPhoneNumberMissing,
-			"Lütfen e-posta adresinizi veya telefon numaranızı girin.",
-			"Please enter your email address or phone number.",
-		),
-		UserNotFound: errapi.New(
-			fiber.StatusNotFound,
-			errapi.ErrCodeUserNotFound,
-			"Kullanıcı bulunamadı.",
-			"User not found.",
-		),
+		UserEmailPhoneNumberMissing: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserEmailPhoneNumberMissing),
+		UserNotFound:                errapi.New(fiber.StatusNotFound, errapi.ErrCodeUserNotFound),
 	}
 
 	ErrValidateParams = struct {
@@ -106,53 +69,13 @@ var (
 		UserSurnameMissing    errapi.Error
 		UserSurnameInvalid    errapi.Error
 	}{
-		UserEmailMissing: errapi.New(
-			fiber.StatusBadRequest,
-			errapi.ErrCodeUserEmailMissing,
-			"Kullanıcı e-posta adresi eksik.",
-			"User email address is missing.",
-		),
-		UserEmailInvalid: errapi.New(
-			fiber.StatusBadRequest,
-			errapi.ErrCodeUserEmailInvalid,
-			"Kullanıcı e-posta adresi geçersiz.",
-			"User email address is invalid.",
-		),
-		UserPasswordMissing: errapi.New(
-			fiber.StatusBadRequest,
-			errapi.ErrCodeUserPasswordMissing,
-			"Kullanıcı şifresi eksik.",
-			"User password is missing.",
-		),
-		UserPasswordInvalid: errapi.New(
-			fiber.StatusBadRequest,
-			errapi.ErrCodeUserPasswordInvalid,
-			"Kullanıcı şifresi 6 karakter uzunluğunda olmalı.",
-			"User password must be 6 characters long.",
-		),
-		UserGivenNamesMissing: errapi.New(
-			fiber.StatusBadRequest,
-			errapi.ErrCodeUserGivenNamesMissing,
-			"Kullanıcı ismi eksik.",
-			"User given names is missing.",
-		),
-		UserGivenNamesInvalid: errapi.New(
-			fiber.StatusBadRequest,
-			errapi.ErrCodeUserGivenNamesInvalid,
-			"Kullanıcı ismi geçersiz.",
-			"User given names is invalid.",
-		),
-		UserSurnameMissing: errapi.New(
-			fiber.StatusBadRequest,
-			errapi.ErrCodeUserSurnameMissing,
-			"Kullanıcı soyismi eksik.",
-			"User surname is missing.",
-		),
-		UserSurnameInvalid: errapi.New(
-			fiber.StatusBadRequest,
-			errapi.ErrCodeUserSurnameInvalid,
-			"Kullanıcı soyismi geçersiz.",
-			"User surname is invalid.",
-		),
+		UserEmailMissing:      errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserEmailMissing),
+		UserEmailInvalid:      errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserEmailInvalid),
+		UserPasswordMissing:   errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserPasswordMissing),
+		UserPasswordInvalid:   errapi.WithParams(errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserPasswordInvalid), i18n.Params{i18n.ParamCount: passwordMinLen}),
+		UserGivenNamesMissing: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserGivenNamesMissing),
+		UserGivenNamesInvalid: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserGivenNamesInvalid),
+		UserSurnameMissing:    errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserSurnameMissing),
+		UserSurnameInvalid:    errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserSurnameInvalid),
 	}
 )
@@ -1,18 +1,31 @@
 package user_v1
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/koraygocmen/golang-boilerplate/internal/context"
 	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	Outbox "github.com/koraygocmen/golang-boilerplate/internal/model/outbox"
 	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
 	UserSession "github.com/koraygocmen/golang-boilerplate/internal/model/user_session"
 	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+	OutboxRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/outbox"
 	UserRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/user"
 	UserSessionRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/user_session"
 	"github.com/koraygocmen/null"
 )
 
+// outboxRepoTest records the emitted events.
+func outboxRepoTest(events *[]string) *OutboxRepo.Repo {
+	return &OutboxRepo.Repo{
+		Create: func(ctx context.Ctx, outbox *Outbox.Outbox) error {
+			*events = append(*events, outbox.Event)
+			return nil
+		},
+	}
+}
+
 func TestCreate(t *testing.T) {
 	// Override the transaction function in order
 	// to return a transaction with the userRepoTest
@@ -28,6 +41,10 @@ func TestCreate(t *testing.T) {
 		},
 	}
 
+	var events []string
+	tx.Outbox = outboxRepoTest(&events)
+	tx.AfterCommit = func(fn func()) {}
+
 	userService := New(tx)
 
 	params := &CreateParams{
@@ -106,6 +123,9 @@ func TestCreate(t *testing.T) {
 	if user == nil {
 		t.Fatalf(`want: user not nil; got: nil`)
 	}
+	if len(events) != 1 || events[0] != "user.created" {
+		t.Fatalf(`want: events = [user.created]; got: events = %v`, events)
+	}
 }
 
 func TestUpdate(t *testing.T) {
@@ -128,6 +148,10 @@ func TestUpdate(t *testing.T) {
 		},
 	}
 
+	var events []string
+	tx.Outbox = outboxRepoTest(&events)
+	tx.AfterCommit = func(fn func()) {}
+
 	userService := New(tx)
 
 	user := &User.User{
@@ -266,6 +290,33 @@ func TestUpdate(t *testing.T) {
 	}
 	params.Surname = null.StringFrom("göçmen")
 
+	// Test if match of another version.
+	user.Version = 3
+	params.IfMatch = `"2"`
+	_, aerr, err = userService.Update(context.Background(), user, userSession, params)
+	if err != nil {
+		t.Fatalf(`want: update err nil; got: err = %v`, err)
+	}
+	if !errapi.Is(aerr, errapi.ErrCodeUserVersionMismatch) {
+		t.Fatalf(`want: aerr = %v; got: aerr = %v`, errapi.ErrCodeUserVersionMismatch, aerr)
+	}
+	params.IfMatch = `"3"`
+
+	// Test user saved by another request.
+	tx.User.Save = func(ctx context.Ctx, user *User.User) error {
+		return fmt.Errorf("user repo save error: %w", UserRepo.ErrVersionConflict)
+	}
+	_, aerr, err = userService.Update(context.Background(), user, userSession, params)
+	if err != nil {
+		t.Fatalf(`want: update err nil; got: err = %v`, err)
+	}
+	if !errapi.Is(aerr, errapi.ErrCodeUserVersionMismatch) {
+		t.Fatalf(`want: aerr = %v; got: aerr = %v`, errapi.ErrCodeUserVersionMismatch, aerr)
+	}
+	tx.User.Save = func(ctx context.Ctx, user *User.User) error {
+		return nil
+	}
+
 	tx.UserSession.Delete = func(ctx context.Ctx, userSession *UserSession.UserSession) error {
 		if userSession.ID != 2 {
 			t.Fatalf(`want: user session id = 2; got: user session id = %v`, userSession.ID)
@@ -308,4 +359,201 @@ func TestUpdate(t *testing.T) {
 	if got := user.Surname.String; got != "GÖÇMEN" {
 		t.Fatalf(`want: user surname = "GÖÇMEN"; got user surname = %v`, got)
 	}
+
+	// The other sessions are revoked since the password changed.
+	if len(events) != 2 || events[0] != "user_session.revoked" || events[1] != "user.updated" {
+		t.Fatalf(`want: events = [user_session.revoked user.updated]; got: events = %v`, events)
+	}
+
+	// Test the names left out are cleared, the
+	// email and the password are kept.
+	passwordHash := user.PasswordHash.String
+	user, aerr, err = userService.Update(context.Background(), user, userSession, &UpdateParams{})
+	if err != nil || aerr != nil {
+		t.Fatalf(`want: update err nil; got: aerr = %v, err = %v`, aerr, err)
+	}
+
+	if user.GivenNames.Valid || user.Surname.Valid {
+		t.Fatalf(`want: user names cleared; got: given names = %v, surname = %v`, user.GivenNames, user.Surname)
+	}
+
+	if user.Email.String != "koray2@test.com" || user.PasswordHash.String != passwordHash {
+		t.Fatalf(`want: user email and password kept; got: email = %v`, user.Email.String)
+	}
+}
+
+func TestPatch(t *testing.T) {
+	tx := &repo.Transaction{
+		User: &UserRepo.Repo{
+			Save: func(ctx context.Ctx, user *User.User) error {
+				return nil
+			},
+			GetByEmail: func(ctx context.Ctx, email string) (*User.User, error) {
+				return nil, nil
+			},
+		},
+	}
+
+	var events []string
+	tx.Outbox = outboxRepoTest(&events)
+	tx.AfterCommit = func(fn func()) {}
+
+	userService := New(tx)
+
+	user := &User.User{
+		Email:      null.StringFrom("koray@test.com"),
+		GivenNames: null.StringFrom("KORAY"),
+		Surname:    null.StringFrom("GOCMEN"),
+		Version:    1,
+	}
+
+	tests := []struct {
+		document string
+		code     string
+	}{
+		{``, errapi.ErrCodeUserPatchInvalid},
+		{`[]`, errapi.ErrCodeUserPatchInvalid},
+		{`null`, errapi.ErrCodeUserPatchInvalid},
+		{`{"surname": 1}`, errapi.ErrCodeUserPatchInvalid},
+		{`{"name": "koray"}`, errapi.ErrCodeUserFieldUnknown},
+		{`{"emailVerified": true}`, errapi.ErrCodeUserFieldReadOnly},
+		{`{"version": 2}`, errapi.ErrCodeUserFieldReadOnly},
+		{`{"email": null}`, errapi.ErrCodeUserEmailMissing},
+		{`{"password": null}`, errapi.ErrCodeUserPasswordMissing},
+		{`{"email": "koray"}`, errapi.ErrCodeUserEmailInvalid},
+		{`{"surname": "A"}`, errapi.ErrCodeUserSurnameInvalid},
+	}
+
+	for _, test := range tests {
+		_, aerr, err := userService.Patch(context.Background(), user, nil, &PatchParams{Document: []byte(test.document)})
+		if err != nil {
+			t.Fatalf(`want: patch err nil; got: err = %v`, err)
+		}
+		if !errapi.Is(aerr, test.code) {
+			t.Fatalf(`want: aerr = %v for %s; got: aerr = %v`, test.code, test.document, aerr)
+		}
+	}
+
+	// Test every invalid field is reported.
+	_, aerr, err := userService.Patch(context.Background(), user, nil, &PatchParams{
+		Document: []byte(`{"id": 2, "name": "koray", "email": "koray", "surname": "A"}`),
+	})
+	if err != nil {
+		t.Fatalf(`want: patch err nil; got: err = %v`, err)
+	}
+	if fields := aerr.Fields(); len(fields) != 4 {
+		t.Fatalf(`want: 4 field errors; got: %d`, len(fields))
+	}
+
+	// Test if match of another version.
+	_, aerr, err = userService.Patch(context.Background(), user, nil, &PatchParams{Document: []byte(`{}`), IfMatch: `"2"`})
+	if err != nil {
+		t.Fatalf(`want: patch err nil; got: err = %v`, err)
+	}
+	if !errapi.Is(aerr, errapi.ErrCodeUserVersionMismatch) {
+		t.Fatalf(`want: aerr = %v; got: aerr = %v`, errapi.ErrCodeUserVersionMismatch, aerr)
+	}
+
+	// Test the absent fields are kept and null clears.
+	user, aerr, err = userService.Patch(context.Background(), user, nil, &PatchParams{
+		Document: []byte(`{"givenNames": "ahmet", "surname": null}`),
+		IfMatch:  `"1"`,
+	})
+	if err != nil {
+		t.Fatalf(`want: patch err nil; got: err = %v`, err)
+	}
+	if aerr != nil {
+		t.Fatalf(`want: aerr nil; got: aerr = %v`, aerr)
+	}
+
+	if got := user.Email.String; got != "koray@test.com" {
+		t.Fatalf(`want: user email = "koray@test.com"; got user email = %v`, got)
+	}
+
+	if got := user.GivenNames.String; got != "AHMET" {
+		t.Fatalf(`want: user given names = "AHMET"; got user given names = %v`, got)
+	}
+
+	if user.Surname.Valid {
+		t.Fatalf(`want: user surname null; got user surname = %v`, user.Surname.String)
+	}
+
+	if len(events) != 1 || events[0] != "user.updated" {
+		t.Fatalf(`want: events = [user.updated]; got: events = %v`, events)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tx := &repo.Transaction{
+		User: &UserRepo.Repo{},
+		UserSession: &UserSessionRepo.Repo{
+			ListActive: func(ctx context.Ctx, userID int64) ([]*UserSession.UserSession, error) {
+				return []*UserSession.UserSession{{ID: 1, UserID: userID}, {ID: 2, UserID: userID}}, nil
+			},
+		},
+	}
+
+	var events []string
+	tx.Outbox = outboxRepoTest(&events)
+	tx.AfterCommit = func(fn func()) {}
+
+	userService := New(tx)
+
+	user := &User.User{
+		ID:    1,
+		Email: null.StringFrom("koray@test.com"),
+	}
+
+	// Test missing user.
+	_, aerr, err := userService.Delete(context.Background(), nil)
+	if err != nil {
+		t.Fatalf(`want: delete err nil; got: err = %v`, err)
+	}
+	if !errapi.Is(aerr, errapi.ErrCodeUserMissing) {
+		t.Fatalf(`want: aerr = %v; got: aerr = %v`, errapi.ErrCodeUserMissing, aerr)
+	}
+
+	// Test the subscribers are not notified if the user is not deleted.
+	tx.User.Delete = func(ctx context.Ctx, user *User.User) error {
+		return fmt.Errorf("user repo delete error")
+	}
+	if _, _, err := userService.Delete(context.Background(), user); err == nil {
+		t.Fatalf(`want: delete err not nil; got: err nil`)
+	}
+	if len(events) != 0 {
+		t.Fatalf(`want: events = []; got: events = %v`, events)
+	}
+
+	var deleted bool
+	tx.User.Delete = func(ctx context.Ctx, userDeleted *User.User) error {
+		deleted = userDeleted.ID == user.ID
+		return nil
+	}
+
+	var userSessionsDeleted int
+	tx.UserSession.Delete = func(ctx context.Ctx, userSession *UserSession.UserSession) error {
+		userSessionsDeleted++
+		return nil
+	}
+
+	_, aerr, err = userService.Delete(context.Background(), user)
+	if err != nil {
+		t.Fatalf(`want: delete err nil; got: err = %v`, err)
+	}
+	if aerr != nil {
+		t.Fatalf(`want: aerr nil; got: aerr = %v`, aerr)
+	}
+
+	if !deleted {
+		t.Fatalf(`want: user deleted; got: user not deleted`)
+	}
+
+	// All the sessions of the deleted user are revoked.
+	if userSessionsDeleted != 2 {
+		t.Fatalf(`want: user sessions deleted = 2; got: user sessions deleted = %v`, userSessionsDeleted)
+	}
+
+	if len(events) != 2 || events[0] != "user_session.revoked" || events[1] != "user.deleted" {
+		t.Fatalf(`want: events = [user_session.revoked user.deleted]; got: events = %v`, events)
+	}
 }
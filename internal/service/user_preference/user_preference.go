@@ -0,0 +1,17 @@
+package user_preference
+
+import (
+	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+	UserPreferenceServiceV1 "github.com/koraygocmen/golang-boilerplate/internal/service/user_preference/v1"
+)
+
+// Service definition.
+type Service struct {
+	V1 *UserPreferenceServiceV1.Service
+}
+
+func New(tx *repo.Transaction) *Service {
+	return &Service{
+		V1: UserPreferenceServiceV1.New(tx),
+	}
+}
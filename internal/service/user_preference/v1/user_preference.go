@@ -0,0 +1,211 @@
+package user_preference_v1
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/golang-boilerplate/internal/i18n"
+	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
+	UserPreference "github.com/koraygocmen/golang-boilerplate/internal/model/user_preference"
+	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+	"github.com/koraygocmen/golang-boilerplate/internal/validate"
+	"github.com/koraygocmen/null"
+	"golang.org/x/text/language"
+)
+
+// Function definitions to make it easier to reference the functions.
+type GetFn func(ctx context.Ctx, user *User.User) (*UserPreference.UserPreference, errapi.Error, error)
+type GetByUserIDsFn func(ctx context.Ctx, userIDs []int64) (map[int64]*UserPreference.UserPreference, errapi.Error, error)
+type NotificationsParams struct {
+	Security  null.Bool `json:"security"`
+	Product   null.Bool `json:"product"`
+	Marketing null.Bool `json:"marketing"`
+}
+type UpdateParams struct {
+	Locale        null.String         `json:"locale"`
+	Timezone      null.String         `json:"timezone"`
+	Notifications NotificationsParams `json:"notifications"`
+}
+type UpdateFn func(ctx context.Ctx, user *User.User, params *UpdateParams) (*UserPreference.UserPreference, errapi.Error, error)
+type WithLocaleFn func(ctx context.Ctx, userID int64) (context.Ctx, error)
+
+// Service definition.
+type Service struct {
+	Get          GetFn
+	GetByUserIDs GetByUserIDsFn
+	Update       UpdateFn
+	WithLocale   WithLocaleFn
+}
+
+func New(tx *repo.Transaction) *Service {
+	return &Service{
+		Get:          get(tx),
+		GetByUserIDs: getByUserIDs(tx),
+		Update:       update(tx),
+		WithLocale:   withLocale(tx),
+	}
+}
+
+// get returns the preferences of the user, the defaults
+// if the user did not set the preferences yet.
+func get(tx *repo.Transaction) GetFn {
+	return func(ctx context.Ctx, user *User.User) (*UserPreference.UserPreference, errapi.Error, error) {
+		if user == nil {
+			return nil, ErrGet.UserMissing, nil
+		}
+
+		userPreference, err := tx.UserPreference.Get(ctx, user.ID)
+		if err != nil {
+			err = fmt.Errorf("user preference service get error: %w", err)
+			return nil, nil, err
+		}
+
+		if userPreference == nil {
+			userPreference = UserPreference.Default(user.ID)
+		}
+
+		return userPreference, nil, nil
+	}
+}
+
+// getByUserIDs returns the preferences of the users by their ids,
+// the defaults for the users that did not set the preferences yet.
+func getByUserIDs(tx *repo.Transaction) GetByUserIDsFn {
+	return func(ctx context.Ctx, userIDs []int64) (map[int64]*UserPreference.UserPreference, errapi.Error, error) {
+		userPreferences, err := tx.UserPreference.ListByUserIDs(ctx, userIDs)
+		if err != nil {
+			err = fmt.Errorf("user preference service get by user ids error: %w", err)
+			return nil, nil, err
+		}
+
+		userPreferencesByUserID := make(map[int64]*UserPreference.UserPreference, len(userIDs))
+		for _, userPreference := range userPreferences {
+			userPreferencesByUserID[userPreference.UserID] = userPreference
+		}
+		for _, userID := range userIDs {
+			if _, ok := userPreferencesByUserID[userID]; !ok {
+				userPreferencesByUserID[userID] = UserPreference.Default(userID)
+			}
+		}
+
+		return userPreferencesByUserID, nil, nil
+	}
+}
+
+// update sets the preferences of the user, the
+// fields that are not set are kept as they are.
+func update(tx *repo.Transaction) UpdateFn {
+	return func(ctx context.Ctx, user *User.User, params *UpdateParams) (*UserPreference.UserPreference, errapi.Error, error) {
+		if user == nil {
+			return nil, ErrUpdate.UserMissing, nil
+		}
+
+		if params == nil {
+			return nil, ErrUpdate.UserPreferenceParamsMissing, nil
+		}
+
+		params, aerr := validateParams(params)
+		if aerr != nil {
+			return nil, aerr, nil
+		}
+
+		userPreference, err := tx.UserPreference.Get(ctx, user.ID)
+		if err != nil {
+			err = fmt.Errorf("user preference service update error: %w", err)
+			return nil, nil, err
+		}
+
+		if userPreference == nil {
+			userPreference = UserPreference.Default(user.ID)
+		}
+
+		if params.Locale.Valid {
+			userPreference.Locale = params.Locale.String
+		}
+		if params.Timezone.Valid {
+			userPreference.Timezone = params.Timezone.String
+		}
+		if params.Notifications.Security.Valid {
+			userPreference.Notifications.Security = params.Notifications.Security.Bool
+		}
+		if params.Notifications.Product.Valid {
+			userPreference.Notifications.Product = params.Notifications.Product.Bool
+		}
+		if params.Notifications.Marketing.Valid {
+			userPreference.Notifications.Marketing = params.Notifications.Marketing.Bool
+		}
+
+		if err := tx.UserPreference.Save(ctx, userPreference); err != nil {
+			err = fmt.Errorf("user preference service update error: %w", err)
+			return nil, nil, err
+		}
+
+		return userPreference, nil, nil
+	}
+}
+
+// withLocale returns the context with the stored language of the user,
+// the fallback language of the requests of the user and of the jobs.
+func withLocale(tx *repo.Transaction) WithLocaleFn {
+	return func(ctx context.Ctx, userID int64) (context.Ctx, error) {
+		userPreference, err := tx.UserPreference.Get(ctx, userID)
+		if err != nil {
+			err = fmt.Errorf("user preference service with locale error: %w", err)
+			return ctx, err
+		}
+
+		if userPreference == nil {
+			return ctx, nil
+		}
+
+		return context.WithValue(ctx, context.KeyUserLang, context.ToLanguage(userPreference.Locale)), nil
+	}
+}
+
+// validateParams validates the preference params, the locale is
+// normalized to the language of the catalogs it matches.
+func validateParams(params *UpdateParams) (*UpdateParams, errapi.Error) {
+	v := validate.New()
+
+	// Validate locale input.
+	if params.Locale.Valid {
+		params.Locale = null.StringFrom(strings.TrimSpace(params.Locale.String))
+	}
+
+	v.String("locale", params.Locale).
+		Func(func(locale string) bool {
+			if _, err := language.Parse(locale); err != nil {
+				return false
+			}
+
+			tag, ok := i18n.Messages.Match(locale)
+			if ok {
+				params.Locale = null.StringFrom(tag.String())
+			}
+			return ok
+		}, ErrValidateParams.UserPreferenceLocaleInvalid)
+
+	// Validate timezone input.
+	if params.Timezone.Valid {
+		params.Timezone = null.StringFrom(strings.TrimSpace(params.Timezone.String))
+	}
+
+	v.String("timezone", params.Timezone).
+		Func(func(timezone string) bool {
+			if timezone == "" || timezone == "Local" {
+				return false
+			}
+
+			_, err := time.LoadLocation(timezone)
+			return err == nil
+		}, ErrValidateParams.UserPreferenceTimezoneInvalid)
+
+	if aerr := v.Error(); aerr != nil {
+		return nil, aerr
+	}
+
+	return params, nil
+}
@@ -0,0 +1,30 @@
+package user_preference_v1
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+)
+
+var (
+	ErrGet = struct {
+		UserMissing errapi.Error
+	}{
+		UserMissing: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserMissing),
+	}
+
+	ErrUpdate = struct {
+		UserMissing                 errapi.Error
+		UserPreferenceParamsMissing errapi.Error
+	}{
+		UserMissing:                 errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserMissing),
+		UserPreferenceParamsMissing: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserPreferenceParamsMissing),
+	}
+
+	ErrValidateParams = struct {
+		UserPreferenceLocaleInvalid   errapi.Error
+		UserPreferenceTimezoneInvalid errapi.Error
+	}{
+		UserPreferenceLocaleInvalid:   errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserPreferenceLocaleInvalid),
+		UserPreferenceTimezoneInvalid: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeUserPreferenceTimezoneInvalid),
+	}
+)
@@ -0,0 +1,171 @@
+package user_preference_v1
+
+import (
+	"testing"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
+	UserPreference "github.com/koraygocmen/golang-boilerplate/internal/model/user_preference"
+	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+	UserPreferenceRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/user_preference"
+	"github.com/koraygocmen/null"
+)
+
+// userPreferenceRepoTest keeps the preferences in a map.
+func userPreferenceRepoTest(userPreferences map[int64]*UserPreference.UserPreference) *UserPreferenceRepo.Repo {
+	return &UserPreferenceRepo.Repo{
+		Get: func(ctx context.Ctx, userID int64) (*UserPreference.UserPreference, error) {
+			return userPreferences[userID], nil
+		},
+		ListByUserIDs: func(ctx context.Ctx, userIDs []int64) ([]*UserPreference.UserPreference, error) {
+			list := []*UserPreference.UserPreference{}
+			for _, userID := range userIDs {
+				if userPreference, ok := userPreferences[userID]; ok {
+					list = append(list, userPreference)
+				}
+			}
+			return list, nil
+		},
+		Save: func(ctx context.Ctx, userPreference *UserPreference.UserPreference) error {
+			userPreferences[userPreference.UserID] = userPreference
+			return nil
+		},
+	}
+}
+
+func TestGet(t *testing.T) {
+	ctx := context.Background()
+
+	userPreferences := map[int64]*UserPreference.UserPreference{}
+	userPreferenceService := New(&repo.Transaction{UserPreference: userPreferenceRepoTest(userPreferences)})
+
+	// Test user missing.
+	_, aerr, err := userPreferenceService.Get(ctx, nil)
+	if err != nil {
+		t.Fatalf(`want: get err nil; got: err = %v`, err)
+	}
+	if !errapi.Is(aerr, errapi.ErrCodeUserMissing) {
+		t.Fatalf(`want: aerr = %v; got: aerr = %v`, errapi.ErrCodeUserMissing, aerr)
+	}
+
+	// Test defaults.
+	userPreference, aerr, err := userPreferenceService.Get(ctx, &User.User{ID: 1})
+	if err != nil || aerr != nil {
+		t.Fatalf(`want: get err nil; got: aerr = %v, err = %v`, aerr, err)
+	}
+	if *userPreference != *UserPreference.Default(1) {
+		t.Fatalf(`want: %+v; got: %+v`, UserPreference.Default(1), userPreference)
+	}
+}
+
+func TestGetByUserIDs(t *testing.T) {
+	ctx := context.Background()
+
+	userPreferences := map[int64]*UserPreference.UserPreference{
+		1: {UserID: 1, Locale: "en", Timezone: "Europe/Berlin"},
+	}
+	userPreferenceService := New(&repo.Transaction{UserPreference: userPreferenceRepoTest(userPreferences)})
+
+	// Test the stored preferences and the defaults.
+	got, aerr, err := userPreferenceService.GetByUserIDs(ctx, []int64{1, 2})
+	if err != nil || aerr != nil {
+		t.Fatalf(`want: get by user ids err nil; got: aerr = %v, err = %v`, aerr, err)
+	}
+	if len(got) != 2 {
+		t.Fatalf(`want: 2 preferences; got: %d`, len(got))
+	}
+	if got[1] != userPreferences[1] {
+		t.Fatalf(`want: %+v; got: %+v`, userPreferences[1], got[1])
+	}
+	if *got[2] != *UserPreference.Default(2) {
+		t.Fatalf(`want: %+v; got: %+v`, UserPreference.Default(2), got[2])
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	user := &User.User{ID: 1}
+
+	userPreferences := map[int64]*UserPreference.UserPreference{}
+	userPreferenceService := New(&repo.Transaction{UserPreference: userPreferenceRepoTest(userPreferences)})
+
+	// Test invalid params.
+	tests := []struct {
+		params *UpdateParams
+		code   string
+	}{
+		{params: nil, code: errapi.ErrCodeUserPreferenceParamsMissing},
+		{params: &UpdateParams{Locale: null.StringFrom("xx")}, code: errapi.ErrCodeUserPreferenceLocaleInvalid},
+		{params: &UpdateParams{Locale: null.StringFrom("fr")}, code: errapi.ErrCodeUserPreferenceLocaleInvalid},
+		{params: &UpdateParams{Locale: null.StringFrom("en;q=0.5")}, code: errapi.ErrCodeUserPreferenceLocaleInvalid},
+		{params: &UpdateParams{Timezone: null.StringFrom("Mars/Olympus")}, code: errapi.ErrCodeUserPreferenceTimezoneInvalid},
+		{params: &UpdateParams{Timezone: null.StringFrom("Local")}, code: errapi.ErrCodeUserPreferenceTimezoneInvalid},
+	}
+
+	for _, test := range tests {
+		_, aerr, err := userPreferenceService.Update(ctx, user, test.params)
+		if err != nil {
+			t.Fatalf(`want: update err nil; got: err = %v`, err)
+		}
+		if !errapi.Is(aerr, test.code) {
+			t.Fatalf(`want: aerr = %v; got: aerr = %v`, test.code, aerr)
+		}
+	}
+
+	// Test update.
+	userPreference, aerr, err := userPreferenceService.Update(ctx, user, &UpdateParams{
+		Locale:        null.StringFrom(" en-US "),
+		Timezone:      null.StringFrom("Europe/Istanbul"),
+		Notifications: NotificationsParams{Product: null.BoolFrom(false)},
+	})
+	if err != nil || aerr != nil {
+		t.Fatalf(`want: update err nil; got: aerr = %v, err = %v`, aerr, err)
+	}
+	if userPreference.Locale != "en" || userPreference.Timezone != "Europe/Istanbul" {
+		t.Fatalf(`want: locale = en, timezone = Europe/Istanbul; got: locale = %s, timezone = %s`, userPreference.Locale, userPreference.Timezone)
+	}
+	if want := (UserPreference.Notifications{Security: true}); userPreference.Notifications != want {
+		t.Fatalf(`want: %+v; got: %+v`, want, userPreference.Notifications)
+	}
+
+	// Test fields not set are kept.
+	userPreference, aerr, err = userPreferenceService.Update(ctx, user, &UpdateParams{
+		Notifications: NotificationsParams{Marketing: null.BoolFrom(true)},
+	})
+	if err != nil || aerr != nil {
+		t.Fatalf(`want: update err nil; got: aerr = %v, err = %v`, aerr, err)
+	}
+	if userPreference.Locale != "en" || !userPreference.Notifications.Marketing || userPreference.Notifications.Product {
+		t.Fatalf(`want: locale en and marketing only; got: %+v`, userPreference)
+	}
+}
+
+func TestWithLocale(t *testing.T) {
+	ctx := context.Background()
+
+	userPreferences := map[int64]*UserPreference.UserPreference{}
+	userPreferenceService := New(&repo.Transaction{UserPreference: userPreferenceRepoTest(userPreferences)})
+
+	// Test no preferences stored.
+	ctx, err := userPreferenceService.WithLocale(ctx, 1)
+	if err != nil {
+		t.Fatalf(`want: with locale err nil; got: err = %v`, err)
+	}
+	if lang := context.Lang(ctx); lang != context.LangDefault {
+		t.Fatalf(`want: %s; got: %s`, context.LangDefault, lang)
+	}
+
+	// Test stored locale.
+	userPreference := UserPreference.Default(1)
+	userPreference.Locale = "de"
+	userPreferences[1] = userPreference
+
+	ctx, err = userPreferenceService.WithLocale(ctx, 1)
+	if err != nil {
+		t.Fatalf(`want: with locale err nil; got: err = %v`, err)
+	}
+	if lang := context.Lang(ctx); lang != context.LangDE {
+		t.Fatalf(`want: %s; got: %s`, context.LangDE, lang)
+	}
+}
@@ -5,9 +5,12 @@ import (
 
 	"github.com/koraygocmen/golang-boilerplate/internal/context"
 	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/golang-boilerplate/internal/event"
 	UserSession "github.com/koraygocmen/golang-boilerplate/internal/model/user_session"
+	"github.com/koraygocmen/golang-boilerplate/internal/outbox"
 	"github.com/koraygocmen/golang-boilerplate/internal/repo"
 	UserService "github.com/koraygocmen/golang-boilerplate/internal/service/user"
+	"github.com/koraygocmen/golang-boilerplate/internal/validate"
 )
 
 // Function definitions to make it easier to reference the functions.
@@ -21,19 +24,22 @@ type CreateParams struct {
 type CreateFn func(ctx context.Ctx, params *CreateParams) (*UserSession.UserSession, errapi.Error, error)
 type GetFn func(ctx context.Ctx, id int64) (*UserSession.UserSession, errapi.Error, error)
 type DeleteFn func(ctx context.Ctx, userID int64, userSessionActive *UserSession.UserSession) (errapi.Error, error)
+type ListByUserIDsFn func(ctx context.Ctx, userIDs []int64) (map[int64][]*UserSession.UserSession, errapi.Error, error)
 
 // Service definition.
 type Service struct {
-	Create CreateFn
-	Get    GetFn
-	Delete DeleteFn
+	Create        CreateFn
+	Get           GetFn
+	Delete        DeleteFn
+	ListByUserIDs ListByUserIDsFn
 }
 
 func New(tx *repo.Transaction, userService *UserService.Service) *Service {
 	return &Service{
-		Create: create(tx, userService),
-		Get:    get(tx),
-		Delete: delete(tx),
+		Create:        create(tx, userService),
+		Get:           get(tx),
+		Delete:        delete(tx),
+		ListByUserIDs: listByUserIDs(tx),
 	}
 }
 
@@ -56,12 +62,11 @@ func create(tx *repo.Transaction, userService *UserService.Service) CreateFn {
 			return nil, nil, err
 		}
 
-		if purpose == "" {
-			return nil, ErrCreate.UserSessionPurposeMissing, nil
-		}
-
-		if !UserSession.Purposes[purpose] {
-			return nil, ErrCreate.UserSessionPurposeInvalid, nil
+		v := validate.New()
+		v.Check("purpose", purpose != "", ErrCreate.UserSessionPurposeMissing)
+		v.Check("purpose", UserSession.Purposes[purpose], ErrCreate.UserSessionPurposeInvalid)
+		if aerr := v.Error(); aerr != nil {
+			return nil, aerr, nil
 		}
 
 		user, err := tx.User.GetByEmail(ctx, email)
@@ -128,6 +133,7 @@ func delete(tx *repo.Transaction) DeleteFn {
 			return nil, err
 		}
 
+		var userSessionIDs []int64
 		for _, userSession := range userSessions {
 			if userSessionActive != nil && userSession.ID == userSessionActive.ID {
 				continue
@@ -137,8 +143,37 @@ func delete(tx *repo.Transaction) DeleteFn {
 				err = fmt.Errorf("user session service delete error: %w", err)
 				return nil, err
 			}
+			userSessionIDs = append(userSessionIDs, userSession.ID)
+		}
+
+		// The devices of the revoked sessions are notified
+		// once the transaction commits.
+		if len(userSessionIDs) > 0 {
+			if err := outbox.Emit(ctx, tx, &event.UserSessionRevoked{UserID: userID, UserSessionIDs: userSessionIDs}); err != nil {
+				err = fmt.Errorf("user session service delete error: %w", err)
+				return nil, err
+			}
 		}
 
 		return nil, nil
 	}
 }
+
+// listByUserIDs returns the active sessions of the users by their
+// ids, the sessions of all the users are loaded with one query.
+func listByUserIDs(tx *repo.Transaction) ListByUserIDsFn {
+	return func(ctx context.Ctx, userIDs []int64) (map[int64][]*UserSession.UserSession, errapi.Error, error) {
+		userSessions, err := tx.UserSession.ListActiveByUserIDs(ctx, userIDs)
+		if err != nil {
+			err = fmt.Errorf("user session service list by user ids error: %w", err)
+			return nil, nil, err
+		}
+
+		userSessionsByUserID := make(map[int64][]*UserSession.UserSession, len(userIDs))
+		for _, userSession := range userSessions {
+			userSessionsByUserID[userSession.UserID] = append(userSessionsByUserID[userSession.UserID], userSession)
+		}
+
+		return userSessionsByUserID, nil, nil
+	}
+}
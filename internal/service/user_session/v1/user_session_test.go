@@ -1,15 +1,18 @@
 package user_session_v1
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/koraygocmen/golang-boilerplate/internal/context"
 	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
 	"github.com/koraygocmen/null"
 
+	Outbox "github.com/koraygocmen/golang-boilerplate/internal/model/outbox"
 	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
 	UserSession "github.com/koraygocmen/golang-boilerplate/internal/model/user_session"
 	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+	OutboxRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/outbox"
 	UserRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/user"
 	UserSessionRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/user_session"
 	UserService "github.com/koraygocmen/golang-boilerplate/internal/service/user"
@@ -182,6 +185,15 @@ func TestDelete(t *testing.T) {
 		},
 	}
 
+	var payloads []string
+	tx.Outbox = &OutboxRepo.Repo{
+		Create: func(ctx context.Ctx, outbox *Outbox.Outbox) error {
+			payloads = append(payloads, outbox.Payload)
+			return nil
+		},
+	}
+	tx.AfterCommit = func(fn func()) {}
+
 	// Create service dependencies for testing.
 	userService := &UserService.Service{
 		V1: &UserServiceV1.Service{},
@@ -197,4 +209,18 @@ func TestDelete(t *testing.T) {
 	if aerr != nil {
 		t.Fatalf(`want: aerr = nil; got: aerr = %v`, aerr)
 	}
+
+	// Test the revoked sessions are emitted without the active one.
+	_, err = userSessionService.Delete(context.Background(), 1, &UserSession.UserSession{ID: 1})
+	if err != nil {
+		t.Fatalf(`want: delete err nil; got: err = %v`, err)
+	}
+
+	want := []string{
+		`{"userId":1,"userSessionIds":[1,2]}`,
+		`{"userId":1,"userSessionIds":[2]}`,
+	}
+	if !reflect.DeepEqual(payloads, want) {
+		t.Fatalf(`want: payloads = %v; got: payloads = %v`, want, payloads)
+	}
 }
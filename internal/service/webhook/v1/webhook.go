@@ -0,0 +1,381 @@
+package webhook_v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	WebhookDelivery "github.com/koraygocmen/golang-boilerplate/internal/model/webhook_delivery"
+	WebhookSubscription "github.com/koraygocmen/golang-boilerplate/internal/model/webhook_subscription"
+	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+	"github.com/koraygocmen/golang-boilerplate/internal/validate"
+	"github.com/koraygocmen/golang-boilerplate/internal/webhook"
+	"github.com/koraygocmen/null"
+)
+
+// Function definitions to make it easier to reference the functions.
+type CreateParams struct {
+	URL    null.String `json:"url"`
+	Events []string    `json:"events"`
+}
+type CreateFn func(ctx context.Ctx, params *CreateParams) (*WebhookSubscription.WebhookSubscription, errapi.Error, error)
+type UpdateParams struct {
+	URL    null.String `json:"url"`
+	Events []string    `json:"events"`
+	Active null.Bool   `json:"active"`
+}
+type UpdateFn func(ctx context.Ctx, id int64, params *UpdateParams) (*WebhookSubscription.WebhookSubscription, errapi.Error, error)
+type ListFn func(ctx context.Ctx) ([]*WebhookSubscription.WebhookSubscription, errapi.Error, error)
+type DeleteFn func(ctx context.Ctx, id int64) (*WebhookSubscription.WebhookSubscription, errapi.Error, error)
+type DeliveryListParams struct {
+	Status   string `query:"status"`
+	PageSize int    `query:"pageSize"`
+	PageNum  int    `query:"pageNum"`
+}
+type DeliveryListFn func(ctx context.Ctx, subscriptionID int64, params *DeliveryListParams) ([]*WebhookDelivery.WebhookDelivery, errapi.Error, error)
+type DeliveryRedeliverFn func(ctx context.Ctx, id int64) (*WebhookDelivery.WebhookDelivery, errapi.Error, error)
+type PublishFn func(ctx context.Ctx, event WebhookSubscription.Event, data interface{}) error
+type ValidateParams struct {
+	URL    null.String
+	Events []string
+}
+
+// Payload is the body posted to the subscriptions.
+type Payload struct {
+	Event     WebhookSubscription.Event `json:"event"`
+	CreatedAt time.Time                 `json:"createdAt"`
+	Data      interface{}               `json:"data"`
+}
+
+const (
+	pageSizeDefault = 50
+	pageSizeMax     = 100
+)
+
+// Service definition.
+type Service struct {
+	Create            CreateFn
+	Update            UpdateFn
+	List              ListFn
+	Delete            DeleteFn
+	DeliveryList      DeliveryListFn
+	DeliveryRedeliver DeliveryRedeliverFn
+	Publish           PublishFn
+}
+
+func New(tx *repo.Transaction) *Service {
+	return &Service{
+		Create:            create(tx),
+		Update:            update(tx),
+		List:              list(tx),
+		Delete:            delete(tx),
+		DeliveryList:      deliveryList(tx),
+		DeliveryRedeliver: deliveryRedeliver(tx),
+		Publish:           publish(tx),
+	}
+}
+
+// Methods.
+func create(tx *repo.Transaction) CreateFn {
+	return func(ctx context.Ctx, params *CreateParams) (*WebhookSubscription.WebhookSubscription, errapi.Error, error) {
+		if params == nil {
+			return nil, ErrCreate.WebhookSubscriptionParamsMissing, nil
+		}
+
+		if !params.URL.Valid {
+			return nil, ErrValidateParams.WebhookSubscriptionUrlMissing, nil
+		}
+
+		if params.Events == nil {
+			return nil, ErrValidateParams.WebhookSubscriptionEventsMissing, nil
+		}
+
+		paramsValidated, events, aerr := validateParams(&ValidateParams{
+			URL:    params.URL,
+			Events: params.Events,
+		})
+		if aerr != nil {
+			return nil, aerr, nil
+		}
+
+		webhookSubscription := &WebhookSubscription.WebhookSubscription{
+			URL:    paramsValidated.URL.String,
+			Active: true,
+		}
+		webhookSubscription.EventsSet(events)
+
+		if err := webhookSubscription.SecretCreate(); err != nil {
+			err = fmt.Errorf("webhook service create error: %w", err)
+			return nil, nil, err
+		}
+
+		if err := tx.WebhookSubscription.Create(ctx, webhookSubscription); err != nil {
+			err = fmt.Errorf("webhook service create error: %w", err)
+			return nil, nil, err
+		}
+
+		return webhookSubscription, nil, nil
+	}
+}
+
+func update(tx *repo.Transaction) UpdateFn {
+	return func(ctx context.Ctx, id int64, params *UpdateParams) (*WebhookSubscription.WebhookSubscription, errapi.Error, error) {
+		if id == 0 {
+			return nil, ErrUpdate.WebhookSubscriptionIdMissing, nil
+		}
+
+		if params == nil {
+			return nil, ErrUpdate.WebhookSubscriptionParamsMissing, nil
+		}
+
+		webhookSubscription, err := tx.WebhookSubscription.GetByID(ctx, id)
+		if err != nil {
+			err = fmt.Errorf("webhook service update error: %w", err)
+			return nil, nil, err
+		}
+
+		if webhookSubscription == nil {
+			return nil, ErrUpdate.WebhookSubscriptionNotFound, nil
+		}
+
+		paramsValidated, events, aerr := validateParams(&ValidateParams{
+			URL:    params.URL,
+			Events: params.Events,
+		})
+		if aerr != nil {
+			return nil, aerr, nil
+		}
+
+		if paramsValidated.URL.Valid {
+			webhookSubscription.URL = paramsValidated.URL.String
+		}
+
+		if events != nil {
+			webhookSubscription.EventsSet(events)
+		}
+
+		if params.Active.Valid {
+			webhookSubscription.Active = params.Active.Bool
+		}
+
+		if err := tx.WebhookSubscription.Save(ctx, webhookSubscription); err != nil {
+			err = fmt.Errorf("webhook service update error: %w", err)
+			return nil, nil, err
+		}
+
+		return webhookSubscription, nil, nil
+	}
+}
+
+func list(tx *repo.Transaction) ListFn {
+	return func(ctx context.Ctx) ([]*WebhookSubscription.WebhookSubscription, errapi.Error, error) {
+		webhookSubscriptions, err := tx.WebhookSubscription.List(ctx)
+		if err != nil {
+			err = fmt.Errorf("webhook service list error: %w", err)
+			return nil, nil, err
+		}
+
+		return webhookSubscriptions, nil, nil
+	}
+}
+
+func delete(tx *repo.Transaction) DeleteFn {
+	return func(ctx context.Ctx, id int64) (*WebhookSubscription.WebhookSubscription, errapi.Error, error) {
+		if id == 0 {
+			return nil, ErrDelete.WebhookSubscriptionIdMissing, nil
+		}
+
+		webhookSubscription, err := tx.WebhookSubscription.GetByID(ctx, id)
+		if err != nil {
+			err = fmt.Errorf("webhook service delete error: %w", err)
+			return nil, nil, err
+		}
+
+		if webhookSubscription == nil {
+			return nil, ErrDelete.WebhookSubscriptionNotFound, nil
+		}
+
+		// The pending deliveries of the subscription
+		// are dead lettered by the dispatcher.
+		if err := tx.WebhookSubscription.Delete(ctx, webhookSubscription); err != nil {
+			err = fmt.Errorf("webhook service delete error: %w", err)
+			return nil, nil, err
+		}
+
+		return webhookSubscription, nil, nil
+	}
+}
+
+func deliveryList(tx *repo.Transaction) DeliveryListFn {
+	return func(ctx context.Ctx, subscriptionID int64, params *DeliveryListParams) ([]*WebhookDelivery.WebhookDelivery, errapi.Error, error) {
+		if subscriptionID == 0 {
+			return nil, ErrDeliveryList.WebhookSubscriptionIdMissing, nil
+		}
+
+		if params == nil {
+			params = &DeliveryListParams{}
+		}
+
+		status := WebhookDelivery.ToStatus(params.Status)
+		if status != "" && !WebhookDelivery.Statuses[status] {
+			return nil, ErrDeliveryList.WebhookDeliveryStatusInvalid, nil
+		}
+
+		pageSize := params.PageSize
+		if pageSize <= 0 || pageSize > pageSizeMax {
+			pageSize = pageSizeDefault
+		}
+
+		pageNum := params.PageNum
+		if pageNum <= 0 {
+			pageNum = 1
+		}
+
+		webhookSubscription, err := tx.WebhookSubscription.GetByID(ctx, subscriptionID)
+		if err != nil {
+			err = fmt.Errorf("webhook service delivery list error: %w", err)
+			return nil, nil, err
+		}
+
+		if webhookSubscription == nil {
+			return nil, ErrDeliveryList.WebhookSubscriptionNotFound, nil
+		}
+
+		webhookDeliveries, err := tx.WebhookDelivery.List(ctx, subscriptionID, status, pageSize, pageNum)
+		if err != nil {
+			err = fmt.Errorf("webhook service delivery list error: %w", err)
+			return nil, nil, err
+		}
+
+		return webhookDeliveries, nil, nil
+	}
+}
+
+// deliveryRedeliver queues a delivery again with a fresh set of
+// attempts, e.g. a dead lettered one once the receiver is fixed.
+func deliveryRedeliver(tx *repo.Transaction) DeliveryRedeliverFn {
+	return func(ctx context.Ctx, id int64) (*WebhookDelivery.WebhookDelivery, errapi.Error, error) {
+		if id == 0 {
+			return nil, ErrDeliveryRedeliver.WebhookDeliveryIdMissing, nil
+		}
+
+		webhookDelivery, err := tx.WebhookDelivery.GetByID(ctx, id)
+		if err != nil {
+			err = fmt.Errorf("webhook service delivery redeliver error: %w", err)
+			return nil, nil, err
+		}
+
+		if webhookDelivery == nil {
+			return nil, ErrDeliveryRedeliver.WebhookDeliveryNotFound, nil
+		}
+
+		if webhookDelivery.Status == WebhookDelivery.StatusPending {
+			return nil, ErrDeliveryRedeliver.WebhookDeliveryPending, nil
+		}
+
+		webhookDelivery.Status = WebhookDelivery.StatusPending
+		webhookDelivery.Attempts = 0
+		webhookDelivery.NextAttemptAt = null.TimeFrom(time.Now().UTC())
+
+		if err := tx.WebhookDelivery.Save(ctx, webhookDelivery); err != nil {
+			err = fmt.Errorf("webhook service delivery redeliver error: %w", err)
+			return nil, nil, err
+		}
+
+		tx.AfterCommit(webhook.Notify)
+
+		return webhookDelivery, nil, nil
+	}
+}
+
+// publish creates a delivery of the event for each subscription in
+// the transaction, they are dispatched once the transaction commits.
+func publish(tx *repo.Transaction) PublishFn {
+	return func(ctx context.Ctx, event WebhookSubscription.Event, data interface{}) error {
+		webhookSubscriptions, err := tx.WebhookSubscription.ListByEvent(ctx, event)
+		if err != nil {
+			err = fmt.Errorf("webhook service publish error: %w", err)
+			return err
+		}
+
+		if len(webhookSubscriptions) == 0 {
+			return nil
+		}
+
+		// The deliveries of the relayed events are keyed on the
+		// event, so the retries of the event are not delivered again.
+		outboxID, _ := ctx.Value(context.KeyOutboxID).(int64)
+
+		now := time.Now().UTC()
+		payload, err := json.Marshal(&Payload{
+			Event:     event,
+			CreatedAt: now,
+			Data:      data,
+		})
+		if err != nil {
+			err = fmt.Errorf("webhook service publish error: marshal payload error: %w", err)
+			return err
+		}
+
+		for _, webhookSubscription := range webhookSubscriptions {
+			webhookDelivery := &WebhookDelivery.WebhookDelivery{
+				SubscriptionID: webhookSubscription.ID,
+				OutboxID:       null.NewInt(outboxID, outboxID != 0),
+				Event:          string(event),
+				Payload:        string(payload),
+				Status:         WebhookDelivery.StatusPending,
+				NextAttemptAt:  null.TimeFrom(now),
+			}
+
+			if err := tx.WebhookDelivery.Create(ctx, webhookDelivery); err != nil {
+				err = fmt.Errorf("webhook service publish error: %w", err)
+				return err
+			}
+		}
+
+		tx.AfterCommit(webhook.Notify)
+
+		return nil
+	}
+}
+
+// validateParams validates the subscription params, the errors of all
+// the fields are returned together. The events are nil if they are not set.
+func validateParams(params *ValidateParams) (*ValidateParams, []WebhookSubscription.Event, errapi.Error) {
+	v := validate.New()
+
+	// Validate url input.
+	if params.URL.Valid {
+		params.URL = null.StringFrom(strings.TrimSpace(params.URL.String))
+	}
+
+	v.String("url", params.URL).
+		NotEmpty(ErrValidateParams.WebhookSubscriptionUrlMissing).
+		Func(func(rawURL string) bool {
+			u, err := url.Parse(rawURL)
+			return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+		}, ErrValidateParams.WebhookSubscriptionUrlInvalid)
+
+	// Validate events input.
+	var events []WebhookSubscription.Event
+	if params.Events != nil {
+		v.Check("events", len(params.Events) > 0, ErrValidateParams.WebhookSubscriptionEventsMissing)
+
+		for i, e := range params.Events {
+			event := WebhookSubscription.ToEvent(e)
+			if v.Check(fmt.Sprintf("events.%d", i), WebhookSubscription.Events[event], ErrValidateParams.WebhookSubscriptionEventInvalid) {
+				events = append(events, event)
+			}
+		}
+	}
+
+	if aerr := v.Error(); aerr != nil {
+		return nil, nil, aerr
+	}
+
+	return params, events, nil
+}
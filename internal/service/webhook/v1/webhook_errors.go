@@ -0,0 +1,64 @@
+package webhook_v1
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+)
+
+var (
+	ErrCreate = struct {
+		WebhookSubscriptionParamsMissing errapi.Error
+	}{
+		WebhookSubscriptionParamsMissing: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeWebhookSubscriptionParamsMissing),
+	}
+
+	ErrUpdate = struct {
+		WebhookSubscriptionIdMissing     errapi.Error
+		WebhookSubscriptionNotFound      errapi.Error
+		WebhookSubscriptionParamsMissing errapi.Error
+	}{
+		WebhookSubscriptionIdMissing:     errapi.New(fiber.StatusBadRequest, errapi.ErrCodeWebhookSubscriptionIdMissing),
+		WebhookSubscriptionNotFound:      errapi.New(fiber.StatusNotFound, errapi.ErrCodeWebhookSubscriptionNotFound),
+		WebhookSubscriptionParamsMissing: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeWebhookSubscriptionParamsMissing),
+	}
+
+	ErrDelete = struct {
+		WebhookSubscriptionIdMissing errapi.Error
+		WebhookSubscriptionNotFound  errapi.Error
+	}{
+		WebhookSubscriptionIdMissing: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeWebhookSubscriptionIdMissing),
+		WebhookSubscriptionNotFound:  errapi.New(fiber.StatusNotFound, errapi.ErrCodeWebhookSubscriptionNotFound),
+	}
+
+	ErrDeliveryList = struct {
+		WebhookSubscriptionIdMissing errapi.Error
+		WebhookSubscriptionNotFound  errapi.Error
+		WebhookDeliveryStatusInvalid errapi.Error
+	}{
+		WebhookSubscriptionIdMissing: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeWebhookSubscriptionIdMissing),
+		WebhookSubscriptionNotFound:  errapi.New(fiber.StatusNotFound, errapi.ErrCodeWebhookSubscriptionNotFound),
+		WebhookDeliveryStatusInvalid: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeWebhookDeliveryStatusInvalid),
+	}
+
+	ErrDeliveryRedeliver = struct {
+		WebhookDeliveryIdMissing errapi.Error
+		WebhookDeliveryNotFound  errapi.Error
+		WebhookDeliveryPending   errapi.Error
+	}{
+		WebhookDeliveryIdMissing: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeWebhookDeliveryIdMissing),
+		WebhookDeliveryNotFound:  errapi.New(fiber.StatusNotFound, errapi.ErrCodeWebhookDeliveryNotFound),
+		WebhookDeliveryPending:   errapi.New(fiber.StatusConflict, errapi.ErrCodeWebhookDeliveryPending),
+	}
+
+	ErrValidateParams = struct {
+		WebhookSubscriptionUrlMissing    errapi.Error
+		WebhookSubscriptionUrlInvalid    errapi.Error
+		WebhookSubscriptionEventsMissing errapi.Error
+		WebhookSubscriptionEventInvalid  errapi.Error
+	}{
+		WebhookSubscriptionUrlMissing:    errapi.New(fiber.StatusBadRequest, errapi.ErrCodeWebhookSubscriptionUrlMissing),
+		WebhookSubscriptionUrlInvalid:    errapi.New(fiber.StatusBadRequest, errapi.ErrCodeWebhookSubscriptionUrlInvalid),
+		WebhookSubscriptionEventsMissing: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeWebhookSubscriptionEventsMissing),
+		WebhookSubscriptionEventInvalid:  errapi.New(fiber.StatusBadRequest, errapi.ErrCodeWebhookSubscriptionEventInvalid),
+	}
+)
@@ -0,0 +1,230 @@
+package webhook_v1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	WebhookDelivery "github.com/koraygocmen/golang-boilerplate/internal/model/webhook_delivery"
+	WebhookSubscription "github.com/koraygocmen/golang-boilerplate/internal/model/webhook_subscription"
+	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+	WebhookDeliveryRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/webhook_delivery"
+	WebhookSubscriptionRepo "github.com/koraygocmen/golang-boilerplate/internal/repo/webhook_subscription"
+	"github.com/koraygocmen/null"
+)
+
+func TestCreate(t *testing.T) {
+	tx := &repo.Transaction{
+		WebhookSubscription: &WebhookSubscriptionRepo.Repo{
+			Create: func(ctx context.Ctx, webhookSubscription *WebhookSubscription.WebhookSubscription) error {
+				return nil
+			},
+		},
+	}
+
+	webhookService := New(tx)
+
+	// Test missing params.
+	_, aerr, err := webhookService.Create(context.Background(), nil)
+	if err != nil {
+		t.Fatalf(`want: create err nil; got: err = %v`, err)
+	}
+	if !errapi.Is(aerr, errapi.ErrCodeWebhookSubscriptionParamsMissing) {
+		t.Fatalf(`want: aerr = %v; got: aerr = %v`, errapi.ErrCodeWebhookSubscriptionParamsMissing, aerr)
+	}
+
+	params := &CreateParams{}
+
+	// Test missing url.
+	_, aerr, err = webhookService.Create(context.Background(), params)
+	if err != nil {
+		t.Fatalf(`want: create err nil; got: err = %v`, err)
+	}
+	if !errapi.Is(aerr, errapi.ErrCodeWebhookSubscriptionUrlMissing) {
+		t.Fatalf(`want: aerr = %v; got: aerr = %v`, errapi.ErrCodeWebhookSubscriptionUrlMissing, aerr)
+	}
+
+	// Test invalid url.
+	params.URL = null.StringFrom("ftp://example.com")
+	params.Events = []string{"user.created"}
+	_, aerr, err = webhookService.Create(context.Background(), params)
+	if err != nil {
+		t.Fatalf(`want: create err nil; got: err = %v`, err)
+	}
+	if !errapi.Is(aerr, errapi.ErrCodeWebhookSubscriptionUrlInvalid) {
+		t.Fatalf(`want: aerr = %v; got: aerr = %v`, errapi.ErrCodeWebhookSubscriptionUrlInvalid, aerr)
+	}
+	params.URL = null.StringFrom(" https://example.com/hooks ")
+
+	// Test missing events.
+	params.Events = []string{}
+	_, aerr, err = webhookService.Create(context.Background(), params)
+	if err != nil {
+		t.Fatalf(`want: create err nil; got: err = %v`, err)
+	}
+	if !errapi.Is(aerr, errapi.ErrCodeWebhookSubscriptionEventsMissing) {
+		t.Fatalf(`want: aerr = %v; got: aerr = %v`, errapi.ErrCodeWebhookSubscriptionEventsMissing, aerr)
+	}
+
+	// Test invalid event.
+	params.Events = []string{"user.created", "user.exploded"}
+	_, aerr, err = webhookService.Create(context.Background(), params)
+	if err != nil {
+		t.Fatalf(`want: create err nil; got: err = %v`, err)
+	}
+	if !errapi.Is(aerr, errapi.ErrCodeWebhookSubscriptionEventInvalid) {
+		t.Fatalf(`want: aerr = %v; got: aerr = %v`, errapi.ErrCodeWebhookSubscriptionEventInvalid, aerr)
+	}
+
+	params.Events = []string{"USER.UPDATED", "user.created"}
+	webhookSubscription, aerr, err := webhookService.Create(context.Background(), params)
+	if err != nil {
+		t.Fatalf(`want: create err nil; got: err = %v`, err)
+	}
+	if aerr != nil {
+		t.Fatalf(`want: aerr nil; got: aerr = %v`, aerr)
+	}
+
+	if webhookSubscription.URL != "https://example.com/hooks" {
+		t.Fatalf(`want: url = "https://example.com/hooks"; got: url = %v`, webhookSubscription.URL)
+	}
+
+	if webhookSubscription.Events != "user.created,user.updated" {
+		t.Fatalf(`want: events = "user.created,user.updated"; got: events = %v`, webhookSubscription.Events)
+	}
+
+	if !webhookSubscription.Active || webhookSubscription.Secret == "" {
+		t.Fatalf(`want: active with a secret; got: %+v`, webhookSubscription)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	var (
+		webhookDeliveries []*WebhookDelivery.WebhookDelivery
+		afterCommit       int
+	)
+
+	tx := &repo.Transaction{
+		AfterCommit: func(fn func()) {
+			afterCommit++
+		},
+		WebhookSubscription: &WebhookSubscriptionRepo.Repo{
+			ListByEvent: func(ctx context.Ctx, event WebhookSubscription.Event) ([]*WebhookSubscription.WebhookSubscription, error) {
+				return []*WebhookSubscription.WebhookSubscription{{ID: 1}, {ID: 2}}, nil
+			},
+		},
+		WebhookDelivery: &WebhookDeliveryRepo.Repo{
+			Create: func(ctx context.Ctx, webhookDelivery *WebhookDelivery.WebhookDelivery) error {
+				webhookDeliveries = append(webhookDeliveries, webhookDelivery)
+				return nil
+			},
+		},
+	}
+
+	webhookService := New(tx)
+
+	ctx := context.WithValue(context.Background(), context.KeyOutboxID, int64(7))
+	err := webhookService.Publish(ctx, WebhookSubscription.EventUserCreated, map[string]interface{}{"id": 1})
+	if err != nil {
+		t.Fatalf(`want: publish err nil; got: err = %v`, err)
+	}
+
+	if len(webhookDeliveries) != 2 {
+		t.Fatalf(`want: 2 deliveries; got: %d`, len(webhookDeliveries))
+	}
+
+	for i, webhookDelivery := range webhookDeliveries {
+		if webhookDelivery.SubscriptionID != int64(i+1) || webhookDelivery.Status != WebhookDelivery.StatusPending || !webhookDelivery.NextAttemptAt.Valid {
+			t.Fatalf(`want: pending delivery to subscription %d; got: %+v`, i+1, webhookDelivery)
+		}
+
+		// The deliveries are keyed on the relayed event.
+		if webhookDelivery.OutboxID != null.IntFrom(7) {
+			t.Fatalf(`want: delivery of outbox 7; got: %+v`, webhookDelivery.OutboxID)
+		}
+
+		var payload map[string]interface{}
+		if err := json.Unmarshal([]byte(webhookDelivery.Payload), &payload); err != nil {
+			t.Fatalf(`want: payload unmarshal err nil; got: err = %v`, err)
+		}
+
+		if payload["event"] != "user.created" || payload["data"].(map[string]interface{})["id"] != float64(1) {
+			t.Fatalf(`want: user.created payload; got: %v`, webhookDelivery.Payload)
+		}
+	}
+
+	// The dispatcher is notified once the transaction commits.
+	if afterCommit != 1 {
+		t.Fatalf(`want: 1 after commit; got: %d`, afterCommit)
+	}
+
+	// Nothing is published without subscriptions.
+	tx.WebhookSubscription.ListByEvent = func(ctx context.Ctx, event WebhookSubscription.Event) ([]*WebhookSubscription.WebhookSubscription, error) {
+		return nil, nil
+	}
+
+	err = webhookService.Publish(context.Background(), WebhookSubscription.EventUserCreated, nil)
+	if err != nil {
+		t.Fatalf(`want: publish err nil; got: err = %v`, err)
+	}
+
+	if len(webhookDeliveries) != 2 || afterCommit != 1 {
+		t.Fatalf(`want: no new deliveries; got: %d deliveries, %d after commit`, len(webhookDeliveries), afterCommit)
+	}
+}
+
+func TestDeliveryRedeliver(t *testing.T) {
+	webhookDelivery := &WebhookDelivery.WebhookDelivery{
+		ID:       1,
+		Status:   WebhookDelivery.StatusDead,
+		Attempts: 8,
+	}
+
+	tx := &repo.Transaction{
+		AfterCommit: func(fn func()) {},
+		WebhookDelivery: &WebhookDeliveryRepo.Repo{
+			GetByID: func(ctx context.Ctx, id int64) (*WebhookDelivery.WebhookDelivery, error) {
+				if id != webhookDelivery.ID {
+					return nil, nil
+				}
+				return webhookDelivery, nil
+			},
+			Save: func(ctx context.Ctx, webhookDelivery *WebhookDelivery.WebhookDelivery) error {
+				return nil
+			},
+		},
+	}
+
+	webhookService := New(tx)
+
+	// Test not found.
+	_, aerr, err := webhookService.DeliveryRedeliver(context.Background(), 2)
+	if err != nil {
+		t.Fatalf(`want: redeliver err nil; got: err = %v`, err)
+	}
+	if !errapi.Is(aerr, errapi.ErrCodeWebhookDeliveryNotFound) {
+		t.Fatalf(`want: aerr = %v; got: aerr = %v`, errapi.ErrCodeWebhookDeliveryNotFound, aerr)
+	}
+
+	got, aerr, err := webhookService.DeliveryRedeliver(context.Background(), 1)
+	if err != nil {
+		t.Fatalf(`want: redeliver err nil; got: err = %v`, err)
+	}
+	if aerr != nil {
+		t.Fatalf(`want: aerr nil; got: aerr = %v`, aerr)
+	}
+
+	if got.Status != WebhookDelivery.StatusPending || got.Attempts != 0 || !got.NextAttemptAt.Valid {
+		t.Fatalf(`want: pending delivery with no attempts; got: %+v`, got)
+	}
+
+	// Test already pending.
+	_, aerr, err = webhookService.DeliveryRedeliver(context.Background(), 1)
+	if err != nil {
+		t.Fatalf(`want: redeliver err nil; got: err = %v`, err)
+	}
+	if !errapi.Is(aerr, errapi.ErrCodeWebhookDeliveryPending) {
+		t.Fatalf(`want: aerr = %v; got: aerr = %v`, errapi.ErrCodeWebhookDeliveryPending, aerr)
+	}
+}
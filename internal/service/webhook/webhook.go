@@ -0,0 +1,17 @@
+package webhook
+
+import (
+	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+	WebhookServiceV1 "github.com/koraygocmen/golang-boilerplate/internal/service/webhook/v1"
+)
+
+// Service definition.
+type Service struct {
+	V1 *WebhookServiceV1.Service
+}
+
+func New(tx *repo.Transaction) *Service {
+	return &Service{
+		V1: WebhookServiceV1.New(tx),
+	}
+}
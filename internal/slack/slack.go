@@ -2,19 +2,83 @@ package slack
 
 import (
 	"bytes"
+	goctx "context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/koraygocmen/golang-boilerplate/internal/config"
 	"github.com/koraygocmen/golang-boilerplate/internal/context"
 	"github.com/koraygocmen/golang-boilerplate/internal/env"
 	slackgo "github.com/slack-go/slack"
 )
 
-type messageErrorFn func(ctx context.Ctx, err error) error
+const (
+	// requestTimeout bounds a single call to slack.
+	requestTimeout = 10 * time.Second
+	// ThreadTTLDefault is how long repeated alerts
+	// are replied to the thread of the first one.
+	ThreadTTLDefault = 24 * time.Hour
+	// stackMaxLen keeps the stack trace attachment under
+	// the slack limits, the top of the stack is kept.
+	stackMaxLen = 7000
+	// fieldsMax is the number of fields slack accepts in a section.
+	fieldsMax = 10
+)
+
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityError    Severity = "error"
+	SeverityCritical Severity = "critical"
+)
+
+var (
+	severityColors = map[Severity]string{
+		SeverityInfo:     "#36a64f",
+		SeverityWarning:  "#daa038",
+		SeverityError:    "#d00000",
+		SeverityCritical: "#7b0000",
+	}
+)
+
+// Alert is an error message.
+type Alert struct {
+	Severity Severity
+	// ThreadKey groups the repeated alerts into a thread,
+	// e.g. the error fingerprint. Threads need a bot token.
+	ThreadKey string
+	Err       error
+	// Count is the number of occurrences the alert stands for.
+	Count int
+}
+
+type Config struct {
+	// Token is the bot token used for the web api.
+	// Webhooks are used without it.
+	Token  string
+	APIURL string
+
+	// Channels and Mentions route by severity, in the form
+	// "critical:#alerts,*:#events". "*" matches any severity.
+	Channels string
+	Mentions string
+
+	ThreadTTL time.Duration
+
+	Webhook struct {
+		Errors string
+		Events string
+	}
+}
+
+type messageErrorFn func(ctx context.Ctx, alert Alert) error
 type messageEventFn func(ctx context.Ctx, title, msg string) error
 
 type client struct {
@@ -22,16 +86,192 @@ type client struct {
 	MessageEvent messageEventFn
 }
 
+type thread struct {
+	channel string
+	ts      string
+	created time.Time
+}
+
+type sender struct {
+	config   Config
+	api      *slackgo.Client
+	channels map[Severity]string
+	mentions map[Severity]string
+
+	lock    sync.Mutex
+	threads map[string]thread
+}
+
 var (
 	httpClient = &http.Client{}
-	Client     = client{
-		MessageError: messageError(),
-		MessageEvent: messageEvent(),
-	}
+
+	// Initialize slack without any outputs.
+	Client = newClient(&sender{})
 )
 
-func message(webhook string, message *slackgo.WebhookMessage) error {
-	if env.IsDev() || webhook == "" {
+// Init sets up the client, messages are not sent in dev.
+func Init(config Config) error {
+	if env.IsDev() {
+		return nil
+	}
+
+	s, err := newSender(config)
+	if err != nil {
+		return err
+	}
+
+	Client = newClient(s)
+	return nil
+}
+
+func newClient(s *sender) client {
+	return client{
+		MessageError: messageError(s),
+		MessageEvent: messageEvent(s),
+	}
+}
+
+func newSender(config Config) (*sender, error) {
+	channels, err := ParseRoutes(config.Channels)
+	if err != nil {
+		err = fmt.Errorf("slack channels parse error: %w", err)
+		return nil, err
+	}
+
+	mentions, err := ParseRoutes(config.Mentions)
+	if err != nil {
+		err = fmt.Errorf("slack mentions parse error: %w", err)
+		return nil, err
+	}
+
+	if config.ThreadTTL <= 0 {
+		config.ThreadTTL = ThreadTTLDefault
+	}
+
+	s := &sender{
+		config:   config,
+		channels: channels,
+		mentions: mentions,
+		threads:  make(map[string]thread),
+	}
+
+	if config.Token != "" {
+		options := []slackgo.Option{slackgo.OptionHTTPClient(httpClient)}
+		if config.APIURL != "" {
+			options = append(options, slackgo.OptionAPIURL(strings.TrimSuffix(config.APIURL, "/")+"/"))
+		}
+		s.api = slackgo.New(config.Token, options...)
+	}
+
+	return s, nil
+}
+
+// ParseRoutes parses the severity routes in the form "critical:#alerts,*:#events".
+// Only the first colon separates, the value may contain colons.
+func ParseRoutes(s string) (map[Severity]string, error) {
+	routes := make(map[Severity]string)
+	for _, route := range strings.Split(s, ",") {
+		if route = strings.TrimSpace(route); route == "" {
+			continue
+		}
+
+		severity, value, ok := strings.Cut(route, ":")
+		if !ok || strings.TrimSpace(value) == "" {
+			return nil, fmt.Errorf("invalid route %q", route)
+		}
+
+		severity = strings.ToLower(strings.TrimSpace(severity))
+		switch Severity(severity) {
+		case "*", SeverityInfo, SeverityWarning, SeverityError, SeverityCritical:
+		default:
+			return nil, fmt.Errorf("invalid severity %q", severity)
+		}
+
+		routes[Severity(severity)] = strings.TrimSpace(value)
+	}
+	return routes, nil
+}
+
+func route(routes map[Severity]string, severity Severity) string {
+	if value, ok := routes[severity]; ok {
+		return value
+	}
+	return routes["*"]
+}
+
+// send posts the message with the web api if a channel is routed for
+// the severity, replying to the thread of the key. Otherwise it falls
+// back to the webhook.
+func (s *sender) send(severity Severity, threadKey string, message *slackgo.WebhookMessage) error {
+	channel := route(s.channels, severity)
+	if s.api == nil || channel == "" {
+		webhook := s.config.Webhook.Errors
+		if severity == SeverityInfo {
+			webhook = s.config.Webhook.Events
+		}
+
+		if mention := route(s.mentions, severity); mention != "" {
+			message.Blocks.BlockSet = append([]slackgo.Block{mentionBlock(mention)}, message.Blocks.BlockSet...)
+		}
+
+		return s.webhook(webhook, message)
+	}
+
+	ctx, cancel := goctx.WithTimeout(goctx.Background(), requestTimeout)
+	defer cancel()
+
+	options := []slackgo.MsgOption{
+		slackgo.MsgOptionText(message.Text, false),
+		slackgo.MsgOptionAttachments(message.Attachments...),
+		slackgo.MsgOptionDisableLinkUnfurl(),
+	}
+
+	// Mentions only ping on the first message of a thread.
+	parent, ok := s.thread(threadKey)
+	if ok {
+		channel = parent.channel
+		options = append(options, slackgo.MsgOptionTS(parent.ts))
+	} else if mention := route(s.mentions, severity); mention != "" {
+		message.Blocks.BlockSet = append([]slackgo.Block{mentionBlock(mention)}, message.Blocks.BlockSet...)
+	}
+	options = append(options, slackgo.MsgOptionBlocks(message.Blocks.BlockSet...))
+
+	channelID, ts, err := s.api.PostMessageContext(ctx, channel, options...)
+	if err != nil {
+		err = fmt.Errorf("slack message error: post message error: %w", err)
+		return err
+	}
+
+	if !ok && threadKey != "" {
+		s.lock.Lock()
+		s.threads[threadKey] = thread{channel: channelID, ts: ts, created: time.Now()}
+		s.lock.Unlock()
+	}
+
+	return nil
+}
+
+func (s *sender) thread(key string) (thread, bool) {
+	if key == "" {
+		return thread{}, false
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now()
+	for k, t := range s.threads {
+		if now.Sub(t.created) >= s.config.ThreadTTL {
+			delete(s.threads, k)
+		}
+	}
+
+	t, ok := s.threads[key]
+	return t, ok
+}
+
+func (s *sender) webhook(webhook string, message *slackgo.WebhookMessage) error {
+	if webhook == "" {
 		return nil
 	}
 
@@ -41,7 +281,10 @@ func message(webhook string, message *slackgo.WebhookMessage) error {
 		return err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, webhook, bytes.NewReader(reqbody))
+	ctx, cancel := goctx.WithTimeout(goctx.Background(), requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(reqbody))
 	if err != nil {
 		err := fmt.Errorf("slack message error: http new request error: %w", err)
 		return err
@@ -53,6 +296,7 @@ func message(webhook string, message *slackgo.WebhookMessage) error {
 		err := fmt.Errorf("slack message error: http client do error: %w", err)
 		return err
 	}
+	defer res.Body.Close()
 
 	if res.StatusCode != http.StatusOK {
 		resbody, err := io.ReadAll(res.Body)
@@ -68,71 +312,110 @@ func message(webhook string, message *slackgo.WebhookMessage) error {
 	return nil
 }
 
-func messageError() messageErrorFn {
-	return func(ctx context.Ctx, err error) error {
-		var contextFields []*slackgo.TextBlockObject
-		for k, v := range context.Map(ctx) {
-			contextFields = append(contextFields, &slackgo.TextBlockObject{
-				Type: slackgo.PlainTextType,
-				Text: fmt.Sprintf("%v: %v", k, v),
+func messageError(s *sender) messageErrorFn {
+	return func(ctx context.Ctx, alert Alert) error {
+		if alert.Severity == "" {
+			alert.Severity = SeverityError
+		}
+
+		title := "Application Error"
+		if alert.Count > 1 {
+			title = fmt.Sprintf("Application Error (x%d)", alert.Count)
+		}
+
+		var (
+			contextFields []*slackgo.TextBlockObject
+			stack         string
+		)
+
+		contextMap := context.Map(ctx)
+		for _, k := range context.Keys {
+			v, ok := contextMap[k]
+			if !ok {
+				continue
+			}
+
+			switch k {
+			case context.KeyErrorStack:
+				// Sent as an attachment.
+				stack = fmt.Sprint(v)
+			case context.KeyError:
+				// Sent as the message.
+			default:
+				contextFields = append(contextFields, &slackgo.TextBlockObject{
+					Type: slackgo.PlainTextType,
+					Text: fmt.Sprintf("%v: %v", k, v),
+				})
+			}
+		}
+
+		blocks := []slackgo.Block{
+			&slackgo.SectionBlock{
+				Type: slackgo.MBTHeader,
+				Text: &slackgo.TextBlockObject{
+					Type:  slackgo.PlainTextType,
+					Text:  title,
+					Emoji: true,
+				},
+			},
+			&slackgo.SectionBlock{
+				Type: slackgo.MBTDivider,
+			},
+		}
+
+		for i := 0; i < len(contextFields); i += fieldsMax {
+			end := i + fieldsMax
+			if end > len(contextFields) {
+				end = len(contextFields)
+			}
+
+			blocks = append(blocks, &slackgo.SectionBlock{
+				Type:   slackgo.MBTSection,
+				Fields: contextFields[i:end],
 			})
 		}
 
-		m := &slackgo.WebhookMessage{
-			Blocks: &slackgo.Blocks{
-				BlockSet: []slackgo.Block{
-					&slackgo.SectionBlock{
-						Type: slackgo.MBTSection,
-						Text: &slackgo.TextBlockObject{
-							Type: slackgo.MarkdownType,
-							Text: "<!channel|channel>",
-						},
-					},
-					&slackgo.SectionBlock{
-						Type: slackgo.MBTHeader,
-						Text: &slackgo.TextBlockObject{
-							Type:  slackgo.PlainTextType,
-							Text:  "Application Error",
-							Emoji: true,
-						},
-					},
-					&slackgo.SectionBlock{
-						Type: slackgo.MBTDivider,
-					},
-					&slackgo.SectionBlock{
-						Type:   slackgo.MBTSection,
-						Fields: contextFields,
-					},
-					&slackgo.SectionBlock{
-						Type: slackgo.MBTDivider,
-					},
-					&slackgo.SectionBlock{
-						Type: slackgo.MBTSection,
-						Text: &slackgo.TextBlockObject{
-							Type: slackgo.MarkdownType,
-							Text: fmt.Sprintf("```%v```", err),
-						},
-					},
+		blocks = append(blocks,
+			&slackgo.SectionBlock{
+				Type: slackgo.MBTDivider,
+			},
+			&slackgo.SectionBlock{
+				Type: slackgo.MBTSection,
+				Text: &slackgo.TextBlockObject{
+					Type: slackgo.MarkdownType,
+					Text: fmt.Sprintf("```%v```", alert.Err),
 				},
 			},
+		)
+
+		m := &slackgo.WebhookMessage{
+			Text:   fmt.Sprintf("%s: %v", title, alert.Err),
+			Blocks: &slackgo.Blocks{BlockSet: blocks},
+		}
+
+		if stack != "" {
+			if len(stack) > stackMaxLen {
+				stack = stack[:stackMaxLen] + "\n..."
+			}
+
+			m.Attachments = []slackgo.Attachment{{
+				Color:      severityColors[alert.Severity],
+				Title:      "Stack trace",
+				Text:       fmt.Sprintf("```%s```", stack),
+				MarkdownIn: []string{"text"},
+			}}
 		}
 
-		return message(config.Slack.Webhook.Errors, m)
+		return s.send(alert.Severity, alert.ThreadKey, m)
 	}
 }
 
-func messageEvent() messageEventFn {
+func messageEvent(s *sender) messageEventFn {
 	return func(ctx context.Ctx, title, msg string) error {
 		m := &slackgo.WebhookMessage{
+			Text: title,
 			Blocks: &slackgo.Blocks{
 				BlockSet: []slackgo.Block{
-					&slackgo.SectionBlock{
-						Type: slackgo.MBTSection,
-						Text: &slackgo.TextBlockObject{
-							Type: slackgo.MarkdownType,
-							Text: "<!channel|channel>",
-						},
-					},
 					&slackgo.SectionBlock{
 						Type: slackgo.MBTHeader,
 						Text: &slackgo.TextBlockObject{
@@ -155,6 +438,16 @@ func messageEvent() messageEventFn {
 			},
 		}
 
-		return message(config.Slack.Webhook.Events, m)
+		return s.send(SeverityInfo, "", m)
+	}
+}
+
+func mentionBlock(mention string) slackgo.Block {
+	return &slackgo.SectionBlock{
+		Type: slackgo.MBTSection,
+		Text: &slackgo.TextBlockObject{
+			Type: slackgo.MarkdownType,
+			Text: mention,
+		},
 	}
 }
@@ -0,0 +1,205 @@
+package slack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+)
+
+type post struct {
+	channel     string
+	threadTS    string
+	blocks      string
+	attachments string
+}
+
+type apiTest struct {
+	lock  sync.Mutex
+	posts []post
+}
+
+func (at *apiTest) handler(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	// The client sends the token as a form field or a bearer header.
+	authorized := r.Form.Get("token") == "xoxb-token" || r.Header.Get("Authorization") == "Bearer xoxb-token"
+	if r.URL.Path != "/chat.postMessage" || !authorized {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	at.lock.Lock()
+	at.posts = append(at.posts, post{
+		channel:     r.Form.Get("channel"),
+		threadTS:    r.Form.Get("thread_ts"),
+		blocks:      r.Form.Get("blocks"),
+		attachments: r.Form.Get("attachments"),
+	})
+	ts := fmt.Sprintf("1700000000.%06d", len(at.posts))
+	at.lock.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"ok":      true,
+		"channel": "C" + strings.TrimPrefix(r.Form.Get("channel"), "#"),
+		"ts":      ts,
+	})
+}
+
+func (at *apiTest) get() []post {
+	at.lock.Lock()
+	defer at.lock.Unlock()
+	return append([]post{}, at.posts...)
+}
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes("critical:<!channel>, error:<!subteam^S1|@oncall>,*:<!here>")
+	if err != nil {
+		t.Fatalf("want: parse routes err nil; got: %v", err)
+	}
+
+	if got := route(routes, SeverityError); got != "<!subteam^S1|@oncall>" {
+		t.Fatalf("want: <!subteam^S1|@oncall>; got: %s", got)
+	}
+
+	if got := route(routes, SeverityInfo); got != "<!here>" {
+		t.Fatalf("want: <!here>; got: %s", got)
+	}
+
+	if _, err := ParseRoutes("fatal:#alerts"); err == nil {
+		t.Fatalf("want: parse routes err not nil; got: nil")
+	}
+}
+
+func TestMessageErrorThread(t *testing.T) {
+	api := &apiTest{}
+	server := httptest.NewServer(http.HandlerFunc(api.handler))
+	defer server.Close()
+
+	s, err := newSender(Config{
+		Token:    "xoxb-token",
+		APIURL:   server.URL,
+		Channels: "critical:#critical,error:#alerts",
+		Mentions: "critical:<!channel>",
+	})
+	if err != nil {
+		t.Fatalf("want: new sender err nil; got: %v", err)
+	}
+	c := newClient(s)
+
+	ctx := context.WithValue(context.Background(), context.KeyPath, "/v1/users")
+	ctx = context.WithValue(ctx, context.KeyErrorStack, "goroutine 1 [running]:\nmain.main()")
+
+	alert := Alert{
+		Severity:  SeverityCritical,
+		ThreadKey: "fingerprint",
+		Err:       errors.New("boom"),
+		Count:     1,
+	}
+
+	if err := c.MessageError(ctx, alert); err != nil {
+		t.Fatalf("want: message error err nil; got: %v", err)
+	}
+
+	alert.Count = 3
+	if err := c.MessageError(ctx, alert); err != nil {
+		t.Fatalf("want: message error err nil; got: %v", err)
+	}
+
+	alert.Severity = SeverityError
+	alert.ThreadKey = "other"
+	if err := c.MessageError(context.Background(), alert); err != nil {
+		t.Fatalf("want: message error err nil; got: %v", err)
+	}
+
+	posts := api.get()
+	if len(posts) != 3 {
+		t.Fatalf("want: 3 posts; got: %d", len(posts))
+	}
+
+	// First alert starts the thread and pings.
+	if posts[0].channel != "#critical" || posts[0].threadTS != "" {
+		t.Fatalf("want: #critical without thread; got: %+v", posts[0])
+	}
+	// Blocks are HTML escaped JSON.
+	if !strings.Contains(posts[0].blocks, "!channel") {
+		t.Fatalf("want: mention in blocks; got: %s", posts[0].blocks)
+	}
+	if !strings.Contains(posts[0].attachments, "goroutine 1 [running]") {
+		t.Fatalf("want: stack trace attachment; got: %s", posts[0].attachments)
+	}
+	if strings.Contains(posts[0].blocks, "error_stack") {
+		t.Fatalf("want: stack trace not in blocks; got: %s", posts[0].blocks)
+	}
+
+	// Repeated alert replies to the thread without pinging.
+	if posts[1].channel != "Ccritical" || posts[1].threadTS != "1700000000.000001" {
+		t.Fatalf("want: reply in thread 1700000000.000001; got: %+v", posts[1])
+	}
+	if strings.Contains(posts[1].blocks, "!channel") || !strings.Contains(posts[1].blocks, "(x3)") {
+		t.Fatalf("want: count without mention; got: %s", posts[1].blocks)
+	}
+
+	// Severity routes to its own channel.
+	if posts[2].channel != "#alerts" || posts[2].threadTS != "" {
+		t.Fatalf("want: #alerts without thread; got: %+v", posts[2])
+	}
+}
+
+func TestMessageWebhook(t *testing.T) {
+	var (
+		lock   sync.Mutex
+		bodies = map[string]string{}
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		var marshalled strings.Builder
+		encoder := json.NewEncoder(&marshalled)
+		encoder.SetEscapeHTML(false)
+		encoder.Encode(body)
+
+		lock.Lock()
+		bodies[r.URL.Path] = marshalled.String()
+		lock.Unlock()
+	}))
+	defer server.Close()
+
+	config := Config{Mentions: "error:<!here>"}
+	config.Webhook.Errors = server.URL + "/errors"
+	config.Webhook.Events = server.URL + "/events"
+
+	s, err := newSender(config)
+	if err != nil {
+		t.Fatalf("want: new sender err nil; got: %v", err)
+	}
+	c := newClient(s)
+
+	if err := c.MessageError(context.Background(), Alert{Err: errors.New("boom")}); err != nil {
+		t.Fatalf("want: message error err nil; got: %v", err)
+	}
+
+	if err := c.MessageEvent(context.Background(), "New user", "user@example.com"); err != nil {
+		t.Fatalf("want: message event err nil; got: %v", err)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if !strings.Contains(bodies["/errors"], "<!here>") || !strings.Contains(bodies["/errors"], "boom") {
+		t.Fatalf("want: error with mention; got: %s", bodies["/errors"])
+	}
+
+	if strings.Contains(bodies["/events"], "<!") || !strings.Contains(bodies["/events"], "New user") {
+		t.Fatalf("want: event without mention; got: %s", bodies["/events"])
+	}
+}
@@ -0,0 +1,92 @@
+package graphql
+
+import (
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+)
+
+// Error is the error of a field, the code and the status of
+// the api error are the extensions of the graphql error.
+type Error struct {
+	aerr    errapi.Error
+	message string
+}
+
+func newError(ctx context.Ctx, aerr errapi.Error) *Error {
+	return &Error{
+		aerr:    aerr,
+		message: aerr.Localize(ctx).Message(),
+	}
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+// Extensions implements gqlerrors.ExtendedError.
+func (e *Error) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{
+		"code":   e.aerr.Code(),
+		"status": e.aerr.Status(),
+	}
+	if fields := e.aerr.Fields(); len(fields) > 0 {
+		extensions["errors"] = fields
+	}
+	return extensions
+}
+
+// Unwrap returns the api error.
+func (e *Error) Unwrap() error {
+	return e.aerr
+}
+
+// extend sets the extensions of the errors of the fields. The executor
+// formats the errors of the thunks before it locates them, which drops
+// their extensions, so the api errors are found in the original errors.
+func extend(errs []gqlerrors.FormattedError) {
+	for i, err := range errs {
+		if err.Extensions != nil {
+			continue
+		}
+		if e := unwrap(err.OriginalError()); e != nil {
+			errs[i].Extensions = e.Extensions()
+		}
+	}
+}
+
+func unwrap(err error) *Error {
+	for err != nil {
+		switch e := err.(type) {
+		case *Error:
+			return e
+		case gqlerrors.FormattedError:
+			err = e.OriginalError()
+		case *gqlerrors.Error:
+			err = e.OriginalError
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// requestError formats the error of a request that is not executed, the
+// message of the parser or the validator is kept as the detail.
+func requestError(ctx context.Ctx, aerr errapi.Error, err gqlerrors.FormattedError) gqlerrors.FormattedError {
+	e := newError(ctx, aerr)
+
+	formatted := gqlerrors.FormattedError{
+		Message:    e.Error(),
+		Locations:  err.Locations,
+		Extensions: e.Extensions(),
+	}
+	if formatted.Locations == nil {
+		formatted.Locations = []location.SourceLocation{}
+	}
+	if err.Message != "" {
+		formatted.Extensions["detail"] = err.Message
+	}
+	return formatted
+}
@@ -0,0 +1,210 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/golang-boilerplate/internal/i18n"
+	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
+	UserPreference "github.com/koraygocmen/golang-boilerplate/internal/model/user_preference"
+	UserSession "github.com/koraygocmen/golang-boilerplate/internal/model/user_session"
+	"github.com/koraygocmen/golang-boilerplate/internal/service"
+)
+
+const (
+	MaxDepthDefault      = 8
+	MaxComplexityDefault = 200
+
+	// ListCost is the estimated length of the lists, the complexity
+	// of the selections of the list fields is multiplied by it.
+	ListCost = 10
+)
+
+var (
+	// Default executes the requests of the graphql handler, it is
+	// set up with the limits of the config on start.
+	Default = mustNew(Config{})
+)
+
+type Config struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// Executor executes the graphql requests against the schema
+// of the users and the sessions.
+type Executor struct {
+	schema        gql.Schema
+	maxDepth      int
+	maxComplexity int
+}
+
+func New(config Config) (*Executor, error) {
+	schema, err := newSchema()
+	if err != nil {
+		err = fmt.Errorf("graphql new error: schema error: %w", err)
+		return nil, err
+	}
+
+	e := &Executor{
+		schema:        schema,
+		maxDepth:      config.MaxDepth,
+		maxComplexity: config.MaxComplexity,
+	}
+	if e.maxDepth <= 0 {
+		e.maxDepth = MaxDepthDefault
+	}
+	if e.maxComplexity <= 0 {
+		e.maxComplexity = MaxComplexityDefault
+	}
+
+	return e, nil
+}
+
+func mustNew(config Config) *Executor {
+	e, err := New(config)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Params are the params of the graphql request.
+type Params struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Loaders load the fields of the objects in batches, the loaders
+// are created for every request with the services of its transaction.
+type Loaders struct {
+	User           *Loader[*User.User]
+	UserSessions   *Loader[[]*UserSession.UserSession]
+	UserPreference *Loader[*UserPreference.UserPreference]
+}
+
+// Request is the state of the request the resolvers share, the user
+// and the user session are the ones of the user auth.
+type Request struct {
+	User        *User.User
+	UserSession *UserSession.UserSession
+	Loaders     *Loaders
+}
+
+// Result is the response body of the graphql request, the data
+// is missing if the request is refused before it is executed.
+type Result struct {
+	Data   interface{}                `json:"data,omitempty"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+}
+
+// request is the state of the request in the context of the resolvers.
+type request struct {
+	*Request
+	// err is the first internal error of the resolvers.
+	err error
+}
+
+type requestKey struct{}
+
+func fromContext(ctx context.Ctx) *request {
+	r, _ := ctx.Value(requestKey{}).(*request)
+	return r
+}
+
+// Do executes the request. The api error is returned if the request is
+// refused before it is executed, the result has the error in the graphql
+// format with its extensions. The internal errors of the resolvers are
+// returned for the transaction to be rolled back.
+func (e *Executor) Do(ctx context.Ctx, req *Request, params *Params) (*Result, errapi.Error, error) {
+	if params == nil || strings.TrimSpace(params.Query) == "" {
+		aerr := service.ErrGraphQL.QueryMissing
+		return refuse(ctx, aerr, gqlerrors.FormattedError{}), aerr, nil
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: params.Query})
+	if err != nil {
+		aerr := service.ErrGraphQL.QueryInvalid
+		return refuse(ctx, aerr, gqlerrors.FormatError(err)), aerr, nil
+	}
+
+	// The limits are checked before the validation,
+	// the validation is as costly as the query is.
+	if operation := operationOf(doc, params.OperationName); operation != nil {
+		depth, complexity := measure(&e.schema, doc, operation)
+		if depth > e.maxDepth {
+			aerr := errapi.WithParams(service.ErrGraphQL.DepthExceeded, i18n.Params{"max": e.maxDepth})
+			return refuse(ctx, aerr, gqlerrors.FormattedError{}), aerr, nil
+		}
+		if complexity > e.maxComplexity {
+			aerr := errapi.WithParams(service.ErrGraphQL.ComplexityExceeded, i18n.Params{"max": e.maxComplexity})
+			return refuse(ctx, aerr, gqlerrors.FormattedError{}), aerr, nil
+		}
+	}
+
+	validation := gql.ValidateDocument(&e.schema, doc, nil)
+	if !validation.IsValid {
+		aerr := service.ErrGraphQL.QueryInvalid
+		return refuse(ctx, aerr, validation.Errors...), aerr, nil
+	}
+
+	r := &request{Request: req}
+
+	result := gql.Execute(gql.ExecuteParams{
+		Schema:        e.schema,
+		AST:           doc,
+		OperationName: params.OperationName,
+		Args:          params.Variables,
+		Context:       context.WithValue(ctx, requestKey{}, r),
+	})
+	extend(result.Errors)
+
+	if r.err != nil {
+		err := fmt.Errorf("graphql do error: %w", r.err)
+		return &Result{Data: result.Data, Errors: result.Errors}, nil, err
+	}
+
+	return &Result{Data: result.Data, Errors: result.Errors}, nil, nil
+}
+
+// refuse returns the result of the request refused with the api error.
+func refuse(ctx context.Ctx, aerr errapi.Error, errs ...gqlerrors.FormattedError) *Result {
+	result := &Result{Errors: make([]gqlerrors.FormattedError, 0, len(errs))}
+	for _, err := range errs {
+		result.Errors = append(result.Errors, requestError(ctx, aerr, err))
+	}
+	return result
+}
+
+// operationOf returns the operation of the name, or the only operation of
+// the document if the name is empty. The operations that are not found
+// are refused by the validation or the execution.
+func operationOf(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = operation
+			continue
+		}
+
+		if operation.Name != nil && operation.Name.Value == name {
+			return operation
+		}
+	}
+	return found
+}
@@ -0,0 +1,243 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
+	UserPreference "github.com/koraygocmen/golang-boilerplate/internal/model/user_preference"
+	UserSession "github.com/koraygocmen/golang-boilerplate/internal/model/user_session"
+	"github.com/koraygocmen/null"
+)
+
+// loadersTest returns the loaders of the users and the sessions, the
+// ids of the batches are recorded to count the calls of the loaders.
+func loadersTest(users map[int64]*User.User, userSessions map[int64][]*UserSession.UserSession, batches map[string][][]int64) *Loaders {
+	record := func(name string, ids []int64) {
+		ids = append([]int64{}, ids...)
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		batches[name] = append(batches[name], ids)
+	}
+
+	return &Loaders{
+		User: NewLoader(func(ctx context.Ctx, ids []int64) (map[int64]*User.User, errapi.Error, error) {
+			record("user", ids)
+			values := make(map[int64]*User.User)
+			for _, id := range ids {
+				if user, ok := users[id]; ok {
+					values[id] = user
+				}
+			}
+			return values, nil, nil
+		}),
+		UserSessions: NewLoader(func(ctx context.Ctx, ids []int64) (map[int64][]*UserSession.UserSession, errapi.Error, error) {
+			record("userSessions", ids)
+			values := make(map[int64][]*UserSession.UserSession)
+			for _, id := range ids {
+				values[id] = userSessions[id]
+			}
+			return values, nil, nil
+		}),
+		UserPreference: NewLoader(func(ctx context.Ctx, ids []int64) (map[int64]*UserPreference.UserPreference, errapi.Error, error) {
+			record("userPreference", ids)
+			values := make(map[int64]*UserPreference.UserPreference)
+			for _, id := range ids {
+				values[id] = UserPreference.Default(id)
+			}
+			return values, nil, nil
+		}),
+	}
+}
+
+// resultTest returns the result as it is marshaled to the response.
+func resultTest(t *testing.T, result *Result) map[string]interface{} {
+	t.Helper()
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("want: marshal error nil; got: %v", err)
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(body, &m); err != nil {
+		t.Fatalf("want: unmarshal error nil; got: %v", err)
+	}
+	return m
+}
+
+func TestDo(t *testing.T) {
+	ctx := context.Background()
+
+	users := map[int64]*User.User{
+		1: {ID: 1, Email: null.StringFrom("koray1@test.com")},
+		2: {ID: 2, Email: null.StringFrom("koray2@test.com")},
+	}
+	userSessions := map[int64][]*UserSession.UserSession{
+		1: {
+			{ID: 1, UserID: 1, Purpose: UserSession.PurposeSessionCreate},
+			{ID: 2, UserID: 2, Purpose: UserSession.PurposeSessionCreate},
+			{ID: 3, UserID: 1, Purpose: UserSession.PurposePasswordReset},
+		},
+	}
+
+	batches := make(map[string][][]int64)
+	request := &Request{
+		User:        users[1],
+		UserSession: userSessions[1][0],
+		Loaders:     loadersTest(users, userSessions, batches),
+	}
+
+	result, aerr, err := Default.Do(ctx, request, &Params{
+		Query: `query Me {
+			me {
+				id
+				email
+				surname
+				sessions {
+					id
+					purpose
+					user { id email preferences { locale } }
+				}
+			}
+			session { id }
+		}`,
+		OperationName: "Me",
+	})
+	if err != nil || aerr != nil {
+		t.Fatalf("want: do error nil; got: aerr = %v, err = %v", aerr, err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("want: no errors; got: %v", result.Errors)
+	}
+
+	m := resultTest(t, result)
+	me := m["data"].(map[string]interface{})["me"].(map[string]interface{})
+	if me["id"] != "1" || me["email"] != "koray1@test.com" || me["surname"] != nil {
+		t.Fatalf("want: me 1 koray1@test.com without surname; got: %v", me)
+	}
+
+	sessions := me["sessions"].([]interface{})
+	if len(sessions) != 3 {
+		t.Fatalf("want: 3 sessions; got: %v", sessions)
+	}
+	user := sessions[1].(map[string]interface{})["user"].(map[string]interface{})
+	if user["email"] != "koray2@test.com" {
+		t.Fatalf("want: user of session 2 koray2@test.com; got: %v", user)
+	}
+
+	// Test the fields of the sessions are loaded in one batch.
+	want := map[string][][]int64{
+		"user":           {{1, 2}},
+		"userSessions":   {{1}},
+		"userPreference": {{1, 2}},
+	}
+	if !reflect.DeepEqual(batches, want) {
+		t.Fatalf("want: batches %v; got: %v", want, batches)
+	}
+}
+
+func TestDoErrors(t *testing.T) {
+	ctx := context.Background()
+
+	users := map[int64]*User.User{
+		1: {ID: 1},
+	}
+	userSessions := map[int64][]*UserSession.UserSession{
+		1: {
+			{ID: 1, UserID: 1},
+			{ID: 2, UserID: 2},
+		},
+	}
+
+	// Test the api errors are the extensions of the errors.
+	request := &Request{
+		User:    users[1],
+		Loaders: loadersTest(users, userSessions, make(map[string][][]int64)),
+	}
+
+	result, aerr, err := Default.Do(ctx, request, &Params{Query: `{ me { sessions { id user { id } } } }`})
+	if err != nil || aerr != nil {
+		t.Fatalf("want: do error nil; got: aerr = %v, err = %v", aerr, err)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("want: 1 error; got: %v", result.Errors)
+	}
+
+	extensions := result.Errors[0].Extensions
+	if extensions["code"] != errapi.ErrCodeUserNotFound || extensions["status"] != 400 {
+		t.Fatalf("want: extensions code %s status 400; got: %v", errapi.ErrCodeUserNotFound, extensions)
+	}
+	if path := fmt.Sprint(result.Errors[0].Path); path != "[me sessions 1 user]" {
+		t.Fatalf("want: path [me sessions 1 user]; got: %s", path)
+	}
+
+	// Test the internal errors are returned and not exposed.
+	request.Loaders.UserPreference = NewLoader(func(ctx context.Ctx, ids []int64) (map[int64]*UserPreference.UserPreference, errapi.Error, error) {
+		return nil, nil, fmt.Errorf("connection refused")
+	})
+
+	result, _, err = Default.Do(ctx, request, &Params{Query: `{ me { id preferences { locale } } }`})
+	if err == nil {
+		t.Fatalf("want: do error; got: nil")
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Extensions["code"] != errapi.ErrCodeInternalServerError {
+		t.Fatalf("want: error %s; got: %v", errapi.ErrCodeInternalServerError, result.Errors)
+	}
+	if result.Errors[0].Message == "connection refused" {
+		t.Fatalf("want: internal error not exposed; got: %s", result.Errors[0].Message)
+	}
+}
+
+func TestDoRefused(t *testing.T) {
+	ctx := context.Background()
+
+	executor, err := New(Config{MaxDepth: 3, MaxComplexity: 20})
+	if err != nil {
+		t.Fatalf("want: new error nil; got: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		params *Params
+		code   string
+		detail bool
+	}{
+		{name: "missing", params: &Params{Query: " "}, code: errapi.ErrCodeGraphQLQueryMissing},
+		{name: "syntax", params: &Params{Query: `{ me { id }`}, code: errapi.ErrCodeGraphQLQueryInvalid, detail: true},
+		{name: "unknown field", params: &Params{Query: `{ me { unknown } }`}, code: errapi.ErrCodeGraphQLQueryInvalid, detail: true},
+		{name: "depth", params: &Params{Query: `{ me { sessions { user { id } } } }`}, code: errapi.ErrCodeGraphQLDepthExceeded},
+		{name: "depth fragment", params: &Params{Query: `{ me { ...Sessions } } fragment Sessions on User { sessions { user { id } } }`}, code: errapi.ErrCodeGraphQLDepthExceeded},
+		{name: "complexity", params: &Params{Query: `{ me { sessions { id purpose } } }`}, code: errapi.ErrCodeGraphQLComplexityExceeded},
+	}
+
+	for _, test := range tests {
+		result, aerr, err := executor.Do(ctx, &Request{}, test.params)
+		if err != nil {
+			t.Fatalf("%s: want: do error nil; got: %v", test.name, err)
+		}
+		if !errapi.Is(aerr, test.code) {
+			t.Fatalf("%s: want: aerr = %s; got: aerr = %v", test.name, test.code, aerr)
+		}
+		if result.Data != nil || len(result.Errors) == 0 {
+			t.Fatalf("%s: want: errors without data; got: %+v", test.name, result)
+		}
+
+		extensions := result.Errors[0].Extensions
+		if extensions["code"] != test.code || result.Errors[0].Message == "" {
+			t.Fatalf("%s: want: localized error %s; got: %+v", test.name, test.code, result.Errors[0])
+		}
+		if _, ok := extensions["detail"]; ok != test.detail {
+			t.Fatalf("%s: want: detail %t; got: %v", test.name, test.detail, extensions)
+		}
+	}
+
+	// Test the introspection is not limited.
+	if _, aerr, _ := executor.Do(ctx, &Request{}, &Params{Query: `{ __schema { types { name fields { name type { name ofType { name ofType { name } } } } } } }`}); aerr != nil {
+		t.Fatalf("want: introspection aerr nil; got: %v", aerr)
+	}
+}
@@ -0,0 +1,129 @@
+package graphql
+
+import (
+	"strings"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// measure returns the depth and the complexity of the operation of the
+// document. Every field costs one and the selections of the list fields
+// cost ListCost times their fields, i.e. the complexity of
+// "{ me { sessions { id } } }" is 1 + 1 + 10 * 1. The introspection
+// fields are not measured.
+func measure(schema *gql.Schema, doc *ast.Document, operation *ast.OperationDefinition) (int, int) {
+	m := &measurer{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		visiting:  make(map[string]bool),
+	}
+	for _, definition := range doc.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok && fragment.Name != nil {
+			m.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	var root gql.Type = schema.QueryType()
+	if operation.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+
+	return m.selectionSet(schema, root, operation.SelectionSet, 1)
+}
+
+type measurer struct {
+	fragments map[string]*ast.FragmentDefinition
+	// visiting are the fragments being measured, the validation refuses
+	// the cycles but the document is measured before it is validated.
+	visiting map[string]bool
+}
+
+func (m *measurer) selectionSet(schema *gql.Schema, parent gql.Type, set *ast.SelectionSet, depth int) (int, int) {
+	if set == nil {
+		return 0, 0
+	}
+
+	var maxDepth, complexity int
+	for _, selection := range set.Selections {
+		var d, c int
+
+		switch selection := selection.(type) {
+		case *ast.Field:
+			name := selection.Name.Value
+			if strings.HasPrefix(name, "__") {
+				continue
+			}
+
+			fieldType, list := field(parent, name)
+			d, c = m.selectionSet(schema, fieldType, selection.SelectionSet, depth+1)
+			if list {
+				c *= ListCost
+			}
+			d, c = max(d, depth), c+1
+
+		case *ast.InlineFragment:
+			d, c = m.selectionSet(schema, condition(schema, parent, selection.TypeCondition), selection.SelectionSet, depth)
+
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			fragment, ok := m.fragments[name]
+			if !ok || m.visiting[name] {
+				continue
+			}
+
+			m.visiting[name] = true
+			d, c = m.selectionSet(schema, condition(schema, parent, fragment.TypeCondition), fragment.SelectionSet, depth)
+			delete(m.visiting, name)
+		}
+
+		maxDepth = max(maxDepth, d)
+		complexity += c
+	}
+
+	return maxDepth, complexity
+}
+
+// field returns the named type of the field of the parent and whether
+// the field is a list, the unknown fields are refused by the validation.
+func field(parent gql.Type, name string) (gql.Type, bool) {
+	object, ok := parent.(*gql.Object)
+	if !ok || object == nil {
+		return nil, false
+	}
+
+	definition, ok := object.Fields()[name]
+	if !ok {
+		return nil, false
+	}
+
+	var (
+		t    gql.Type = definition.Type
+		list bool
+	)
+	for {
+		switch wrapped := t.(type) {
+		case *gql.NonNull:
+			t = wrapped.OfType
+		case *gql.List:
+			t, list = wrapped.OfType, true
+		default:
+			return t, list
+		}
+	}
+}
+
+// condition returns the type of the type condition of the fragment,
+// the parent if the fragment does not have one.
+func condition(schema *gql.Schema, parent gql.Type, named *ast.Named) gql.Type {
+	if named == nil || named.Name == nil {
+		return parent
+	}
+	return schema.Type(named.Name.Value)
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,48 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		query      string
+		depth      int
+		complexity int
+	}{
+		{query: `{ me { id } }`, depth: 2, complexity: 2},
+		{query: `{ me { id email } session { id } }`, depth: 2, complexity: 5},
+		{query: `{ me { sessions { id } } }`, depth: 3, complexity: 1 + 1 + ListCost},
+		{query: `{ me { sessions { user { id } } } }`, depth: 4, complexity: 1 + 1 + ListCost*2},
+		{query: `{ me { ... on User { id } } }`, depth: 2, complexity: 2},
+		{query: `{ me { ...Fields } } fragment Fields on User { id sessions { id } }`, depth: 3, complexity: 1 + 1 + 1 + ListCost},
+		{query: `{ __typename me { __typename id } }`, depth: 2, complexity: 2},
+	}
+
+	for _, test := range tests {
+		doc, err := parser.Parse(parser.ParseParams{Source: test.query})
+		if err != nil {
+			t.Fatalf("want: parse %s error nil; got: %v", test.query, err)
+		}
+
+		depth, complexity := measure(&Default.schema, doc, operationOf(doc, ""))
+		if depth != test.depth || complexity != test.complexity {
+			t.Fatalf("want: %s depth %d complexity %d; got: depth %d complexity %d", test.query, test.depth, test.complexity, depth, complexity)
+		}
+	}
+}
+
+func TestMeasureFragmentCycle(t *testing.T) {
+	doc, err := parser.Parse(parser.ParseParams{Source: `{ me { ...A } } fragment A on User { ...B } fragment B on User { id ...A }`})
+	if err != nil {
+		t.Fatalf("want: parse error nil; got: %v", err)
+	}
+
+	// The cycles are refused by the validation, they are not followed.
+	depth, complexity := measure(&Default.schema, doc, operationOf(doc, ""))
+	if depth != 2 || complexity != 2 {
+		t.Fatalf("want: depth 2 complexity 2; got: depth %d complexity %d", depth, complexity)
+	}
+}
@@ -0,0 +1,66 @@
+package graphql
+
+import (
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+)
+
+// BatchFn loads the values of the ids in one go, the ids
+// without a value are missing from the map.
+type BatchFn[V any] func(ctx context.Ctx, ids []int64) (map[int64]V, errapi.Error, error)
+
+// Loader loads the values of a field of many objects with one call of
+// the batch function. The resolvers queue the ids and return thunks,
+// the executor resolves the thunks once the fields of the level are
+// resolved and the first thunk loads the ids queued so far. The values
+// are kept for the rest of the request.
+//
+// The executor resolves the fields on a single goroutine, so the loader
+// is not safe for concurrent use, and neither is the transaction it loads
+// the values with.
+type Loader[V any] struct {
+	batchFn BatchFn[V]
+	batches map[int64]*batch[V]
+	pending *batch[V]
+}
+
+type batch[V any] struct {
+	ids    []int64
+	loaded bool
+	values map[int64]V
+	aerr   errapi.Error
+	err    error
+}
+
+func NewLoader[V any](batchFn BatchFn[V]) *Loader[V] {
+	return &Loader[V]{
+		batchFn: batchFn,
+		batches: make(map[int64]*batch[V]),
+	}
+}
+
+// Load queues the id and returns the thunk of its value.
+func (l *Loader[V]) Load(ctx context.Ctx, id int64) func() (V, errapi.Error, error) {
+	b, ok := l.batches[id]
+	if !ok {
+		if l.pending == nil {
+			l.pending = &batch[V]{}
+		}
+		b = l.pending
+		b.ids = append(b.ids, id)
+		l.batches[id] = b
+	}
+
+	return func() (V, errapi.Error, error) {
+		if !b.loaded {
+			// The ids queued after are loaded with the next batch.
+			if l.pending == b {
+				l.pending = nil
+			}
+			b.values, b.aerr, b.err = l.batchFn(ctx, b.ids)
+			b.loaded = true
+		}
+
+		return b.values[id], b.aerr, b.err
+	}
+}
@@ -0,0 +1,54 @@
+package graphql
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+)
+
+func TestLoader(t *testing.T) {
+	ctx := context.Background()
+
+	var batches [][]int64
+	loader := NewLoader(func(ctx context.Ctx, ids []int64) (map[int64]int64, errapi.Error, error) {
+		batches = append(batches, ids)
+		values := make(map[int64]int64, len(ids))
+		for _, id := range ids {
+			values[id] = id * 10
+		}
+		return values, nil, nil
+	})
+
+	// Test the ids queued before the first thunk are loaded together.
+	thunk1 := loader.Load(ctx, 1)
+	thunk2 := loader.Load(ctx, 2)
+	thunk1Again := loader.Load(ctx, 1)
+
+	if value, _, _ := thunk2(); value != 20 {
+		t.Fatalf("want: value 20; got: %d", value)
+	}
+	if value, _, _ := thunk1(); value != 10 {
+		t.Fatalf("want: value 10; got: %d", value)
+	}
+	if value, _, _ := thunk1Again(); value != 10 {
+		t.Fatalf("want: value 10; got: %d", value)
+	}
+
+	// Test the ids queued after are loaded with the next
+	// batch and the loaded ids are not loaded again.
+	thunk3 := loader.Load(ctx, 3)
+	thunk1Loaded := loader.Load(ctx, 1)
+	if value, _, _ := thunk3(); value != 30 {
+		t.Fatalf("want: value 30; got: %d", value)
+	}
+	if value, _, _ := thunk1Loaded(); value != 10 {
+		t.Fatalf("want: value 10; got: %d", value)
+	}
+
+	want := [][]int64{{1, 2}, {3}}
+	if !reflect.DeepEqual(batches, want) {
+		t.Fatalf("want: batches %v; got: %v", want, batches)
+	}
+}
@@ -0,0 +1,186 @@
+package graphql
+
+import (
+	"database/sql/driver"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
+	UserPreference "github.com/koraygocmen/golang-boilerplate/internal/model/user_preference"
+	UserSession "github.com/koraygocmen/golang-boilerplate/internal/model/user_session"
+	"github.com/koraygocmen/golang-boilerplate/internal/service"
+	UserServiceV1 "github.com/koraygocmen/golang-boilerplate/internal/service/user/v1"
+)
+
+// newSchema returns the schema of the users and the sessions, the user
+// and the session of the request are the ones of the user auth.
+func newSchema() (gql.Schema, error) {
+	notificationsType := gql.NewObject(gql.ObjectConfig{
+		Name: "Notifications",
+		Fields: gql.Fields{
+			"security":  &gql.Field{Type: gql.NewNonNull(gql.Boolean)},
+			"product":   &gql.Field{Type: gql.NewNonNull(gql.Boolean)},
+			"marketing": &gql.Field{Type: gql.NewNonNull(gql.Boolean)},
+		},
+	})
+
+	userPreferenceType := gql.NewObject(gql.ObjectConfig{
+		Name: "UserPreference",
+		Fields: gql.Fields{
+			"locale":        &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"timezone":      &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"notifications": &gql.Field{Type: gql.NewNonNull(notificationsType)},
+			"updatedAt":     &gql.Field{Type: gql.DateTime},
+		},
+	})
+
+	userSessionType := gql.NewObject(gql.ObjectConfig{
+		Name: "UserSession",
+		Fields: gql.Fields{
+			"id":        &gql.Field{Type: gql.NewNonNull(gql.ID)},
+			"createdAt": &gql.Field{Type: gql.NewNonNull(gql.DateTime)},
+			"purpose":   &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"expireAt":  &gql.Field{Type: gql.DateTime, Resolve: resolveNull},
+		},
+	})
+
+	userType := gql.NewObject(gql.ObjectConfig{
+		Name: "User",
+		Fields: gql.Fields{
+			"id":            &gql.Field{Type: gql.NewNonNull(gql.ID)},
+			"createdAt":     &gql.Field{Type: gql.NewNonNull(gql.DateTime)},
+			"email":         &gql.Field{Type: gql.String, Resolve: resolveNull},
+			"emailVerified": &gql.Field{Type: gql.Boolean, Resolve: resolveNull},
+			"passwordSet":   &gql.Field{Type: gql.NewNonNull(gql.Boolean)},
+			"givenNames":    &gql.Field{Type: gql.String, Resolve: resolveNull},
+			"surname":       &gql.Field{Type: gql.String, Resolve: resolveNull},
+			"version":       &gql.Field{Type: gql.NewNonNull(gql.Int)},
+			"sessions": &gql.Field{
+				Type:        gql.NewNonNull(gql.NewList(gql.NewNonNull(userSessionType))),
+				Description: "The active sessions of the user.",
+				Resolve:     resolveUserSessions,
+			},
+			"preferences": &gql.Field{
+				Type:    gql.NewNonNull(userPreferenceType),
+				Resolve: resolveUserPreference,
+			},
+		},
+	})
+
+	// The user of the session refers back to the user.
+	userSessionType.AddFieldConfig("user", &gql.Field{
+		Type:    gql.NewNonNull(userType),
+		Resolve: resolveUserSessionUser,
+	})
+
+	queryType := gql.NewObject(gql.ObjectConfig{
+		Name: "Query",
+		Fields: gql.Fields{
+			"me": &gql.Field{
+				Type:        gql.NewNonNull(userType),
+				Description: "The authenticated user.",
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return fromContext(p.Context).User, nil
+				},
+			},
+			"session": &gql.Field{
+				Type:        gql.NewNonNull(userSessionType),
+				Description: "The session of the authenticated user.",
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return fromContext(p.Context).UserSession, nil
+				},
+			},
+		},
+	})
+
+	return gql.NewSchema(gql.SchemaConfig{Query: queryType})
+}
+
+// resolveNull resolves the fields of the null types, the
+// fields that are not valid are null.
+func resolveNull(p gql.ResolveParams) (interface{}, error) {
+	value, err := gql.DefaultResolveFn(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if valuer, ok := value.(driver.Valuer); ok {
+		return valuer.Value()
+	}
+	return value, nil
+}
+
+func resolveUserSessions(p gql.ResolveParams) (interface{}, error) {
+	user, _ := p.Source.(*User.User)
+	if user == nil {
+		return nil, nil
+	}
+
+	load := fromContext(p.Context).Loaders.UserSessions.Load(p.Context, user.ID)
+	return func() (interface{}, error) {
+		userSessions, aerr, err := load()
+		if err != nil || aerr != nil {
+			return nil, failure(p.Context, aerr, err)
+		}
+
+		if userSessions == nil {
+			userSessions = []*UserSession.UserSession{}
+		}
+		return userSessions, nil
+	}, nil
+}
+
+func resolveUserPreference(p gql.ResolveParams) (interface{}, error) {
+	user, _ := p.Source.(*User.User)
+	if user == nil {
+		return nil, nil
+	}
+
+	load := fromContext(p.Context).Loaders.UserPreference.Load(p.Context, user.ID)
+	return func() (interface{}, error) {
+		userPreference, aerr, err := load()
+		if err != nil || aerr != nil {
+			return nil, failure(p.Context, aerr, err)
+		}
+
+		if userPreference == nil {
+			userPreference = UserPreference.Default(user.ID)
+		}
+		return userPreference, nil
+	}, nil
+}
+
+func resolveUserSessionUser(p gql.ResolveParams) (interface{}, error) {
+	userSession, _ := p.Source.(*UserSession.UserSession)
+	if userSession == nil {
+		return nil, nil
+	}
+
+	load := fromContext(p.Context).Loaders.User.Load(p.Context, userSession.UserID)
+	return func() (interface{}, error) {
+		user, aerr, err := load()
+		if err != nil || aerr != nil {
+			return nil, failure(p.Context, aerr, err)
+		}
+
+		if user == nil {
+			return nil, failure(p.Context, UserServiceV1.ErrGet.UserNotFound, nil)
+		}
+		return user, nil
+	}, nil
+}
+
+// failure returns the graphql error of the api error, the internal
+// errors are kept for the handler and the internal server error is
+// returned in their place.
+func failure(ctx context.Ctx, aerr errapi.Error, err error) error {
+	if err != nil {
+		r := fromContext(ctx)
+		if r.err == nil {
+			r.err = err
+		}
+		return newError(ctx, service.ErrInternalServer)
+	}
+	return newError(ctx, aerr)
+}
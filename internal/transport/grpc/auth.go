@@ -0,0 +1,137 @@
+package grpc
+
+import (
+	gocontext "context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
+	UserSession "github.com/koraygocmen/golang-boilerplate/internal/model/user_session"
+	"github.com/koraygocmen/golang-boilerplate/internal/service"
+	user_v1 "github.com/koraygocmen/golang-boilerplate/pkg/pb/user/v1"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	// public are the methods that are not authenticated.
+	public = map[string]bool{
+		user_v1.UserService_CreateUser_FullMethodName:               true,
+		user_v1.UserSessionService_CreateUserSession_FullMethodName: true,
+	}
+)
+
+type userKey struct{}
+type userSessionKey struct{}
+
+// authenticate authenticates the calls with the authorization metadata
+// like the user auth middleware does with the authorization header, the
+// user and the user session are attached to the context of the call.
+func authenticate(ctx gocontext.Context, req interface{}, info *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (interface{}, error) {
+	if public[info.FullMethod] {
+		return handler(ctx, req)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	authorization := first(md, MetadataAuthorization)
+	if authorization == "" {
+		return nil, failure(ctx, nil, service.ErrUserAuth.AuthorizationMissing)
+	}
+
+	authorizationPieces := strings.Split(authorization, "-")
+	if len(authorizationPieces) != 2 {
+		return nil, failure(ctx, nil, service.ErrUserAuth.AuthorizationInvalid)
+	}
+	sessionIDStr, sessionToken := authorizationPieces[0], authorizationPieces[1]
+
+	sessionID, err := strconv.ParseInt(sessionIDStr, 10, 64)
+	if err != nil {
+		return nil, failure(ctx, nil, service.ErrUserAuth.AuthorizationInvalid)
+	}
+
+	// Create the service with a new transaction.
+	srv := service.Service.Transaction(ctx, 30*time.Second)
+
+	userSession, aerr, err := srv.UserSession.V1.Get(ctx, sessionID)
+	if err != nil {
+		err = fmt.Errorf("grpc authenticate error: %w", err)
+		srv.Rollback(err)
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	if aerr != nil {
+		srv.Rollback(nil)
+		return nil, failure(ctx, nil, aerr)
+	}
+
+	if !userSession.TokenHashCompare(sessionToken) {
+		srv.Rollback(nil)
+		return nil, failure(ctx, nil, service.ErrUserAuth.AuthorizationWrong)
+	}
+
+	if userSession.IsExpired() {
+		srv.Rollback(nil)
+		return nil, failure(ctx, nil, service.ErrUserAuth.AuthorizationExpired)
+	}
+
+	user, aerr, err := srv.User.V1.Get(ctx, userSession.UserID)
+	if err != nil {
+		err = fmt.Errorf("grpc authenticate error: %w", err)
+		srv.Rollback(err)
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	if aerr != nil {
+		srv.Rollback(nil)
+
+		if !errapi.Is(aerr, errapi.ErrCodeUserNotFound) {
+			err = fmt.Errorf("grpc authenticate error: user not found for user session: %d", userSession.ID)
+			return nil, failure(ctx, err, service.ErrInternalServer)
+		}
+
+		return nil, failure(ctx, nil, aerr)
+	}
+
+	// The stored locale of the user is the fallback
+	// language if the accept language is not set.
+	ctx, err = srv.UserPreference.V1.WithLocale(ctx, user.ID)
+	if err != nil {
+		err = fmt.Errorf("grpc authenticate error: %w", err)
+		srv.Rollback(err)
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	// Commit to release the transaction.
+	if err := srv.Commit(); err != nil {
+		err = fmt.Errorf("grpc authenticate error: commit error: %w", err)
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	// Attach user and user session to context.
+	ctx = context.WithValue(ctx, context.KeyUserID, user.ID)
+	ctx = context.WithValue(ctx, context.KeyUserSessionID, userSession.ID)
+	ctx = context.WithValue(ctx, userKey{}, user)
+	ctx = context.WithValue(ctx, userSessionKey{}, userSession)
+
+	return handler(ctx, req)
+}
+
+// authenticated returns the user and the user session of the call.
+func authenticated(ctx context.Ctx) (*User.User, *UserSession.UserSession, bool) {
+	user, ok := ctx.Value(userKey{}).(*User.User)
+	if !ok {
+		return nil, nil, false
+	}
+
+	userSession, ok := ctx.Value(userSessionKey{}).(*UserSession.UserSession)
+	if !ok {
+		return nil, nil, false
+	}
+
+	return user, userSession, true
+}
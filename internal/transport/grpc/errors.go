@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// Domain is the domain of the error infos of the api errors.
+	Domain = "golang-boilerplate"
+)
+
+var (
+	// statusCodes are the grpc codes of the statuses of the api errors,
+	// the statuses that are not found are the unknown code.
+	statusCodes = map[int]codes.Code{
+		fiber.StatusBadRequest:            codes.InvalidArgument,
+		fiber.StatusUnauthorized:          codes.Unauthenticated,
+		fiber.StatusForbidden:             codes.PermissionDenied,
+		fiber.StatusNotFound:              codes.NotFound,
+		fiber.StatusMethodNotAllowed:      codes.Unimplemented,
+		fiber.StatusRequestTimeout:        codes.DeadlineExceeded,
+		fiber.StatusConflict:              codes.AlreadyExists,
+		fiber.StatusGone:                  codes.NotFound,
+		fiber.StatusPreconditionFailed:    codes.FailedPrecondition,
+		fiber.StatusRequestEntityTooLarge: codes.ResourceExhausted,
+		fiber.StatusUnsupportedMediaType:  codes.InvalidArgument,
+		fiber.StatusUpgradeRequired:       codes.FailedPrecondition,
+		fiber.StatusTooManyRequests:       codes.ResourceExhausted,
+		fiber.StatusInternalServerError:   codes.Internal,
+		fiber.StatusServiceUnavailable:    codes.Unavailable,
+	}
+)
+
+// Code returns the grpc code of the status of the api error.
+func Code(status int) codes.Code {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	return codes.Unknown
+}
+
+// Status returns the grpc status of the api error. The message is the
+// localized message of the error, the code and the status are the error
+// info of the details and the errors of the fields are the violations
+// of the bad request of the details.
+func Status(ctx context.Ctx, aerr errapi.Error) *status.Status {
+	aerr = aerr.Localize(ctx)
+
+	s := status.New(Code(aerr.Status()), aerr.Message())
+
+	info := &errdetails.ErrorInfo{
+		Reason: aerr.Code(),
+		Domain: Domain,
+		Metadata: map[string]string{
+			"status": strconv.Itoa(aerr.Status()),
+		},
+	}
+
+	fields := aerr.Fields()
+	if len(fields) == 0 {
+		if detailed, err := s.WithDetails(info); err == nil {
+			s = detailed
+		}
+		return s
+	}
+
+	badRequest := &errdetails.BadRequest{
+		FieldViolations: make([]*errdetails.BadRequest_FieldViolation, 0, len(fields)),
+	}
+	for _, field := range fields {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field.Field,
+			Description: field.Error.Localize(ctx).Message(),
+		})
+	}
+
+	if detailed, err := s.WithDetails(info, badRequest); err == nil {
+		s = detailed
+	}
+	return s
+}
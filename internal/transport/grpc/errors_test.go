@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/golang-boilerplate/internal/service"
+	"github.com/koraygocmen/golang-boilerplate/internal/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		status int
+		code   codes.Code
+	}{
+		{status: fiber.StatusBadRequest, code: codes.InvalidArgument},
+		{status: fiber.StatusUnauthorized, code: codes.Unauthenticated},
+		{status: fiber.StatusNotFound, code: codes.NotFound},
+		{status: fiber.StatusConflict, code: codes.AlreadyExists},
+		{status: fiber.StatusPreconditionFailed, code: codes.FailedPrecondition},
+		{status: fiber.StatusTooManyRequests, code: codes.ResourceExhausted},
+		{status: fiber.StatusInternalServerError, code: codes.Internal},
+		{status: fiber.StatusTeapot, code: codes.Unknown},
+	}
+
+	for _, test := range tests {
+		if code := Code(test.status); code != test.code {
+			t.Fatalf("want: status %d code %s; got: %s", test.status, test.code, code)
+		}
+	}
+}
+
+func TestStatus(t *testing.T) {
+	ctx := context.WithValue(context.Background(), context.KeyLang, context.LangEN)
+
+	// Test the error info of the api error is the detail.
+	s := Status(ctx, service.ErrUserAuth.AuthorizationMissing)
+	if s.Code() != codes.Unauthenticated || s.Message() != "Authorization is missing." {
+		t.Fatalf("want: unauthenticated localized; got: %s %s", s.Code(), s.Message())
+	}
+
+	details := s.Details()
+	if len(details) != 1 {
+		t.Fatalf("want: 1 detail; got: %v", details)
+	}
+	info, ok := details[0].(*errdetails.ErrorInfo)
+	if !ok || info.Reason != errapi.ErrCodeAuthorizationMissing || info.Domain != Domain || info.Metadata["status"] != "401" {
+		t.Fatalf("want: error info %s; got: %v", errapi.ErrCodeAuthorizationMissing, details[0])
+	}
+
+	// Test the errors of the fields are the violations of the bad request.
+	v := validate.New()
+	v.Check("email", false, validate.ErrBody.FieldTypeInvalid)
+	v.Check("surname", false, validate.ErrBody.FieldTypeInvalid)
+
+	s = Status(ctx, v.Error())
+	if s.Code() != codes.InvalidArgument {
+		t.Fatalf("want: code %s; got: %s", codes.InvalidArgument, s.Code())
+	}
+
+	details = s.Details()
+	if len(details) != 2 {
+		t.Fatalf("want: 2 details; got: %v", details)
+	}
+	badRequest, ok := details[1].(*errdetails.BadRequest)
+	if !ok || len(badRequest.FieldViolations) != 2 {
+		t.Fatalf("want: bad request with 2 violations; got: %v", details[1])
+	}
+	violation := badRequest.FieldViolations[1]
+	if violation.Field != "surname" || violation.Description == "" {
+		t.Fatalf("want: localized violation of surname; got: %v", violation)
+	}
+}
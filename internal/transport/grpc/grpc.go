@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	gocontext "context"
+	"fmt"
+	"net"
+
+	"github.com/google/uuid"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/env"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/golang-boilerplate/internal/errhandle"
+	"github.com/koraygocmen/golang-boilerplate/internal/i18n"
+	"github.com/koraygocmen/golang-boilerplate/internal/ratelimit"
+	"github.com/koraygocmen/golang-boilerplate/internal/service"
+	user_v1 "github.com/koraygocmen/golang-boilerplate/pkg/pb/user/v1"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	MetadataAuthorization  = "authorization"
+	MetadataAcceptLanguage = "accept-language"
+	MetadataRequestID      = "x-request-id"
+	MetadataForwardedFor   = "x-forwarded-for"
+
+	// Method is the method of the context of the calls,
+	// the path of the context is the full method.
+	Method = "GRPC"
+)
+
+// New returns the grpc server of the user and the user session
+// services. The calls are served with the context of the request
+// like the http requests, limited with the same policies and
+// authenticated with the user session.
+func New() *gogrpc.Server {
+	server := gogrpc.NewServer(gogrpc.ChainUnaryInterceptor(
+		contextual,
+		recoverer,
+		rateLimit(ratelimit.SubjectRoute, ratelimit.SubjectIP, ratelimit.SubjectAPIKey),
+		authenticate,
+		rateLimit(ratelimit.SubjectUser),
+	))
+
+	user_v1.RegisterUserServiceServer(server, &userServer{})
+	user_v1.RegisterUserSessionServiceServer(server, &userSessionServer{})
+
+	return server
+}
+
+// contextual sets the request id, the remote ip, the full method and the
+// language of the accept language metadata to the context of the call.
+func contextual(ctx gocontext.Context, req interface{}, info *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestID := first(md, MetadataRequestID)
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+	_ = gogrpc.SetHeader(ctx, metadata.Pairs(MetadataRequestID, requestID))
+
+	var remoteIP net.IP
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		remoteIP = net.ParseIP(host)
+	}
+	if !env.IsDev() && (remoteIP == nil || remoteIP.IsPrivate() || remoteIP.IsLoopback() || remoteIP.IsUnspecified()) {
+		remoteIP = net.ParseIP(first(md, MetadataForwardedFor))
+	}
+
+	ctx = context.WithValue(ctx, context.KeyRemoteIP, remoteIP.String())
+	ctx = context.WithValue(ctx, context.KeyRequestID, requestID)
+	ctx = context.WithValue(ctx, context.KeyMethod, Method)
+	ctx = context.WithValue(ctx, context.KeyPath, info.FullMethod)
+
+	// Language is negotiated like the accept language header, the
+	// language of the user is the fallback once it is authenticated.
+	if lang, ok := i18n.Messages.Match(first(md, MetadataAcceptLanguage)); ok {
+		ctx = context.WithValue(ctx, context.KeyLang, context.ToLanguage(lang))
+	}
+
+	return handler(ctx, req)
+}
+
+// recoverer recovers the panics of the calls as internal errors.
+func recoverer(ctx gocontext.Context, req interface{}, info *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (res interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = failure(ctx, fmt.Errorf("grpc recover error: %s: %v", info.FullMethod, r), service.ErrInternalServer)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// failure handles the errors of the call like the http handlers
+// do and returns the grpc status of the api error.
+func failure(ctx context.Ctx, err error, aerr errapi.Error) error {
+	errhandle.Handle(ctx, aerr, err, false)
+	return Status(ctx, aerr).Err()
+}
+
+// first returns the first value of the key of the metadata.
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
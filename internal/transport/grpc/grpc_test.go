@@ -0,0 +1,125 @@
+package grpc
+
+import (
+	gocontext "context"
+	"net"
+	"testing"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/env"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/golang-boilerplate/internal/ratelimit"
+	user_v1 "github.com/koraygocmen/golang-boilerplate/pkg/pb/user/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// clientTest returns the client of the server served in memory.
+func clientTest(t *testing.T) user_v1.UserServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := New()
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := gogrpc.Dial("bufnet",
+		gogrpc.WithContextDialer(func(ctx gocontext.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		gogrpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("want: dial error nil; got: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return user_v1.NewUserServiceClient(conn)
+}
+
+func TestAuthenticate(t *testing.T) {
+	client := clientTest(t)
+
+	tests := []struct {
+		name          string
+		authorization string
+		code          string
+	}{
+		{name: "missing", authorization: "", code: errapi.ErrCodeAuthorizationMissing},
+		{name: "invalid", authorization: "token", code: errapi.ErrCodeAuthorizationInvalid},
+		{name: "invalid id", authorization: "id-token", code: errapi.ErrCodeAuthorizationInvalid},
+	}
+
+	for _, test := range tests {
+		ctx := metadata.AppendToOutgoingContext(gocontext.Background(), MetadataAcceptLanguage, "en")
+		if test.authorization != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, MetadataAuthorization, test.authorization)
+		}
+
+		var header metadata.MD
+		_, err := client.GetUser(ctx, &user_v1.GetUserRequest{}, gogrpc.Header(&header))
+
+		s := status.Convert(err)
+		if s.Code() != codes.Unauthenticated {
+			t.Fatalf("%s: want: code %s; got: %s", test.name, codes.Unauthenticated, s.Code())
+		}
+		if len(s.Details()) == 0 {
+			t.Fatalf("%s: want: error info; got: no details", test.name)
+		}
+		if info, ok := s.Details()[0].(*errdetails.ErrorInfo); !ok || info.Reason != test.code {
+			t.Fatalf("%s: want: reason %s; got: %v", test.name, test.code, s.Details()[0])
+		}
+		if len(header.Get(MetadataRequestID)) != 1 {
+			t.Fatalf("%s: want: request id header; got: %v", test.name, header)
+		}
+	}
+
+	// Test the message is localized with the accept language.
+	ctx := metadata.AppendToOutgoingContext(gocontext.Background(), MetadataAcceptLanguage, "en")
+	_, err := client.GetUser(ctx, &user_v1.GetUserRequest{})
+	if message := status.Convert(err).Message(); message != "Authorization is missing." {
+		t.Fatalf("want: message Authorization is missing.; got: %s", message)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	// The calls are not limited in dev.
+	envPrev, limiterPrev := env.ENV, ratelimit.Limiter
+	t.Cleanup(func() {
+		env.ENV, ratelimit.Limiter = envPrev, limiterPrev
+	})
+	env.ENV = env.EnvTest
+
+	policies, err := ratelimit.ParsePolicies("/v1/users:GET:ip:1:60")
+	if err != nil {
+		t.Fatalf("want: parse policies error nil; got: %v", err)
+	}
+	ratelimit.Limiter = ratelimit.NewWithStorage(ratelimit.NewMemoryStorage(), policies)
+
+	client := clientTest(t)
+
+	// The first call is allowed and fails to authenticate.
+	_, err = client.GetUser(gocontext.Background(), &user_v1.GetUserRequest{})
+	if code := status.Code(err); code != codes.Unauthenticated {
+		t.Fatalf("want: code %s; got: %s", codes.Unauthenticated, code)
+	}
+
+	// Test the calls share the limits of the http route.
+	var header metadata.MD
+	_, err = client.GetUser(gocontext.Background(), &user_v1.GetUserRequest{}, gogrpc.Header(&header))
+
+	s := status.Convert(err)
+	if s.Code() != codes.ResourceExhausted {
+		t.Fatalf("want: code %s; got: %s", codes.ResourceExhausted, s.Code())
+	}
+	if info, ok := s.Details()[0].(*errdetails.ErrorInfo); !ok || info.Reason != errapi.ErrCodeTooManyRequests {
+		t.Fatalf("want: reason %s; got: %v", errapi.ErrCodeTooManyRequests, s.Details()[0])
+	}
+	if len(header.Get("retry-after")) != 1 {
+		t.Fatalf("want: retry after header; got: %v", header)
+	}
+}
@@ -0,0 +1,99 @@
+package grpc
+
+import (
+	gocontext "context"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/env"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/golang-boilerplate/internal/i18n"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	"github.com/koraygocmen/golang-boilerplate/internal/ratelimit"
+	"github.com/koraygocmen/golang-boilerplate/internal/service"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/header"
+	user_v1 "github.com/koraygocmen/golang-boilerplate/pkg/pb/user/v1"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	MetadataAPIKey = "x-api-key"
+)
+
+// route is the http route the policies of a method are matched with.
+type route struct {
+	method string
+	path   string
+}
+
+var (
+	// routes are the http routes of the methods, so the calls share the
+	// limits of the routes. The other methods are matched as the posts
+	// of their full method like the grpc requests are sent.
+	routes = map[string]route{
+		user_v1.UserService_CreateUser_FullMethodName:                {fiber.MethodPost, "/v1/users"},
+		user_v1.UserService_GetUser_FullMethodName:                   {fiber.MethodGet, "/v1/users"},
+		user_v1.UserService_UpdateUser_FullMethodName:                {fiber.MethodPut, "/v1/users"},
+		user_v1.UserService_DeleteUser_FullMethodName:                {fiber.MethodDelete, "/v1/users"},
+		user_v1.UserSessionService_CreateUserSession_FullMethodName:  {fiber.MethodPost, "/v1/users/sessions"},
+		user_v1.UserSessionService_DeleteUserSessions_FullMethodName: {fiber.MethodDelete, "/v1/users/sessions"},
+	}
+)
+
+// rateLimit limits the calls with the policies of the subjects like the
+// rate limit middleware does the requests. The user policies need the
+// user in the context, so the user subject is limited after authenticate.
+func rateLimit(subjects ...ratelimit.Subject) gogrpc.UnaryServerInterceptor {
+	return func(ctx gocontext.Context, req interface{}, info *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (interface{}, error) {
+		limiter := ratelimit.Limiter
+		if limiter == nil || env.IsDev() {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		values := make(map[ratelimit.Subject]string, len(subjects))
+		for _, subject := range subjects {
+			switch subject {
+			case ratelimit.SubjectRoute:
+				values[subject] = ""
+			case ratelimit.SubjectIP:
+				values[subject] = context.RemoteIP(ctx)
+			case ratelimit.SubjectUser:
+				if userID, ok := ctx.Value(context.KeyUserID).(int64); ok {
+					values[subject] = strconv.FormatInt(userID, 10)
+				}
+			case ratelimit.SubjectAPIKey:
+				values[subject] = first(md, MetadataAPIKey)
+			}
+		}
+
+		r, ok := routes[info.FullMethod]
+		if !ok {
+			r = route{fiber.MethodPost, info.FullMethod}
+		}
+
+		result, err := limiter.Allow(ctx, r.method, r.path, values)
+		if err != nil {
+			// Fail open, an unavailable storage
+			// should not take the api down.
+			logger.Logger.Errorf(ctx, `msg="rate limit failed", err="%v"`, err)
+			return handler(ctx, req)
+		}
+
+		if result == nil || result.Allowed {
+			return handler(ctx, req)
+		}
+
+		seconds := ratelimit.Seconds(result.RetryAfter())
+		_ = gogrpc.SetHeader(ctx, metadata.Pairs(
+			header.HeaderRateLimitPolicy, ratelimit.PolicyHeader(result.Policy),
+			fiber.HeaderRetryAfter, strconv.Itoa(seconds),
+		))
+
+		aerr := errapi.WithParams(service.ErrTooManyRequestsRetryAfter, i18n.Params{i18n.ParamCount: seconds})
+		return nil, failure(ctx, nil, aerr)
+	}
+}
@@ -0,0 +1,139 @@
+package grpc
+
+import (
+	gocontext "context"
+	"fmt"
+	"time"
+
+	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
+	"github.com/koraygocmen/golang-boilerplate/internal/service"
+	UserServiceV1 "github.com/koraygocmen/golang-boilerplate/internal/service/user/v1"
+	user_v1 "github.com/koraygocmen/golang-boilerplate/pkg/pb/user/v1"
+	"github.com/koraygocmen/null"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// userServer serves the user service v1 over grpc.
+type userServer struct {
+	user_v1.UnimplementedUserServiceServer
+}
+
+func (s *userServer) CreateUser(ctx gocontext.Context, req *user_v1.CreateUserRequest) (*user_v1.CreateUserResponse, error) {
+	// Create the service with a new transaction.
+	srv := service.Service.Transaction(ctx, 30*time.Second)
+
+	user, aerr, err := srv.User.V1.Create(ctx, &UserServiceV1.CreateParams{
+		Email:      null.StringFromPtr(req.Email),
+		Password:   null.StringFromPtr(req.Password),
+		GivenNames: null.StringFromPtr(req.GivenNames),
+		Surname:    null.StringFromPtr(req.Surname),
+	})
+	if err != nil {
+		err = fmt.Errorf("grpc user create error: %w", err)
+		srv.Rollback(err)
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	if aerr != nil {
+		srv.Rollback(nil)
+		return nil, failure(ctx, nil, aerr)
+	}
+
+	if err := srv.Commit(); err != nil {
+		err = fmt.Errorf("grpc user create error: commit error: %w", err)
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	return &user_v1.CreateUserResponse{User: userOf(user)}, nil
+}
+
+func (s *userServer) GetUser(ctx gocontext.Context, req *user_v1.GetUserRequest) (*user_v1.GetUserResponse, error) {
+	user, _, ok := authenticated(ctx)
+	if !ok {
+		err := fmt.Errorf("grpc user get error: user not found in ctx")
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	return &user_v1.GetUserResponse{User: userOf(user)}, nil
+}
+
+func (s *userServer) UpdateUser(ctx gocontext.Context, req *user_v1.UpdateUserRequest) (*user_v1.UpdateUserResponse, error) {
+	user, userSession, ok := authenticated(ctx)
+	if !ok {
+		err := fmt.Errorf("grpc user update error: user not found in ctx")
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	// Create the service with a new transaction.
+	srv := service.Service.Transaction(ctx, 30*time.Second)
+
+	user, aerr, err := srv.User.V1.Update(ctx, user, userSession, &UserServiceV1.UpdateParams{
+		Email:      null.StringFromPtr(req.Email),
+		Password:   null.StringFromPtr(req.Password),
+		GivenNames: null.StringFromPtr(req.GivenNames),
+		Surname:    null.StringFromPtr(req.Surname),
+		IfMatch:    req.IfMatch,
+	})
+	if err != nil {
+		err = fmt.Errorf("grpc user update error: %w", err)
+		srv.Rollback(err)
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	if aerr != nil {
+		srv.Rollback(nil)
+		return nil, failure(ctx, nil, aerr)
+	}
+
+	if err := srv.Commit(); err != nil {
+		err = fmt.Errorf("grpc user update error: commit error: %w", err)
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	return &user_v1.UpdateUserResponse{User: userOf(user)}, nil
+}
+
+func (s *userServer) DeleteUser(ctx gocontext.Context, req *user_v1.DeleteUserRequest) (*user_v1.DeleteUserResponse, error) {
+	user, _, ok := authenticated(ctx)
+	if !ok {
+		err := fmt.Errorf("grpc user delete error: user not found in ctx")
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	// Create the service with a new transaction.
+	srv := service.Service.Transaction(ctx, 30*time.Second)
+
+	user, aerr, err := srv.User.V1.Delete(ctx, user)
+	if err != nil {
+		err = fmt.Errorf("grpc user delete error: %w", err)
+		srv.Rollback(err)
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	if aerr != nil {
+		srv.Rollback(nil)
+		return nil, failure(ctx, nil, aerr)
+	}
+
+	if err := srv.Commit(); err != nil {
+		err = fmt.Errorf("grpc user delete error: commit error: %w", err)
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	return &user_v1.DeleteUserResponse{User: userOf(user)}, nil
+}
+
+// userOf returns the message of the user.
+func userOf(user *User.User) *user_v1.User {
+	return &user_v1.User{
+		Id:            user.ID,
+		CreatedAt:     timestamppb.New(user.CreatedAt),
+		Email:         user.Email.Ptr(),
+		EmailVerified: user.EmailVerified.Ptr(),
+		PasswordSet:   user.PasswordSet,
+		GivenNames:    user.GivenNames.Ptr(),
+		Surname:       user.Surname.Ptr(),
+		Version:       user.Version,
+		Etag:          user.ETag(),
+	}
+}
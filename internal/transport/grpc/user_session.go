@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	gocontext "context"
+	"fmt"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	UserSession "github.com/koraygocmen/golang-boilerplate/internal/model/user_session"
+	"github.com/koraygocmen/golang-boilerplate/internal/service"
+	UserSessionServiceV1 "github.com/koraygocmen/golang-boilerplate/internal/service/user_session/v1"
+	user_v1 "github.com/koraygocmen/golang-boilerplate/pkg/pb/user/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// userSessionServer serves the user session service v1 over grpc.
+type userSessionServer struct {
+	user_v1.UnimplementedUserSessionServiceServer
+}
+
+func (s *userSessionServer) CreateUserSession(ctx gocontext.Context, req *user_v1.CreateUserSessionRequest) (*user_v1.CreateUserSessionResponse, error) {
+	// Create the service with a new transaction.
+	srv := service.Service.Transaction(ctx, 30*time.Second)
+
+	userSession, aerr, err := srv.UserSession.V1.Create(ctx, &UserSessionServiceV1.CreateParams{
+		Email:          req.Email,
+		Password:       req.Password,
+		Purpose:        req.Purpose,
+		DeliveryMethod: req.DeliveryMethod,
+		ClientIP:       context.RemoteIP(ctx),
+	})
+	if err != nil {
+		err = fmt.Errorf("grpc user session create error: %w", err)
+		srv.Rollback(err)
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	if aerr != nil {
+		srv.Rollback(nil)
+		return nil, failure(ctx, nil, aerr)
+	}
+
+	if err := srv.Commit(); err != nil {
+		err = fmt.Errorf("grpc user session create error: commit error: %w", err)
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	return &user_v1.CreateUserSessionResponse{UserSession: userSessionOf(userSession)}, nil
+}
+
+func (s *userSessionServer) GetUserSession(ctx gocontext.Context, req *user_v1.GetUserSessionRequest) (*user_v1.GetUserSessionResponse, error) {
+	_, userSession, ok := authenticated(ctx)
+	if !ok {
+		err := fmt.Errorf("grpc user session get error: user session not found in ctx")
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	return &user_v1.GetUserSessionResponse{UserSession: userSessionOf(userSession)}, nil
+}
+
+func (s *userSessionServer) DeleteUserSessions(ctx gocontext.Context, req *user_v1.DeleteUserSessionsRequest) (*user_v1.DeleteUserSessionsResponse, error) {
+	_, userSession, ok := authenticated(ctx)
+	if !ok {
+		err := fmt.Errorf("grpc user sessions delete error: user session not found in ctx")
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	// Create the service with a new transaction.
+	srv := service.Service.Transaction(ctx, 30*time.Second)
+
+	aerr, err := srv.UserSession.V1.Delete(ctx, userSession.UserID, nil)
+	if err != nil {
+		err = fmt.Errorf("grpc user sessions delete error: %w", err)
+		srv.Rollback(err)
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	if aerr != nil {
+		srv.Rollback(nil)
+		return nil, failure(ctx, nil, aerr)
+	}
+
+	if err := srv.Commit(); err != nil {
+		err = fmt.Errorf("grpc user sessions delete error: commit error: %w", err)
+		return nil, failure(ctx, err, service.ErrInternalServer)
+	}
+
+	return &user_v1.DeleteUserSessionsResponse{UserSession: userSessionOf(userSession)}, nil
+}
+
+// userSessionOf returns the message of the user session, the
+// token is only set on the sessions that are just created.
+func userSessionOf(userSession *UserSession.UserSession) *user_v1.UserSession {
+	return &user_v1.UserSession{
+		Id:        userSession.ID,
+		CreatedAt: timestamppb.New(userSession.CreatedAt),
+		UserId:    userSession.UserID,
+		Token:     userSession.Token,
+		Purpose:   string(userSession.Purpose),
+	}
+}
@@ -0,0 +1,145 @@
+package admin_v1
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	v1 "github.com/koraygocmen/golang-boilerplate/internal/transport/response/v1"
+	"github.com/koraygocmen/golang-boilerplate/internal/validate"
+	"github.com/koraygocmen/null"
+)
+
+type LogLevelParams struct {
+	Level string `json:"level"`
+}
+
+type LogRouteLevelsParams struct {
+	RouteLevels []logger.RouteLevel `json:"routeLevels"`
+}
+
+type LogSampleRulesParams struct {
+	SampleRules []logger.SampleRule `json:"sampleRules"`
+}
+
+// Handler.
+type Handler struct {
+	*v1.Response
+}
+
+func New(v1Response *v1.Response) *Handler {
+	return &Handler{v1Response}
+}
+
+// GET /v1/admin/log
+func (v1 *Handler) LogGet(c *fiber.Ctx) error {
+	ctx := context.FromFiberCtx(c)
+
+	return c.Status(fiber.StatusOK).
+		JSON(v1.Handler.Success(ctx, fiber.Map{
+			"log": logSettings(),
+		}))
+}
+
+// PUT /v1/admin/log/level
+func (v1 *Handler) LogLevelUpdate(c *fiber.Ctx) error {
+	ctx := context.FromFiberCtx(c)
+
+	var params LogLevelParams
+	if err := c.BodyParser(&params); err != nil {
+		aerr := validate.Body(err)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, nil, aerr))
+	}
+
+	level, err := logger.ToLevel(params.Level)
+	if err != nil {
+		aerr := ErrLog.LevelInvalid
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, nil, aerr))
+	}
+
+	if err := logger.Logger.SetLevel(level); err != nil {
+		aerr := ErrLog.LevelInvalid
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, nil, aerr))
+	}
+
+	logger.Logger.Emerf(ctx, `msg="log level set", level="%d"`, level)
+
+	return c.Status(fiber.StatusOK).
+		JSON(v1.Handler.Success(ctx, fiber.Map{
+			"log": logSettings(),
+		}))
+}
+
+// DELETE /v1/admin/log/level
+func (v1 *Handler) LogLevelReset(c *fiber.Ctx) error {
+	ctx := context.FromFiberCtx(c)
+
+	logger.Logger.ResetLevel()
+	logger.Logger.Emerf(ctx, `msg="log level reset"`)
+
+	return c.Status(fiber.StatusOK).
+		JSON(v1.Handler.Success(ctx, fiber.Map{
+			"log": logSettings(),
+		}))
+}
+
+// PUT /v1/admin/log/routes
+func (v1 *Handler) LogRouteLevelsUpdate(c *fiber.Ctx) error {
+	ctx := context.FromFiberCtx(c)
+
+	var params LogRouteLevelsParams
+	if err := c.BodyParser(&params); err != nil {
+		aerr := validate.Body(err)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, nil, aerr))
+	}
+
+	if err := logger.Logger.SetRouteLevels(params.RouteLevels); err != nil {
+		aerr := ErrLog.RouteLevelsInvalid
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, nil, aerr))
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(v1.Handler.Success(ctx, fiber.Map{
+			"log": logSettings(),
+		}))
+}
+
+// PUT /v1/admin/log/sampling
+func (v1 *Handler) LogSampleRulesUpdate(c *fiber.Ctx) error {
+	ctx := context.FromFiberCtx(c)
+
+	var params LogSampleRulesParams
+	if err := c.BodyParser(&params); err != nil {
+		aerr := validate.Body(err)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, nil, aerr))
+	}
+
+	if err := logger.Logger.SetSampleRules(params.SampleRules); err != nil {
+		aerr := ErrLog.SampleRulesInvalid
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, nil, aerr))
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(v1.Handler.Success(ctx, fiber.Map{
+			"log": logSettings(),
+		}))
+}
+
+func logSettings() fiber.Map {
+	var level null.Int
+	if l, ok := logger.Logger.Level(); ok {
+		level = null.IntFrom(int64(l))
+	}
+
+	return fiber.Map{
+		"level":       level,
+		"routeLevels": logger.Logger.RouteLevels(),
+		"sampleRules": logger.Logger.SampleRules(),
+	}
+}
@@ -0,0 +1,18 @@
+package admin_v1
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+)
+
+var (
+	ErrLog = struct {
+		LevelInvalid       errapi.Error
+		RouteLevelsInvalid errapi.Error
+		SampleRulesInvalid errapi.Error
+	}{
+		LevelInvalid:       errapi.New(fiber.StatusBadRequest, errapi.ErrCodeLogLevelInvalid),
+		RouteLevelsInvalid: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeLogRouteLevelsInvalid),
+		SampleRulesInvalid: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeLogSampleRulesInvalid),
+	}
+)
@@ -0,0 +1,144 @@
+package admin_v1
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/config"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/handler"
+	v1Middleware "github.com/koraygocmen/golang-boilerplate/internal/transport/middleware/v1"
+	v1 "github.com/koraygocmen/golang-boilerplate/internal/transport/response/v1"
+	"github.com/koraygocmen/golang-boilerplate/pkg/fibertest"
+)
+
+var (
+	appTest *fiber.App
+)
+
+func TestMain(m *testing.M) {
+	// Setup logger.
+	logger.Logger, _ = logger.New(logger.Config{
+		Mode: string(logger.ModeNone),
+	})
+
+	config.Admin.Token = "token"
+
+	v1Response := v1.New(handler.New(handler.Config{}))
+	v1AdminHandler := New(v1Response)
+
+	appTest = fiber.New(fiber.Config{})
+
+	v1AdminApp := appTest.Group("/v1/admin", v1Middleware.New(v1Response).AdminAuth)
+	v1AdminApp.Get("/log", v1AdminHandler.LogGet)
+	v1AdminApp.Put("/log/level", v1AdminHandler.LogLevelUpdate)
+	v1AdminApp.Delete("/log/level", v1AdminHandler.LogLevelReset)
+	v1AdminApp.Put("/log/routes", v1AdminHandler.LogRouteLevelsUpdate)
+	v1AdminApp.Put("/log/sampling", v1AdminHandler.LogSampleRulesUpdate)
+
+	m.Run()
+}
+
+func errorCode(resbody fiber.Map) string {
+	if aerr, ok := resbody["error"].(map[string]interface{}); ok {
+		code, _ := aerr["code"].(string)
+		return code
+	}
+	return ""
+}
+
+func TestAdminAuth(t *testing.T) {
+	status, _, resbody, err := fibertest.Request(appTest, fiber.MethodGet, "/v1/admin/log", nil, nil)
+	if err != nil {
+		t.Fatalf("want: request err nil; got: %v", err)
+	}
+	if status != fiber.StatusUnauthorized || errorCode(resbody) != errapi.ErrCodeAuthorizationMissing {
+		t.Fatalf("want: %d %s; got: %d %v", fiber.StatusUnauthorized, errapi.ErrCodeAuthorizationMissing, status, resbody)
+	}
+
+	headers := map[string]string{fiber.HeaderAuthorization: "wrong"}
+	status, _, resbody, err = fibertest.Request(appTest, fiber.MethodGet, "/v1/admin/log", headers, nil)
+	if err != nil {
+		t.Fatalf("want: request err nil; got: %v", err)
+	}
+	if status != fiber.StatusUnauthorized || errorCode(resbody) != errapi.ErrCodeAuthorizationWrong {
+		t.Fatalf("want: %d %s; got: %d %v", fiber.StatusUnauthorized, errapi.ErrCodeAuthorizationWrong, status, resbody)
+	}
+}
+
+func TestLogLevel(t *testing.T) {
+	headers := map[string]string{fiber.HeaderAuthorization: "token"}
+
+	status, _, resbody, err := fibertest.Request(appTest, fiber.MethodPut, "/v1/admin/log/level", headers, LogLevelParams{Level: "loud"})
+	if err != nil {
+		t.Fatalf("want: request err nil; got: %v", err)
+	}
+	if status != fiber.StatusBadRequest || errorCode(resbody) != errapi.ErrCodeLogLevelInvalid {
+		t.Fatalf("want: %d %s; got: %d %v", fiber.StatusBadRequest, errapi.ErrCodeLogLevelInvalid, status, resbody)
+	}
+
+	status, _, _, err = fibertest.Request(appTest, fiber.MethodPut, "/v1/admin/log/level", headers, LogLevelParams{Level: "debug"})
+	if err != nil {
+		t.Fatalf("want: request err nil; got: %v", err)
+	}
+	if status != fiber.StatusOK {
+		t.Fatalf("want: status = %d; got: %d", fiber.StatusOK, status)
+	}
+	if level, ok := logger.Logger.Level(); !ok || level != logger.LevelDebug {
+		t.Fatalf("want: level = %d; got: %d, %t", logger.LevelDebug, level, ok)
+	}
+
+	status, _, _, err = fibertest.Request(appTest, fiber.MethodDelete, "/v1/admin/log/level", headers, nil)
+	if err != nil {
+		t.Fatalf("want: request err nil; got: %v", err)
+	}
+	if status != fiber.StatusOK {
+		t.Fatalf("want: status = %d; got: %d", fiber.StatusOK, status)
+	}
+	if _, ok := logger.Logger.Level(); ok {
+		t.Fatalf("want: level not set; got: set")
+	}
+}
+
+func TestLogRouteLevelsAndSampling(t *testing.T) {
+	headers := map[string]string{fiber.HeaderAuthorization: "token"}
+
+	routeLevels := LogRouteLevelsParams{
+		RouteLevels: []logger.RouteLevel{{Route: "/v1/users*", Level: logger.LevelDebug}},
+	}
+	status, _, _, err := fibertest.Request(appTest, fiber.MethodPut, "/v1/admin/log/routes", headers, routeLevels)
+	if err != nil {
+		t.Fatalf("want: request err nil; got: %v", err)
+	}
+	if status != fiber.StatusOK {
+		t.Fatalf("want: status = %d; got: %d", fiber.StatusOK, status)
+	}
+	if _, ok := logger.Logger.RouteLevel("/v1/users"); !ok {
+		t.Fatalf("want: route level set; got: not set")
+	}
+
+	sampleRules := LogSampleRulesParams{
+		SampleRules: []logger.SampleRule{{Route: "*", Status: "9xx", Rate: 1}},
+	}
+	status, _, resbody, err := fibertest.Request(appTest, fiber.MethodPut, "/v1/admin/log/sampling", headers, sampleRules)
+	if err != nil {
+		t.Fatalf("want: request err nil; got: %v", err)
+	}
+	if status != fiber.StatusBadRequest || errorCode(resbody) != errapi.ErrCodeLogSampleRulesInvalid {
+		t.Fatalf("want: %d %s; got: %d %v", fiber.StatusBadRequest, errapi.ErrCodeLogSampleRulesInvalid, status, resbody)
+	}
+
+	sampleRules.SampleRules[0].Status = "2xx"
+	sampleRules.SampleRules[0].Rate = 0
+	status, _, _, err = fibertest.Request(appTest, fiber.MethodPut, "/v1/admin/log/sampling", headers, sampleRules)
+	if err != nil {
+		t.Fatalf("want: request err nil; got: %v", err)
+	}
+	if status != fiber.StatusOK {
+		t.Fatalf("want: status = %d; got: %d", fiber.StatusOK, status)
+	}
+	if logger.Logger.Sample("/v1/users", fiber.StatusOK) {
+		t.Fatalf("want: 2xx not sampled; got: sampled")
+	}
+}
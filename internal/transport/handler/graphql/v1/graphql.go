@@ -0,0 +1,92 @@
+package graphql_v1
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errhandle"
+	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
+	UserPreference "github.com/koraygocmen/golang-boilerplate/internal/model/user_preference"
+	UserSession "github.com/koraygocmen/golang-boilerplate/internal/model/user_session"
+	"github.com/koraygocmen/golang-boilerplate/internal/service"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/graphql"
+	v1 "github.com/koraygocmen/golang-boilerplate/internal/transport/response/v1"
+	"github.com/koraygocmen/golang-boilerplate/internal/validate"
+)
+
+// Handler.
+type Handler struct {
+	*v1.Response
+}
+
+func New(v1Response *v1.Response) *Handler {
+	return &Handler{v1Response}
+}
+
+// POST /graphql
+//
+// The response is the graphql result rather than the v1 response, the
+// errors of the fields are in the errors of the result with the codes
+// of the api errors as their extensions.
+func (v1 *Handler) Query(c *fiber.Ctx) error {
+	ctx := context.FromFiberCtx(c)
+
+	user, ok := c.Locals("user").(*User.User)
+	if !ok {
+		err := fmt.Errorf("graphql handle query error: user not found in local ctx")
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	userSession, ok := c.Locals("userSession").(*UserSession.UserSession)
+	if !ok {
+		err := fmt.Errorf("graphql handle query error: user session not found in local ctx")
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	var params graphql.Params
+	if err := c.BodyParser(&params); err != nil {
+		aerr := validate.Body(err)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, nil, aerr))
+	}
+
+	// Create the service with a new transaction, the
+	// fields of the query are all loaded with it.
+	srv := service.Service.Transaction(ctx, 30*time.Second)
+
+	request := &graphql.Request{
+		User:        user,
+		UserSession: userSession,
+		Loaders: &graphql.Loaders{
+			User:           graphql.NewLoader(graphql.BatchFn[*User.User](srv.User.V1.GetByIDs)),
+			UserSessions:   graphql.NewLoader(graphql.BatchFn[[]*UserSession.UserSession](srv.UserSession.V1.ListByUserIDs)),
+			UserPreference: graphql.NewLoader(graphql.BatchFn[*UserPreference.UserPreference](srv.UserPreference.V1.GetByUserIDs)),
+		},
+	}
+
+	result, aerr, err := graphql.Default.Do(ctx, request, &params)
+	if err != nil {
+		err = fmt.Errorf("graphql handle query error: %w", err)
+		srv.Rollback(err)
+		errhandle.Handle(ctx, service.ErrInternalServer, err, false)
+		return c.Status(fiber.StatusOK).JSON(result)
+	}
+
+	if aerr != nil {
+		srv.Rollback(nil)
+		errhandle.Handle(ctx, aerr, nil, false)
+		return c.Status(aerr.Status()).JSON(result)
+	}
+
+	if err := srv.Commit(); err != nil {
+		err = fmt.Errorf("graphql handle query error: commit error: %w", err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
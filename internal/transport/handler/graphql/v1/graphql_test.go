@@ -0,0 +1 @@
+package graphql_v1
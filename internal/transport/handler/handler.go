@@ -2,19 +2,26 @@ package handler
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/golang-boilerplate/internal/i18n"
 	"github.com/koraygocmen/golang-boilerplate/internal/service"
 )
 
 type Config struct {
-	SHASUM string
+	SHASUM             string
+	ProblemTypeBaseURL string
+	OpenAPIDocs        bool
 }
 
 type Handler struct {
-	SHASUM string
+	SHASUM             string
+	ProblemTypeBaseURL string
+	OpenAPIDocs        bool
 }
 
 // Create the handler object with the logger to use the
@@ -22,7 +29,9 @@ type Handler struct {
 // available before creating the fiber app.
 func New(c Config) *Handler {
 	return &Handler{
-		SHASUM: c.SHASUM,
+		SHASUM:             c.SHASUM,
+		ProblemTypeBaseURL: c.ProblemTypeBaseURL,
+		OpenAPIDocs:        c.OpenAPIDocs,
 	}
 }
 
@@ -71,11 +80,18 @@ func (h *Handler) Error(c *fiber.Ctx, err error) error {
 		return c.Status(fiber.StatusRequestTimeout).
 			JSON(h.Failure(ctx, nil, service.ErrRequestTimeout))
 	case fiber.ErrTooManyRequests:
+		aerr := service.ErrTooManyRequests
+		if seconds, err := strconv.Atoi(c.GetRespHeader(fiber.HeaderRetryAfter)); err == nil {
+			aerr = errapi.WithParams(service.ErrTooManyRequestsRetryAfter, i18n.Params{i18n.ParamCount: seconds})
+		}
 		return c.Status(fiber.StatusTooManyRequests).
-			JSON(h.Failure(ctx, nil, service.ErrTooManyRequests))
+			JSON(h.Failure(ctx, nil, aerr))
 	case fiber.ErrRequestEntityTooLarge:
 		return c.Status(fiber.StatusRequestEntityTooLarge).
 			JSON(h.Failure(ctx, nil, service.ErrRequestEntityTooLarge))
+	case fiber.ErrUnsupportedMediaType:
+		return c.Status(fiber.StatusUnsupportedMediaType).
+			JSON(h.Failure(ctx, nil, service.ErrUnsupportedMediaType))
 	}
 
 	return c.Status(fiber.StatusInternalServerError).
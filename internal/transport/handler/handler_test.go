@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"encoding/json"
 	"net/http/httptest"
 	"testing"
 	"time"
@@ -68,3 +69,96 @@ func TestHealth(t *testing.T) {
 		t.Fatalf("/health status = %d; want %d", statusCode, fiber.StatusOK)
 	}
 }
+
+func TestProblem(t *testing.T) {
+	h := New(Config{
+		ProblemTypeBaseURL: "https://docs.example.com/problems/",
+	})
+
+	ctx := context.WithValue(context.Background(), context.KeyLang, context.LangEN)
+	ctx = context.WithValue(ctx, context.KeyPath, "/v1/users")
+	ctx = context.WithValue(ctx, context.KeyRequestID, "request-id")
+
+	// Test the v1 envelope is the default.
+	marshalled, err := json.Marshal(h.Failure(ctx, nil, service.ErrNotFound))
+	if err != nil {
+		t.Fatalf("want: marshal err nil; got: err = %v", err)
+	}
+	if want := `{"success":false,"error":{"code":"notFound","message":"You entered an incorrect url address."}}`; string(marshalled) != want {
+		t.Fatalf("want: %s; got: %s", want, marshalled)
+	}
+
+	// Test the problem details.
+	ctx = context.WithValue(ctx, context.KeyProblem, true)
+	marshalled, err = json.Marshal(h.Failure(ctx, nil, service.ErrNotFound))
+	if err != nil {
+		t.Fatalf("want: marshal err nil; got: err = %v", err)
+	}
+
+	var problem Problem
+	if err := json.Unmarshal(marshalled, &problem); err != nil {
+		t.Fatalf("want: unmarshal err nil; got: err = %v", err)
+	}
+	if problem.Type != "https://docs.example.com/problems/notFound" {
+		t.Fatalf("problem type = %s; want https://docs.example.com/problems/notFound", problem.Type)
+	}
+	if problem.Title != "Not Found" || problem.Status != fiber.StatusNotFound {
+		t.Fatalf("problem title, status = %s, %d; want Not Found, 404", problem.Title, problem.Status)
+	}
+	if problem.Detail != "You entered an incorrect url address." || problem.Code != "notFound" {
+		t.Fatalf("problem detail, code = %s, %s; want You entered an incorrect url address., notFound", problem.Detail, problem.Code)
+	}
+	if problem.Instance != "/v1/users" || problem.RequestID != "request-id" {
+		t.Fatalf("problem instance, request id = %s, %s; want /v1/users, request-id", problem.Instance, problem.RequestID)
+	}
+
+	// Test the default type without the base url.
+	if problem := New(Config{}).Problem(ctx, service.ErrNotFound); problem.Type != ProblemTypeDefault {
+		t.Fatalf("problem type = %s; want %s", problem.Type, ProblemTypeDefault)
+	}
+}
+
+func TestErrorLocalized(t *testing.T) {
+	h := New(Config{})
+	app := fiber.New(fiber.Config{
+		ErrorHandler: h.Error,
+	})
+	app.Get("/limited", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderRetryAfter, c.Query("retryAfter"))
+		return fiber.ErrTooManyRequests
+	})
+
+	tests := []struct {
+		acceptLanguage string
+		retryAfter     string
+		want           string
+	}{
+		{"en", "1", "You have sent too many requests. Please try again in 1 second."},
+		{"en-GB,tr;q=0.5", "30", "You have sent too many requests. Please try again in 30 seconds."},
+		{"de", "30", "Du hast zu viele Anfragen gesendet. Bitte versuche es in 30 Sekunden erneut."},
+		{"tr", "30", "Çok fazla istek gönderdin. Lütfen 30 saniye sonra tekrar dene."},
+		{"fr", "", "Çok fazla istek gönderdin. Lütfen daha sonra tekrar dene."},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(fiber.MethodGet, "/limited?retryAfter="+test.retryAfter, nil)
+		req.Header.Set(fiber.HeaderAcceptLanguage, test.acceptLanguage)
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("/limited error: %v", err)
+		}
+
+		var body struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("/limited decode error: %v", err)
+		}
+		if body.Error.Message != test.want {
+			t.Fatalf("/limited message = %q for %s; want %q", body.Error.Message, test.acceptLanguage, test.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+)
+
+const (
+	MIMEApplicationProblemJSON = "application/problem+json"
+
+	// ProblemTypeDefault is the problem type when the type base
+	// url is not configured, the title is the status text then.
+	ProblemTypeDefault = "about:blank"
+)
+
+// Problem is the RFC 7807 problem details of an error. The code,
+// request id and the field errors are the extension members.
+type Problem struct {
+	Type      string              `json:"type"`
+	Title     string              `json:"title"`
+	Status    int                 `json:"status"`
+	Detail    string              `json:"detail,omitempty"`
+	Instance  string              `json:"instance,omitempty"`
+	Code      string              `json:"code"`
+	RequestID string              `json:"requestId,omitempty"`
+	Errors    []errapi.FieldError `json:"errors,omitempty"`
+}
+
+// IsProblem returns true if the errors of the
+// request are responded as problem details.
+func IsProblem(ctx context.Ctx) bool {
+	problem, _ := ctx.Value(context.KeyProblem).(bool)
+	return problem
+}
+
+// Problem creates the problem details of the localized error.
+func (h *Handler) Problem(ctx context.Ctx, aerr errapi.Error) *Problem {
+	problem := &Problem{
+		Type:   ProblemTypeDefault,
+		Title:  http.StatusText(aerr.Status()),
+		Status: aerr.Status(),
+		Detail: aerr.Message(),
+		Code:   aerr.Code(),
+		Errors: aerr.Fields(),
+	}
+
+	if h.ProblemTypeBaseURL != "" {
+		problem.Type = strings.TrimSuffix(h.ProblemTypeBaseURL, "/") + "/" + aerr.Code()
+	}
+	if path, ok := ctx.Value(context.KeyPath).(string); ok {
+		problem.Instance = path
+	}
+	if requestID, ok := ctx.Value(context.KeyRequestID).(string); ok {
+		problem.RequestID = requestID
+	}
+
+	return problem
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"encoding/json"
+
 	"github.com/koraygocmen/golang-boilerplate/internal/context"
 	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
 	"github.com/koraygocmen/golang-boilerplate/internal/errhandle"
@@ -10,6 +12,19 @@ type ApiResponse struct {
 	Success bool         `json:"success"`
 	Body    interface{}  `json:"body,omitempty"`
 	Error   errapi.Error `json:"error,omitempty"`
+
+	// Problem replaces the response if the client
+	// accepts the errors as problem details.
+	problem *Problem
+}
+
+func (r ApiResponse) MarshalJSON() ([]byte, error) {
+	if r.problem != nil {
+		return json.Marshal(r.problem)
+	}
+
+	type apiResponse ApiResponse
+	return json.Marshal(apiResponse(r))
 }
 
 func (h *Handler) Success(ctx context.Ctx, body interface{}) ApiResponse {
@@ -21,8 +36,13 @@ func (h *Handler) Success(ctx context.Ctx, body interface{}) ApiResponse {
 
 func (h *Handler) Failure(ctx context.Ctx, err error, aerr errapi.Error) ApiResponse {
 	errhandle.Handle(ctx, aerr, err, false)
-	return ApiResponse{
+	response := ApiResponse{
 		Success: false,
 		Error:   aerr.Localize(ctx),
 	}
+
+	if IsProblem(ctx) {
+		response.problem = h.Problem(ctx, response.Error)
+	}
+	return response
 }
@@ -2,6 +2,7 @@ package user_v1
 
 import (
 	"fmt"
+	"mime"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -11,6 +12,11 @@ import (
 	"github.com/koraygocmen/golang-boilerplate/internal/service"
 	UserServiceV1 "github.com/koraygocmen/golang-boilerplate/internal/service/user/v1"
 	v1 "github.com/koraygocmen/golang-boilerplate/internal/transport/response/v1"
+	"github.com/koraygocmen/golang-boilerplate/internal/validate"
+)
+
+const (
+	MIMEApplicationMergePatchJSON = "application/merge-patch+json"
 )
 
 // Handler.
@@ -28,9 +34,9 @@ func (v1 *Handler) Create(c *fiber.Ctx) error {
 
 	var userCreateParams UserServiceV1.CreateParams
 	if err := c.BodyParser(&userCreateParams); err != nil {
-		err = fmt.Errorf("user handle create error: body parser error: %w", err)
-		return c.Status(fiber.StatusInternalServerError).
-			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+		aerr := validate.Body(err)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, nil, aerr))
 	}
 
 	// Create the service with a new transaction.
@@ -56,6 +62,7 @@ func (v1 *Handler) Create(c *fiber.Ctx) error {
 			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
 	}
 
+	c.Set(fiber.HeaderETag, user.ETag())
 	return c.Status(fiber.StatusCreated).
 		JSON(v1.Handler.Success(ctx, fiber.Map{
 			"user": user,
@@ -73,6 +80,7 @@ func (v1 *Handler) Get(c *fiber.Ctx) error {
 			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
 	}
 
+	c.Set(fiber.HeaderETag, user.ETag())
 	return c.Status(fiber.StatusOK).
 		JSON(v1.Handler.Success(ctx, fiber.Map{
 			"user": user,
@@ -99,10 +107,11 @@ func (v1 *Handler) Update(c *fiber.Ctx) error {
 
 	var userUpdateParams UserServiceV1.UpdateParams
 	if err := c.BodyParser(&userUpdateParams); err != nil {
-		err = fmt.Errorf("user handle update error: body parser error: %w", err)
-		return c.Status(fiber.StatusInternalServerError).
-			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+		aerr := validate.Body(err)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, nil, aerr))
 	}
+	userUpdateParams.IfMatch = c.Get(fiber.HeaderIfMatch)
 
 	// Create the service with a new transaction.
 	srv := service.Service.Transaction(ctx, 30*time.Second)
@@ -127,6 +136,67 @@ func (v1 *Handler) Update(c *fiber.Ctx) error {
 			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
 	}
 
+	c.Set(fiber.HeaderETag, user.ETag())
+	return c.Status(fiber.StatusOK).
+		JSON(v1.Handler.Success(ctx, fiber.Map{
+			"user": user,
+		}))
+}
+
+// PATCH /v1/users
+func (v1 *Handler) Patch(c *fiber.Ctx) error {
+	ctx := context.FromFiberCtx(c)
+
+	user, ok := c.Locals("user").(*User.User)
+	if !ok {
+		err := fmt.Errorf("user handle patch error: user not found in local ctx")
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	userSession, ok := c.Locals("userSession").(*UserSession.UserSession)
+	if !ok {
+		err := fmt.Errorf("user handle patch error: user session not found in local ctx")
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	// Merge patch documents are json, plain json is
+	// accepted for the clients that cannot set it.
+	mediaType, _, _ := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	if mediaType != MIMEApplicationMergePatchJSON && mediaType != fiber.MIMEApplicationJSON {
+		// Errors returned from handlers are handled by the handler.Error
+		// which was setup when initializing the fiber app.
+		return fiber.ErrUnsupportedMediaType
+	}
+
+	// Create the service with a new transaction.
+	srv := service.Service.Transaction(ctx, 30*time.Second)
+
+	user, aerr, err := srv.User.V1.Patch(ctx, user, userSession, &UserServiceV1.PatchParams{
+		Document: c.Body(),
+		IfMatch:  c.Get(fiber.HeaderIfMatch),
+	})
+	if err != nil {
+		err = fmt.Errorf("user handle patch error: %w", err)
+		srv.Rollback(err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	if aerr != nil {
+		srv.Rollback(nil)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, err, aerr))
+	}
+
+	if err := srv.Commit(); err != nil {
+		err = fmt.Errorf("user handle patch error: commit error: %w", err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	c.Set(fiber.HeaderETag, user.ETag())
 	return c.Status(fiber.StatusOK).
 		JSON(v1.Handler.Success(ctx, fiber.Map{
 			"user": user,
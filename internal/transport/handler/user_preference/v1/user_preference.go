@@ -0,0 +1,110 @@
+package user_preference_v1
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
+	"github.com/koraygocmen/golang-boilerplate/internal/service"
+	UserPreferenceServiceV1 "github.com/koraygocmen/golang-boilerplate/internal/service/user_preference/v1"
+	v1 "github.com/koraygocmen/golang-boilerplate/internal/transport/response/v1"
+	"github.com/koraygocmen/golang-boilerplate/internal/validate"
+)
+
+// Handler.
+type Handler struct {
+	*v1.Response
+}
+
+func New(v1Response *v1.Response) *Handler {
+	return &Handler{v1Response}
+}
+
+// GET /v1/users/preferences
+func (v1 *Handler) Get(c *fiber.Ctx) error {
+	ctx := context.FromFiberCtx(c)
+
+	user, ok := c.Locals("user").(*User.User)
+	if !ok {
+		err := fmt.Errorf("user preference handle get error: user not found in local ctx")
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	// Create the service with a new transaction.
+	srv := service.Service.Transaction(ctx, 30*time.Second)
+
+	userPreference, aerr, err := srv.UserPreference.V1.Get(ctx, user)
+	if err != nil {
+		err = fmt.Errorf("user preference handle get error: %w", err)
+		srv.Rollback(err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	if aerr != nil {
+		srv.Rollback(nil)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, err, aerr))
+	}
+
+	if err := srv.Commit(); err != nil {
+		err = fmt.Errorf("user preference handle get error: commit error: %w", err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(v1.Handler.Success(ctx, fiber.Map{
+			"preferences": userPreference,
+		}))
+}
+
+// PUT /v1/users/preferences
+func (v1 *Handler) Update(c *fiber.Ctx) error {
+	ctx := context.FromFiberCtx(c)
+
+	user, ok := c.Locals("user").(*User.User)
+	if !ok {
+		err := fmt.Errorf("user preference handle update error: user not found in local ctx")
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	var userPreferenceUpdateParams UserPreferenceServiceV1.UpdateParams
+	if err := c.BodyParser(&userPreferenceUpdateParams); err != nil {
+		aerr := validate.Body(err)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, nil, aerr))
+	}
+
+	// Create the service with a new transaction.
+	srv := service.Service.Transaction(ctx, 30*time.Second)
+
+	userPreference, aerr, err := srv.UserPreference.V1.Update(ctx, user, &userPreferenceUpdateParams)
+	if err != nil {
+		err = fmt.Errorf("user preference handle update error: %w", err)
+		srv.Rollback(err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	if aerr != nil {
+		srv.Rollback(nil)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, err, aerr))
+	}
+
+	if err := srv.Commit(); err != nil {
+		err = fmt.Errorf("user preference handle update error: commit error: %w", err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(v1.Handler.Success(ctx, fiber.Map{
+			"preferences": userPreference,
+		}))
+}
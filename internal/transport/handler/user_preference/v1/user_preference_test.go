@@ -0,0 +1 @@
+package user_preference_v1
@@ -1,15 +1,18 @@
 package user_session_v1
 
 import (
+	"bufio"
 	"fmt"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/koraygocmen/golang-boilerplate/internal/context"
 	UserSession "github.com/koraygocmen/golang-boilerplate/internal/model/user_session"
+	"github.com/koraygocmen/golang-boilerplate/internal/realtime"
 	"github.com/koraygocmen/golang-boilerplate/internal/service"
 	UserSessionServiceV1 "github.com/koraygocmen/golang-boilerplate/internal/service/user_session/v1"
 	v1 "github.com/koraygocmen/golang-boilerplate/internal/transport/response/v1"
+	"github.com/koraygocmen/golang-boilerplate/internal/validate"
 )
 
 // Handler.
@@ -27,9 +30,9 @@ func (v1 *Handler) Create(c *fiber.Ctx) error {
 
 	var userSessionCreateParams UserSessionServiceV1.CreateParams
 	if err := c.BodyParser(&userSessionCreateParams); err != nil {
-		err = fmt.Errorf("user session handle create error: body parser error: %w", err)
-		return c.Status(fiber.StatusInternalServerError).
-			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+		aerr := validate.Body(err)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, nil, aerr))
 	}
 	userSessionCreateParams.ClientIP = context.RemoteIP(ctx)
 
@@ -101,3 +104,36 @@ func (v1 *Handler) Delete(c *fiber.Ctx) error {
 			"userSession": userSession,
 		}))
 }
+
+// GET /v1/users/sessions/events
+func (v1 *Handler) Events(c *fiber.Ctx) error {
+	ctx := context.FromFiberCtx(c)
+
+	// The events are only streamed to the
+	// requests that accept the event streams.
+	if !realtime.Accepted(c.Get(fiber.HeaderAccept)) {
+		return fiber.ErrNotAcceptable
+	}
+
+	userSession, ok := c.Locals("userSession").(*UserSession.UserSession)
+	if !ok {
+		err := fmt.Errorf("user sessions handle events error: user session not found in local ctx")
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	// The subscription is created before the stream so the events
+	// of the request are not missed, the stream is written after
+	// the handler returns and closes the subscription when it ends.
+	broker := realtime.Default
+	subscription := broker.Subscribe(userSession.UserID, userSession.ID)
+
+	c.Set(fiber.HeaderContentType, realtime.MIMETextEventStream)
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set("X-Accel-Buffering", "no")
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		broker.Stream(w, subscription)
+	})
+
+	return nil
+}
@@ -0,0 +1,266 @@
+package webhook_v1
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/service"
+	WebhookServiceV1 "github.com/koraygocmen/golang-boilerplate/internal/service/webhook/v1"
+	v1 "github.com/koraygocmen/golang-boilerplate/internal/transport/response/v1"
+	"github.com/koraygocmen/golang-boilerplate/internal/validate"
+)
+
+// Handler.
+type Handler struct {
+	*v1.Response
+}
+
+func New(v1Response *v1.Response) *Handler {
+	return &Handler{v1Response}
+}
+
+// POST /v1/admin/webhooks
+func (v1 *Handler) Create(c *fiber.Ctx) error {
+	ctx := context.FromFiberCtx(c)
+
+	var webhookCreateParams WebhookServiceV1.CreateParams
+	if err := c.BodyParser(&webhookCreateParams); err != nil {
+		aerr := validate.Body(err)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, nil, aerr))
+	}
+
+	// Create the service with a new transaction.
+	srv := service.Service.Transaction(ctx, 30*time.Second)
+
+	webhookSubscription, aerr, err := srv.Webhook.V1.Create(ctx, &webhookCreateParams)
+	if err != nil {
+		err = fmt.Errorf("webhook handle create error: %w", err)
+		srv.Rollback(err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	if aerr != nil {
+		srv.Rollback(nil)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, err, aerr))
+	}
+
+	if err := srv.Commit(); err != nil {
+		err = fmt.Errorf("webhook handle create error: commit error: %w", err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	// The secret is only returned once.
+	return c.Status(fiber.StatusCreated).
+		JSON(v1.Handler.Success(ctx, fiber.Map{
+			"webhook": webhookSubscription,
+			"secret":  webhookSubscription.Secret,
+		}))
+}
+
+// GET /v1/admin/webhooks
+func (v1 *Handler) List(c *fiber.Ctx) error {
+	ctx := context.FromFiberCtx(c)
+
+	// Create the service with a new transaction.
+	srv := service.Service.Transaction(ctx, 30*time.Second)
+
+	webhookSubscriptions, aerr, err := srv.Webhook.V1.List(ctx)
+	if err != nil {
+		err = fmt.Errorf("webhook handle list error: %w", err)
+		srv.Rollback(err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	if aerr != nil {
+		srv.Rollback(nil)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, err, aerr))
+	}
+
+	if err := srv.Commit(); err != nil {
+		err = fmt.Errorf("webhook handle list error: commit error: %w", err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(v1.Handler.Success(ctx, fiber.Map{
+			"webhooks": webhookSubscriptions,
+		}))
+}
+
+// PUT /v1/admin/webhooks/:id
+func (v1 *Handler) Update(c *fiber.Ctx) error {
+	ctx := context.FromFiberCtx(c)
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(v1.Handler.Failure(ctx, nil, service.ErrUrlParamInvalid))
+	}
+
+	var webhookUpdateParams WebhookServiceV1.UpdateParams
+	if err := c.BodyParser(&webhookUpdateParams); err != nil {
+		aerr := validate.Body(err)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, nil, aerr))
+	}
+
+	// Create the service with a new transaction.
+	srv := service.Service.Transaction(ctx, 30*time.Second)
+
+	webhookSubscription, aerr, err := srv.Webhook.V1.Update(ctx, int64(id), &webhookUpdateParams)
+	if err != nil {
+		err = fmt.Errorf("webhook handle update error: %w", err)
+		srv.Rollback(err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	if aerr != nil {
+		srv.Rollback(nil)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, err, aerr))
+	}
+
+	if err := srv.Commit(); err != nil {
+		err = fmt.Errorf("webhook handle update error: commit error: %w", err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(v1.Handler.Success(ctx, fiber.Map{
+			"webhook": webhookSubscription,
+		}))
+}
+
+// DELETE /v1/admin/webhooks/:id
+func (v1 *Handler) Delete(c *fiber.Ctx) error {
+	ctx := context.FromFiberCtx(c)
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(v1.Handler.Failure(ctx, nil, service.ErrUrlParamInvalid))
+	}
+
+	// Create the service with a new transaction.
+	srv := service.Service.Transaction(ctx, 30*time.Second)
+
+	webhookSubscription, aerr, err := srv.Webhook.V1.Delete(ctx, int64(id))
+	if err != nil {
+		err = fmt.Errorf("webhook handle delete error: %w", err)
+		srv.Rollback(err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	if aerr != nil {
+		srv.Rollback(nil)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, err, aerr))
+	}
+
+	if err := srv.Commit(); err != nil {
+		err = fmt.Errorf("webhook handle delete error: commit error: %w", err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(v1.Handler.Success(ctx, fiber.Map{
+			"webhook": webhookSubscription,
+		}))
+}
+
+// GET /v1/admin/webhooks/:id/deliveries
+func (v1 *Handler) DeliveryList(c *fiber.Ctx) error {
+	ctx := context.FromFiberCtx(c)
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(v1.Handler.Failure(ctx, nil, service.ErrUrlParamInvalid))
+	}
+
+	var deliveryListParams WebhookServiceV1.DeliveryListParams
+	if err := c.QueryParser(&deliveryListParams); err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(v1.Handler.Failure(ctx, nil, service.ErrUrlParamInvalid))
+	}
+
+	// Create the service with a new transaction.
+	srv := service.Service.Transaction(ctx, 30*time.Second)
+
+	webhookDeliveries, aerr, err := srv.Webhook.V1.DeliveryList(ctx, int64(id), &deliveryListParams)
+	if err != nil {
+		err = fmt.Errorf("webhook handle delivery list error: %w", err)
+		srv.Rollback(err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	if aerr != nil {
+		srv.Rollback(nil)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, err, aerr))
+	}
+
+	if err := srv.Commit(); err != nil {
+		err = fmt.Errorf("webhook handle delivery list error: commit error: %w", err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(v1.Handler.Success(ctx, fiber.Map{
+			"deliveries": webhookDeliveries,
+		}))
+}
+
+// POST /v1/admin/webhooks/deliveries/:id/redeliver
+func (v1 *Handler) DeliveryRedeliver(c *fiber.Ctx) error {
+	ctx := context.FromFiberCtx(c)
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(v1.Handler.Failure(ctx, nil, service.ErrUrlParamInvalid))
+	}
+
+	// Create the service with a new transaction.
+	srv := service.Service.Transaction(ctx, 30*time.Second)
+
+	webhookDelivery, aerr, err := srv.Webhook.V1.DeliveryRedeliver(ctx, int64(id))
+	if err != nil {
+		err = fmt.Errorf("webhook handle delivery redeliver error: %w", err)
+		srv.Rollback(err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	if aerr != nil {
+		srv.Rollback(nil)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, err, aerr))
+	}
+
+	if err := srv.Commit(); err != nil {
+		err = fmt.Errorf("webhook handle delivery redeliver error: commit error: %w", err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	return c.Status(fiber.StatusOK).
+		JSON(v1.Handler.Success(ctx, fiber.Map{
+			"delivery": webhookDelivery,
+		}))
+}
@@ -0,0 +1,58 @@
+package webhook_v1
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/handler"
+	v1 "github.com/koraygocmen/golang-boilerplate/internal/transport/response/v1"
+	"github.com/koraygocmen/golang-boilerplate/pkg/fibertest"
+)
+
+var (
+	appTest *fiber.App
+)
+
+func TestMain(m *testing.M) {
+	// Setup logger.
+	logger.Logger, _ = logger.New(logger.Config{
+		Mode: string(logger.ModeNone),
+	})
+
+	v1WebhookHandler := New(v1.New(handler.New(handler.Config{})))
+
+	appTest = fiber.New(fiber.Config{})
+	appTest.Put("/v1/admin/webhooks/:id", v1WebhookHandler.Update)
+	appTest.Delete("/v1/admin/webhooks/:id", v1WebhookHandler.Delete)
+	appTest.Get("/v1/admin/webhooks/:id/deliveries", v1WebhookHandler.DeliveryList)
+	appTest.Post("/v1/admin/webhooks/deliveries/:id/redeliver", v1WebhookHandler.DeliveryRedeliver)
+
+	m.Run()
+}
+
+func TestUrlParamInvalid(t *testing.T) {
+	requests := []struct {
+		method string
+		path   string
+	}{
+		{fiber.MethodPut, "/v1/admin/webhooks/abc"},
+		{fiber.MethodDelete, "/v1/admin/webhooks/abc"},
+		{fiber.MethodGet, "/v1/admin/webhooks/abc/deliveries"},
+		{fiber.MethodGet, "/v1/admin/webhooks/1/deliveries?pageSize=abc"},
+		{fiber.MethodPost, "/v1/admin/webhooks/deliveries/abc/redeliver"},
+	}
+
+	for _, r := range requests {
+		status, _, resbody, err := fibertest.Request(appTest, r.method, r.path, nil, nil)
+		if err != nil {
+			t.Fatalf("want: request err nil; got: %v", err)
+		}
+
+		aerr, _ := resbody["error"].(map[string]interface{})
+		if status != fiber.StatusBadRequest || aerr["code"] != errapi.ErrCodeUrlParamInvalid {
+			t.Fatalf("%s %s: want: %d %s; got: %d %v", r.method, r.path, fiber.StatusBadRequest, errapi.ErrCodeUrlParamInvalid, status, resbody)
+		}
+	}
+}
@@ -2,4 +2,25 @@ package header
 
 const (
 	HeaderAppVersion = "X-App-Version"
+	HeaderAPIKey     = "X-API-Key"
+
+	// Version headers, the api version routes the requests without
+	// a version in the path and is responded with the version served.
+	// The deprecated versions are responded with the deprecation
+	// and the sunset headers of RFC 9745 and RFC 8594.
+	HeaderAPIVersion  = "X-API-Version"
+	HeaderDeprecation = "Deprecation"
+	HeaderSunset      = "Sunset"
+
+	// Idempotency headers, a replayed response
+	// is marked with the replayed header.
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	// Rate limit headers of the IETF RateLimit header fields draft,
+	// the reset is the number of seconds until the window resets.
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
 )
@@ -1,12 +1,16 @@
 package middleware
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/header"
+)
 
 var (
 	allowedMethods = []string{
 		fiber.MethodGet,
 		fiber.MethodPost,
 		fiber.MethodPut,
+		fiber.MethodPatch,
 		fiber.MethodDelete,
 		fiber.MethodOptions,
 	}
@@ -15,5 +19,15 @@ var (
 		fiber.HeaderContentType,
 		fiber.HeaderAccept,
 		fiber.HeaderAuthorization,
+		fiber.HeaderETag,
+		fiber.HeaderRetryAfter,
+		header.HeaderRateLimitLimit,
+		header.HeaderRateLimitRemaining,
+		header.HeaderRateLimitReset,
+		header.HeaderRateLimitPolicy,
+		header.HeaderIdempotentReplayed,
+		header.HeaderAPIVersion,
+		header.HeaderDeprecation,
+		header.HeaderSunset,
 	}
 )
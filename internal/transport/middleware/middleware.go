@@ -3,19 +3,18 @@ package middleware
 import (
 	"runtime"
 	"strings"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/etag"
-	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/gofiber/helmet/v2"
 	"github.com/koraygocmen/golang-boilerplate/internal/context"
-	"github.com/koraygocmen/golang-boilerplate/internal/env"
 	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	"github.com/koraygocmen/golang-boilerplate/internal/ratelimit"
+	"github.com/koraygocmen/golang-boilerplate/internal/realtime"
 	"github.com/koraygocmen/golang-boilerplate/internal/transport/handler"
 )
 
@@ -24,7 +23,9 @@ func Setup(app *fiber.App, handler *handler.Handler) {
 	app.Use(requestid.New())
 	app.Use(recover.New())
 	app.Use(helmet.New())
-	app.Use(etag.New())
+	app.Use(etag.New(etag.Config{
+		Next: eventStream,
+	}))
 	app.Use(requestid.New(requestid.Config{
 		ContextKey: string(context.KeyRequestID),
 	}))
@@ -33,14 +34,26 @@ func Setup(app *fiber.App, handler *handler.Handler) {
 		ExposeHeaders: strings.Join(exposedHeaders, ","),
 	}))
 	app.Use(compress.New(compress.Config{
+		Next:  eventStream,
 		Level: compress.LevelBestSpeed,
 	}))
 
+	// Version router middleware, the requests without a
+	// version in the path are routed to their version.
+	app.Use(VersionRouter(app))
+
 	// Context middleware.
 	app.Use(func(c *fiber.Ctx) error {
 		// Context created here is used by each handler to log the request.
 		// Defered cancel is called when each handler returns.
 		ctx, cancel := context.NewFiberCtx(c)
+
+		// Route level overrides the log level for the whole request.
+		path := string(c.Context().Path())
+		if level, ok := logger.Logger.RouteLevel(path); ok {
+			ctx = context.WithValue(ctx, context.KeyLogRouteLevel, level)
+		}
+
 		c.Locals("ctx", ctx)
 		c.Locals("cancel", cancel)
 
@@ -48,8 +61,16 @@ func Setup(app *fiber.App, handler *handler.Handler) {
 			ctx := c.Locals("ctx").(context.Ctx)
 
 			// Response status and body are available when returning from handler.
-			ctx = context.WithValue(ctx, context.KeyStatus, c.Context().Response.StatusCode())
-			if !strings.Contains(string(c.Context().Path()), "/health") {
+			status := c.Context().Response.StatusCode()
+			ctx = context.WithValue(ctx, context.KeyStatus, status)
+			if !strings.Contains(path, "/health") && logger.Logger.Sample(path, status) {
+				// Bodies are only captured if enabled and redacted before logging.
+				ctx = logger.Logger.CaptureHeaders(ctx, c.GetReqHeaders())
+				ctx = logger.Logger.CaptureBody(ctx, context.KeyReqBody, c.Get(fiber.HeaderContentType), c.Body())
+				// The streamed bodies are not read, they are still being written.
+				if !c.Response().IsBodyStream() {
+					ctx = logger.Logger.CaptureBody(ctx, context.KeyResBody, string(c.Response().Header.ContentType()), c.Response().Body())
+				}
 				logger.Logger.Infof(ctx, "")
 			}
 			cancel()
@@ -58,6 +79,10 @@ func Setup(app *fiber.App, handler *handler.Handler) {
 		return c.Next()
 	})
 
+	// Problem details middleware, it is set before the
+	// rest to respond their errors as problem details too.
+	app.Use(Problem)
+
 	// Recover middleware with stack trace.
 	app.Use(recover.New(recover.Config{
 		EnableStackTrace: true,
@@ -73,26 +98,17 @@ func Setup(app *fiber.App, handler *handler.Handler) {
 		},
 	}))
 
-	// Rate limiter middleware.
-	app.Use(limiter.New(limiter.Config{
-		Next: func(c *fiber.Ctx) bool {
-			isGetRequest := c.Method() == fiber.MethodGet
-			isHealthCheck := strings.Contains(string(c.Context().Path()), "/health")
-			return (env.IsDev() || isHealthCheck || isGetRequest)
-		},
-		Max:               20,
-		Expiration:        60 * time.Second,
-		LimiterMiddleware: limiter.SlidingWindow{},
-		KeyGenerator: func(c *fiber.Ctx) string {
-			ctx := context.FromFiberCtx(c)
-			ip := context.RemoteIP(ctx)
-			return ip
-		},
-		LimitReached: func(c *fiber.Ctx) error {
-			// Errors returned from middleware are handled by the handler.Error
-			// which was setup when initializing the fiber app.
-			return fiber.ErrTooManyRequests
-		},
-	}))
+	// Version middleware, the deprecated versions and
+	// the outdated apps are refused before the handlers.
+	app.Use(Version(handler))
+
+	// Rate limiter middleware, the user
+	// policies apply after authentication.
+	app.Use(RateLimit(ratelimit.SubjectRoute, ratelimit.SubjectIP, ratelimit.SubjectAPIKey))
+}
 
+// eventStream reports whether the request is of an event stream, the
+// middleware that reads the whole response body skips the streams.
+func eventStream(c *fiber.Ctx) bool {
+	return realtime.Accepted(c.Get(fiber.HeaderAccept))
 }
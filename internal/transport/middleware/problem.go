@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/handler"
+)
+
+// Problem responds the errors as the RFC 7807 problem details if the
+// client prefers them over json in the accept header, the v1 envelope
+// stays the default. The errors returned by the handlers are passed to
+// the error handler here, so the content type is set for them as well.
+func Problem(c *fiber.Ctx) error {
+	offer := c.Accepts(fiber.MIMEApplicationJSON, handler.MIMEApplicationProblemJSON)
+	if offer != handler.MIMEApplicationProblemJSON {
+		return c.Next()
+	}
+
+	ctx := context.WithValue(context.FromFiberCtx(c), context.KeyProblem, true)
+	c.Locals("ctx", ctx)
+
+	if err := c.Next(); err != nil {
+		if err := c.App().ErrorHandler(c, err); err != nil {
+			return err
+		}
+	}
+
+	contentType := string(c.Response().Header.ContentType())
+	if c.Response().StatusCode() >= fiber.StatusBadRequest && strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) {
+		c.Set(fiber.HeaderContentType, handler.MIMEApplicationProblemJSON)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	"github.com/koraygocmen/golang-boilerplate/internal/service"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/handler"
+)
+
+func TestProblem(t *testing.T) {
+	logger.Logger, _ = logger.New(logger.Config{
+		Mode: string(logger.ModeNone),
+	})
+
+	h := handler.New(handler.Config{})
+	app := fiber.New(fiber.Config{
+		ErrorHandler: h.Error,
+	})
+	app.Use(Problem)
+	app.Get("/failure", func(c *fiber.Ctx) error {
+		ctx := context.FromFiberCtx(c)
+		return c.Status(fiber.StatusNotFound).
+			JSON(h.Failure(ctx, nil, service.ErrNotFound))
+	})
+	app.Get("/error", func(c *fiber.Ctx) error {
+		return fiber.ErrTooManyRequests
+	})
+	app.Get("/success", func(c *fiber.Ctx) error {
+		ctx := context.FromFiberCtx(c)
+		return c.Status(fiber.StatusOK).
+			JSON(h.Success(ctx, nil))
+	})
+
+	tests := []struct {
+		path        string
+		accept      string
+		status      int
+		contentType string
+	}{
+		{"/failure", "", fiber.StatusNotFound, fiber.MIMEApplicationJSON},
+		{"/failure", "application/json, application/problem+json;q=0.5", fiber.StatusNotFound, fiber.MIMEApplicationJSON},
+		{"/failure", "application/problem+json", fiber.StatusNotFound, handler.MIMEApplicationProblemJSON},
+		{"/failure", "application/json;q=0.5, application/problem+json", fiber.StatusNotFound, handler.MIMEApplicationProblemJSON},
+		{"/error", "application/problem+json", fiber.StatusTooManyRequests, handler.MIMEApplicationProblemJSON},
+		{"/success", "application/problem+json", fiber.StatusOK, fiber.MIMEApplicationJSON},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(fiber.MethodGet, test.path, nil)
+		if test.accept != "" {
+			req.Header.Set(fiber.HeaderAccept, test.accept)
+		}
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s error: %v", test.path, err)
+		}
+		if resp.StatusCode != test.status {
+			t.Fatalf("%s status = %d; want %d", test.path, resp.StatusCode, test.status)
+		}
+		if contentType := resp.Header.Get(fiber.HeaderContentType); contentType != test.contentType {
+			t.Fatalf("%s content type = %s for %s; want %s", test.path, contentType, test.accept, test.contentType)
+		}
+
+		// The problem details have the status as a member.
+		var body map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("%s decode error: %v", test.path, err)
+		}
+		if _, ok := body["status"]; ok != (test.contentType == handler.MIMEApplicationProblemJSON) {
+			t.Fatalf("%s body = %v; want problem details %t", test.path, body, !ok)
+		}
+	}
+}
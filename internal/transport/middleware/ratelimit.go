@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/env"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	"github.com/koraygocmen/golang-boilerplate/internal/ratelimit"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/header"
+)
+
+// RateLimit limits the requests with the policies of the subjects. The
+// user policies need the user in the context, so the user subject is
+// limited after the user is authenticated.
+func RateLimit(subjects ...ratelimit.Subject) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		limiter := ratelimit.Limiter
+		path := c.Path()
+
+		if limiter == nil || env.IsDev() || strings.Contains(path, "/health") {
+			return c.Next()
+		}
+
+		ctx := context.FromFiberCtx(c)
+
+		values := make(map[ratelimit.Subject]string, len(subjects))
+		for _, subject := range subjects {
+			switch subject {
+			case ratelimit.SubjectRoute:
+				values[subject] = ""
+			case ratelimit.SubjectIP:
+				values[subject] = context.RemoteIP(ctx)
+			case ratelimit.SubjectUser:
+				if userID, ok := ctx.Value(context.KeyUserID).(int64); ok {
+					values[subject] = strconv.FormatInt(userID, 10)
+				}
+			case ratelimit.SubjectAPIKey:
+				values[subject] = c.Get(header.HeaderAPIKey)
+			}
+		}
+
+		result, err := limiter.Allow(ctx, c.Method(), path, values)
+		if err != nil {
+			// Fail open, an unavailable storage
+			// should not take the api down.
+			logger.Logger.Errorf(ctx, `msg="rate limit failed", err="%v"`, err)
+			return c.Next()
+		}
+
+		if result == nil {
+			return c.Next()
+		}
+
+		c.Set(header.HeaderRateLimitLimit, strconv.FormatInt(result.Limit, 10))
+		c.Set(header.HeaderRateLimitRemaining, strconv.FormatInt(result.Remaining, 10))
+		c.Set(header.HeaderRateLimitReset, strconv.Itoa(ratelimit.Seconds(result.Reset)))
+		c.Set(header.HeaderRateLimitPolicy, ratelimit.PolicyHeader(result.Policy))
+
+		if !result.Allowed {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ratelimit.Seconds(result.RetryAfter())))
+
+			// Errors returned from middleware are handled by the handler.Error
+			// which was setup when initializing the fiber app.
+			return fiber.ErrTooManyRequests
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,34 @@
+package middleware_v1
+
+import (
+	"crypto/subtle"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/config"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/service"
+)
+
+func (v1 *Handler) AdminAuth(c *fiber.Ctx) error {
+	ctx := context.FromFiberCtx(c)
+
+	// Admin endpoints are disabled without a token.
+	if config.Admin.Token == "" {
+		return fiber.ErrNotFound
+	}
+
+	authorization := c.Get(fiber.HeaderAuthorization)
+	if authorization == "" {
+		aerr := service.ErrAdminAuth.AuthorizationMissing
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, nil, aerr))
+	}
+
+	if subtle.ConstantTimeCompare([]byte(authorization), []byte(config.Admin.Token)) != 1 {
+		aerr := service.ErrAdminAuth.AuthorizationWrong
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, nil, aerr))
+	}
+
+	return c.Next()
+}
@@ -91,6 +91,16 @@ func (v1 *Handler) UserAuth(c *fiber.Ctx) error {
 			JSON(v1.Handler.Failure(ctx, nil, aerr))
 	}
 
+	// The stored locale of the user is the fallback
+	// language if the accept language header is not set.
+	ctx, err = srv.UserPreference.V1.WithLocale(ctx, user.ID)
+	if err != nil {
+		err = fmt.Errorf("auth user middleware error: %w", err)
+		srv.Rollback(err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
 	// Commit to release the transaction.
 	if err := srv.Commit(); err != nil {
 		err = fmt.Errorf("auth user middleware error: commit error: %w", err)
@@ -0,0 +1,105 @@
+package middleware_v1
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/config"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	"github.com/koraygocmen/golang-boilerplate/internal/service"
+	IdempotencyKeyServiceV1 "github.com/koraygocmen/golang-boilerplate/internal/service/idempotency_key/v1"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/header"
+	"github.com/koraygocmen/golang-boilerplate/pkg/duration"
+)
+
+// Idempotency replays the first response of the requests sent with the
+// same idempotency key by the same user. The keys sent without a user are
+// scoped to the request, so the clients behind an IP do not share the keys
+// and only the same request replays the response. The key is claimed in a
+// transaction that is held until the response is stored, so concurrent
+// duplicates wait for the first request and replay its response. Server
+// errors are not stored and the request can be retried with the key. The
+// responses are stored encrypted since they can have the session tokens.
+func (v1 *Handler) Idempotency(c *fiber.Ctx) error {
+	key := c.Get(header.HeaderIdempotencyKey)
+	if key == "" {
+		return c.Next()
+	}
+
+	ctx := context.FromFiberCtx(c)
+
+	scope := "request:" + IdempotencyKeyServiceV1.Fingerprint(c.Method(), c.Path(), c.Body())
+	if userID, ok := ctx.Value(context.KeyUserID).(int64); ok {
+		scope = "user:" + strconv.FormatInt(userID, 10)
+	}
+
+	// Create the service with a new transaction.
+	srv := service.Service.Transaction(ctx, 30*time.Second)
+
+	idempotencyKey, aerr, err := srv.IdempotencyKey.V1.Claim(ctx, &IdempotencyKeyServiceV1.ClaimParams{
+		Scope:  scope,
+		Key:    key,
+		Method: c.Method(),
+		Path:   c.Path(),
+		Body:   c.Body(),
+		TTL:    duration.Hours(config.Idempotency.TTLHours),
+		Secret: config.Idempotency.Secret,
+	})
+	if err != nil {
+		err = fmt.Errorf("idempotency middleware error: %w", err)
+		srv.Rollback(err)
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(v1.Handler.Failure(ctx, err, service.ErrInternalServer))
+	}
+
+	if aerr != nil {
+		srv.Rollback(nil)
+		return c.Status(aerr.Status()).
+			JSON(v1.Handler.Failure(ctx, nil, aerr))
+	}
+
+	if idempotencyKey.IsReplay() {
+		srv.Rollback(nil)
+		c.Set(header.HeaderIdempotentReplayed, "true")
+		c.Set(fiber.HeaderContentType, idempotencyKey.ContentType)
+		return c.Status(idempotencyKey.StatusCode).
+			Send(idempotencyKey.Body)
+	}
+
+	// Errors returned from the handlers are handled by the
+	// handler.Error after the chain, the response is unknown.
+	if err := c.Next(); err != nil {
+		srv.Rollback(nil)
+		return err
+	}
+
+	statusCode := c.Response().StatusCode()
+	if statusCode >= fiber.StatusInternalServerError {
+		srv.Rollback(nil)
+		return nil
+	}
+
+	err = srv.IdempotencyKey.V1.Save(ctx, idempotencyKey, &IdempotencyKeyServiceV1.SaveParams{
+		StatusCode:  statusCode,
+		ContentType: string(c.Response().Header.ContentType()),
+		Body:        append([]byte{}, c.Response().Body()...),
+		Secret:      config.Idempotency.Secret,
+	})
+	if err != nil {
+		err = fmt.Errorf("idempotency middleware error: %w", err)
+		srv.Rollback(err)
+		logger.Logger.Errorf(ctx, `msg="idempotency key not saved", err="%v"`, err)
+		return nil
+	}
+
+	// The response of the handler stands, a failed
+	// commit only releases the key for the retries.
+	if err := srv.Commit(); err != nil {
+		logger.Logger.Errorf(ctx, `msg="idempotency key not saved", err="idempotency middleware error: commit error: %v"`, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/apiversion"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/golang-boilerplate/internal/i18n"
+	"github.com/koraygocmen/golang-boilerplate/internal/service"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/handler"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/header"
+)
+
+// VersionRouter routes the requests without a version in the path
+// to the version of the version header, or the default version, i.e.
+// "/users" to "/v1/users". The path is only rewritten if the version
+// has routes under its first segment, so the shared routes such as
+// "/health" are not versioned. The shared routes are kept even if a
+// version has routes under their segment, i.e. "/graphql" is not
+// routed to "/v1/graphql". It is set before the rest so they see the
+// versioned path.
+func VersionRouter(app *fiber.App) fiber.Handler {
+	var (
+		once sync.Once
+		// segments are the first segments of the
+		// routes of the versions after the version.
+		segments map[string]map[string]bool
+		// shared are the first segments of the
+		// routes without a version.
+		shared map[string]bool
+	)
+
+	return func(c *fiber.Ctx) error {
+		// The routes are set up after the middleware.
+		once.Do(func() {
+			segments = make(map[string]map[string]bool)
+			shared = make(map[string]bool)
+			for _, route := range app.GetRoutes(true) {
+				version, rest, ok := apiversion.FromPath(route.Path)
+				if !ok {
+					shared[firstSegment(route.Path)] = true
+					continue
+				}
+				if segments[version] == nil {
+					segments[version] = make(map[string]bool)
+				}
+				segments[version][firstSegment(rest)] = true
+			}
+		})
+
+		path := c.Path()
+		if _, _, ok := apiversion.FromPath(path); ok || shared[firstSegment(path)] {
+			return c.Next()
+		}
+
+		version := apiversion.Default
+		if v := c.Get(header.HeaderAPIVersion); v != "" {
+			version = apiversion.Parse(v)
+		}
+
+		// The invalid versions are refused by the version middleware.
+		if segments[version][firstSegment(path)] {
+			c.Path("/v" + version + path)
+		}
+
+		return c.Next()
+	}
+}
+
+// Version responds the version of the request, the deprecation
+// headers of the deprecated versions and refuses the versions past
+// their sunset. The apps older than the minimum app version are asked
+// to update, the requests without the app version header are not gated.
+func Version(h *handler.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := context.FromFiberCtx(c)
+
+		if v := c.Get(header.HeaderAPIVersion); v != "" && !apiversion.Versions[apiversion.Parse(v)] {
+			aerr := service.ErrVersion.APIVersionInvalid
+			return c.Status(aerr.Status()).
+				JSON(h.Failure(ctx, nil, aerr))
+		}
+
+		// The shared routes are not versioned.
+		version, _, ok := apiversion.FromPath(c.Path())
+		if !ok || !apiversion.Versions[version] {
+			return c.Next()
+		}
+
+		c.Set(header.HeaderAPIVersion, version)
+
+		versioner := apiversion.Versioner
+		if versioner == nil {
+			return c.Next()
+		}
+
+		if deprecation, ok := versioner.Deprecation(version); ok {
+			c.Set(header.HeaderDeprecation, "@"+strconv.FormatInt(deprecation.At.Unix(), 10))
+			if !deprecation.Sunset.IsZero() {
+				c.Set(header.HeaderSunset, deprecation.Sunset.UTC().Format(http.TimeFormat))
+			}
+
+			if versioner.Sunset(version) {
+				aerr := service.ErrVersion.APIVersionSunset
+				return c.Status(aerr.Status()).
+					JSON(h.Failure(ctx, nil, aerr))
+			}
+		}
+
+		minAppVersion, ok := versioner.MinAppVersion()
+		appVersion := c.Get(header.HeaderAppVersion)
+		if !ok || appVersion == "" {
+			return c.Next()
+		}
+
+		parsed, err := apiversion.ParseAppVersion(appVersion)
+		if err != nil {
+			aerr := service.ErrVersion.AppVersionInvalid
+			return c.Status(aerr.Status()).
+				JSON(h.Failure(ctx, nil, aerr))
+		}
+
+		if !versioner.AppVersionAllowed(parsed) {
+			aerr := errapi.WithParams(service.ErrVersion.AppVersionUnsupported, i18n.Params{"version": minAppVersion.String()})
+			return c.Status(aerr.Status()).
+				JSON(h.Failure(ctx, nil, aerr))
+		}
+
+		return c.Next()
+	}
+}
+
+func firstSegment(path string) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return segment
+}
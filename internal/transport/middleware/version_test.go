@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/apiversion"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/handler"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/header"
+)
+
+func TestVersion(t *testing.T) {
+	logger.Logger, _ = logger.New(logger.Config{
+		Mode: string(logger.ModeNone),
+	})
+	defer func() { apiversion.Versioner = nil }()
+
+	h := handler.New(handler.Config{})
+	app := fiber.New(fiber.Config{
+		ErrorHandler: h.Error,
+	})
+	app.Use(VersionRouter(app))
+	app.Use(Version(h))
+	app.Get("/health", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/graphql", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/v1/graphql", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusTeapot)
+	})
+	app.Get("/v1/users", func(c *fiber.Ctx) error {
+		ctx := context.FromFiberCtx(c)
+		return c.Status(fiber.StatusOK).
+			JSON(h.Success(ctx, nil))
+	})
+	app.Use(func(c *fiber.Ctx) error {
+		return fiber.ErrNotFound
+	})
+
+	tests := []struct {
+		name         string
+		deprecations string
+		minVersion   string
+		path         string
+		headers      map[string]string
+		status       int
+		code         string
+		version      string
+		deprecation  string
+		sunset       string
+	}{
+		{name: "path", path: "/v1/users", status: fiber.StatusOK, version: "1"},
+		{name: "default", path: "/users", status: fiber.StatusOK, version: "1"},
+		{name: "header", path: "/users", headers: map[string]string{header.HeaderAPIVersion: "v1"}, status: fiber.StatusOK, version: "1"},
+		{name: "header invalid", path: "/users", headers: map[string]string{header.HeaderAPIVersion: "9"}, status: fiber.StatusBadRequest, code: errapi.ErrCodeAPIVersionInvalid},
+		{name: "shared", path: "/health", status: fiber.StatusOK},
+		{name: "shared of a version segment", path: "/graphql", status: fiber.StatusOK},
+		{name: "unknown version", path: "/v9/users", status: fiber.StatusNotFound, code: errapi.ErrCodeNotFound},
+		{
+			name: "deprecated", deprecations: "1:2020-01-01:2100-01-01", path: "/users", status: fiber.StatusOK, version: "1",
+			deprecation: "@1577836800", sunset: "Fri, 01 Jan 2100 00:00:00 GMT",
+		},
+		{
+			name: "sunset", deprecations: "1:2020-01-01:2021-01-01", path: "/v1/users", status: fiber.StatusGone, code: errapi.ErrCodeAPIVersionSunset, version: "1",
+			deprecation: "@1577836800", sunset: "Fri, 01 Jan 2021 00:00:00 GMT",
+		},
+		{name: "sunset shared", deprecations: "1:2020-01-01:2021-01-01", path: "/health", status: fiber.StatusOK},
+		{name: "app version missing", minVersion: "2.4.0", path: "/users", status: fiber.StatusOK, version: "1"},
+		{name: "app version", minVersion: "2.4.0", path: "/users", headers: map[string]string{header.HeaderAppVersion: "2.4.0"}, status: fiber.StatusOK, version: "1"},
+		{name: "app version invalid", minVersion: "2.4.0", path: "/users", headers: map[string]string{header.HeaderAppVersion: "latest"}, status: fiber.StatusBadRequest, code: errapi.ErrCodeAppVersionInvalid, version: "1"},
+		{name: "app version old", minVersion: "2.4.0", path: "/users", headers: map[string]string{header.HeaderAppVersion: "2.3.9"}, status: fiber.StatusUpgradeRequired, code: errapi.ErrCodeAppVersionUnsupported, version: "1"},
+	}
+
+	for _, test := range tests {
+		var err error
+		if apiversion.Versioner, err = apiversion.New(apiversion.Config{
+			Deprecations:  test.deprecations,
+			MinAppVersion: test.minVersion,
+		}); err != nil {
+			t.Fatalf("%s versioning error: %v", test.name, err)
+		}
+
+		req := httptest.NewRequest(fiber.MethodGet, test.path, nil)
+		req.Header.Set(fiber.HeaderAcceptLanguage, "en")
+		for key, value := range test.headers {
+			req.Header.Set(key, value)
+		}
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s error: %v", test.name, err)
+		}
+		if resp.StatusCode != test.status {
+			t.Fatalf("%s status = %d; want %d", test.name, resp.StatusCode, test.status)
+		}
+		if version := resp.Header.Get(header.HeaderAPIVersion); version != test.version {
+			t.Fatalf("%s version = %q; want %q", test.name, version, test.version)
+		}
+		if deprecation := resp.Header.Get(header.HeaderDeprecation); deprecation != test.deprecation {
+			t.Fatalf("%s deprecation = %q; want %q", test.name, deprecation, test.deprecation)
+		}
+		if sunset := resp.Header.Get(header.HeaderSunset); sunset != test.sunset {
+			t.Fatalf("%s sunset = %q; want %q", test.name, sunset, test.sunset)
+		}
+
+		if test.code == "" {
+			continue
+		}
+
+		var body struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("%s decode error: %v", test.name, err)
+		}
+		if body.Error.Code != test.code {
+			t.Fatalf("%s code = %s; want %s", test.name, body.Error.Code, test.code)
+		}
+
+		// The apps are asked to update to the minimum version.
+		if test.code == errapi.ErrCodeAppVersionUnsupported && !strings.Contains(body.Error.Message, test.minVersion) {
+			t.Fatalf("%s message = %s; want the version %s", test.name, body.Error.Message, test.minVersion)
+		}
+	}
+}
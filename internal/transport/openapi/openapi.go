@@ -0,0 +1,375 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/handler"
+)
+
+const (
+	Version = "3.1.0"
+)
+
+var (
+	// pathParam matches the params of the fiber paths, i.e. ":id".
+	pathParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+)
+
+// Document is the OpenAPI document, only the parts
+// used to describe the routes of the api are defined.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+	Tags       []Tag               `json:"tags,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem is the operations of a path by the lowercase method.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Security    []SecurityRequirement `json:"security,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+}
+
+type SecurityRequirement map[string][]string
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	Name        string `json:"name,omitempty"`
+	In          string `json:"in,omitempty"`
+}
+
+// Header is a request header of the route.
+type Header struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+// Route describes a route registered on the router, the operation
+// of the route is generated from the param structs with the json,
+// params and query tags, and the codes of the errors it responds.
+type Route struct {
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Tag         string
+	// Security is the name of the security scheme of the route.
+	Security string
+	// Hidden routes are registered but not documented, i.e. the docs.
+	Hidden bool
+
+	// Version is the version of the response envelope, the
+	// shared routes respond without the version.
+	Version string
+	// Params and Query are the structs the url params
+	// and the query are parsed into.
+	Params  interface{}
+	Query   interface{}
+	Headers []Header
+	// Body is the struct the request body is parsed into, it is
+	// accepted as json unless the body types are set.
+	Body      interface{}
+	BodyTypes []string
+
+	// Status and Response are the success status and the body
+	// of the response, maps are described by their values. The
+	// response is json unless the response type is set.
+	Status       int
+	Response     interface{}
+	ResponseType string
+	// Raw routes respond the response without the envelope,
+	// i.e. the results of the graphql queries.
+	Raw bool
+	// Errors are the errors or the structs of the errors the
+	// route responds, they are grouped by their status.
+	Errors []interface{}
+}
+
+// New generates the document of the routes.
+func New(info Info, securitySchemes map[string]*SecurityScheme, routes []Route) *Document {
+	g := newGenerator()
+
+	d := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas:         g.schemas,
+			SecuritySchemes: securitySchemes,
+		},
+	}
+
+	tags := make(map[string]bool)
+	for _, route := range routes {
+		if route.Hidden {
+			continue
+		}
+
+		path := Path(route.Path)
+		if d.Paths[path] == nil {
+			d.Paths[path] = make(PathItem)
+		}
+		d.Paths[path][strings.ToLower(route.Method)] = g.operation(route)
+
+		if route.Tag != "" && !tags[route.Tag] {
+			tags[route.Tag] = true
+			d.Tags = append(d.Tags, Tag{Name: route.Tag})
+		}
+	}
+
+	return d
+}
+
+// Path converts the fiber path to the path of the document.
+func Path(path string) string {
+	return pathParam.ReplaceAllString(path, "{$1}")
+}
+
+func (g *generator) operation(route Route) *Operation {
+	operation := &Operation{
+		OperationID: route.OperationID,
+		Summary:     route.Summary,
+		Responses:   make(map[string]*Response),
+	}
+
+	if route.Tag != "" {
+		operation.Tags = []string{route.Tag}
+	}
+	if route.Security != "" {
+		operation.Security = []SecurityRequirement{{route.Security: {}}}
+	}
+
+	// Parameters.
+	operation.Parameters = append(operation.Parameters, g.parameters("path", "params", route.Params)...)
+	operation.Parameters = append(operation.Parameters, g.parameters("query", "query", route.Query)...)
+	for _, header := range route.Headers {
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:        header.Name,
+			In:          "header",
+			Description: header.Description,
+			Required:    header.Required,
+			Schema:      &Schema{Type: "string"},
+		})
+	}
+
+	// Request body.
+	if route.Body != nil {
+		bodyTypes := route.BodyTypes
+		if len(bodyTypes) == 0 {
+			bodyTypes = []string{fiber.MIMEApplicationJSON}
+		}
+
+		schema := g.value(route.Body)
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content:  make(map[string]MediaType, len(bodyTypes)),
+		}
+		for _, bodyType := range bodyTypes {
+			operation.RequestBody.Content[bodyType] = MediaType{Schema: schema}
+		}
+	}
+
+	// Success response.
+	status := route.Status
+	if status == 0 {
+		status = fiber.StatusOK
+	}
+	schema := g.value(route.Response)
+	if !route.Raw {
+		schema = envelope(route.Version, true, "body", schema)
+	}
+	responseType := route.ResponseType
+	if responseType == "" {
+		responseType = fiber.MIMEApplicationJSON
+	}
+	operation.Responses[strconv.Itoa(status)] = &Response{
+		Description: http.StatusText(status),
+		Content: map[string]MediaType{
+			responseType: {Schema: schema},
+		},
+	}
+
+	// Error responses, the codes of the status
+	// narrow the code of the error and the problem.
+	for status, codes := range errorsByStatus(route.Errors) {
+		code := &Schema{Properties: map[string]*Schema{
+			"code": {Type: "string", Enum: stringsToEnum(codes)},
+		}}
+
+		operation.Responses[strconv.Itoa(status)] = &Response{
+			Description: http.StatusText(status),
+			Content: map[string]MediaType{
+				fiber.MIMEApplicationJSON: {Schema: envelope(route.Version, false, "error", &Schema{
+					AllOf: []*Schema{g.schema(errorType), code},
+				})},
+				handler.MIMEApplicationProblemJSON: {Schema: &Schema{
+					AllOf: []*Schema{g.schema(problemType), code},
+				}},
+			},
+		}
+	}
+
+	return operation
+}
+
+// parameters returns the parameters of the fields of the struct
+// with the tag, the path parameters are always required.
+func (g *generator) parameters(in, tag string, v interface{}) []Parameter {
+	if v == nil {
+		return nil
+	}
+
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var parameters []Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		parameters = append(parameters, Parameter{
+			Name:     name,
+			In:       in,
+			Required: in == "path",
+			Schema:   g.schema(field.Type),
+		})
+	}
+	return parameters
+}
+
+// envelope returns the schema of the response body of the version,
+// the body or the error is the field of the envelope.
+func envelope(version string, success bool, field string, schema *Schema) *Schema {
+	envelope := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"success": {Type: "boolean", Const: success},
+			field:     schema,
+		},
+		Required: []string{"success", field},
+	}
+
+	if version != "" {
+		envelope.Properties["version"] = &Schema{Type: "string", Const: version}
+		envelope.Required = append([]string{"version"}, envelope.Required...)
+	}
+	return envelope
+}
+
+// errorsByStatus returns the codes of the errors by their status.
+func errorsByStatus(errs []interface{}) map[int][]string {
+	byStatus := make(map[int][]string)
+	seen := make(map[string]bool)
+
+	var add func(v reflect.Value)
+	add = func(v reflect.Value) {
+		if !v.IsValid() {
+			return
+		}
+
+		if aerr, ok := v.Interface().(errapi.Error); ok {
+			if aerr == nil {
+				return
+			}
+
+			key := strconv.Itoa(aerr.Status()) + aerr.Code()
+			if !seen[key] {
+				seen[key] = true
+				byStatus[aerr.Status()] = append(byStatus[aerr.Status()], aerr.Code())
+			}
+			return
+		}
+
+		switch v.Kind() {
+		case reflect.Interface, reflect.Pointer:
+			add(v.Elem())
+		case reflect.Struct:
+			for i := 0; i < v.NumField(); i++ {
+				if v.Type().Field(i).IsExported() {
+					add(v.Field(i))
+				}
+			}
+		case reflect.Slice:
+			for i := 0; i < v.Len(); i++ {
+				add(v.Index(i))
+			}
+		}
+	}
+
+	for _, err := range errs {
+		add(reflect.ValueOf(err))
+	}
+
+	for status := range byStatus {
+		sort.Strings(byStatus[status])
+	}
+	return byStatus
+}
+
+func stringsToEnum(values []string) []interface{} {
+	enum := make([]interface{}, len(values))
+	for i, value := range values {
+		enum[i] = value
+	}
+	return enum
+}
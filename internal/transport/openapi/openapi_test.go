@@ -0,0 +1,190 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/handler"
+	"github.com/koraygocmen/null"
+)
+
+type Embedded struct {
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type Params struct {
+	Embedded
+	Name     null.String    `json:"name"`
+	Tags     []string       `json:"tags"`
+	Labels   map[string]int `json:"labels"`
+	Data     []byte         `json:"data"`
+	Parent   *Params        `json:"parent"`
+	Secret   string         `json:"-"`
+	Internal string         // Not described without the tag.
+	Anything interface{}    `json:"anything,omitempty"`
+}
+
+var (
+	errTest = struct {
+		NameMissing errapi.Error
+		NameExists  errapi.Error
+	}{
+		NameMissing: errapi.New(fiber.StatusBadRequest, "testNameMissing"),
+		NameExists:  errapi.New(fiber.StatusConflict, "testNameExists"),
+	}
+)
+
+func TestSchema(t *testing.T) {
+	g := newGenerator()
+
+	// Test named structs are referenced.
+	schema := g.schema(reflect.TypeOf(&Params{}))
+	if want := "#/components/schemas/OpenapiParams"; schema.Ref != want {
+		t.Fatalf("want: ref %s; got: %s", want, schema.Ref)
+	}
+
+	schema = g.schemas["OpenapiParams"]
+	tests := map[string]Schema{
+		"createdAt": {Type: "string", Format: "date-time"},
+		"name":      {Type: []string{"string", "null"}},
+		"data":      {Type: "string", Format: "byte"},
+		"parent":    {Ref: "#/components/schemas/OpenapiParams"},
+		"anything":  {},
+	}
+	for name, want := range tests {
+		if got := schema.Properties[name]; got == nil || !reflect.DeepEqual(*got, want) {
+			t.Fatalf("want: property %s = %+v; got: %+v", name, want, got)
+		}
+	}
+
+	if got := schema.Properties["tags"]; got.Type != "array" || got.Items.Type != "string" {
+		t.Fatalf("want: tags array of string; got: %+v", got)
+	}
+	if got := schema.Properties["labels"]; got.Type != "object" || got.AdditionalProperties.Type != "integer" {
+		t.Fatalf("want: labels object of integer; got: %+v", got)
+	}
+
+	// Test the fields without the tags are not described.
+	for _, name := range []string{"Secret", "-", "Internal", "Embedded"} {
+		if _, ok := schema.Properties[name]; ok {
+			t.Fatalf("want: property %s missing; got: described", name)
+		}
+	}
+
+	// Test maps of interfaces are described by their values.
+	schema = g.value(fiber.Map{"params": Params{}, "count": 1})
+	if schema.Properties["params"].Ref == "" || schema.Properties["count"].Type != "integer" {
+		t.Fatalf("want: params ref and count integer; got: %+v", schema.Properties)
+	}
+}
+
+func TestNew(t *testing.T) {
+	d := New(Info{Title: "Test", Version: "1"}, nil, []Route{
+		{Method: fiber.MethodGet, Path: "/docs", Hidden: true},
+		{
+			Method: fiber.MethodPut, Path: "/v1/tests/:id",
+			OperationID: "testUpdate", Tag: "tests", Version: "1",
+			Params: struct {
+				ID int64 `params:"id"`
+			}{},
+			Body:     Params{},
+			Response: fiber.Map{"test": Params{}},
+			Errors:   []interface{}{errTest, []interface{}{errTest.NameMissing}},
+		},
+		{
+			Method: fiber.MethodPost, Path: "/v1/tests/raw",
+			OperationID: "testRaw", Tag: "tests", Version: "1",
+			Response: Params{}, Raw: true,
+		},
+		{
+			Method: fiber.MethodGet, Path: "/v1/tests/events",
+			OperationID: "testEvents", Tag: "tests", Version: "1",
+			Response: Params{}, Raw: true, ResponseType: "text/event-stream",
+		},
+	})
+
+	if _, ok := d.Paths["/docs"]; ok {
+		t.Fatalf("want: hidden route not documented; got: documented")
+	}
+
+	operation := d.Paths["/v1/tests/{id}"]["put"]
+	if operation == nil {
+		t.Fatalf("want: operation put /v1/tests/{id}; got: nil")
+	}
+
+	if len(operation.Parameters) != 1 || operation.Parameters[0].Name != "id" || !operation.Parameters[0].Required {
+		t.Fatalf("want: required id path parameter; got: %+v", operation.Parameters)
+	}
+	if operation.RequestBody.Content[fiber.MIMEApplicationJSON].Schema.Ref == "" {
+		t.Fatalf("want: json request body; got: %+v", operation.RequestBody)
+	}
+
+	// Test the raw routes respond without the envelope.
+	raw := d.Paths["/v1/tests/raw"]["post"]
+	if schema := raw.Responses["200"].Content[fiber.MIMEApplicationJSON].Schema; schema.Ref == "" {
+		t.Fatalf("want: raw response schema ref; got: %+v", schema)
+	}
+
+	// Test the response type of the routes.
+	events := d.Paths["/v1/tests/events"]["get"]
+	if _, ok := events.Responses["200"].Content["text/event-stream"]; !ok {
+		t.Fatalf("want: event stream response; got: %+v", events.Responses["200"].Content)
+	}
+
+	// Test the codes of the errors are grouped by their status.
+	tests := map[string][]interface{}{
+		"400": {"testNameMissing"},
+		"409": {"testNameExists"},
+	}
+	for status, want := range tests {
+		response := operation.Responses[status]
+		if response == nil {
+			t.Fatalf("want: response %s; got: nil", status)
+		}
+
+		code := response.Content[handler.MIMEApplicationProblemJSON].Schema.AllOf[1].Properties["code"]
+		if !reflect.DeepEqual(code.Enum, want) {
+			t.Fatalf("want: response %s codes %v; got: %v", status, want, code.Enum)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	d := New(Info{Title: "Test", Version: "1"}, nil, nil)
+
+	app := fiber.New()
+	app.Get("/openapi.json", d.Handler())
+	app.Get("/docs", d.Docs("/openapi.json"))
+
+	// Test the document.
+	res, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/openapi.json", nil))
+	if err != nil {
+		t.Fatalf("want: test err nil; got: err = %v", err)
+	}
+
+	var document Document
+	if err := json.NewDecoder(res.Body).Decode(&document); err != nil {
+		t.Fatalf("want: decode err nil; got: err = %v", err)
+	}
+	if document.OpenAPI != Version || document.Info.Title != "Test" {
+		t.Fatalf("want: openapi %s titled Test; got: %+v", Version, document)
+	}
+
+	// Test the docs ui.
+	res, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/docs", nil))
+	if err != nil {
+		t.Fatalf("want: test err nil; got: err = %v", err)
+	}
+
+	body, _ := io.ReadAll(res.Body)
+	if !strings.Contains(string(body), `spec-url="/openapi.json"`) {
+		t.Fatalf("want: docs of /openapi.json; got: %s", body)
+	}
+}
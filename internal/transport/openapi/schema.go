@@ -0,0 +1,223 @@
+package openapi
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/handler"
+	"github.com/koraygocmen/golang-boilerplate/pkg/str"
+	"github.com/koraygocmen/null"
+)
+
+var (
+	errorType      = reflect.TypeOf((*errapi.Error)(nil)).Elem()
+	fieldErrorType = reflect.TypeOf(errapi.FieldError{})
+	problemType    = reflect.TypeOf(handler.Problem{})
+
+	// types are the schemas of the types marshaled as another type.
+	types = map[reflect.Type]Schema{
+		reflect.TypeOf(time.Time{}):   {Type: "string", Format: "date-time"},
+		reflect.TypeOf(null.String{}): {Type: []string{"string", "null"}},
+		reflect.TypeOf(null.Bool{}):   {Type: []string{"boolean", "null"}},
+		reflect.TypeOf(null.Int{}):    {Type: []string{"integer", "null"}, Format: "int64"},
+		reflect.TypeOf(null.Float{}):  {Type: []string{"number", "null"}},
+		reflect.TypeOf(null.Time{}):   {Type: []string{"string", "null"}, Format: "date-time"},
+	}
+
+	// version matches the version packages, i.e. "v1".
+	version = regexp.MustCompile(`^v[0-9]+$`)
+)
+
+// Schema is the JSON schema of a value, the type is either the
+// name of the type or the names of the types of nullable values.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Const                interface{}        `json:"const,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+}
+
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// generator generates the schemas of the types, the named structs
+// are added to the components and referenced by their names.
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newGenerator() *generator {
+	g := &generator{
+		schemas: make(map[string]*Schema),
+		names: map[reflect.Type]string{
+			errorType:      "Error",
+			fieldErrorType: "FieldError",
+			problemType:    "Problem",
+		},
+	}
+
+	// The errors are marshaled by themselves.
+	g.schemas["FieldError"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"field":   {Type: "string"},
+			"code":    {Type: "string"},
+			"message": {Type: "string"},
+		},
+		Required: []string{"field", "code", "message"},
+	}
+	g.schemas["Error"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"code":    {Type: "string"},
+			"message": {Type: "string"},
+			"errors":  {Type: "array", Items: ref("FieldError")},
+		},
+		Required: []string{"code", "message"},
+	}
+
+	return g
+}
+
+// value returns the schema of the value, the maps of interfaces
+// such as fiber.Map are described by the values they have.
+func (g *generator) value(v interface{}) *Schema {
+	if v == nil {
+		return &Schema{}
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map || rv.Type().Elem().Kind() != reflect.Interface {
+		return g.schema(rv.Type())
+	}
+
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema, rv.Len())}
+	for _, key := range rv.MapKeys() {
+		schema.Properties[key.String()] = g.value(rv.MapIndex(key).Interface())
+	}
+	return schema
+}
+
+// schema returns the schema of the type as it is marshaled to json.
+func (g *generator) schema(t reflect.Type) *Schema {
+	if schema, ok := types[t]; ok {
+		return &schema
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schema(t.Elem())
+	case reflect.Interface:
+		if name, ok := g.names[t]; ok {
+			return ref(name)
+		}
+		return &Schema{}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		// Bytes are marshaled as base64.
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.component(t)
+	}
+
+	return &Schema{}
+}
+
+// component adds the schema of the struct to the components, the
+// anonymous structs are not named and returned as they are.
+func (g *generator) component(t reflect.Type) *Schema {
+	name := g.name(t)
+	if name == "" {
+		return g.object(t)
+	}
+
+	if _, ok := g.schemas[name]; !ok {
+		// Set before generating for the recursive types.
+		g.schemas[name] = &Schema{}
+		*g.schemas[name] = *g.object(t)
+	}
+	return ref(name)
+}
+
+// name returns the name of the struct prefixed with its package,
+// unless it is prefixed already, i.e. "User" and "UserV1CreateParams".
+func (g *generator) name(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	if t.Name() == "" {
+		return ""
+	}
+
+	pieces := strings.Split(t.PkgPath(), "/")
+	pkg := pieces[len(pieces)-1]
+	if version.MatchString(pkg) && len(pieces) > 1 {
+		pkg = pieces[len(pieces)-2] + "_" + pkg
+	}
+
+	name := t.Name()
+	if prefix := str.SnakeToCamel(pkg); !strings.HasPrefix(name, prefix) {
+		name = prefix + name
+	}
+
+	g.names[t] = name
+	return name
+}
+
+// object returns the schema of the fields of the struct with the json
+// tags, the fields without the tags are internal and not described.
+// The fields are not required, the params are validated by the services.
+func (g *generator) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.fields(t, schema)
+	return schema
+}
+
+func (g *generator) fields(t reflect.Type, schema *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+
+		// Embedded structs without the tags are flattened.
+		if field.Anonymous && name == "" {
+			if ft := field.Type; ft.Kind() == reflect.Struct {
+				g.fields(ft, schema)
+			}
+			continue
+		}
+
+		if name == "" || !field.IsExported() {
+			continue
+		}
+		schema.Properties[name] = g.schema(field.Type)
+	}
+}
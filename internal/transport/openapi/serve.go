@@ -0,0 +1,54 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	// docs is the page of the docs ui, the ui is loaded from the cdn.
+	docs = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html>
+  <head>
+    <meta charset="utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>{{ .Title }}</title>
+  </head>
+  <body>
+    <redoc spec-url="{{ .URL }}"></redoc>
+    <script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>
+  </body>
+</html>
+`))
+)
+
+// Handler responds the document, it is marshaled once
+// since the routes do not change once the app starts.
+func (d *Document) Handler() fiber.Handler {
+	body, err := json.Marshal(d)
+	if err != nil {
+		panic(fmt.Sprintf("openapi marshal error: %v", err))
+	}
+
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
+		return c.Send(body)
+	}
+}
+
+// Docs responds the page of the docs ui of the document at the url.
+func (d *Document) Docs(url string) fiber.Handler {
+	var body strings.Builder
+	if err := docs.Execute(&body, map[string]string{"Title": d.Info.Title, "URL": url}); err != nil {
+		panic(fmt.Sprintf("openapi docs error: %v", err))
+	}
+
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.SendString(body.String())
+	}
+}
@@ -1,9 +1,12 @@
 package response_v1
 
 import (
+	"encoding/json"
+
 	"github.com/koraygocmen/golang-boilerplate/internal/context"
 	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
 	"github.com/koraygocmen/golang-boilerplate/internal/errhandle"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/handler"
 )
 
 type ApiResponse struct {
@@ -16,6 +19,19 @@ type ApiResponseBody struct {
 	Success bool         `json:"success"`
 	Body    interface{}  `json:"body,omitempty"`
 	Error   errapi.Error `json:"error,omitempty"`
+
+	// Problem replaces the response body if the
+	// client accepts the errors as problem details.
+	problem *handler.Problem
+}
+
+func (b ApiResponseBody) MarshalJSON() ([]byte, error) {
+	if b.problem != nil {
+		return json.Marshal(b.problem)
+	}
+
+	type apiResponseBody ApiResponseBody
+	return json.Marshal(apiResponseBody(b))
 }
 
 func (r *Response) Success(ctx context.Ctx, body interface{}) ApiResponseBody {
@@ -28,9 +44,14 @@ func (r *Response) Success(ctx context.Ctx, body interface{}) ApiResponseBody {
 
 func (r *Response) Failure(ctx context.Ctx, err error, aerr errapi.Error) ApiResponseBody {
 	errhandle.Handle(ctx, aerr, err, false)
-	return ApiResponseBody{
+	body := ApiResponseBody{
 		Version: "1",
 		Success: false,
 		Error:   aerr.Localize(ctx),
 	}
+
+	if handler.IsProblem(ctx) {
+		body.problem = r.Problem(ctx, body.Error)
+	}
+	return body
 }
@@ -0,0 +1,284 @@
+package router
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	User "github.com/koraygocmen/golang-boilerplate/internal/model/user"
+	UserPreference "github.com/koraygocmen/golang-boilerplate/internal/model/user_preference"
+	UserSession "github.com/koraygocmen/golang-boilerplate/internal/model/user_session"
+	WebhookDelivery "github.com/koraygocmen/golang-boilerplate/internal/model/webhook_delivery"
+	WebhookSubscription "github.com/koraygocmen/golang-boilerplate/internal/model/webhook_subscription"
+	"github.com/koraygocmen/golang-boilerplate/internal/realtime"
+	"github.com/koraygocmen/golang-boilerplate/internal/service"
+	IdempotencyKeyServiceV1 "github.com/koraygocmen/golang-boilerplate/internal/service/idempotency_key/v1"
+	UserServiceV1 "github.com/koraygocmen/golang-boilerplate/internal/service/user/v1"
+	UserPreferenceServiceV1 "github.com/koraygocmen/golang-boilerplate/internal/service/user_preference/v1"
+	UserSessionServiceV1 "github.com/koraygocmen/golang-boilerplate/internal/service/user_session/v1"
+	WebhookServiceV1 "github.com/koraygocmen/golang-boilerplate/internal/service/webhook/v1"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/graphql"
+	v1Admin "github.com/koraygocmen/golang-boilerplate/internal/transport/handler/admin/v1"
+	v1User "github.com/koraygocmen/golang-boilerplate/internal/transport/handler/user/v1"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/header"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/openapi"
+	"github.com/koraygocmen/golang-boilerplate/internal/validate"
+	"github.com/koraygocmen/null"
+)
+
+const (
+	PathOpenAPI = "/openapi.json"
+	PathDocs    = "/docs"
+
+	securityUser  = "userSession"
+	securityAdmin = "adminToken"
+)
+
+var (
+	// Errors shared by the routes.
+	errorsCommon      = []interface{}{service.ErrInternalServer, service.ErrTooManyRequests}
+	errorsVersion     = []interface{}{service.ErrVersion}
+	errorsBody        = []interface{}{validate.ErrBody}
+	errorsParams      = []interface{}{service.ErrUrlParamInvalid}
+	errorsIdempotency = []interface{}{IdempotencyKeyServiceV1.ErrClaim}
+	errorsUserAuth    = []interface{}{service.ErrUserAuth, UserSessionServiceV1.ErrGet, UserServiceV1.ErrGet.UserNotFound}
+	errorsAdminAuth   = []interface{}{service.ErrAdminAuth, service.ErrNotFound}
+
+	// Headers of the routes.
+	headerIdempotencyKey = openapi.Header{
+		Name:        header.HeaderIdempotencyKey,
+		Description: "The first response of the requests with the same key is replayed.",
+	}
+	headerIfMatch = openapi.Header{
+		Name:        fiber.HeaderIfMatch,
+		Description: "The ETag of the user, the user is only updated if it is not modified.",
+	}
+
+	// Params of the routes.
+	paramsID = struct {
+		ID int64 `params:"id"`
+	}{}
+
+	logSettings = fiber.Map{
+		"log": fiber.Map{
+			"level":       null.Int{},
+			"routeLevels": []logger.RouteLevel{},
+			"sampleRules": []logger.SampleRule{},
+		},
+	}
+
+	securitySchemes = map[string]*openapi.SecurityScheme{
+		securityUser: {
+			Type:        "apiKey",
+			Description: "The user session id and token joined with a dash, i.e. \"1-token\".",
+			Name:        fiber.HeaderAuthorization,
+			In:          "header",
+		},
+		securityAdmin: {
+			Type:        "apiKey",
+			Description: "The admin token.",
+			Name:        fiber.HeaderAuthorization,
+			In:          "header",
+		},
+	}
+)
+
+// Routes describes the routes registered in Setup, the
+// document is generated from them. A route missing here
+// fails the tests of the router.
+var Routes = []openapi.Route{
+	// Docs ui.
+	{Method: fiber.MethodGet, Path: PathDocs, Hidden: true},
+
+	// Shared endpoints.
+	{
+		Method: fiber.MethodGet, Path: "/health",
+		OperationID: "health", Summary: "Check the health of the api.", Tag: "shared",
+		Response: fiber.Map{"shasum": ""},
+		Errors:   errorsCommon,
+	},
+	{
+		Method: fiber.MethodGet, Path: PathOpenAPI,
+		OperationID: "openapi", Summary: "Get the OpenAPI document of the api.", Tag: "shared",
+		Response: map[string]interface{}{},
+		Errors:   errorsCommon,
+	},
+
+	// Users.
+	{
+		Method: fiber.MethodPost, Path: "/v1/users",
+		OperationID: "userCreate", Summary: "Create a user.", Tag: "users", Version: "1",
+		Headers: []openapi.Header{headerIdempotencyKey},
+		Body:    UserServiceV1.CreateParams{},
+		Status:  fiber.StatusCreated, Response: fiber.Map{"user": User.User{}},
+		Errors: []interface{}{errorsCommon, errorsVersion, errorsBody, errorsIdempotency,
+			UserServiceV1.ErrCreate, UserServiceV1.ErrValidateParams},
+	},
+	{
+		Method: fiber.MethodGet, Path: "/v1/users",
+		OperationID: "userGet", Summary: "Get the authenticated user.", Tag: "users", Version: "1", Security: securityUser,
+		Response: fiber.Map{"user": User.User{}},
+		Errors:   []interface{}{errorsCommon, errorsVersion, errorsUserAuth},
+	},
+	{
+		Method: fiber.MethodPut, Path: "/v1/users",
+		OperationID: "userUpdate", Summary: "Replace the authenticated user, the names left out are cleared.", Tag: "users", Version: "1", Security: securityUser,
+		Headers:  []openapi.Header{headerIfMatch},
+		Body:     UserServiceV1.UpdateParams{},
+		Response: fiber.Map{"user": User.User{}},
+		Errors: []interface{}{errorsCommon, errorsVersion, errorsUserAuth, errorsBody,
+			UserServiceV1.ErrUpdate, UserServiceV1.ErrValidateParams},
+	},
+	{
+		Method: fiber.MethodPatch, Path: "/v1/users",
+		OperationID: "userPatch", Summary: "Patch the authenticated user with a JSON merge patch document.", Tag: "users", Version: "1", Security: securityUser,
+		Headers:   []openapi.Header{headerIfMatch},
+		Body:      UserServiceV1.UpdateParams{},
+		BodyTypes: []string{v1User.MIMEApplicationMergePatchJSON, fiber.MIMEApplicationJSON},
+		Response:  fiber.Map{"user": User.User{}},
+		Errors: []interface{}{errorsCommon, errorsVersion, errorsUserAuth, service.ErrUnsupportedMediaType,
+			UserServiceV1.ErrPatch, UserServiceV1.ErrUpdate, UserServiceV1.ErrValidateParams},
+	},
+	{
+		Method: fiber.MethodDelete, Path: "/v1/users",
+		OperationID: "userDelete", Summary: "Delete the authenticated user.", Tag: "users", Version: "1", Security: securityUser,
+		Response: fiber.Map{"user": User.User{}},
+		Errors:   []interface{}{errorsCommon, errorsVersion, errorsUserAuth, UserServiceV1.ErrDelete},
+	},
+
+	// User Preferences.
+	{
+		Method: fiber.MethodGet, Path: "/v1/users/preferences",
+		OperationID: "userPreferenceGet", Summary: "Get the preferences of the authenticated user.", Tag: "users", Version: "1", Security: securityUser,
+		Response: fiber.Map{"preferences": UserPreference.UserPreference{}},
+		Errors:   []interface{}{errorsCommon, errorsVersion, errorsUserAuth, UserPreferenceServiceV1.ErrGet},
+	},
+	{
+		Method: fiber.MethodPut, Path: "/v1/users/preferences",
+		OperationID: "userPreferenceUpdate", Summary: "Update the preferences of the authenticated user.", Tag: "users", Version: "1", Security: securityUser,
+		Body:     UserPreferenceServiceV1.UpdateParams{},
+		Response: fiber.Map{"preferences": UserPreference.UserPreference{}},
+		Errors: []interface{}{errorsCommon, errorsVersion, errorsUserAuth, errorsBody,
+			UserPreferenceServiceV1.ErrUpdate, UserPreferenceServiceV1.ErrValidateParams},
+	},
+
+	// User Sessions.
+	{
+		Method: fiber.MethodPost, Path: "/v1/users/sessions",
+		OperationID: "userSessionCreate", Summary: "Create a user session.", Tag: "users", Version: "1",
+		Headers: []openapi.Header{headerIdempotencyKey},
+		Body:    UserSessionServiceV1.CreateParams{},
+		Status:  fiber.StatusCreated, Response: fiber.Map{"userSession": UserSession.UserSession{}},
+		Errors: []interface{}{errorsCommon, errorsVersion, errorsBody, errorsIdempotency, UserSessionServiceV1.ErrCreate},
+	},
+	{
+		Method: fiber.MethodDelete, Path: "/v1/users/sessions",
+		OperationID: "userSessionDelete", Summary: "Delete the authenticated user session.", Tag: "users", Version: "1", Security: securityUser,
+		Response: fiber.Map{"userSession": UserSession.UserSession{}},
+		Errors:   []interface{}{errorsCommon, errorsVersion, errorsUserAuth, UserSessionServiceV1.ErrDelete},
+	},
+	{
+		Method: fiber.MethodGet, Path: "/v1/users/sessions/events",
+		OperationID: "userSessionEvents", Summary: "Stream the events of the authenticated user session as server sent events.", Tag: "users", Version: "1", Security: securityUser,
+		Response: realtime.Event{}, Raw: true, ResponseType: realtime.MIMETextEventStream,
+		Errors: []interface{}{errorsCommon, errorsVersion, errorsUserAuth},
+	},
+
+	// GraphQL, the errors of the queries are in the errors of the
+	// result. The versioned path is an alias that is not documented.
+	{Method: fiber.MethodPost, Path: "/v1/graphql", Hidden: true},
+	{
+		Method: fiber.MethodPost, Path: "/graphql",
+		OperationID: "graphqlQuery", Summary: "Query the authenticated user and the sessions with GraphQL.", Tag: "graphql", Version: "1", Security: securityUser,
+		Body:     graphql.Params{},
+		Response: graphql.Result{}, Raw: true,
+		Errors: []interface{}{errorsCommon, errorsVersion, errorsUserAuth, errorsBody},
+	},
+
+	// Admin log.
+	{
+		Method: fiber.MethodGet, Path: "/v1/admin/log",
+		OperationID: "adminLogGet", Summary: "Get the runtime log settings.", Tag: "admin", Version: "1", Security: securityAdmin,
+		Response: logSettings,
+		Errors:   []interface{}{errorsCommon, errorsVersion, errorsAdminAuth},
+	},
+	{
+		Method: fiber.MethodPut, Path: "/v1/admin/log/level",
+		OperationID: "adminLogLevelUpdate", Summary: "Override the global log level.", Tag: "admin", Version: "1", Security: securityAdmin,
+		Body:     v1Admin.LogLevelParams{},
+		Response: logSettings,
+		Errors:   []interface{}{errorsCommon, errorsVersion, errorsAdminAuth, errorsBody, v1Admin.ErrLog.LevelInvalid},
+	},
+	{
+		Method: fiber.MethodDelete, Path: "/v1/admin/log/level",
+		OperationID: "adminLogLevelReset", Summary: "Reset the global log level.", Tag: "admin", Version: "1", Security: securityAdmin,
+		Response: logSettings,
+		Errors:   []interface{}{errorsCommon, errorsVersion, errorsAdminAuth},
+	},
+	{
+		Method: fiber.MethodPut, Path: "/v1/admin/log/routes",
+		OperationID: "adminLogRouteLevelsUpdate", Summary: "Set the route log levels.", Tag: "admin", Version: "1", Security: securityAdmin,
+		Body:     v1Admin.LogRouteLevelsParams{},
+		Response: logSettings,
+		Errors:   []interface{}{errorsCommon, errorsVersion, errorsAdminAuth, errorsBody, v1Admin.ErrLog.RouteLevelsInvalid},
+	},
+	{
+		Method: fiber.MethodPut, Path: "/v1/admin/log/sampling",
+		OperationID: "adminLogSampleRulesUpdate", Summary: "Set the log sample rules.", Tag: "admin", Version: "1", Security: securityAdmin,
+		Body:     v1Admin.LogSampleRulesParams{},
+		Response: logSettings,
+		Errors:   []interface{}{errorsCommon, errorsVersion, errorsAdminAuth, errorsBody, v1Admin.ErrLog.SampleRulesInvalid},
+	},
+
+	// Admin webhooks.
+	{
+		Method: fiber.MethodPost, Path: "/v1/admin/webhooks",
+		OperationID: "webhookCreate", Summary: "Create a webhook subscription.", Tag: "admin", Version: "1", Security: securityAdmin,
+		Body:   WebhookServiceV1.CreateParams{},
+		Status: fiber.StatusCreated, Response: fiber.Map{"webhook": WebhookSubscription.WebhookSubscription{}, "secret": ""},
+		Errors: []interface{}{errorsCommon, errorsVersion, errorsAdminAuth, errorsBody,
+			WebhookServiceV1.ErrCreate, WebhookServiceV1.ErrValidateParams},
+	},
+	{
+		Method: fiber.MethodGet, Path: "/v1/admin/webhooks",
+		OperationID: "webhookList", Summary: "List the webhook subscriptions.", Tag: "admin", Version: "1", Security: securityAdmin,
+		Response: fiber.Map{"webhooks": []WebhookSubscription.WebhookSubscription{}},
+		Errors:   []interface{}{errorsCommon, errorsVersion, errorsAdminAuth},
+	},
+	{
+		Method: fiber.MethodPut, Path: "/v1/admin/webhooks/:id",
+		OperationID: "webhookUpdate", Summary: "Update a webhook subscription.", Tag: "admin", Version: "1", Security: securityAdmin,
+		Params:   paramsID,
+		Body:     WebhookServiceV1.UpdateParams{},
+		Response: fiber.Map{"webhook": WebhookSubscription.WebhookSubscription{}},
+		Errors: []interface{}{errorsCommon, errorsVersion, errorsAdminAuth, errorsParams, errorsBody,
+			WebhookServiceV1.ErrUpdate, WebhookServiceV1.ErrValidateParams},
+	},
+	{
+		Method: fiber.MethodDelete, Path: "/v1/admin/webhooks/:id",
+		OperationID: "webhookDelete", Summary: "Delete a webhook subscription.", Tag: "admin", Version: "1", Security: securityAdmin,
+		Params:   paramsID,
+		Response: fiber.Map{"webhook": WebhookSubscription.WebhookSubscription{}},
+		Errors:   []interface{}{errorsCommon, errorsVersion, errorsAdminAuth, errorsParams, WebhookServiceV1.ErrDelete},
+	},
+	{
+		Method: fiber.MethodGet, Path: "/v1/admin/webhooks/:id/deliveries",
+		OperationID: "webhookDeliveryList", Summary: "List the deliveries of a webhook subscription.", Tag: "admin", Version: "1", Security: securityAdmin,
+		Params:   paramsID,
+		Query:    WebhookServiceV1.DeliveryListParams{},
+		Response: fiber.Map{"deliveries": []WebhookDelivery.WebhookDelivery{}},
+		Errors:   []interface{}{errorsCommon, errorsVersion, errorsAdminAuth, errorsParams, WebhookServiceV1.ErrDeliveryList},
+	},
+	{
+		Method: fiber.MethodPost, Path: "/v1/admin/webhooks/deliveries/:id/redeliver",
+		OperationID: "webhookDeliveryRedeliver", Summary: "Redeliver a webhook delivery.", Tag: "admin", Version: "1", Security: securityAdmin,
+		Params:   paramsID,
+		Response: fiber.Map{"delivery": WebhookDelivery.WebhookDelivery{}},
+		Errors:   []interface{}{errorsCommon, errorsVersion, errorsAdminAuth, errorsParams, WebhookServiceV1.ErrDeliveryRedeliver},
+	},
+}
+
+// Document is the OpenAPI document of the routes.
+var Document = openapi.New(openapi.Info{
+	Title:       "API",
+	Description: "The errors are responded as problem details if the client accepts application/problem+json.",
+	Version:     "1",
+}, securitySchemes, Routes)
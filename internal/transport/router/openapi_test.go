@@ -0,0 +1,68 @@
+package router
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/handler"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/openapi"
+)
+
+func TestRoutes(t *testing.T) {
+	app := fiber.New()
+	Setup(app, handler.New(handler.Config{OpenAPIDocs: true}))
+
+	routes := make(map[string]bool)
+	for _, route := range Routes {
+		routes[route.Method+" "+route.Path] = true
+	}
+
+	// Test the routes registered are described, the head
+	// routes are registered by fiber for the get routes.
+	registered := make(map[string]bool)
+	for _, route := range app.GetRoutes(true) {
+		if route.Method == fiber.MethodHead {
+			continue
+		}
+
+		key := route.Method + " " + route.Path
+		registered[key] = true
+		if !routes[key] {
+			t.Fatalf("want: route %s in the openapi routes; got: missing", key)
+		}
+	}
+
+	// Test the routes described are registered.
+	for key := range routes {
+		if !registered[key] {
+			t.Fatalf("want: route %s registered; got: missing", key)
+		}
+	}
+
+	// Test the routes are in the document unless hidden.
+	for _, route := range Routes {
+		operation := Document.Paths[openapi.Path(route.Path)][strings.ToLower(route.Method)]
+		if route.Hidden != (operation == nil) {
+			t.Fatalf("want: route %s %s documented = %t; got: %t", route.Method, route.Path, !route.Hidden, operation != nil)
+		}
+	}
+}
+
+func TestDocument(t *testing.T) {
+	if _, err := json.Marshal(Document); err != nil {
+		t.Fatalf("want: marshal err nil; got: err = %v", err)
+	}
+
+	// Test the operation ids are unique.
+	ids := make(map[string]bool)
+	for path, pathItem := range Document.Paths {
+		for method, operation := range pathItem {
+			if operation.OperationID == "" || ids[operation.OperationID] {
+				t.Fatalf("want: unique operation id of %s %s; got: %q", method, path, operation.OperationID)
+			}
+			ids[operation.OperationID] = true
+		}
+	}
+}
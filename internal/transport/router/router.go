@@ -2,9 +2,15 @@ package router
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/ratelimit"
 	"github.com/koraygocmen/golang-boilerplate/internal/transport/handler"
+	v1Admin "github.com/koraygocmen/golang-boilerplate/internal/transport/handler/admin/v1"
+	v1GraphQL "github.com/koraygocmen/golang-boilerplate/internal/transport/handler/graphql/v1"
 	v1User "github.com/koraygocmen/golang-boilerplate/internal/transport/handler/user/v1"
+	v1UserPreference "github.com/koraygocmen/golang-boilerplate/internal/transport/handler/user_preference/v1"
 	v1UserSession "github.com/koraygocmen/golang-boilerplate/internal/transport/handler/user_session/v1"
+	v1Webhook "github.com/koraygocmen/golang-boilerplate/internal/transport/handler/webhook/v1"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/middleware"
 	v1Middleware "github.com/koraygocmen/golang-boilerplate/internal/transport/middleware/v1"
 	v1 "github.com/koraygocmen/golang-boilerplate/internal/transport/response/v1"
 )
@@ -18,8 +24,12 @@ func Setup(app *fiber.App, handler *handler.Handler) {
 	v1Middleware := v1Middleware.New(v1Response)
 
 	// V1 Handlers.
+	v1AdminHandler := v1Admin.New(v1Response)
+	v1GraphQLHandler := v1GraphQL.New(v1Response)
 	v1UserHandler := v1User.New(v1Response)
+	v1UserPreferenceHandler := v1UserPreference.New(v1Response)
 	v1UserSessionHandler := v1UserSession.New(v1Response)
+	v1WebhookHandler := v1Webhook.New(v1Response)
 
 	// Static files.
 	app.Static("/", "./public")
@@ -27,22 +37,58 @@ func Setup(app *fiber.App, handler *handler.Handler) {
 	// Shared endpoints.
 	app.Get("/health", handler.Health)
 
+	// OpenAPI document and the docs ui.
+	app.Get(PathOpenAPI, Document.Handler())
+	if handler.OpenAPIDocs {
+		app.Get(PathDocs, Document.Docs(PathOpenAPI))
+	}
+
 	// Users.
-	app.Post("/v1/users", v1UserHandler.Create) // Create a user.
+	app.Post("/v1/users", v1Middleware.Idempotency, v1UserHandler.Create) // Create a user.
 
 	// User Sessions.
-	app.Post("/v1/users/sessions", v1UserSessionHandler.Create) // Create a user session.
+	app.Post("/v1/users/sessions", v1Middleware.Idempotency, v1UserSessionHandler.Create) // Create a user session.
+
+	// Requests that require the admin to be authenticated.
+	v1AdminApp := app.Group("/v1/admin", v1Middleware.AdminAuth)
+	{
+		// Log.
+		v1AdminApp.Get("/log", v1AdminHandler.LogGet)                        // Get the runtime log settings.
+		v1AdminApp.Put("/log/level", v1AdminHandler.LogLevelUpdate)          // Override the global log level.
+		v1AdminApp.Delete("/log/level", v1AdminHandler.LogLevelReset)        // Reset the global log level.
+		v1AdminApp.Put("/log/routes", v1AdminHandler.LogRouteLevelsUpdate)   // Set the route log levels.
+		v1AdminApp.Put("/log/sampling", v1AdminHandler.LogSampleRulesUpdate) // Set the log sample rules.
 
-	// Requests that require the user to be authenticated.
-	v1AuthApp := app.Use(v1Middleware.UserAuth)
+		// Webhooks.
+		v1AdminApp.Post("/webhooks", v1WebhookHandler.Create)                                     // Create a webhook subscription.
+		v1AdminApp.Get("/webhooks", v1WebhookHandler.List)                                        // List the webhook subscriptions.
+		v1AdminApp.Put("/webhooks/:id", v1WebhookHandler.Update)                                  // Update a webhook subscription.
+		v1AdminApp.Delete("/webhooks/:id", v1WebhookHandler.Delete)                               // Delete a webhook subscription.
+		v1AdminApp.Get("/webhooks/:id/deliveries", v1WebhookHandler.DeliveryList)                 // List the deliveries of a webhook subscription.
+		v1AdminApp.Post("/webhooks/deliveries/:id/redeliver", v1WebhookHandler.DeliveryRedeliver) // Redeliver a webhook delivery.
+	}
+
+	// Requests that require the user to be authenticated,
+	// the user policies are limited once the user is known.
+	v1AuthApp := app.Use(v1Middleware.UserAuth, middleware.RateLimit(ratelimit.SubjectUser))
 	{
 		// Users.
 		v1AuthApp.Get("/v1/users", v1UserHandler.Get)       // Get authenticated user.
 		v1AuthApp.Put("/v1/users", v1UserHandler.Update)    // Update authenticated user.
+		v1AuthApp.Patch("/v1/users", v1UserHandler.Patch)   // Patch authenticated user.
 		v1AuthApp.Delete("/v1/users", v1UserHandler.Delete) // Delete authenticated user.
 
+		// User Preferences.
+		v1AuthApp.Get("/v1/users/preferences", v1UserPreferenceHandler.Get)    // Get authenticated user preferences.
+		v1AuthApp.Put("/v1/users/preferences", v1UserPreferenceHandler.Update) // Update authenticated user preferences.
+
 		// User Sessions.
-		v1AuthApp.Delete("/v1/users/sessions", v1UserSessionHandler.Delete) // Delete authenticated user session.
+		v1AuthApp.Delete("/v1/users/sessions", v1UserSessionHandler.Delete)     // Delete authenticated user session.
+		v1AuthApp.Get("/v1/users/sessions/events", v1UserSessionHandler.Events) // Stream authenticated user session events.
+
+		// GraphQL, the versioned path is an alias.
+		v1AuthApp.Post("/graphql", v1GraphQLHandler.Query)    // Query the authenticated user and sessions.
+		v1AuthApp.Post("/v1/graphql", v1GraphQLHandler.Query) // Query the authenticated user and sessions.
 	}
 
 	app.Use(func(c *fiber.Ctx) error {
@@ -0,0 +1,32 @@
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+)
+
+var (
+	ErrBody = struct {
+		RequestBodyInvalid errapi.Error
+		FieldTypeInvalid   errapi.Error
+	}{
+		RequestBodyInvalid: errapi.New(fiber.StatusBadRequest, errapi.ErrCodeRequestBodyInvalid),
+		FieldTypeInvalid:   errapi.New(fiber.StatusBadRequest, errapi.ErrCodeFieldTypeInvalid),
+	}
+)
+
+// Body maps the error of parsing the request body to a bad request,
+// a field of the wrong type is reported with the path of the field.
+func Body(err error) errapi.Error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return errapi.WithFields(ErrBody.RequestBodyInvalid, []errapi.FieldError{
+			{Field: typeErr.Field, Error: ErrBody.FieldTypeInvalid},
+		})
+	}
+
+	return ErrBody.RequestBodyInvalid
+}
@@ -0,0 +1,121 @@
+package validate
+
+import (
+	"unicode/utf8"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/null"
+)
+
+// Validator collects the errors of the fields of the params. Only the
+// first error of a field is kept, so the rules of a field are checked
+// in order and the later rules can assume the earlier ones hold.
+type Validator struct {
+	fields []errapi.FieldError
+	failed map[string]bool
+}
+
+func New() *Validator {
+	return &Validator{
+		failed: make(map[string]bool),
+	}
+}
+
+// Check records the error of the field unless ok, it
+// reports whether the field is still valid.
+func (v *Validator) Check(field string, ok bool, aerr errapi.Error) bool {
+	if v.failed[field] {
+		return false
+	}
+
+	if !ok {
+		v.failed[field] = true
+		v.fields = append(v.fields, errapi.FieldError{Field: field, Error: aerr})
+	}
+	return ok
+}
+
+// Failed reports whether the field has an error.
+func (v *Validator) Failed(field string) bool {
+	return v.failed[field]
+}
+
+// Fields returns the errors of the fields in the order they were checked.
+func (v *Validator) Fields() []errapi.FieldError {
+	return v.fields
+}
+
+// Error returns the error of the first field with the errors of all
+// the fields, so the clients that only read the code keep working.
+// It returns nil if all the fields are valid.
+func (v *Validator) Error() errapi.Error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return errapi.WithFields(v.fields[0].Error, v.fields)
+}
+
+// String starts the rules of a string field. The rules other
+// than Required are skipped when the value is not set.
+func (v *Validator) String(field string, value null.String) *String {
+	return &String{
+		v:     v,
+		field: field,
+		value: value,
+	}
+}
+
+// String is the rule builder of a string field, the value
+// is checked as is so it should be normalized before.
+type String struct {
+	v     *Validator
+	field string
+	value null.String
+}
+
+func (s *String) check(ok func(value string) bool, aerr errapi.Error) *String {
+	if !s.value.Valid {
+		return s
+	}
+
+	s.v.Check(s.field, ok(s.value.String), aerr)
+	return s
+}
+
+// Required fails if the value is not set or empty.
+func (s *String) Required(aerr errapi.Error) *String {
+	s.v.Check(s.field, s.value.Valid && s.value.String != "", aerr)
+	return s
+}
+
+// NotEmpty fails if the value is set and empty.
+func (s *String) NotEmpty(aerr errapi.Error) *String {
+	return s.check(func(value string) bool {
+		return value != ""
+	}, aerr)
+}
+
+// MinLen fails if the value has less than n characters.
+func (s *String) MinLen(n int, aerr errapi.Error) *String {
+	return s.check(func(value string) bool {
+		return utf8.RuneCountInString(value) >= n
+	}, aerr)
+}
+
+// MaxLen fails if the value has more than n characters.
+func (s *String) MaxLen(n int, aerr errapi.Error) *String {
+	return s.check(func(value string) bool {
+		return utf8.RuneCountInString(value) <= n
+	}, aerr)
+}
+
+// Email fails if the value is not an email address.
+func (s *String) Email(aerr errapi.Error) *String {
+	return s.check(govalidator.IsEmail, aerr)
+}
+
+// Func fails if the function does not accept the value.
+func (s *String) Func(ok func(value string) bool, aerr errapi.Error) *String {
+	return s.check(ok, aerr)
+}
@@ -0,0 +1,133 @@
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/null"
+)
+
+var (
+	errMissing = errapi.New(fiber.StatusBadRequest, "missing")
+	errInvalid = errapi.New(fiber.StatusBadRequest, "invalid")
+)
+
+func TestValidator(t *testing.T) {
+	v := New()
+
+	// Not set values are only checked by required.
+	v.String("optional", null.String{}).
+		NotEmpty(errMissing).
+		MinLen(2, errInvalid)
+
+	if aerr := v.Error(); aerr != nil {
+		t.Fatalf("want: aerr nil; got: aerr = %v", aerr)
+	}
+
+	v.String("required", null.String{}).
+		Required(errMissing)
+	v.String("email", null.StringFrom("koray")).
+		NotEmpty(errMissing).
+		Email(errInvalid)
+	v.String("name", null.StringFrom("")).
+		NotEmpty(errMissing).
+		MinLen(2, errInvalid)
+	v.String("surname", null.StringFrom("Göç")).
+		MinLen(3, errInvalid).
+		MaxLen(3, errInvalid)
+	v.Check("events.1", false, errInvalid)
+
+	aerr := v.Error()
+	if !errapi.Is(aerr, "missing") {
+		t.Fatalf("want: aerr = missing; got: aerr = %v", aerr)
+	}
+
+	want := []struct {
+		field string
+		code  string
+	}{
+		{"required", "missing"},
+		{"email", "invalid"},
+		{"name", "missing"},
+		{"events.1", "invalid"},
+	}
+
+	fields := aerr.Fields()
+	if len(fields) != len(want) {
+		t.Fatalf("want: %d field errors; got: %d", len(want), len(fields))
+	}
+	for i, w := range want {
+		if fields[i].Field != w.field || fields[i].Error.Code() != w.code {
+			t.Fatalf("want: %s = %s; got: %s = %s", w.field, w.code, fields[i].Field, fields[i].Error.Code())
+		}
+	}
+
+	if !v.Failed("name") || v.Failed("surname") {
+		t.Fatalf("want: name failed and surname valid; got: name = %t, surname = %t", v.Failed("name"), v.Failed("surname"))
+	}
+
+	// The shared error is not changed.
+	if len(errMissing.Fields()) != 0 {
+		t.Fatalf("want: no field errors on the shared error; got: %d", len(errMissing.Fields()))
+	}
+}
+
+func TestErrorJSON(t *testing.T) {
+	v := New()
+	v.Check("email", false, errInvalid)
+	v.Check("surname", false, errMissing)
+
+	ctx := context.WithValue(context.Background(), context.KeyLang, context.LangEN)
+	marshalled, err := json.Marshal(v.Error().Localize(ctx))
+	if err != nil {
+		t.Fatalf("want: marshal err nil; got: err = %v", err)
+	}
+
+	want := `{"code":"invalid","errors":[{"code":"invalid","field":"email","message":"invalid"},{"code":"missing","field":"surname","message":"missing"}],"message":"invalid"}`
+	if string(marshalled) != want {
+		t.Fatalf("want: %s; got: %s", want, marshalled)
+	}
+
+	// The errors without fields are not changed.
+	marshalled, _ = json.Marshal(errMissing.Localize(ctx))
+	if want := `{"code":"missing","message":"missing"}`; string(marshalled) != want {
+		t.Fatalf("want: %s; got: %s", want, marshalled)
+	}
+}
+
+func TestBody(t *testing.T) {
+	var params struct {
+		Email string `json:"email"`
+		User  struct {
+			Age int `json:"age"`
+		} `json:"user"`
+	}
+
+	// Test malformed body.
+	aerr := Body(json.Unmarshal([]byte(`{"email":`), &params))
+	if !errapi.Is(aerr, errapi.ErrCodeRequestBodyInvalid) || aerr.Status() != fiber.StatusBadRequest {
+		t.Fatalf("want: aerr = %s; got: aerr = %v", errapi.ErrCodeRequestBodyInvalid, aerr)
+	}
+	if len(aerr.Fields()) != 0 {
+		t.Fatalf("want: no field errors; got: %d", len(aerr.Fields()))
+	}
+
+	// Test field of the wrong type.
+	aerr = Body(json.Unmarshal([]byte(`{"user":{"age":"old"}}`), &params))
+	if !errapi.Is(aerr, errapi.ErrCodeRequestBodyInvalid) {
+		t.Fatalf("want: aerr = %s; got: aerr = %v", errapi.ErrCodeRequestBodyInvalid, aerr)
+	}
+	fields := aerr.Fields()
+	if len(fields) != 1 || fields[0].Field != "user.age" || fields[0].Error.Code() != errapi.ErrCodeFieldTypeInvalid {
+		t.Fatalf("want: user.age = %s; got: %+v", errapi.ErrCodeFieldTypeInvalid, fields)
+	}
+
+	// Test other errors.
+	if aerr := Body(errors.New("unprocessable")); !errapi.Is(aerr, errapi.ErrCodeRequestBodyInvalid) {
+		t.Fatalf("want: aerr = %s; got: aerr = %v", errapi.ErrCodeRequestBodyInvalid, aerr)
+	}
+}
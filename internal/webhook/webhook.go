@@ -0,0 +1,330 @@
+package webhook
+
+import (
+	"bytes"
+	goctx "context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	WebhookDelivery "github.com/koraygocmen/golang-boilerplate/internal/model/webhook_delivery"
+	WebhookSubscription "github.com/koraygocmen/golang-boilerplate/internal/model/webhook_subscription"
+	"github.com/koraygocmen/golang-boilerplate/internal/repo"
+	"github.com/koraygocmen/golang-boilerplate/pkg/duration"
+	"github.com/koraygocmen/null"
+)
+
+const (
+	MaxAttemptsDefault = 8
+	BackoffBaseDefault = 30 * time.Second
+	BackoffMaxDefault  = 6 * time.Hour
+	PollDefault        = 10 * time.Second
+	TimeoutDefault     = 10 * time.Second
+	BatchSizeDefault   = 20
+
+	// HeaderSignature carries the timestamp and the hex HMAC-SHA256
+	// of "<timestamp>.<body>" in the form "t=<timestamp>,v1=<hex>".
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderEvent     = "X-Webhook-Event"
+	// HeaderDelivery is the delivery id, deliveries are at least
+	// once and receivers can use it to drop the duplicates.
+	HeaderDelivery = "X-Webhook-Delivery"
+
+	// errorMaxLen bounds the response body stored with the delivery.
+	errorMaxLen = 1024
+	// closeTimeout bounds the wait for the batch in flight on Close.
+	closeTimeout = 30 * time.Second
+)
+
+var (
+	httpClient = &http.Client{}
+
+	// notify wakes up the dispatcher before the next poll.
+	notify = make(chan struct{}, 1)
+)
+
+type Config struct {
+	MaxAttempts    int
+	BackoffBaseSec int
+	BackoffMaxSec  int
+	PollSec        int
+	TimeoutSec     int
+	BatchSize      int
+}
+
+// Dispatcher delivers the pending webhook deliveries. The changes emit
+// their events to the outbox and the webhookPublish subscriber creates
+// the deliveries of a relayed event in its own transaction, once per
+// event and subscription, so they are dispatched after that commits.
+type Dispatcher struct {
+	maxAttempts int
+	backoffBase time.Duration
+	backoffMax  time.Duration
+	poll        time.Duration
+	timeout     time.Duration
+	batchSize   int
+	now         func() time.Time
+
+	cancel goctx.CancelFunc
+	done   chan struct{}
+}
+
+// Notify wakes up the dispatcher, it never blocks.
+func Notify() {
+	select {
+	case notify <- struct{}{}:
+	default:
+	}
+}
+
+func New(config Config) *Dispatcher {
+	d := &Dispatcher{
+		maxAttempts: config.MaxAttempts,
+		backoffBase: duration.Seconds(config.BackoffBaseSec),
+		backoffMax:  duration.Seconds(config.BackoffMaxSec),
+		poll:        duration.Seconds(config.PollSec),
+		timeout:     duration.Seconds(config.TimeoutSec),
+		batchSize:   config.BatchSize,
+		now:         time.Now,
+	}
+
+	if d.maxAttempts <= 0 {
+		d.maxAttempts = MaxAttemptsDefault
+	}
+
+	if d.backoffBase <= 0 {
+		d.backoffBase = BackoffBaseDefault
+	}
+
+	if d.backoffMax <= 0 {
+		d.backoffMax = BackoffMaxDefault
+	}
+
+	if d.poll <= 0 {
+		d.poll = PollDefault
+	}
+
+	if d.timeout <= 0 {
+		d.timeout = TimeoutDefault
+	}
+
+	if d.batchSize <= 0 {
+		d.batchSize = BatchSizeDefault
+	}
+
+	return d
+}
+
+// Start dispatches in a goroutine until Close.
+func (d *Dispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.done = make(chan struct{})
+
+	go d.run(ctx)
+}
+
+// Close stops the dispatcher and waits for the batch in flight.
+func (d *Dispatcher) Close() error {
+	if d.cancel == nil {
+		return nil
+	}
+	d.cancel()
+
+	select {
+	case <-d.done:
+		return nil
+	case <-time.After(closeTimeout):
+		return fmt.Errorf("webhook dispatcher close error: timed out after %v", closeTimeout)
+	}
+}
+
+func (d *Dispatcher) run(ctx context.Ctx) {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.poll)
+	defer ticker.Stop()
+
+	for {
+		// Keep going while full batches are due.
+		for {
+			n, err := d.Dispatch(ctx)
+			if err != nil {
+				logger.Logger.Errorf(ctx, `msg="webhook dispatch failed", err="%v"`, err)
+				break
+			}
+
+			if n < d.batchSize || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-notify:
+		}
+	}
+}
+
+// Dispatch attempts a batch of the due deliveries and
+// returns the number of deliveries attempted.
+func (d *Dispatcher) Dispatch(ctx context.Ctx) (int, error) {
+	tx := repo.New(ctx)
+
+	webhookDeliveries, err := tx.WebhookDelivery.ListDue(ctx, d.now().UTC(), d.batchSize)
+	if err != nil {
+		tx.Rollback()
+		err = fmt.Errorf("webhook dispatch error: %w", err)
+		return 0, err
+	}
+
+	if len(webhookDeliveries) == 0 {
+		tx.Rollback()
+		return 0, nil
+	}
+
+	// The rows stay locked while they are sent, the requests
+	// are sent concurrently to keep the transaction short.
+	results := make([]result, len(webhookDeliveries))
+
+	var wg sync.WaitGroup
+	for i, webhookDelivery := range webhookDeliveries {
+		webhookSubscription, err := tx.WebhookSubscription.GetByID(ctx, webhookDelivery.SubscriptionID)
+		if err != nil {
+			tx.Rollback()
+			err = fmt.Errorf("webhook dispatch error: %w", err)
+			return 0, err
+		}
+
+		switch {
+		case webhookSubscription == nil:
+			results[i] = result{err: fmt.Errorf("subscription deleted"), dead: true}
+			continue
+		case !webhookSubscription.Active:
+			results[i] = result{err: fmt.Errorf("subscription inactive"), dead: true}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, webhookSubscription *WebhookSubscription.WebhookSubscription, webhookDelivery *WebhookDelivery.WebhookDelivery) {
+			defer wg.Done()
+			results[i] = d.send(ctx, webhookSubscription, webhookDelivery)
+		}(i, webhookSubscription, webhookDelivery)
+	}
+	wg.Wait()
+
+	for i, webhookDelivery := range webhookDeliveries {
+		d.record(webhookDelivery, results[i])
+
+		if webhookDelivery.Status == WebhookDelivery.StatusDead {
+			logger.Logger.Warnf(ctx, `msg="webhook delivery dead", delivery_id="%d", subscription_id="%d", attempts="%d", err="%v"`,
+				webhookDelivery.ID, webhookDelivery.SubscriptionID, webhookDelivery.Attempts, results[i].err)
+		}
+
+		if err := tx.WebhookDelivery.Save(ctx, webhookDelivery); err != nil {
+			tx.Rollback()
+			err = fmt.Errorf("webhook dispatch error: %w", err)
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		err = fmt.Errorf("webhook dispatch error: commit error: %w", err)
+		return 0, err
+	}
+
+	return len(webhookDeliveries), nil
+}
+
+type result struct {
+	statusCode int
+	err        error
+	// dead deliveries are not attempted again.
+	dead bool
+}
+
+// send posts the signed payload to the subscription url.
+func (d *Dispatcher) send(ctx context.Ctx, webhookSubscription *WebhookSubscription.WebhookSubscription, webhookDelivery *WebhookDelivery.WebhookDelivery) result {
+	ctx, cancel := goctx.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	body := []byte(webhookDelivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookSubscription.URL, bytes.NewReader(body))
+	if err != nil {
+		err = fmt.Errorf("http new request error: %w", err)
+		return result{err: err, dead: true}
+	}
+
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(fiber.HeaderUserAgent, "golang-boilerplate-webhook/1.0")
+	req.Header.Set(HeaderEvent, webhookDelivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(webhookDelivery.ID, 10))
+	req.Header.Set(HeaderSignature, Sign(webhookSubscription.Secret, d.now().UTC(), body))
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		err = fmt.Errorf("http client do error: %w", err)
+		return result{err: err}
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		resbody, _ := io.ReadAll(io.LimitReader(res.Body, errorMaxLen))
+		err = fmt.Errorf("status code: %v, body: %v", res.StatusCode, string(resbody))
+		return result{statusCode: res.StatusCode, err: err}
+	}
+
+	return result{statusCode: res.StatusCode}
+}
+
+// record updates the delivery with the result of the attempt. Failed
+// deliveries are retried with an exponential backoff and dead lettered
+// once they run out of attempts.
+func (d *Dispatcher) record(webhookDelivery *WebhookDelivery.WebhookDelivery, r result) {
+	now := d.now().UTC()
+
+	webhookDelivery.Attempts++
+	webhookDelivery.LastAttemptAt = null.TimeFrom(now)
+	webhookDelivery.LastStatusCode = null.NewInt(int64(r.statusCode), r.statusCode != 0)
+
+	if r.err == nil {
+		webhookDelivery.Status = WebhookDelivery.StatusSucceeded
+		webhookDelivery.LastError = null.String{}
+		webhookDelivery.NextAttemptAt = null.Time{}
+		webhookDelivery.DeliveredAt = null.TimeFrom(now)
+		return
+	}
+
+	webhookDelivery.LastError = null.StringFrom(r.err.Error())
+
+	if r.dead || webhookDelivery.Attempts >= d.maxAttempts {
+		webhookDelivery.Status = WebhookDelivery.StatusDead
+		webhookDelivery.NextAttemptAt = null.Time{}
+		return
+	}
+
+	webhookDelivery.NextAttemptAt = null.TimeFrom(now.Add(duration.Backoff(webhookDelivery.Attempts, d.backoffBase, d.backoffMax)))
+}
+
+// Sign returns the signature header of the body sent at t.
+func Sign(secret string, t time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
@@ -0,0 +1,135 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/internal/context"
+	WebhookDelivery "github.com/koraygocmen/golang-boilerplate/internal/model/webhook_delivery"
+	WebhookSubscription "github.com/koraygocmen/golang-boilerplate/internal/model/webhook_subscription"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"user.created"}`)
+	now := time.Unix(1700000000, 0)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := "t=1700000000,v1=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign("secret", now, body); got != want {
+		t.Fatalf("want: %s; got: %s", want, got)
+	}
+}
+
+func TestSend(t *testing.T) {
+	var (
+		gotHeader http.Header
+		gotBody   []byte
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header
+		gotBody, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	d := New(Config{})
+	now := time.Unix(1700000000, 0)
+	d.now = func() time.Time { return now }
+
+	webhookSubscription := &WebhookSubscription.WebhookSubscription{URL: server.URL, Secret: "secret"}
+	webhookDelivery := &WebhookDelivery.WebhookDelivery{ID: 7, Event: "user.created", Payload: `{"event":"user.created"}`}
+
+	r := d.send(context.Background(), webhookSubscription, webhookDelivery)
+	if r.err != nil || r.statusCode != http.StatusOK {
+		t.Fatalf("want: status 200, err nil; got: %d, %v", r.statusCode, r.err)
+	}
+
+	if string(gotBody) != webhookDelivery.Payload {
+		t.Fatalf("want: %s; got: %s", webhookDelivery.Payload, gotBody)
+	}
+
+	if got := gotHeader.Get(HeaderSignature); got != Sign("secret", now, gotBody) {
+		t.Fatalf("want: valid signature; got: %s", got)
+	}
+
+	if gotHeader.Get(HeaderEvent) != "user.created" || gotHeader.Get(HeaderDelivery) != "7" {
+		t.Fatalf("want: event and delivery headers; got: %v", gotHeader)
+	}
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("down"))
+	})
+
+	r = d.send(context.Background(), webhookSubscription, webhookDelivery)
+	if r.statusCode != http.StatusServiceUnavailable || r.err == nil || !strings.Contains(r.err.Error(), "down") {
+		t.Fatalf("want: status 503 with the body; got: %d, %v", r.statusCode, r.err)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	d := New(Config{MaxAttempts: 3, BackoffBaseSec: 10, BackoffMaxSec: 3600})
+	now := time.Now().UTC()
+	d.now = func() time.Time { return now }
+
+	webhookDelivery := &WebhookDelivery.WebhookDelivery{Status: WebhookDelivery.StatusPending}
+
+	// Failures are retried with a backoff.
+	d.record(webhookDelivery, result{statusCode: 500, err: errors.New("status code: 500")})
+	if webhookDelivery.Status != WebhookDelivery.StatusPending || webhookDelivery.NextAttemptAt.Time != now.Add(10*time.Second) {
+		t.Fatalf("want: pending in 10s; got: %+v", webhookDelivery)
+	}
+
+	d.record(webhookDelivery, result{err: errors.New("connection refused")})
+	if webhookDelivery.NextAttemptAt.Time != now.Add(20*time.Second) || webhookDelivery.LastStatusCode.Valid {
+		t.Fatalf("want: pending in 20s without status code; got: %+v", webhookDelivery)
+	}
+
+	// Dead lettered once out of attempts.
+	d.record(webhookDelivery, result{statusCode: 500, err: errors.New("status code: 500")})
+	if webhookDelivery.Status != WebhookDelivery.StatusDead || webhookDelivery.NextAttemptAt.Valid || webhookDelivery.Attempts != 3 {
+		t.Fatalf("want: dead after 3 attempts; got: %+v", webhookDelivery)
+	}
+
+	// Success clears the error.
+	webhookDelivery = &WebhookDelivery.WebhookDelivery{Status: WebhookDelivery.StatusPending, LastError: webhookDelivery.LastError}
+	d.record(webhookDelivery, result{statusCode: 204})
+	if webhookDelivery.Status != WebhookDelivery.StatusSucceeded || !webhookDelivery.DeliveredAt.Valid || webhookDelivery.LastError.Valid {
+		t.Fatalf("want: succeeded; got: %+v", webhookDelivery)
+	}
+
+	// Permanent failures are dead lettered right away.
+	webhookDelivery = &WebhookDelivery.WebhookDelivery{Status: WebhookDelivery.StatusPending}
+	d.record(webhookDelivery, result{err: errors.New("subscription deleted"), dead: true})
+	if webhookDelivery.Status != WebhookDelivery.StatusDead || webhookDelivery.Attempts != 1 {
+		t.Fatalf("want: dead after 1 attempt; got: %+v", webhookDelivery)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	// Notify never blocks, pending wake ups are coalesced.
+	Notify()
+	Notify()
+
+	select {
+	case <-notify:
+	default:
+		t.Fatalf("want: notified; got: not notified")
+	}
+
+	select {
+	case <-notify:
+		t.Fatalf("want: single notification; got: two")
+	default:
+	}
+}
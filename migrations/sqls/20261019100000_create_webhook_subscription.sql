@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "webhook_subscription" (
+  "id" SERIAL PRIMARY KEY,
+  "created_at" timestamp DEFAULT (now() at time zone 'utc'),
+  "deleted_at" timestamp,
+  "url" text NOT NULL,
+  "secret" text NOT NULL,
+  "events" text NOT NULL,
+  "active" boolean NOT NULL DEFAULT true
+);
+
+CREATE INDEX ON "webhook_subscription" ("created_at");
+CREATE INDEX ON "webhook_subscription" ("deleted_at");
+CREATE INDEX ON "webhook_subscription" ("active");
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "webhook_subscription";
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "webhook_delivery" (
+  "id" SERIAL PRIMARY KEY,
+  "created_at" timestamp DEFAULT (now() at time zone 'utc'),
+  "subscription_id" int NOT NULL,
+  "event" text NOT NULL,
+  "payload" text NOT NULL,
+  "status" text NOT NULL,
+  "attempts" int NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamp,
+  "last_attempt_at" timestamp,
+  "last_status_code" int,
+  "last_error" text,
+  "delivered_at" timestamp
+);
+
+CREATE INDEX ON "webhook_delivery" ("created_at");
+CREATE INDEX ON "webhook_delivery" ("subscription_id");
+CREATE INDEX ON "webhook_delivery" ("event");
+CREATE INDEX ON "webhook_delivery" ("status", "next_attempt_at");
+
+ALTER TABLE "webhook_delivery" ADD FOREIGN KEY ("subscription_id") REFERENCES "webhook_subscription" ("id");
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "webhook_delivery";
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "outbox" (
+  "id" SERIAL PRIMARY KEY,
+  "created_at" timestamp DEFAULT (now() at time zone 'utc'),
+  "aggregate_type" text NOT NULL,
+  "aggregate_id" text NOT NULL,
+  "event" text NOT NULL,
+  "payload" text NOT NULL,
+  "request_id" text,
+  "attempts" int NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamp NOT NULL,
+  "last_error" text,
+  "published_at" timestamp
+);
+
+CREATE INDEX ON "outbox" ("created_at");
+CREATE INDEX ON "outbox" ("event");
+CREATE INDEX ON "outbox" ("published_at");
+CREATE INDEX ON "outbox" ("next_attempt_at") WHERE "published_at" IS NULL;
+CREATE INDEX ON "outbox" ("aggregate_type", "aggregate_id", "id") WHERE "published_at" IS NULL;
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "outbox";
+-- +goose StatementEnd
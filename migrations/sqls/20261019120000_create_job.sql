@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "job" (
+  "id" SERIAL PRIMARY KEY,
+  "created_at" timestamp DEFAULT (now() at time zone 'utc'),
+  "kind" text NOT NULL,
+  "payload" text NOT NULL,
+  "status" text NOT NULL,
+  "unique_key" text,
+  "attempts" int NOT NULL DEFAULT 0,
+  "max_attempts" int NOT NULL DEFAULT 0,
+  "run_at" timestamp NOT NULL,
+  "last_error" text,
+  "finished_at" timestamp
+);
+
+CREATE INDEX ON "job" ("created_at");
+CREATE INDEX ON "job" ("kind");
+CREATE INDEX ON "job" ("status", "run_at");
+CREATE UNIQUE INDEX ON "job" ("unique_key") WHERE "status" = 'PENDING';
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "job";
+-- +goose StatementEnd
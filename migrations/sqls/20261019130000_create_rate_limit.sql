@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "rate_limit" (
+  "key" text PRIMARY KEY,
+  "count" int NOT NULL DEFAULT 0,
+  "expire_at" timestamp NOT NULL
+);
+
+CREATE INDEX ON "rate_limit" ("expire_at");
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "rate_limit";
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "idempotency_key" (
+  "id" SERIAL PRIMARY KEY,
+  "scope" text NOT NULL,
+  "key" text NOT NULL,
+  "fingerprint" text NOT NULL,
+  "status_code" int NOT NULL DEFAULT 0,
+  "content_type" text NOT NULL DEFAULT '',
+  "body" bytea,
+  "created_at" timestamp DEFAULT (now() at time zone 'utc'),
+  "expire_at" timestamp NOT NULL
+);
+
+CREATE UNIQUE INDEX ON "idempotency_key" ("scope", "key");
+CREATE INDEX ON "idempotency_key" ("expire_at");
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "idempotency_key";
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS "user_preference" (
+  "user_id" int PRIMARY KEY,
+  "created_at" timestamp DEFAULT (now() at time zone 'utc'),
+  "updated_at" timestamp DEFAULT (now() at time zone 'utc'),
+  "locale" text NOT NULL,
+  "timezone" text NOT NULL,
+  "notify_security" boolean NOT NULL DEFAULT true,
+  "notify_product" boolean NOT NULL DEFAULT true,
+  "notify_marketing" boolean NOT NULL DEFAULT false
+);
+
+ALTER TABLE "user_preference" ADD FOREIGN KEY ("user_id") REFERENCES "user" ("id") ON DELETE CASCADE;
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS "user_preference";
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE "webhook_delivery" ADD COLUMN IF NOT EXISTS "outbox_id" int;
+
+CREATE UNIQUE INDEX IF NOT EXISTS "webhook_delivery_outbox_id_subscription_id_idx" ON "webhook_delivery" ("outbox_id", "subscription_id");
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS "webhook_delivery_outbox_id_subscription_id_idx";
+
+ALTER TABLE "webhook_delivery" DROP COLUMN IF EXISTS "outbox_id";
+-- +goose StatementEnd
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+func (c *Client) AdminLogGet(ctx context.Context) (*LogSettings, error) {
+	return c.adminLog(ctx, request{
+		method: http.MethodGet,
+		path:   "/v1/admin/log",
+	})
+}
+
+// AdminLogLevelUpdate overrides the global log level, the
+// level is either the name or the number of the level.
+func (c *Client) AdminLogLevelUpdate(ctx context.Context, level string) (*LogSettings, error) {
+	return c.adminLog(ctx, request{
+		method: http.MethodPut,
+		path:   "/v1/admin/log/level",
+		body:   map[string]string{"level": level},
+	})
+}
+
+func (c *Client) AdminLogLevelReset(ctx context.Context) (*LogSettings, error) {
+	return c.adminLog(ctx, request{
+		method: http.MethodDelete,
+		path:   "/v1/admin/log/level",
+	})
+}
+
+func (c *Client) AdminLogRouteLevelsUpdate(ctx context.Context, routeLevels []RouteLevel) (*LogSettings, error) {
+	return c.adminLog(ctx, request{
+		method: http.MethodPut,
+		path:   "/v1/admin/log/routes",
+		body:   map[string][]RouteLevel{"routeLevels": routeLevels},
+	})
+}
+
+func (c *Client) AdminLogSampleRulesUpdate(ctx context.Context, sampleRules []SampleRule) (*LogSettings, error) {
+	return c.adminLog(ctx, request{
+		method: http.MethodPut,
+		path:   "/v1/admin/log/sampling",
+		body:   map[string][]SampleRule{"sampleRules": sampleRules},
+	})
+}
+
+func (c *Client) adminLog(ctx context.Context, r request) (*LogSettings, error) {
+	var body struct {
+		Log *LogSettings `json:"log"`
+	}
+	err := c.do(ctx, r, &body)
+	return body.Log, err
+}
+
+// WebhookCreate creates a webhook subscription and returns it with
+// its secret, the secret is only returned when it is created.
+func (c *Client) WebhookCreate(ctx context.Context, params WebhookCreateParams) (*WebhookSubscription, string, error) {
+	var body struct {
+		Webhook *WebhookSubscription `json:"webhook"`
+		Secret  string               `json:"secret"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "/v1/admin/webhooks",
+		body:   params,
+	}, &body)
+	return body.Webhook, body.Secret, err
+}
+
+func (c *Client) WebhookList(ctx context.Context) ([]WebhookSubscription, error) {
+	var body struct {
+		Webhooks []WebhookSubscription `json:"webhooks"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "/v1/admin/webhooks",
+	}, &body)
+	return body.Webhooks, err
+}
+
+func (c *Client) WebhookUpdate(ctx context.Context, id int64, params WebhookUpdateParams) (*WebhookSubscription, error) {
+	var body struct {
+		Webhook *WebhookSubscription `json:"webhook"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodPut,
+		path:   "/v1/admin/webhooks/" + strconv.FormatInt(id, 10),
+		body:   params,
+	}, &body)
+	return body.Webhook, err
+}
+
+func (c *Client) WebhookDelete(ctx context.Context, id int64) (*WebhookSubscription, error) {
+	var body struct {
+		Webhook *WebhookSubscription `json:"webhook"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodDelete,
+		path:   "/v1/admin/webhooks/" + strconv.FormatInt(id, 10),
+	}, &body)
+	return body.Webhook, err
+}
+
+func (c *Client) WebhookDeliveryList(ctx context.Context, id int64, params WebhookDeliveryListParams) ([]WebhookDelivery, error) {
+	query := make(url.Values)
+	if params.Status != "" {
+		query.Set("status", params.Status)
+	}
+	if params.PageSize != 0 {
+		query.Set("pageSize", strconv.Itoa(params.PageSize))
+	}
+	if params.PageNum != 0 {
+		query.Set("pageNum", strconv.Itoa(params.PageNum))
+	}
+
+	var body struct {
+		Deliveries []WebhookDelivery `json:"deliveries"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "/v1/admin/webhooks/" + strconv.FormatInt(id, 10) + "/deliveries",
+		query:  query,
+	}, &body)
+	return body.Deliveries, err
+}
+
+// WebhookDeliveryRedeliver schedules the delivery to be sent again,
+// it is not retried since every request schedules another attempt.
+func (c *Client) WebhookDeliveryRedeliver(ctx context.Context, id int64) (*WebhookDelivery, error) {
+	var body struct {
+		Delivery *WebhookDelivery `json:"delivery"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "/v1/admin/webhooks/deliveries/" + strconv.FormatInt(id, 10) + "/redeliver",
+	}, &body)
+	return body.Delivery, err
+}
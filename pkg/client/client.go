@@ -0,0 +1,368 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/koraygocmen/golang-boilerplate/pkg/duration"
+)
+
+const (
+	TimeoutDefault       = 30 * time.Second
+	MaxRetriesDefault    = 3
+	RetryWaitBaseDefault = 500 * time.Millisecond
+	RetryWaitMaxDefault  = 10 * time.Second
+
+	HeaderIdempotencyKey = "Idempotency-Key"
+	HeaderRequestID      = "X-Request-ID"
+	HeaderAppVersion     = "X-App-Version"
+
+	MIMEApplicationJSON           = "application/json"
+	MIMEApplicationProblemJSON    = "application/problem+json"
+	MIMEApplicationMergePatchJSON = "application/merge-patch+json"
+)
+
+var (
+	// retryStatuses are the statuses the requests are retried with.
+	retryStatuses = map[int]bool{
+		http.StatusTooManyRequests:    true,
+		http.StatusBadGateway:         true,
+		http.StatusServiceUnavailable: true,
+		http.StatusGatewayTimeout:     true,
+	}
+)
+
+type Config struct {
+	// BaseURL is the url of the api, i.e. "https://api.example.com".
+	BaseURL    string
+	HTTPClient *http.Client
+
+	// Timeout bounds the requests of the contexts without a deadline.
+	Timeout time.Duration
+
+	// MaxRetries is the number of retries of the requests that are safe
+	// to retry, the wait between them doubles from the base up to the max.
+	// The retries are disabled with a negative value.
+	MaxRetries    int
+	RetryWaitBase time.Duration
+	RetryWaitMax  time.Duration
+
+	// AcceptLanguage is the language of the error messages.
+	AcceptLanguage string
+
+	// AppVersion is the version of the app using the client, the api
+	// refuses the apps older than its minimum app version.
+	AppVersion string
+}
+
+// Client is the client of the v1 api. The client is safe for concurrent
+// use, the clients of the users and the admin are copies of it.
+type Client struct {
+	baseURL        string
+	httpClient     *http.Client
+	timeout        time.Duration
+	maxRetries     int
+	retryWaitBase  time.Duration
+	retryWaitMax   time.Duration
+	acceptLanguage string
+	appVersion     string
+	authorization  string
+}
+
+func New(config Config) (*Client, error) {
+	baseURL, err := url.Parse(config.BaseURL)
+	if err != nil || baseURL.Scheme == "" || baseURL.Host == "" {
+		err = fmt.Errorf("client new error: base url %q is invalid", config.BaseURL)
+		return nil, err
+	}
+
+	c := &Client{
+		baseURL:        strings.TrimSuffix(baseURL.String(), "/"),
+		httpClient:     config.HTTPClient,
+		timeout:        config.Timeout,
+		maxRetries:     config.MaxRetries,
+		retryWaitBase:  config.RetryWaitBase,
+		retryWaitMax:   config.RetryWaitMax,
+		acceptLanguage: config.AcceptLanguage,
+		appVersion:     config.AppVersion,
+	}
+
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+	}
+	if c.timeout == 0 {
+		c.timeout = TimeoutDefault
+	}
+	if c.maxRetries == 0 {
+		c.maxRetries = MaxRetriesDefault
+	}
+	if c.retryWaitBase == 0 {
+		c.retryWaitBase = RetryWaitBaseDefault
+	}
+	if c.retryWaitMax == 0 {
+		c.retryWaitMax = RetryWaitMaxDefault
+	}
+
+	return c, nil
+}
+
+// Authorization returns the authorization of the user
+// session, the id and the token joined with a dash.
+func Authorization(userSessionID int64, token string) string {
+	return strconv.FormatInt(userSessionID, 10) + "-" + token
+}
+
+// ParseAuthorization returns the user session id and the token of the authorization.
+func ParseAuthorization(authorization string) (int64, string, error) {
+	pieces := strings.Split(authorization, "-")
+	if len(pieces) != 2 || pieces[1] == "" {
+		return 0, "", fmt.Errorf("authorization must be the user session id and the token joined with a dash")
+	}
+
+	userSessionID, err := strconv.ParseInt(pieces[0], 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("authorization user session id error: %w", err)
+	}
+	return userSessionID, pieces[1], nil
+}
+
+// WithUserSession returns a copy of the client authenticated
+// with the user session created with UserSessionCreate.
+func (c *Client) WithUserSession(userSession *UserSession) *Client {
+	return c.WithAuthorization(Authorization(userSession.ID, userSession.Token))
+}
+
+// WithAdminToken returns a copy of the client authenticated as the admin.
+func (c *Client) WithAdminToken(token string) *Client {
+	return c.WithAuthorization(token)
+}
+
+// WithAuthorization returns a copy of the client with the authorization header.
+func (c *Client) WithAuthorization(authorization string) *Client {
+	clone := *c
+	clone.authorization = authorization
+	return &clone
+}
+
+// request is a request of the client, the body is marshaled to json.
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	headers     map[string]string
+	body        interface{}
+	contentType string
+	// idempotent requests are sent with an idempotency key, the
+	// api replays the response of the first request on the retries.
+	idempotent bool
+}
+
+// envelope is the response body of the v1 api.
+type envelope struct {
+	Version string          `json:"version"`
+	Success bool            `json:"success"`
+	Body    json.RawMessage `json:"body"`
+	Error   *Error          `json:"error"`
+}
+
+// do sends the request and decodes the body of the response into
+// out. The requests safe to retry are retried on the network errors
+// and the statuses of the temporary failures until the deadline.
+func (c *Client) do(ctx context.Context, r request, out interface{}) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var body []byte
+	if r.body != nil {
+		var err error
+		if body, err = json.Marshal(r.body); err != nil {
+			return fmt.Errorf("client %s %s error: marshal body error: %w", r.method, r.path, err)
+		}
+	}
+
+	if r.idempotent {
+		if r.headers == nil {
+			r.headers = make(map[string]string)
+		}
+		if _, ok := r.headers[HeaderIdempotencyKey]; !ok {
+			key, err := idempotencyKey()
+			if err != nil {
+				return fmt.Errorf("client %s %s error: %w", r.method, r.path, err)
+			}
+			r.headers[HeaderIdempotencyKey] = key
+		}
+	}
+
+	// The patches and the posts without the idempotency
+	// key are not retried, they may be applied twice.
+	retry := r.idempotent || r.method == http.MethodGet || r.method == http.MethodPut || r.method == http.MethodDelete
+
+	for attempt := 0; ; attempt++ {
+		res, err := c.send(ctx, r, body)
+
+		var wait time.Duration
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return fmt.Errorf("client %s %s error: %w", r.method, r.path, ctx.Err())
+		case err != nil:
+			wait = duration.Backoff(attempt+1, c.retryWaitBase, c.retryWaitMax)
+		case retryStatuses[res.StatusCode]:
+			wait = duration.Backoff(attempt+1, c.retryWaitBase, c.retryWaitMax)
+			if retryAfter, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && duration.Seconds(retryAfter) > wait {
+				wait = duration.Seconds(retryAfter)
+			}
+		default:
+			defer res.Body.Close()
+			return c.decode(r, res, out)
+		}
+
+		// The last error is returned if the retries are
+		// used up or the wait would pass the deadline.
+		deadline, _ := ctx.Deadline()
+		if !retry || attempt >= c.maxRetries || time.Now().Add(wait).After(deadline) {
+			if err != nil {
+				return fmt.Errorf("client %s %s error: %w", r.method, r.path, err)
+			}
+			defer res.Body.Close()
+			return c.decode(r, res, out)
+		}
+
+		if res != nil {
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("client %s %s error: %w", r.method, r.path, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, r request, body []byte) (*http.Response, error) {
+	u := c.baseURL + r.path
+	if len(r.query) > 0 {
+		u += "?" + r.query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, u, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("new request error: %w", err)
+	}
+
+	req.Header.Set("Accept", MIMEApplicationJSON)
+	if body != nil {
+		contentType := r.contentType
+		if contentType == "" {
+			contentType = MIMEApplicationJSON
+		}
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.authorization != "" {
+		req.Header.Set("Authorization", c.authorization)
+	}
+	if c.acceptLanguage != "" {
+		req.Header.Set("Accept-Language", c.acceptLanguage)
+	}
+	if c.appVersion != "" {
+		req.Header.Set(HeaderAppVersion, c.appVersion)
+	}
+	for key, value := range r.headers {
+		if value != "" {
+			req.Header.Set(key, value)
+		}
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// decode decodes the body of the envelope into out, or the error
+// of the envelope or the problem details into an Error.
+func (c *Client) decode(r request, res *http.Response, out interface{}) error {
+	resbody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("client %s %s error: read body error: %w", r.method, r.path, err)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+
+	if res.StatusCode >= http.StatusBadRequest {
+		aerr := &Error{Status: res.StatusCode}
+
+		switch mediaType {
+		case MIMEApplicationProblemJSON:
+			json.Unmarshal(resbody, aerr)
+		case MIMEApplicationJSON:
+			var e envelope
+			if json.Unmarshal(resbody, &e) == nil && e.Error != nil {
+				aerr = e.Error
+				aerr.Status = res.StatusCode
+			}
+		}
+
+		if aerr.Code == "" {
+			aerr.Message = strings.TrimSpace(string(resbody))
+		}
+		if aerr.RequestID == "" {
+			aerr.RequestID = res.Header.Get(HeaderRequestID)
+		}
+		return aerr
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	var e envelope
+	if err := json.Unmarshal(resbody, &e); err != nil {
+		return fmt.Errorf("client %s %s error: unmarshal body error: %w", r.method, r.path, err)
+	}
+	if len(e.Body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(e.Body, out); err != nil {
+		return fmt.Errorf("client %s %s error: unmarshal body error: %w", r.method, r.path, err)
+	}
+
+	return nil
+}
+
+func idempotencyKey() (string, error) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("idempotency key error: %w", err)
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// Is returns true if the error is the error of the api with the code.
+func Is(err error, code string) bool {
+	var aerr *Error
+	if errors.As(err, &aerr) {
+		return aerr.Code == code
+	}
+	return false
+}
@@ -0,0 +1,383 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/koraygocmen/golang-boilerplate/internal/apiversion"
+	"github.com/koraygocmen/golang-boilerplate/internal/config"
+	"github.com/koraygocmen/golang-boilerplate/internal/database"
+	"github.com/koraygocmen/golang-boilerplate/internal/database/databasetest"
+	"github.com/koraygocmen/golang-boilerplate/internal/errapi"
+	"github.com/koraygocmen/golang-boilerplate/internal/logger"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/handler"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/middleware"
+	"github.com/koraygocmen/golang-boilerplate/internal/transport/router"
+	"github.com/koraygocmen/null"
+)
+
+var (
+	appTest    *fiber.App
+	clientTest *Client
+)
+
+// appTransport sends the requests to the fiber app in-process.
+type appTransport struct {
+	app *fiber.App
+}
+
+func (t *appTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.app.Test(req, -1)
+}
+
+func TestMain(m *testing.M) {
+	// Setup logger.
+	logger.Logger, _ = logger.New(logger.Config{
+		Mode: string(logger.ModeNone),
+	})
+
+	config.Admin.Token = "token"
+
+	// The app is set up the same as the api serve command.
+	h := handler.New(handler.Config{})
+	appTest = fiber.New(fiber.Config{
+		ErrorHandler: h.Error,
+	})
+	middleware.Setup(appTest, h)
+	router.Setup(appTest, h)
+
+	var err error
+	clientTest, err = New(Config{
+		BaseURL:    "http://api.test",
+		HTTPClient: &http.Client{Transport: &appTransport{appTest}},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	m.Run()
+}
+
+func TestNew(t *testing.T) {
+	for _, baseURL := range []string{"", "api.test", "/v1"} {
+		if _, err := New(Config{BaseURL: baseURL}); err == nil {
+			t.Fatalf("want: new err with base url %q; got: nil", baseURL)
+		}
+	}
+
+	c, err := New(Config{BaseURL: "https://api.test/"})
+	if err != nil {
+		t.Fatalf("want: new err nil; got: %v", err)
+	}
+	if c.baseURL != "https://api.test" {
+		t.Fatalf("want: base url https://api.test; got: %s", c.baseURL)
+	}
+	if c.timeout != TimeoutDefault || c.maxRetries != MaxRetriesDefault {
+		t.Fatalf("want: timeout %s, max retries %d; got: %s, %d", TimeoutDefault, MaxRetriesDefault, c.timeout, c.maxRetries)
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	authorization := Authorization(42, "token")
+	if authorization != "42-token" {
+		t.Fatalf("want: 42-token; got: %s", authorization)
+	}
+
+	id, token, err := ParseAuthorization(authorization)
+	if err != nil || id != 42 || token != "token" {
+		t.Fatalf("want: 42, token, err nil; got: %d, %s, %v", id, token, err)
+	}
+
+	for _, authorization := range []string{"", "42", "42-", "id-token", "1-2-3"} {
+		if _, _, err := ParseAuthorization(authorization); err == nil {
+			t.Fatalf("want: parse err with authorization %q; got: nil", authorization)
+		}
+	}
+
+	// Test the copies do not change the client.
+	admin := clientTest.WithAdminToken("token")
+	if clientTest.authorization != "" || admin.authorization != "token" {
+		t.Fatalf("want: client authorization empty, admin token; got: %q, %q", clientTest.authorization, admin.authorization)
+	}
+}
+
+func TestError(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := clientTest.UserGet(ctx)
+	if !Is(err, errapi.ErrCodeAuthorizationMissing) {
+		t.Fatalf("want: error %s; got: %v", errapi.ErrCodeAuthorizationMissing, err)
+	}
+
+	aerr := err.(*Error)
+	if aerr.Status != http.StatusUnauthorized || aerr.Message == "" || aerr.RequestID == "" {
+		t.Fatalf("want: status %d with message and request id; got: %+v", http.StatusUnauthorized, aerr)
+	}
+
+	_, err = clientTest.WithAuthorization("wrong").UserGet(ctx)
+	if !Is(err, errapi.ErrCodeAuthorizationInvalid) {
+		t.Fatalf("want: error %s; got: %v", errapi.ErrCodeAuthorizationInvalid, err)
+	}
+
+	// Test the messages are localized.
+	tr, en := *clientTest, *clientTest
+	tr.acceptLanguage, en.acceptLanguage = "tr", "en"
+	_, errTr := tr.UserGet(ctx)
+	_, errEn := en.UserGet(ctx)
+	if errTr.(*Error).Message == errEn.(*Error).Message {
+		t.Fatalf("want: localized messages; got: %s, %s", errTr.(*Error).Message, errEn.(*Error).Message)
+	}
+}
+
+func TestProblem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", MIMEApplicationProblemJSON)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"type":"about:blank","title":"Bad Request","status":400,"detail":"email is invalid","code":"userEmailInvalid","requestId":"id","errors":[{"field":"email","code":"userEmailInvalid","message":"email is invalid"}]}`))
+	}))
+	defer server.Close()
+
+	c, _ := New(Config{BaseURL: server.URL})
+	_, err := c.UserCreate(context.Background(), UserCreateParams{})
+
+	aerr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("want: api error; got: %v", err)
+	}
+	if aerr.Status != http.StatusBadRequest || aerr.Code != "userEmailInvalid" || aerr.Message != "email is invalid" || aerr.RequestID != "id" {
+		t.Fatalf("want: problem decoded; got: %+v", aerr)
+	}
+	if len(aerr.Fields) != 1 || aerr.Fields[0].Field != "email" {
+		t.Fatalf("want: email field error; got: %+v", aerr.Fields)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts int
+		keys     = make(map[string]bool)
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		attempts++
+		keys[r.Header.Get(HeaderIdempotencyKey)] = true
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", MIMEApplicationJSON)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"version":"1","success":true,"body":{"user":{"id":1,"version":1}}}`))
+	}))
+	defer server.Close()
+
+	c, _ := New(Config{
+		BaseURL:       server.URL,
+		RetryWaitBase: time.Millisecond,
+		RetryWaitMax:  time.Millisecond,
+	})
+
+	user, err := c.UserCreate(context.Background(), UserCreateParams{})
+	if err != nil || user == nil || user.ID != 1 {
+		t.Fatalf("want: user 1, err nil; got: %+v, %v", user, err)
+	}
+	if attempts != 3 {
+		t.Fatalf("want: 3 attempts; got: %d", attempts)
+	}
+
+	// Test the retries are sent with the same idempotency key.
+	if len(keys) != 1 || keys[""] {
+		t.Fatalf("want: 1 idempotency key; got: %v", keys)
+	}
+
+	// Test the patches are not retried.
+	attempts = 0
+	_, err = c.UserPatch(context.Background(), UserPatchParams{})
+	if aerr, ok := err.(*Error); !ok || aerr.Status != http.StatusServiceUnavailable || attempts != 1 {
+		t.Fatalf("want: 1 attempt with status %d; got: %d, %v", http.StatusServiceUnavailable, attempts, err)
+	}
+
+	// Test the retries stop at the deadline.
+	attempts = 0
+	c.retryWaitBase, c.retryWaitMax = time.Second, time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := c.UserGet(ctx); err == nil || attempts != 1 {
+		t.Fatalf("want: 1 attempt with error; got: %d, %v", attempts, err)
+	}
+}
+
+func TestAppVersion(t *testing.T) {
+	versioner, err := apiversion.New(apiversion.Config{MinAppVersion: "2.4.0"})
+	if err != nil {
+		t.Fatalf("want: versioning err nil; got: %v", err)
+	}
+	apiversion.Versioner = versioner
+	defer func() { apiversion.Versioner = nil }()
+
+	c := clientTest.WithAdminToken("token")
+	c.appVersion = "2.3.0"
+	if _, err := c.AdminLogGet(context.Background()); !Is(err, errapi.ErrCodeAppVersionUnsupported) {
+		t.Fatalf("want: error %s; got: %v", errapi.ErrCodeAppVersionUnsupported, err)
+	}
+
+	c.appVersion = "2.4.0"
+	if _, err := c.AdminLogGet(context.Background()); err != nil {
+		t.Fatalf("want: admin log get err nil; got: %v", err)
+	}
+}
+
+func TestAdminLog(t *testing.T) {
+	ctx := context.Background()
+	admin := clientTest.WithAdminToken("token")
+
+	if _, err := clientTest.AdminLogGet(ctx); !Is(err, errapi.ErrCodeAuthorizationMissing) {
+		t.Fatalf("want: error %s; got: %v", errapi.ErrCodeAuthorizationMissing, err)
+	}
+
+	settings, err := admin.AdminLogLevelUpdate(ctx, "debug")
+	if err != nil {
+		t.Fatalf("want: log level update err nil; got: %v", err)
+	}
+	if !settings.Level.Valid || settings.Level.Int64 != int64(logger.Levels["DEBUG"]) {
+		t.Fatalf("want: level %d; got: %v", logger.Levels["DEBUG"], settings.Level)
+	}
+
+	if _, err := admin.AdminLogLevelUpdate(ctx, "loud"); !Is(err, errapi.ErrCodeLogLevelInvalid) {
+		t.Fatalf("want: error %s; got: %v", errapi.ErrCodeLogLevelInvalid, err)
+	}
+
+	settings, err = admin.AdminLogRouteLevelsUpdate(ctx, []RouteLevel{{Route: "/v1/users", Level: logger.Levels["DEBUG"]}})
+	if err != nil || len(settings.RouteLevels) != 1 || settings.RouteLevels[0].Route != "/v1/users" {
+		t.Fatalf("want: route level /v1/users; got: %+v, %v", settings, err)
+	}
+
+	settings, err = admin.AdminLogSampleRulesUpdate(ctx, []SampleRule{{Route: "/v1/users", Status: "2xx", Rate: 0.5}})
+	if err != nil || len(settings.SampleRules) != 1 || settings.SampleRules[0].Rate != 0.5 {
+		t.Fatalf("want: sample rule with rate 0.5; got: %+v, %v", settings, err)
+	}
+
+	// Reset the settings for the rest of the tests.
+	admin.AdminLogRouteLevelsUpdate(ctx, []RouteLevel{})
+	admin.AdminLogSampleRulesUpdate(ctx, []SampleRule{})
+	settings, err = admin.AdminLogLevelReset(ctx)
+	if err != nil || settings.Level.Valid {
+		t.Fatalf("want: level reset; got: %+v, %v", settings, err)
+	}
+
+	settings, err = admin.AdminLogGet(ctx)
+	if err != nil || len(settings.RouteLevels) != 0 || len(settings.SampleRules) != 0 {
+		t.Fatalf("want: log settings reset; got: %+v, %v", settings, err)
+	}
+}
+
+// TestUser runs last, it needs the test database.
+func TestUser(t *testing.T) {
+	dbtest := databasetest.Get()
+	database.DB = dbtest.DB
+	defer dbtest.Purge()
+
+	ctx := context.Background()
+
+	user, err := clientTest.UserCreate(ctx, UserCreateParams{
+		Email:      null.StringFrom("client@test.com"),
+		Password:   null.StringFrom("password"),
+		GivenNames: null.StringFrom("Client"),
+	})
+	if err != nil {
+		t.Fatalf("want: user create err nil; got: %v", err)
+	}
+	if user.ID == 0 || user.Email.String != "client@test.com" || !user.PasswordSet {
+		t.Fatalf("want: user created; got: %+v", user)
+	}
+
+	if _, err := clientTest.UserSessionCreate(ctx, UserSessionCreateParams{
+		Email:    "client@test.com",
+		Password: "wrong",
+	}); !Is(err, errapi.ErrCodeUserSessionCredentialsInvalid) {
+		t.Fatalf("want: error %s; got: %v", errapi.ErrCodeUserSessionCredentialsInvalid, err)
+	}
+
+	userSession, err := clientTest.UserSessionCreate(ctx, UserSessionCreateParams{
+		Email:    "client@test.com",
+		Password: "password",
+	})
+	if err != nil || userSession.Token == "" || userSession.UserID != user.ID {
+		t.Fatalf("want: user session of user %d; got: %+v, %v", user.ID, userSession, err)
+	}
+
+	userClient := clientTest.WithUserSession(userSession)
+
+	got, err := userClient.UserGet(ctx)
+	if err != nil || got.ID != user.ID {
+		t.Fatalf("want: user %d; got: %+v, %v", user.ID, got, err)
+	}
+
+	// Test the updates with a stale etag fail.
+	updated, err := userClient.UserUpdate(ctx, UserUpdateParams{
+		Surname: null.StringFrom("Test"),
+		IfMatch: got.ETag(),
+	})
+	if err != nil || updated.Surname.String != "Test" {
+		t.Fatalf("want: surname Test; got: %+v, %v", updated, err)
+	}
+	if _, err := userClient.UserUpdate(ctx, UserUpdateParams{
+		Surname: null.StringFrom("Stale"),
+		IfMatch: got.ETag(),
+	}); !Is(err, errapi.ErrCodeUserVersionMismatch) {
+		t.Fatalf("want: error %s; got: %v", errapi.ErrCodeUserVersionMismatch, err)
+	}
+
+	patched, err := userClient.UserPatch(ctx, UserPatchParams{
+		Document: map[string]interface{}{"surname": nil},
+	})
+	if err != nil || patched.Surname.Valid {
+		t.Fatalf("want: surname removed; got: %+v, %v", patched, err)
+	}
+
+	preferences, err := userClient.UserPreferenceUpdate(ctx, UserPreferenceUpdateParams{
+		Locale:   null.StringFrom("de"),
+		Timezone: null.StringFrom("Europe/Berlin"),
+	})
+	if err != nil || preferences.Locale != "de" || preferences.Timezone != "Europe/Berlin" {
+		t.Fatalf("want: locale de, timezone Europe/Berlin; got: %+v, %v", preferences, err)
+	}
+
+	// Test the field errors are decoded.
+	_, _, err = clientTest.WithAdminToken("token").WebhookCreate(ctx, WebhookCreateParams{})
+	if aerr, ok := err.(*Error); !ok || aerr.Status != http.StatusBadRequest || len(aerr.Fields) == 0 {
+		t.Fatalf("want: status %d with field errors; got: %v", http.StatusBadRequest, err)
+	}
+
+	// Test the other sessions are deleted with the session delete.
+	userSessionOther, err := clientTest.UserSessionCreate(ctx, UserSessionCreateParams{
+		Email:    "client@test.com",
+		Password: "password",
+	})
+	if err != nil {
+		t.Fatalf("want: user session create err nil; got: %v", err)
+	}
+	if _, err := userClient.UserSessionDelete(ctx); err != nil {
+		t.Fatalf("want: user session delete err nil; got: %v", err)
+	}
+	if _, err := clientTest.WithUserSession(userSessionOther).UserGet(ctx); !Is(err, errapi.ErrCodeUserSessionNotFound) {
+		t.Fatalf("want: error %s; got: %v", errapi.ErrCodeUserSessionNotFound, err)
+	}
+	if _, err := userClient.UserGet(ctx); err != nil {
+		t.Fatalf("want: user get err nil; got: %v", err)
+	}
+
+	if _, err := userClient.UserDelete(ctx); err != nil {
+		t.Fatalf("want: user delete err nil; got: %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// FieldError is the error of a field of the request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error is the error responded by the api, it is decoded from the
+// error of the response body or the problem details. The code is
+// empty if the response is not an error of the api, i.e. a proxy
+// error, and the message is the body of the response then.
+type Error struct {
+	Status    int
+	Code      string
+	Message   string
+	Fields    []FieldError
+	RequestID string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("api error: status %d, code %s: %s", e.Status, e.Code, e.Message)
+}
+
+func (e *Error) UnmarshalJSON(data []byte) error {
+	// The message is the detail of the problem details.
+	var v struct {
+		Status    int          `json:"status"`
+		Code      string       `json:"code"`
+		Message   string       `json:"message"`
+		Detail    string       `json:"detail"`
+		Errors    []FieldError `json:"errors"`
+		RequestID string       `json:"requestId"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	e.Status = v.Status
+	e.Code = v.Code
+	e.Message = v.Message
+	if e.Message == "" {
+		e.Message = v.Detail
+	}
+	e.Fields = v.Errors
+	e.RequestID = v.RequestID
+	return nil
+}
@@ -0,0 +1,149 @@
+package client
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/koraygocmen/null"
+)
+
+type User struct {
+	ID            int64       `json:"id"`
+	CreatedAt     time.Time   `json:"createdAt"`
+	Email         null.String `json:"email"`
+	EmailVerified null.Bool   `json:"emailVerified"`
+	PasswordSet   bool        `json:"passwordSet"`
+	GivenNames    null.String `json:"givenNames"`
+	Surname       null.String `json:"surname"`
+	Version       int64       `json:"version"`
+}
+
+// ETag returns the entity tag of the version of the user, the
+// updates with it fail if the user was modified in the meantime.
+func (u *User) ETag() string {
+	return strconv.Quote(strconv.FormatInt(u.Version, 10))
+}
+
+type UserSession struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UserID    int64     `json:"userId"`
+	// Token is only returned when the session is created.
+	Token   string `json:"token"`
+	Purpose string `json:"purpose"`
+}
+
+type Notifications struct {
+	Security  bool `json:"security"`
+	Product   bool `json:"product"`
+	Marketing bool `json:"marketing"`
+}
+
+type UserPreference struct {
+	UpdatedAt     time.Time     `json:"updatedAt"`
+	Locale        string        `json:"locale"`
+	Timezone      string        `json:"timezone"`
+	Notifications Notifications `json:"notifications"`
+}
+
+type WebhookSubscription struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	URL       string    `json:"url"`
+	Events    string    `json:"events"`
+	Active    bool      `json:"active"`
+}
+
+type WebhookDelivery struct {
+	ID             int64       `json:"id"`
+	CreatedAt      time.Time   `json:"createdAt"`
+	SubscriptionID int64       `json:"subscriptionId"`
+	Event          string      `json:"event"`
+	Payload        string      `json:"payload"`
+	Status         string      `json:"status"`
+	Attempts       int         `json:"attempts"`
+	NextAttemptAt  null.Time   `json:"nextAttemptAt"`
+	LastAttemptAt  null.Time   `json:"lastAttemptAt"`
+	LastStatusCode null.Int    `json:"lastStatusCode"`
+	LastError      null.String `json:"lastError"`
+	DeliveredAt    null.Time   `json:"deliveredAt"`
+}
+
+type RouteLevel struct {
+	Route string `json:"route"`
+	Level int    `json:"level"`
+}
+
+type SampleRule struct {
+	Route  string  `json:"route"`
+	Status string  `json:"status"`
+	Rate   float64 `json:"rate"`
+}
+
+type LogSettings struct {
+	Level       null.Int     `json:"level"`
+	RouteLevels []RouteLevel `json:"routeLevels"`
+	SampleRules []SampleRule `json:"sampleRules"`
+}
+
+type UserCreateParams struct {
+	Email      null.String `json:"email"`
+	Password   null.String `json:"password"`
+	GivenNames null.String `json:"givenNames"`
+	Surname    null.String `json:"surname"`
+}
+
+type UserUpdateParams struct {
+	Email      null.String `json:"email"`
+	Password   null.String `json:"password"`
+	GivenNames null.String `json:"givenNames"`
+	Surname    null.String `json:"surname"`
+
+	// IfMatch is the etag of the user, the update fails if the user
+	// was modified after it was read. The update is unconditional if empty.
+	IfMatch string `json:"-"`
+}
+
+type UserPatchParams struct {
+	// Document is the json merge patch document, the fields
+	// set to nil are removed and the missing ones are kept.
+	Document map[string]interface{}
+
+	IfMatch string
+}
+
+type NotificationsParams struct {
+	Security  null.Bool `json:"security"`
+	Product   null.Bool `json:"product"`
+	Marketing null.Bool `json:"marketing"`
+}
+
+type UserPreferenceUpdateParams struct {
+	Locale        null.String         `json:"locale"`
+	Timezone      null.String         `json:"timezone"`
+	Notifications NotificationsParams `json:"notifications"`
+}
+
+type UserSessionCreateParams struct {
+	Email          string `json:"email"`
+	Password       string `json:"password"`
+	Purpose        string `json:"purpose"`
+	DeliveryMethod string `json:"deliveryMethod"`
+}
+
+type WebhookCreateParams struct {
+	URL    null.String `json:"url"`
+	Events []string    `json:"events"`
+}
+
+type WebhookUpdateParams struct {
+	URL    null.String `json:"url"`
+	Events []string    `json:"events"`
+	Active null.Bool   `json:"active"`
+}
+
+type WebhookDeliveryListParams struct {
+	Status   string
+	PageSize int
+	PageNum  int
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	UserSessionPurposeSessionCreate = "SESSION_CREATE"
+	UserSessionPurposePasswordReset = "PASSWORD_RESET"
+)
+
+// UserCreate creates a user, the request is sent with an
+// idempotency key so the retries do not create another user.
+func (c *Client) UserCreate(ctx context.Context, params UserCreateParams) (*User, error) {
+	var body struct {
+		User *User `json:"user"`
+	}
+	err := c.do(ctx, request{
+		method:     http.MethodPost,
+		path:       "/v1/users",
+		body:       params,
+		idempotent: true,
+	}, &body)
+	return body.User, err
+}
+
+func (c *Client) UserGet(ctx context.Context) (*User, error) {
+	var body struct {
+		User *User `json:"user"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "/v1/users",
+	}, &body)
+	return body.User, err
+}
+
+// UserUpdate replaces the user, the names left out are cleared.
+// UserPatch changes only the fields of its document.
+func (c *Client) UserUpdate(ctx context.Context, params UserUpdateParams) (*User, error) {
+	var body struct {
+		User *User `json:"user"`
+	}
+	err := c.do(ctx, request{
+		method:  http.MethodPut,
+		path:    "/v1/users",
+		headers: map[string]string{"If-Match": params.IfMatch},
+		body:    params,
+	}, &body)
+	return body.User, err
+}
+
+// UserPatch patches the user with the json merge patch document.
+// The patches are not retried, they may not be safe to apply twice.
+func (c *Client) UserPatch(ctx context.Context, params UserPatchParams) (*User, error) {
+	var body struct {
+		User *User `json:"user"`
+	}
+	err := c.do(ctx, request{
+		method:      http.MethodPatch,
+		path:        "/v1/users",
+		headers:     map[string]string{"If-Match": params.IfMatch},
+		body:        params.Document,
+		contentType: MIMEApplicationMergePatchJSON,
+	}, &body)
+	return body.User, err
+}
+
+func (c *Client) UserDelete(ctx context.Context) (*User, error) {
+	var body struct {
+		User *User `json:"user"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodDelete,
+		path:   "/v1/users",
+	}, &body)
+	return body.User, err
+}
+
+func (c *Client) UserPreferenceGet(ctx context.Context) (*UserPreference, error) {
+	var body struct {
+		Preferences *UserPreference `json:"preferences"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodGet,
+		path:   "/v1/users/preferences",
+	}, &body)
+	return body.Preferences, err
+}
+
+func (c *Client) UserPreferenceUpdate(ctx context.Context, params UserPreferenceUpdateParams) (*UserPreference, error) {
+	var body struct {
+		Preferences *UserPreference `json:"preferences"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodPut,
+		path:   "/v1/users/preferences",
+		body:   params,
+	}, &body)
+	return body.Preferences, err
+}
+
+// UserSessionCreate creates a user session, the client of the user
+// session is returned by WithUserSession. The purpose defaults to
+// UserSessionPurposeSessionCreate.
+func (c *Client) UserSessionCreate(ctx context.Context, params UserSessionCreateParams) (*UserSession, error) {
+	if params.Purpose == "" {
+		params.Purpose = UserSessionPurposeSessionCreate
+	}
+
+	var body struct {
+		UserSession *UserSession `json:"userSession"`
+	}
+	err := c.do(ctx, request{
+		method:     http.MethodPost,
+		path:       "/v1/users/sessions",
+		body:       params,
+		idempotent: true,
+	}, &body)
+	return body.UserSession, err
+}
+
+func (c *Client) UserSessionDelete(ctx context.Context) (*UserSession, error) {
+	var body struct {
+		UserSession *UserSession `json:"userSession"`
+	}
+	err := c.do(ctx, request{
+		method: http.MethodDelete,
+		path:   "/v1/users/sessions",
+	}, &body)
+	return body.UserSession, err
+}
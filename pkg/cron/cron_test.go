@@ -0,0 +1,74 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	valid := []string{
+		"* * * * *",
+		"*/15 * * * *",
+		"0 3 * * 1-5",
+		"5,35 0-6/2 1 */3 7",
+		"@daily",
+		"@Hourly",
+	}
+	for _, expr := range valid {
+		if _, err := Parse(expr); err != nil {
+			t.Fatalf("%s: want: err = nil; got: err = %v", expr, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@sometimes",
+	}
+	for _, expr := range invalid {
+		if _, err := Parse(expr); err == nil {
+			t.Fatalf("%s: want: err != nil; got: err = nil", expr)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	// A monday.
+	from := time.Date(2026, 1, 5, 10, 7, 30, 0, time.UTC)
+
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{expr: "* * * * *", want: time.Date(2026, 1, 5, 10, 8, 0, 0, time.UTC)},
+		{expr: "*/15 * * * *", want: time.Date(2026, 1, 5, 10, 15, 0, 0, time.UTC)},
+		{expr: "@hourly", want: time.Date(2026, 1, 5, 11, 0, 0, 0, time.UTC)},
+		{expr: "30 3 * * *", want: time.Date(2026, 1, 6, 3, 30, 0, 0, time.UTC)},
+		{expr: "0 0 * * 0", want: time.Date(2026, 1, 11, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 * * 7", want: time.Date(2026, 1, 11, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1 * *", want: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 29 2 *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Either day matches when both are restricted.
+		{expr: "0 0 15 * 3", want: time.Date(2026, 1, 7, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 30 2 *", want: time.Time{}},
+	}
+
+	for _, c := range cases {
+		s, err := Parse(c.expr)
+		if err != nil {
+			t.Fatalf("%s: want: err = nil; got: err = %v", c.expr, err)
+		}
+
+		if got := s.Next(from); !got.Equal(c.want) {
+			t.Fatalf("%s: want: %v; got: %v", c.expr, c.want, got)
+		}
+	}
+}
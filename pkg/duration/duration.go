@@ -5,3 +5,24 @@ import "time"
 func Seconds(t int) time.Duration {
 	return time.Duration(t) * time.Second
 }
+
+func Hours(t int) time.Duration {
+	return time.Duration(t) * time.Hour
+}
+
+// Backoff returns the wait after the given number of
+// attempts, doubling from base up to max.
+func Backoff(attempts int, base, max time.Duration) time.Duration {
+	wait := base
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= max {
+			return max
+		}
+	}
+
+	if wait > max {
+		return max
+	}
+	return wait
+}
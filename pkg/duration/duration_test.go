@@ -10,3 +10,29 @@ func TestSeconds(t *testing.T) {
 		t.Fatalf("Seconds(1) = %d; want 1", got)
 	}
 }
+
+func TestHours(t *testing.T) {
+	if got := Hours(1); got != 1*time.Hour {
+		t.Fatalf("Hours(1) = %d; want 1", got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{6, 16 * time.Minute},
+		{10, time.Hour},
+		{100, time.Hour},
+	}
+
+	for _, test := range tests {
+		if got := Backoff(test.attempts, 30*time.Second, time.Hour); got != test.want {
+			t.Fatalf("Backoff(%d) = %v; want %v", test.attempts, got, test.want)
+		}
+	}
+}
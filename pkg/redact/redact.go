@@ -0,0 +1,219 @@
+package redact
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Mask replaces every redacted value.
+const Mask = "***"
+
+var (
+	// DefaultFields are always redacted. A field matches if its
+	// normalized name contains one of these, e.g. passwordHash
+	// and token_hash both match.
+	DefaultFields = []string{
+		"password",
+		"token",
+		"email",
+		"secret",
+		"authorization",
+		"cookie",
+		"apikey",
+	}
+
+	// sqlCompare matches `"column" = $1` style comparisons and assignments.
+	sqlCompare = regexp.MustCompile(`(?i)"?([a-z_][a-z0-9_]*)"?\s*(?:=|<>|!=|\s+i?like\s+)\s*\$(\d+)`)
+	// sqlIn matches `"column" IN ($1,$2)` lists.
+	sqlIn = regexp.MustCompile(`(?i)"?([a-z_][a-z0-9_]*)"?\s+in\s*\(([^()]*)\)`)
+	// sqlInsert matches the column list and values of an insert.
+	sqlInsert = regexp.MustCompile(`(?is)insert\s+into\s+\S+\s*\(([^()]*)\)\s*values\s*(.*)`)
+	// sqlTuple matches a single parenthesized value tuple.
+	sqlTuple = regexp.MustCompile(`\(([^()]*)\)`)
+)
+
+type Config struct {
+	// Fields are redacted next to the DefaultFields.
+	Fields []string
+	// Paths are dot separated JSON paths, a "*" segment
+	// matches any key or array index, e.g. "items.*.card".
+	Paths []string
+}
+
+type Redactor struct {
+	fields []string
+	paths  [][]string
+}
+
+func New(config Config) *Redactor {
+	r := &Redactor{}
+
+	for _, field := range append(DefaultFields, config.Fields...) {
+		if field = normalize(field); field != "" {
+			r.fields = append(r.fields, field)
+		}
+	}
+
+	for _, path := range config.Paths {
+		if path = strings.TrimSpace(path); path != "" {
+			r.paths = append(r.paths, strings.Split(path, "."))
+		}
+	}
+
+	return r
+}
+
+// Field reports whether the field name is sensitive.
+func (r *Redactor) Field(name string) bool {
+	name = normalize(name)
+	if name == "" {
+		return false
+	}
+
+	for _, field := range r.fields {
+		if strings.Contains(name, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// Value returns a copy of the decoded JSON value with sensitive
+// fields and configured paths masked. Booleans and nulls are kept
+// since they don't carry the value itself, e.g. emailVerified.
+func (r *Redactor) Value(v interface{}) interface{} {
+	return r.value(v, nil, false)
+}
+
+func (r *Redactor) value(v interface{}, path []string, sensitive bool) interface{} {
+	sensitive = sensitive || r.path(path)
+
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(typed))
+		for key, val := range typed {
+			m[key] = r.value(val, append(path[:len(path):len(path)], key), sensitive || r.Field(key))
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(typed))
+		for i, val := range typed {
+			s[i] = r.value(val, append(path[:len(path):len(path)], strconv.Itoa(i)), sensitive)
+		}
+		return s
+	case nil, bool:
+		return v
+	}
+
+	if sensitive {
+		return Mask
+	}
+	return v
+}
+
+func (r *Redactor) path(path []string) bool {
+	for _, p := range r.paths {
+		if len(p) != len(path) {
+			continue
+		}
+
+		match := true
+		for i := range p {
+			if p[i] != "*" && p[i] != path[i] {
+				match = false
+				break
+			}
+		}
+
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// JSON redacts the JSON document and returns it re-encoded.
+func (r *Redactor) JSON(data []byte) (json.RawMessage, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(r.Value(v))
+}
+
+// Header returns the header value, masked if the header is sensitive.
+func (r *Redactor) Header(key, value string) string {
+	if r.Field(key) {
+		return Mask
+	}
+	return value
+}
+
+// SQL masks the params of the statement that are compared to,
+// assigned to or inserted into sensitive columns. Placeholders
+// are expected in the postgres $N form.
+func (r *Redactor) SQL(sql string, params []interface{}) []interface{} {
+	masked := make([]interface{}, len(params))
+	copy(masked, params)
+
+	mask := func(column, placeholder string) {
+		if !r.Field(column) {
+			return
+		}
+
+		placeholder = strings.TrimSpace(placeholder)
+		if !strings.HasPrefix(placeholder, "$") {
+			return
+		}
+
+		i, err := strconv.Atoi(placeholder[1:])
+		if err != nil || i < 1 || i > len(masked) {
+			return
+		}
+
+		// Keep booleans and nulls as in Value.
+		param := masked[i-1]
+		if valuer, ok := param.(driver.Valuer); ok {
+			param, _ = valuer.Value()
+		}
+
+		switch param.(type) {
+		case nil, bool:
+			return
+		}
+		masked[i-1] = Mask
+	}
+
+	for _, match := range sqlCompare.FindAllStringSubmatch(sql, -1) {
+		mask(match[1], "$"+match[2])
+	}
+
+	for _, match := range sqlIn.FindAllStringSubmatch(sql, -1) {
+		for _, placeholder := range strings.Split(match[2], ",") {
+			mask(match[1], placeholder)
+		}
+	}
+
+	if match := sqlInsert.FindStringSubmatch(sql); match != nil {
+		columns := strings.Split(match[1], ",")
+		for _, tuple := range sqlTuple.FindAllStringSubmatch(match[2], -1) {
+			for i, placeholder := range strings.Split(tuple[1], ",") {
+				if i < len(columns) {
+					mask(strings.Trim(strings.TrimSpace(columns[i]), `"`), placeholder)
+				}
+			}
+		}
+	}
+
+	return masked
+}
+
+// normalize lowercases the name and strips separators
+// so that snake, kebab and camel case names compare equal.
+func normalize(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer("_", "", "-", "", " ", "").Replace(name)
+}
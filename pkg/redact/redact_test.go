@@ -0,0 +1,105 @@
+package redact
+
+import (
+	"testing"
+)
+
+func TestField(t *testing.T) {
+	r := New(Config{Fields: []string{"phone"}})
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"password", true},
+		{"passwordHash", true},
+		{"token_hash", true},
+		{"Authorization", true},
+		{"X-Api-Key", true},
+		{"phoneNumber", true},
+		{"givenNames", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if got := r.Field(test.name); got != test.want {
+			t.Fatalf("want: field(%s) = %t; got: %t", test.name, test.want, got)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	r := New(Config{Paths: []string{"card.number", "items.*.code"}})
+
+	body := `{"email":"a@b.com","emailVerified":true,"givenNames":"Ada","user":{"password":"secret123"},"card":{"number":4111,"expiry":"12/30"},"items":[{"code":"x"},{"code":"y"}],"tokens":["a","b"]}`
+
+	got, err := r.JSON([]byte(body))
+	if err != nil {
+		t.Fatalf("want: json err nil; got: %v", err)
+	}
+
+	want := `{"card":{"expiry":"12/30","number":"***"},"email":"***","emailVerified":true,"givenNames":"Ada","items":[{"code":"***"},{"code":"***"}],"tokens":["***","***"],"user":{"password":"***"}}`
+	if string(got) != want {
+		t.Fatalf("want: %s; got: %s", want, got)
+	}
+
+	if _, err := r.JSON([]byte(`{"email":`)); err == nil {
+		t.Fatalf("want: json err not nil; got: nil")
+	}
+}
+
+func TestHeader(t *testing.T) {
+	r := New(Config{})
+
+	if got := r.Header("Authorization", "1-token"); got != Mask {
+		t.Fatalf("want: %s; got: %s", Mask, got)
+	}
+
+	if got := r.Header("Accept-Language", "en"); got != "en" {
+		t.Fatalf("want: en; got: %s", got)
+	}
+}
+
+func TestSQL(t *testing.T) {
+	r := New(Config{})
+
+	tests := []struct {
+		sql    string
+		params []interface{}
+		want   []interface{}
+	}{
+		{
+			sql:    `SELECT * FROM "user" WHERE "email" = $1 AND "id" = $2`,
+			params: []interface{}{"a@b.com", 1},
+			want:   []interface{}{Mask, 1},
+		},
+		{
+			sql:    `SELECT * FROM "user_session" WHERE token_hash IN ($1, $2) AND purpose = $3`,
+			params: []interface{}{"a", "b", "auth"},
+			want:   []interface{}{Mask, Mask, "auth"},
+		},
+		{
+			sql:    `INSERT INTO "user" ("created_at","email","password_hash","given_names") VALUES ($1,$2,$3,$4),($5,$6,$7,$8) RETURNING "id"`,
+			params: []interface{}{"t", "a@b.com", "hash", "Ada", "t", "c@d.com", "hash", "Bob"},
+			want:   []interface{}{"t", Mask, Mask, "Ada", "t", Mask, Mask, "Bob"},
+		},
+		{
+			sql:    `UPDATE "user" SET "email_verified"=$1,"password_hash"=$2 WHERE "id" = $3`,
+			params: []interface{}{true, "hash", 1},
+			want:   []interface{}{true, Mask, 1},
+		},
+	}
+
+	for _, test := range tests {
+		got := r.SQL(test.sql, test.params)
+		if len(got) != len(test.want) {
+			t.Fatalf("want: len = %d; got: %d", len(test.want), len(got))
+		}
+
+		for i := range test.want {
+			if got[i] != test.want[i] {
+				t.Fatalf("want: params[%d] = %v; got: %v, sql: %s", i, test.want[i], got[i], test.sql)
+			}
+		}
+	}
+}